@@ -14,6 +14,7 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/tools"
 	"github.com/nguyenvanduocit/jira-mcp/util"
 
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
@@ -41,6 +42,46 @@ func fatal(format string, args ...any) {
 	os.Exit(1)
 }
 
+// bodyADF converts a description or comment flag written in format to ADF,
+// exiting when it cannot be read.
+func bodyADF(flagName, text, format string) *models.CommentNodeScheme {
+	body, err := util.BodyToADF(text, format)
+	if err != nil {
+		fatal("invalid %s: %v", flagName, err)
+	}
+	return body
+}
+
+// estimateFields turns --original-estimate and --remaining-estimate into
+// the timetracking field, in minutes so Jira reads them as they were meant.
+// On update the estimates are edited one by one, leaving out the other.
+// It returns nil when neither is given and exits when one cannot be read.
+func estimateFields(ctx context.Context, original, remaining string, update bool) *models.CustomFields {
+	if original == "" && remaining == "" {
+		return nil
+	}
+	calendar := services.JiraWorkingCalendar(ctx)
+	estimates := map[string]interface{}{}
+	for _, estimate := range []struct{ flagName, key, value string }{
+		{"--original-estimate", "originalEstimate", original},
+		{"--remaining-estimate", "remainingEstimate", remaining},
+	} {
+		if estimate.value == "" {
+			continue
+		}
+		seconds, err := util.ParseDuration(estimate.value, calendar)
+		if err != nil {
+			fatal("invalid %s: %v", estimate.flagName, err)
+		}
+		estimates[estimate.key] = fmt.Sprintf("%dm", (seconds+30)/60)
+	}
+	node := map[string]interface{}{"fields": map[string]interface{}{"timetracking": estimates}}
+	if update {
+		node = map[string]interface{}{"update": map[string]interface{}{"timetracking": []map[string]interface{}{{"edit": estimates}}}}
+	}
+	return &models.CustomFields{Fields: []map[string]interface{}{node}}
+}
+
 func printUsage() {
 	fmt.Fprintf(os.Stderr, `jira-cli - Command line interface for Atlassian Jira
 
@@ -67,6 +108,11 @@ CONFIGURATION
       ATLASSIAN_PAT    Personal Access Token (generate from User Profile → Personal Access Tokens)
       Note: ATLASSIAN_PAT takes precedence if all three vars are set.
 
+  Optional:
+    JIRA_DEPLOYMENT  cloud | server | datacenter | auto (default: auto)
+                     "auto" asks /rest/api/2/serverInfo. Server/DC instances are
+                     served through REST v2 with wiki markup instead of ADF.
+
   Example .env file (Jira Cloud):
     ATLASSIAN_HOST=https://mycompany.atlassian.net
     ATLASSIAN_EMAIL=user@company.com
@@ -89,7 +135,10 @@ COMMANDS
     --issue-key string     Issue key, e.g. PROJ-123 (required)
     --fields string        Comma-separated fields to retrieve (default: all)
     --expand string        Comma-separated expansions (default: transitions,changelog,subtasks,description)
+    --profile string       Render the issue: minimal, standard, full, or a comma-separated list of sections
+    --max-chars int        Character budget for the rendered issue (with --profile)
     Example: jira-cli get-issue --issue-key PROJ-123
+    Example: jira-cli get-issue --issue-key PROJ-123 --profile standard --max-chars 4000
     Example: jira-cli get-issue --issue-key PROJ-123 --fields summary,status --output json
 
   create-issue           Create a new Jira issue
@@ -97,8 +146,11 @@ COMMANDS
     --summary string       Issue title (required)
     --type string          Issue type: Bug, Task, Story, Epic, Subtask (required)
     --description string   Issue description in markdown format
+    --format string        Markup of the description: markdown (default), wiki or adf_json
     --assignee string      Assignee account ID
     --priority string      Priority name: Highest, High, Medium, Low, Lowest
+    --original-estimate string Original estimate, e.g. 2d or 1w 2d 3h 30m
+    --remaining-estimate string Remaining estimate (default: the original estimate)
     Example: jira-cli create-issue --project PROJ --summary "Fix login bug" --type Bug
     Example: jira-cli create-issue --project PROJ --summary "New feature" --type Story \
              --description "## Overview\nA new feature" --priority High
@@ -107,6 +159,7 @@ COMMANDS
     --parent-key string    Parent issue key (required)
     --summary string       Child issue title (required)
     --description string   Description in markdown format
+    --format string        Markup of the description: markdown (default), wiki or adf_json
     --type string          Issue type (default: Subtask)
     Example: jira-cli create-child-issue --parent-key PROJ-123 --summary "Subtask 1"
 
@@ -114,13 +167,18 @@ COMMANDS
     --issue-key string     Issue key (required)
     --summary string       New title
     --description string   New description in markdown format
+    --format string        Markup of the description: markdown (default), wiki or adf_json
     --assignee string      Assignee account ID
     --priority string      Priority name: Highest, High, Medium, Low, Lowest
+    --original-estimate string New original estimate, e.g. 2d or 1.5h
+    --remaining-estimate string New remaining estimate
     Example: jira-cli update-issue --issue-key PROJ-123 --summary "Updated title"
     Example: jira-cli update-issue --issue-key PROJ-123 --priority High --assignee 5a1234b
+    Example: jira-cli update-issue --issue-key PROJ-123 --remaining-estimate "1d 4h"
 
   delete-issue           Permanently delete an issue (cannot be undone)
     --issue-key string     Issue key (required)
+    --delete-subtasks      Also delete the issue's subtasks (required for issues that have any)
     Example: jira-cli delete-issue --issue-key PROJ-123
 
   list-issue-types       List all available issue types
@@ -132,10 +190,17 @@ COMMANDS
   search-issues          Search issues using JQL (Jira Query Language)
     --jql string           JQL query (required)
     --max-results int      Maximum results to return (default: 30)
+    --page-token string    Continue from the token printed by a previous search
+    --fetch-all            Follow every page (stops at a 1000-issue safety cap)
     --fields string        Comma-separated fields to retrieve
     --expand string        Comma-separated expansions
+    --output string        text, json, compact, markdown_table, csv or full (default: text)
+    --columns string       Columns for compact, markdown_table and csv (default: key,summary,status,assignee,priority);
+                           built-in names or custom field names/IDs, e.g. key,summary,"Story Points"
     Example: jira-cli search-issues --jql "project = PROJ AND status = 'In Progress'"
     Example: jira-cli search-issues --jql "assignee = currentUser() ORDER BY updated DESC" --max-results 10
+    Example: jira-cli search-issues --jql "project = PROJ" --fetch-all --output json
+    Example: jira-cli search-issues --jql "project = PROJ" --fetch-all --output csv --columns key,summary,status,created > issues.csv
 
   Sprint Management
   ─────────────────
@@ -168,6 +233,7 @@ COMMANDS
   add-comment            Add a comment to an issue (supports markdown)
     --issue-key string     Issue key (required)
     --comment string       Comment text in markdown format (required)
+    --format string        Markup of the comment: markdown (default), wiki or adf_json
     Example: jira-cli add-comment --issue-key PROJ-123 --comment "Fixed in **v2.1**"
 
   get-comments           Retrieve all comments on an issue
@@ -178,13 +244,55 @@ COMMANDS
   ────────
   add-worklog            Log time spent on an issue
     --issue-key string     Issue key (required)
-    --time-spent string    Duration: "1h30m", "3h", "30m", or seconds (required)
+    --time-spent string    Duration in Jira's notation: "1h 30m", "1.5h", "1d 2h" (required);
+                           days and weeks follow the instance's working calendar
     --comment string       Work description in markdown format
+    --format string        Markup of the comment: markdown (default), wiki or adf_json
     --started string       Start time in ISO 8601 (default: now)
+    --adjust-estimate string Remaining estimate: auto (default), leave, new or manual
+    --new-estimate string  Remaining estimate to set with --adjust-estimate new (e.g. 2d)
+    --reduce-by string     Amount to reduce it by with --adjust-estimate manual
     Example: jira-cli add-worklog --issue-key PROJ-123 --time-spent 2h30m
     Example: jira-cli add-worklog --issue-key PROJ-123 --time-spent 1h \
              --comment "Code review" --started "2025-01-15T09:00:00.000+0700"
 
+  get-worklogs           List the worklogs of an issue, a page at a time
+    --issue-key string     Issue key (required)
+    --author string        Only worklogs by this account ID, username, email or name
+    --started-after string Only worklogs started on or after this date (2025-01-01) or time
+    --started-before string Only worklogs started on or before this date, or before this time
+    --start-at int         Index of the first worklog (default: 0)
+    --max-results int      Worklogs per page (default: 50)
+    Example: jira-cli get-worklogs --issue-key PROJ-123 --author jdoe --started-after 2025-01-01
+
+  update-worklog         Correct a worklog's time, start or comment
+    --issue-key string     Issue key (required)
+    --worklog-id string    Worklog ID from get-worklogs (required)
+    --time-spent string    New time spent
+    --started string       New start time in ISO 8601
+    --comment string       New work description
+    --format string        Markup of the comment: markdown (default), wiki or adf_json
+    --adjust-estimate string Remaining estimate: auto (default), leave or new
+    --new-estimate string  Remaining estimate to set with --adjust-estimate new
+    Example: jira-cli update-worklog --issue-key PROJ-123 --worklog-id 10042 --time-spent 1h30m
+
+  delete-worklog         Delete a worklog
+    --issue-key string     Issue key (required)
+    --worklog-id string    Worklog ID from get-worklogs (required)
+    --adjust-estimate string Remaining estimate: auto (default), leave, new or manual
+    --new-estimate string  Remaining estimate to set with --adjust-estimate new
+    --increase-by string   Amount to increase it by with --adjust-estimate manual
+    Example: jira-cli delete-worklog --issue-key PROJ-123 --worklog-id 10042 --adjust-estimate leave
+
+  timesheet              Hours logged on the issues matching JQL, by user, day and issue
+    --jql string           JQL selecting the issues (required)
+    --from string          First day, e.g. 2025-01-06 (required)
+    --to string            Last day, included (default: today)
+    --author string        Only worklogs by this account ID, username, email or name
+    --format string        markdown_table (default), csv or json
+    Example: jira-cli timesheet --jql "project = PROJ" --from 2025-01-06 --to 2025-01-12
+    Example: jira-cli timesheet --jql "sprint in openSprints()" --from 2025-01-06 --format csv > hours.csv
+
   Status & Transitions
   ────────────────────
   get-transitions        List available status transitions for an issue
@@ -193,13 +301,29 @@ COMMANDS
 
   transition-issue       Move an issue to a new status
     --issue-key string     Issue key (required)
-    --transition-id string Transition ID from get-transitions (required)
-    Example: jira-cli transition-issue --issue-key PROJ-123 --transition-id 31
+    --to-status string     Target status name; the transition is looked up
+    --transition-id string Transition ID from get-transitions (instead of --to-status)
+    Example: jira-cli transition-issue --issue-key PROJ-123 --to-status "In Progress"
 
   list-statuses          List all statuses for a project
     --project-key string   Project key (required)
     Example: jira-cli list-statuses --project-key PROJ
 
+  bulk                   Apply the same changes to every issue matching JQL
+    --jql string                  JQL selecting the issues (required)
+    --to-status string            Move each issue to this status
+    --assignee string             Email, display name, account ID, "me" or "none"
+    --add-labels string           Comma-separated labels to add
+    --remove-labels string        Comma-separated labels to remove
+    --add-fix-versions string     Comma-separated fix versions to add
+    --remove-fix-versions string  Comma-separated fix versions to remove
+    --comment string              Comment to add to every issue
+    --format string               Markup of the comment: markdown (default), wiki or adf_json
+    --max-issues int              Maximum issues to change (default: 50, max 500)
+    --concurrency int             Issues updated in parallel (default: 4, max 10)
+    --dry-run bool                List matching issues without changing them
+    Example: jira-cli bulk --jql "sprint = 42 AND status = 'In Review'" --to-status Done --dry-run
+
   History & Relationships
   ──────────────────────
   get-issue-history      View the change history of an issue
@@ -215,6 +339,7 @@ COMMANDS
     --outward-issue string Outward issue key (required)
     --link-type string     Relationship type: Blocks, Duplicate, Relates, etc. (required)
     --comment string       Optional comment in markdown format
+    --format string        Markup of the comment: markdown (default), wiki or adf_json
     Example: jira-cli link-issues --inward-issue PROJ-1 --outward-issue PROJ-2 --link-type Blocks
 
   Versions
@@ -243,14 +368,25 @@ COMMANDS
     --attachment-id string Attachment ID (required)
     Example: jira-cli download-attachment --attachment-id 10500
 
+  upload-attachment      Attach a local file to an issue
+    --issue-key string     Issue key (required)
+    --file string          Path of the file to upload (required)
+    --filename string      Name of the attachment in Jira (default: the file's name)
+    --insert-into string   Also show it in the "description" or a new "comment"
+    --comment string       Text of the new comment, with --insert-into comment
+    --format string        Markup of the comment: markdown (default), wiki or adf_json
+    Example: jira-cli upload-attachment --issue-key PROJ-123 --file ./screenshot.png --insert-into comment
+
 NOTES
   - Description and comment fields accept markdown, which is automatically
-    converted to Atlassian Document Format (ADF) before sending to Jira.
+    converted to Atlassian Document Format (ADF) before sending to Jira
+    (or to wiki markup on Server/Data Center).
+  - upload-attachment refuses files over JIRA_MCP_UPLOAD_LIMIT (default 10MB).
   - Use --output json on any command to get machine-readable output.
   - Sprint commands require either --board-id or --project-key. If you use
     --project-key, the CLI looks up associated boards automatically.
-  - To find a transition ID, first run get-transitions for your issue,
-    then use the returned ID with transition-issue.
+  - transition-issue --to-status picks the transition for you; use
+    get-transitions to see what is available from the current status.
 
 `)
 }
@@ -290,12 +426,22 @@ func main() {
 		runGetComments(os.Args[2:])
 	case "add-worklog":
 		runAddWorklog(os.Args[2:])
+	case "get-worklogs":
+		runGetWorklogs(os.Args[2:])
+	case "update-worklog":
+		runUpdateWorklog(os.Args[2:])
+	case "delete-worklog":
+		runDeleteWorklog(os.Args[2:])
+	case "timesheet":
+		runTimesheet(os.Args[2:])
 	case "get-transitions":
 		runGetTransitions(os.Args[2:])
 	case "transition-issue":
 		runTransitionIssue(os.Args[2:])
 	case "list-statuses":
 		runListStatuses(os.Args[2:])
+	case "bulk":
+		runBulk(os.Args[2:])
 	case "get-issue-history":
 		runGetIssueHistory(os.Args[2:])
 	case "get-related-issues":
@@ -310,6 +456,8 @@ func main() {
 		runGetDevelopmentInfo(os.Args[2:])
 	case "download-attachment":
 		runDownloadAttachment(os.Args[2:])
+	case "upload-attachment":
+		runUploadAttachment(os.Args[2:])
 	case "help", "--help", "-h":
 		printUsage()
 	default:
@@ -327,21 +475,36 @@ func runGetIssue(args []string) {
 	issueKey := fs.String("issue-key", "", "Issue key (required, e.g. PROJ-123)")
 	fields := fs.String("fields", "", "Comma-separated fields to retrieve")
 	expand := fs.String("expand", "", "Comma-separated fields to expand")
+	profileName := fs.String("profile", "", "Render profile: minimal, standard, full, or a comma-separated list of sections")
+	maxChars := fs.Int("max-chars", 0, "Character budget for the rendered issue")
 	fs.Parse(args)
 
 	loadEnv(*env)
 	if *issueKey == "" {
 		fatal("--issue-key is required")
 	}
+	var profile util.RenderProfile
+	if *profileName != "" {
+		var err error
+		if profile, err = util.ParseRenderProfile(*profileName); err != nil {
+			fatal("invalid --profile: %v", err)
+		}
+	}
 
 	ctx := context.Background()
 	client := services.JiraClient()
 
 	var fieldSlice []string
+	if *profileName != "" {
+		fieldSlice = profile.JiraFields()
+	}
 	if *fields != "" {
 		fieldSlice = strings.Split(strings.ReplaceAll(*fields, " ", ""), ",")
 	}
 	expandSlice := []string{"transitions", "changelog", "subtasks", "description"}
+	if *profileName != "" {
+		expandSlice = profile.Expand()
+	}
 	if *expand != "" {
 		expandSlice = strings.Split(strings.ReplaceAll(*expand, " ", ""), ",")
 	}
@@ -358,6 +521,10 @@ func runGetIssue(args []string) {
 		printJSON(issue)
 		return
 	}
+	if *profileName != "" {
+		fmt.Print(util.RenderIssueBudget(issue, profile, *maxChars))
+		return
+	}
 
 	fmt.Printf("Key: %s\n", issue.Key)
 	fmt.Printf("ID:  %s\n", issue.ID)
@@ -383,9 +550,12 @@ func runGetIssue(args []string) {
 func runSearchIssues(args []string) {
 	fs := flag.NewFlagSet("search-issues", flag.ExitOnError)
 	env := fs.String("env", "", "Path to .env file")
-	output := fs.String("output", "text", "Output format: text or json")
+	output := fs.String("output", "text", "Output format: text, json, compact, markdown_table, csv or full")
+	columns := fs.String("columns", "", "Columns for compact, markdown_table and csv")
 	jql := fs.String("jql", "", "JQL query (required)")
-	maxResults := fs.Int("max-results", 30, "Maximum number of results")
+	maxResults := fs.Int("max-results", util.SearchDefaultMaxResults, "Maximum number of results")
+	pageToken := fs.String("page-token", "", "Token from a previous search to continue from")
+	fetchAll := fs.Bool("fetch-all", false, "Follow every page (capped for safety)")
 	fields := fs.String("fields", "", "Comma-separated fields to retrieve")
 	expand := fs.String("expand", "", "Comma-separated fields to expand")
 	fs.Parse(args)
@@ -398,29 +568,97 @@ func runSearchIssues(args []string) {
 	ctx := context.Background()
 
 	var fieldSlice []string
+	expandSlice := []string{"transitions", "changelog", "subtasks", "description"}
+	var format util.SearchFormat
+	var tableColumns []util.IssueColumn
+	var names map[string]string
+	switch *output {
+	case "text", "json":
+	default:
+		var err error
+		if format, err = util.ParseSearchFormat(*output); err != nil {
+			fatal("invalid --output: %v", err)
+		}
+		names, _ = services.JiraFieldCatalog().Names(ctx)
+		if format == util.SearchFormatFull {
+			fieldSlice, expandSlice = util.ProfileFull.JiraFields(), util.ProfileFull.Expand()
+			break
+		}
+		if tableColumns, err = util.ParseIssueColumns(*columns, names); err != nil {
+			fatal("invalid --columns: %v", err)
+		}
+		fieldSlice, expandSlice = util.IssueColumnFields(tableColumns), nil
+	}
 	if *fields != "" {
 		fieldSlice = strings.Split(strings.ReplaceAll(*fields, " ", ""), ",")
 	}
-	expandSlice := []string{"transitions", "changelog", "subtasks", "description"}
 	if *expand != "" {
 		expandSlice = strings.Split(strings.ReplaceAll(*expand, " ", ""), ",")
 	}
 
-	issues, err := searchIssuesJQL(ctx, *jql, fieldSlice, expandSlice, 0, *maxResults)
+	rawIssues := make(map[string][]byte)
+	fetchPage := func(ctx context.Context, token string, size int) (*util.SearchJQLResult, error) {
+		page, err := searchIssuesJQL(ctx, *jql, fieldSlice, expandSlice, token, size)
+		if err != nil {
+			return nil, err
+		}
+		for i, issue := range page.Issues {
+			if i < len(page.RawIssues) {
+				rawIssues[issue.Key] = page.RawIssues[i]
+			}
+		}
+		return page, nil
+	}
+	issues, nextPageToken, err := util.FetchSearchPages(ctx, fetchPage, *pageToken, *maxResults, *fetchAll)
 	if err != nil {
 		fatal("failed to search issues: %v", err)
 	}
 
 	if *output == "json" {
-		printJSON(issues)
+		printJSON(map[string]any{
+			"returned":      len(issues),
+			"nextPageToken": nextPageToken,
+			"isLast":        nextPageToken == "",
+			"issues":        issues,
+		})
 		return
 	}
 
-	if len(issues.Issues) == 0 {
+	customFields := func(issue *models.IssueScheme) []util.CustomFieldValue {
+		return util.ExtractCustomFields(rawIssues[issue.Key], names)
+	}
+	switch format {
+	case util.SearchFormatFull:
+		fmt.Println(util.FormatSearchHeader(-1, len(issues), nextPageToken, *fetchAll))
+		fmt.Println()
+		fmt.Print(util.RenderIssueList(issues, util.ProfileFull, 0, customFields))
+		return
+	case util.SearchFormatCSV:
+		// The header goes to stderr so stdout stays valid CSV.
+		fmt.Fprintln(os.Stderr, util.FormatSearchHeader(-1, len(issues), nextPageToken, *fetchAll))
+		table, err := util.FormatIssueTable(format, issues, tableColumns, customFields, 0)
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Print(table)
+		return
+	case util.SearchFormatCompact, util.SearchFormatMarkdownTable:
+		fmt.Println(util.FormatSearchHeader(-1, len(issues), nextPageToken, *fetchAll))
+		fmt.Println()
+		table, err := util.FormatIssueTable(format, issues, tableColumns, customFields, 0)
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Print(table)
+		return
+	}
+
+	if len(issues) == 0 {
 		fmt.Println("No issues found.")
 		return
 	}
-	for _, issue := range issues.Issues {
+	fmt.Println(util.FormatSearchHeader(-1, len(issues), nextPageToken, *fetchAll))
+	for _, issue := range issues {
 		fmt.Printf("Key: %s", issue.Key)
 		if issue.Fields != nil {
 			fmt.Printf("  Summary: %s", issue.Fields.Summary)
@@ -432,13 +670,9 @@ func runSearchIssues(args []string) {
 	}
 }
 
-// searchIssuesJQL uses the new /rest/api/3/search/jql endpoint directly
-func searchIssuesJQL(ctx context.Context, jql string, fields []string, expand []string, startAt, maxResults int) (*models.IssueSearchScheme, error) {
-	host := os.Getenv("ATLASSIAN_HOST")
-	mail := os.Getenv("ATLASSIAN_EMAIL")
-	token := os.Getenv("ATLASSIAN_TOKEN")
-	pat := os.Getenv("ATLASSIAN_PAT")
-
+// searchIssuesJQL fetches one page from the /rest/api/3/search/jql endpoint
+// directly. An empty pageToken starts from the first result.
+func searchIssuesJQL(ctx context.Context, jql string, fields []string, expand []string, pageToken string, maxResults int) (*util.SearchJQLResult, error) {
 	params := url.Values{}
 	params.Set("jql", jql)
 	if len(fields) > 0 {
@@ -447,34 +681,16 @@ func searchIssuesJQL(ctx context.Context, jql string, fields []string, expand []
 	if len(expand) > 0 {
 		params.Set("expand", strings.Join(expand, ","))
 	}
-	if startAt > 0 {
-		params.Set("startAt", strconv.Itoa(startAt))
+	if pageToken != "" {
+		params.Set("nextPageToken", pageToken)
 	}
 	if maxResults > 0 {
 		params.Set("maxResults", strconv.Itoa(maxResults))
 	}
 
-	endpoint := fmt.Sprintf("%s/rest/api/3/search/jql?%s", host, params.Encode())
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	services.ApplyAtlassianAuth(req, mail, token, pat)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
-	}
-
-	var result models.IssueSearchScheme
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	var result util.SearchJQLResult
+	if _, err := services.JiraRawClient().Do(ctx, http.MethodGet, "rest/api/3/search/jql", params, nil, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
@@ -489,8 +705,11 @@ func runCreateIssue(args []string) {
 	summary := fs.String("summary", "", "Issue summary (required)")
 	issueType := fs.String("type", "", "Issue type (required, e.g. Bug, Task, Story)")
 	description := fs.String("description", "", "Issue description (markdown supported)")
+	format := fs.String("format", "markdown", "Markup of the description: markdown, wiki or adf_json")
 	assignee := fs.String("assignee", "", "Assignee account ID")
 	priority := fs.String("priority", "", "Priority name (e.g. High, Medium, Low)")
+	originalEstimate := fs.String("original-estimate", "", "Original estimate (e.g. 2d, 1w 2d 3h 30m)")
+	remainingEstimate := fs.String("remaining-estimate", "", "Remaining estimate (default: the original estimate)")
 	fs.Parse(args)
 
 	loadEnv(*env)
@@ -515,7 +734,7 @@ func runCreateIssue(args []string) {
 		},
 	}
 	if *description != "" {
-		payload.Fields.Description = util.MarkdownToADF(*description)
+		payload.Fields.Description = bodyADF("--description", *description, *format)
 	}
 	if *assignee != "" {
 		payload.Fields.Assignee = &models.UserScheme{AccountID: *assignee}
@@ -524,7 +743,7 @@ func runCreateIssue(args []string) {
 		payload.Fields.Priority = &models.PriorityScheme{Name: *priority}
 	}
 
-	issue, response, err := client.Issue.Create(ctx, payload, nil)
+	issue, response, err := client.Issue.Create(ctx, payload, estimateFields(ctx, *originalEstimate, *remainingEstimate, false))
 	if err != nil {
 		if response != nil {
 			fatal("failed to create issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -548,6 +767,7 @@ func runCreateChildIssue(args []string) {
 	parentKey := fs.String("parent-key", "", "Parent issue key (required)")
 	summary := fs.String("summary", "", "Issue summary (required)")
 	description := fs.String("description", "", "Issue description")
+	format := fs.String("format", "markdown", "Markup of the description: markdown, wiki or adf_json")
 	issueType := fs.String("type", "Subtask", "Issue type (default: Subtask)")
 	fs.Parse(args)
 
@@ -579,7 +799,7 @@ func runCreateChildIssue(args []string) {
 		},
 	}
 	if *description != "" {
-		payload.Fields.Description = util.MarkdownToADF(*description)
+		payload.Fields.Description = bodyADF("--description", *description, *format)
 	}
 
 	issue, response, err := client.Issue.Create(ctx, payload, nil)
@@ -606,8 +826,11 @@ func runUpdateIssue(args []string) {
 	issueKey := fs.String("issue-key", "", "Issue key (required)")
 	summary := fs.String("summary", "", "New summary")
 	description := fs.String("description", "", "New description (markdown supported)")
+	format := fs.String("format", "markdown", "Markup of the description: markdown, wiki or adf_json")
 	assignee := fs.String("assignee", "", "Assignee account ID")
 	priority := fs.String("priority", "", "Priority name (e.g. High, Medium, Low)")
+	originalEstimate := fs.String("original-estimate", "", "New original estimate (e.g. 2d, 1.5h)")
+	remainingEstimate := fs.String("remaining-estimate", "", "New remaining estimate")
 	fs.Parse(args)
 
 	loadEnv(*env)
@@ -625,7 +848,7 @@ func runUpdateIssue(args []string) {
 		payload.Fields.Summary = *summary
 	}
 	if *description != "" {
-		payload.Fields.Description = util.MarkdownToADF(*description)
+		payload.Fields.Description = bodyADF("--description", *description, *format)
 	}
 	if *assignee != "" {
 		payload.Fields.Assignee = &models.UserScheme{AccountID: *assignee}
@@ -634,7 +857,7 @@ func runUpdateIssue(args []string) {
 		payload.Fields.Priority = &models.PriorityScheme{Name: *priority}
 	}
 
-	response, err := client.Issue.Update(ctx, *issueKey, true, payload, nil, nil)
+	response, err := client.Issue.Update(ctx, *issueKey, true, payload, estimateFields(ctx, *originalEstimate, *remainingEstimate, true), nil)
 	if err != nil {
 		if response != nil {
 			fatal("failed to update issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -656,6 +879,7 @@ func runDeleteIssue(args []string) {
 	env := fs.String("env", "", "Path to .env file")
 	output := fs.String("output", "text", "Output format: text or json")
 	issueKey := fs.String("issue-key", "", "Issue key (required)")
+	deleteSubtasks := fs.Bool("delete-subtasks", false, "Also delete the issue's subtasks")
 	fs.Parse(args)
 
 	loadEnv(*env)
@@ -666,7 +890,7 @@ func runDeleteIssue(args []string) {
 	ctx := context.Background()
 	client := services.JiraClient()
 
-	response, err := client.Issue.Delete(ctx, *issueKey, false)
+	response, err := client.Issue.Delete(ctx, *issueKey, *deleteSubtasks)
 	if err != nil {
 		if response != nil {
 			fatal("failed to delete issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -930,6 +1154,7 @@ func runAddComment(args []string) {
 	output := fs.String("output", "text", "Output format: text or json")
 	issueKey := fs.String("issue-key", "", "Issue key (required)")
 	comment := fs.String("comment", "", "Comment text (required)")
+	format := fs.String("format", "markdown", "Markup of the comment: markdown, wiki or adf_json")
 	fs.Parse(args)
 
 	loadEnv(*env)
@@ -944,7 +1169,7 @@ func runAddComment(args []string) {
 	client := services.JiraClient()
 
 	payload := &models.CommentPayloadScheme{
-		Body: util.MarkdownToADF(*comment),
+		Body: bodyADF("--comment", *comment, *format),
 	}
 
 	result, response, err := client.Issue.Comment.Add(ctx, *issueKey, payload, nil)
@@ -1026,9 +1251,13 @@ func runAddWorklog(args []string) {
 	env := fs.String("env", "", "Path to .env file")
 	output := fs.String("output", "text", "Output format: text or json")
 	issueKey := fs.String("issue-key", "", "Issue key (required)")
-	timeSpent := fs.String("time-spent", "", "Time spent (required, e.g. 1h30m)")
+	timeSpent := fs.String("time-spent", "", "Time spent (required, e.g. 1h 30m, 1.5h, 1d)")
 	comment := fs.String("comment", "", "Work description")
+	format := fs.String("format", "markdown", "Markup of the comment: markdown, wiki or adf_json")
 	started := fs.String("started", "", "Start time in ISO 8601 (default: now)")
+	adjustEstimate := fs.String("adjust-estimate", "auto", "Remaining estimate: auto, leave, new or manual")
+	newEstimate := fs.String("new-estimate", "", "Remaining estimate to set with --adjust-estimate new")
+	reduceBy := fs.String("reduce-by", "", "Amount to reduce the estimate by with --adjust-estimate manual")
 	fs.Parse(args)
 
 	loadEnv(*env)
@@ -1047,14 +1276,19 @@ func runAddWorklog(args []string) {
 		startedStr = time.Now().Format("2006-01-02T15:04:05.000-0700")
 	}
 
-	timeSpentSecs, err := parseTimeSpent(*timeSpent)
+	timeSpentSecs, err := util.ParseDuration(*timeSpent, services.JiraWorkingCalendar(ctx))
 	if err != nil {
 		fatal("invalid --time-spent: %v", err)
 	}
+	if timeSpentSecs < 60 {
+		fatal("invalid --time-spent: %q is less than a minute", *timeSpent)
+	}
 
 	options := &models.WorklogOptionsScheme{
 		Notify:         true,
-		AdjustEstimate: "auto",
+		AdjustEstimate: *adjustEstimate,
+		NewEstimate:    *newEstimate,
+		ReduceBy:       *reduceBy,
 	}
 
 	payload := &models.WorklogADFPayloadScheme{
@@ -1062,7 +1296,7 @@ func runAddWorklog(args []string) {
 		Started:          startedStr,
 	}
 	if *comment != "" {
-		payload.Comment = util.MarkdownToADF(*comment)
+		payload.Comment = bodyADF("--comment", *comment, *format)
 	}
 
 	worklog, response, err := client.Issue.Worklog.Add(ctx, *issueKey, payload, options)
@@ -1081,18 +1315,162 @@ func runAddWorklog(args []string) {
 		worklog.ID, *issueKey, *timeSpent, worklog.TimeSpentSeconds, worklog.Started, worklog.Author.DisplayName)
 }
 
-// parseTimeSpent converts "1h30m", "3h", "30m", or plain seconds to int seconds.
-func parseTimeSpent(s string) (int, error) {
-	// Plain integer = seconds
-	var secs int
-	if _, err := fmt.Sscanf(s, "%d", &secs); err == nil && fmt.Sprintf("%d", secs) == s {
-		return secs, nil
+// ── get-worklogs / update-worklog / delete-worklog ───────────────────────────
+
+func runGetWorklogs(args []string) {
+	fs := flag.NewFlagSet("get-worklogs", flag.ExitOnError)
+	env := fs.String("env", "", "Path to .env file")
+	output := fs.String("output", "text", "Output format: text or json")
+	issueKey := fs.String("issue-key", "", "Issue key (required)")
+	author := fs.String("author", "", "Only worklogs by this user")
+	startedAfter := fs.String("started-after", "", "Only worklogs started on or after this date or time")
+	startedBefore := fs.String("started-before", "", "Only worklogs started on or before this date, or before this time")
+	startAt := fs.Int("start-at", 0, "Index of the first worklog")
+	maxResults := fs.Int("max-results", 50, "Worklogs per page")
+	fs.Parse(args)
+
+	loadEnv(*env)
+	if *issueKey == "" {
+		fatal("--issue-key is required")
+	}
+
+	result, err := tools.GetWorklogs(context.Background(), tools.GetWorklogsInput{
+		IssueKey:      *issueKey,
+		Author:        *author,
+		StartedAfter:  *startedAfter,
+		StartedBefore: *startedBefore,
+		StartAt:       *startAt,
+		MaxResults:    *maxResults,
+	})
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if *output == "json" {
+		printJSON(result)
+		return
+	}
+	fmt.Println(tools.FormatWorklogs(result))
+}
+
+func runUpdateWorklog(args []string) {
+	fs := flag.NewFlagSet("update-worklog", flag.ExitOnError)
+	env := fs.String("env", "", "Path to .env file")
+	output := fs.String("output", "text", "Output format: text or json")
+	issueKey := fs.String("issue-key", "", "Issue key (required)")
+	worklogID := fs.String("worklog-id", "", "Worklog ID (required)")
+	timeSpent := fs.String("time-spent", "", "New time spent (e.g. 1h30m)")
+	started := fs.String("started", "", "New start time in ISO 8601")
+	comment := fs.String("comment", "", "New work description")
+	format := fs.String("format", "markdown", "Markup of the comment: markdown, wiki or adf_json")
+	adjustEstimate := fs.String("adjust-estimate", "auto", "Remaining estimate: auto, leave or new")
+	newEstimate := fs.String("new-estimate", "", "Remaining estimate to set with --adjust-estimate new")
+	fs.Parse(args)
+
+	loadEnv(*env)
+	if *issueKey == "" {
+		fatal("--issue-key is required")
+	}
+	if *worklogID == "" {
+		fatal("--worklog-id is required")
+	}
+
+	result, err := tools.UpdateWorklog(context.Background(), tools.UpdateWorklogInput{
+		IssueKey:       *issueKey,
+		WorklogID:      *worklogID,
+		TimeSpent:      *timeSpent,
+		Started:        *started,
+		Comment:        *comment,
+		Format:         *format,
+		AdjustEstimate: *adjustEstimate,
+		NewEstimate:    *newEstimate,
+	})
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if *output == "json" {
+		printJSON(result)
+		return
+	}
+	fmt.Println(tools.FormatWorklogChange(result))
+}
+
+func runDeleteWorklog(args []string) {
+	fs := flag.NewFlagSet("delete-worklog", flag.ExitOnError)
+	env := fs.String("env", "", "Path to .env file")
+	output := fs.String("output", "text", "Output format: text or json")
+	issueKey := fs.String("issue-key", "", "Issue key (required)")
+	worklogID := fs.String("worklog-id", "", "Worklog ID (required)")
+	adjustEstimate := fs.String("adjust-estimate", "auto", "Remaining estimate: auto, leave, new or manual")
+	newEstimate := fs.String("new-estimate", "", "Remaining estimate to set with --adjust-estimate new")
+	increaseBy := fs.String("increase-by", "", "Amount to increase the estimate by with --adjust-estimate manual")
+	fs.Parse(args)
+
+	loadEnv(*env)
+	if *issueKey == "" {
+		fatal("--issue-key is required")
+	}
+	if *worklogID == "" {
+		fatal("--worklog-id is required")
 	}
-	d, err := time.ParseDuration(s)
+
+	result, err := tools.DeleteWorklog(context.Background(), tools.DeleteWorklogInput{
+		IssueKey:       *issueKey,
+		WorklogID:      *worklogID,
+		AdjustEstimate: *adjustEstimate,
+		NewEstimate:    *newEstimate,
+		IncreaseBy:     *increaseBy,
+	})
 	if err != nil {
-		return 0, fmt.Errorf("could not parse time %q: %w", s, err)
+		fatal("%v", err)
+	}
+
+	if *output == "json" {
+		printJSON(result)
+		return
 	}
-	return int(d.Seconds()), nil
+	fmt.Println(tools.FormatWorklogChange(result))
+}
+
+// ── timesheet ─────────────────────────────────────────────────────────────────
+
+func runTimesheet(args []string) {
+	fs := flag.NewFlagSet("timesheet", flag.ExitOnError)
+	env := fs.String("env", "", "Path to .env file")
+	jql := fs.String("jql", "", "JQL selecting the issues (required)")
+	from := fs.String("from", "", "First day of the range (required)")
+	to := fs.String("to", "", "Last day of the range, included (default: today)")
+	author := fs.String("author", "", "Only worklogs by this user")
+	format := fs.String("format", "markdown_table", "Output format: markdown_table, csv or json")
+	fs.Parse(args)
+
+	loadEnv(*env)
+	if *jql == "" {
+		fatal("--jql is required")
+	}
+	if *from == "" {
+		fatal("--from is required")
+	}
+	sheetFormat, err := util.ParseTimesheetFormat(*format)
+	if err != nil {
+		fatal("invalid --format: %v", err)
+	}
+
+	sheet, err := tools.Timesheet(context.Background(), tools.TimesheetInput{
+		JQL:    *jql,
+		From:   *from,
+		To:     *to,
+		Author: *author,
+	})
+	if err != nil {
+		fatal("%v", err)
+	}
+	text, err := util.FormatTimesheet(sheet, sheetFormat)
+	if err != nil {
+		fatal("%v", err)
+	}
+	fmt.Print(strings.TrimRight(text, "\n") + "\n")
 }
 
 // ── get-transitions ───────────────────────────────────────────────────────────
@@ -1141,20 +1519,44 @@ func runTransitionIssue(args []string) {
 	env := fs.String("env", "", "Path to .env file")
 	output := fs.String("output", "text", "Output format: text or json")
 	issueKey := fs.String("issue-key", "", "Issue key (required)")
-	transitionID := fs.String("transition-id", "", "Transition ID (required)")
+	transitionID := fs.String("transition-id", "", "Transition ID")
+	toStatus := fs.String("to-status", "", "Target status name")
 	fs.Parse(args)
 
 	loadEnv(*env)
 	if *issueKey == "" {
 		fatal("--issue-key is required")
 	}
-	if *transitionID == "" {
-		fatal("--transition-id is required")
+	if (*transitionID == "") == (*toStatus == "") {
+		fatal("exactly one of --to-status or --transition-id is required")
 	}
 
 	ctx := context.Background()
 	client := services.JiraClient()
 
+	if *toStatus != "" {
+		transitions, response, err := client.Issue.Transitions(ctx, *issueKey)
+		if err != nil {
+			if response != nil {
+				fatal("failed to get transitions: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			fatal("failed to get transitions: %v", err)
+		}
+		var available []string
+		for _, t := range transitions.Transitions {
+			if t.To != nil && strings.EqualFold(t.To.Name, *toStatus) {
+				*transitionID = t.ID
+				break
+			}
+			if t.To != nil {
+				available = append(available, t.To.Name)
+			}
+		}
+		if *transitionID == "" {
+			fatal("no transition leads to status %q (available: %s)", *toStatus, strings.Join(available, ", "))
+		}
+	}
+
 	response, err := client.Issue.Move(ctx, *issueKey, *transitionID, nil)
 	if err != nil {
 		if response != nil {
@@ -1208,6 +1610,59 @@ func runListStatuses(args []string) {
 	}
 }
 
+// ── bulk ──────────────────────────────────────────────────────────────────────
+
+func runBulk(args []string) {
+	fs := flag.NewFlagSet("bulk", flag.ExitOnError)
+	env := fs.String("env", "", "Path to .env file")
+	output := fs.String("output", "text", "Output format: text or json")
+	jql := fs.String("jql", "", "JQL selecting the issues (required)")
+	toStatus := fs.String("to-status", "", "Move each issue to this status")
+	assignee := fs.String("assignee", "", "Assignee: email, display name, account ID, me or none")
+	addLabels := fs.String("add-labels", "", "Comma-separated labels to add")
+	removeLabels := fs.String("remove-labels", "", "Comma-separated labels to remove")
+	addFixVersions := fs.String("add-fix-versions", "", "Comma-separated fix versions to add")
+	removeFixVersions := fs.String("remove-fix-versions", "", "Comma-separated fix versions to remove")
+	comment := fs.String("comment", "", "Comment to add to every issue")
+	format := fs.String("format", "markdown", "Markup of the comment: markdown, wiki or adf_json")
+	maxIssues := fs.Int("max-issues", tools.BulkDefaultMaxIssues, "Maximum number of issues to change")
+	concurrency := fs.Int("concurrency", tools.BulkDefaultConcurrency, "Issues updated in parallel")
+	dryRun := fs.Bool("dry-run", false, "List matching issues without changing them")
+	fs.Parse(args)
+
+	loadEnv(*env)
+	if *jql == "" {
+		fatal("--jql is required")
+	}
+
+	report, err := tools.RunBulkUpdate(context.Background(), tools.BulkUpdateInput{
+		JQL:               *jql,
+		ToStatus:          *toStatus,
+		Assignee:          *assignee,
+		AddLabels:         *addLabels,
+		RemoveLabels:      *removeLabels,
+		AddFixVersions:    *addFixVersions,
+		RemoveFixVersions: *removeFixVersions,
+		Comment:           *comment,
+		Format:            *format,
+		MaxIssues:         *maxIssues,
+		Concurrency:       *concurrency,
+		DryRun:            *dryRun,
+	})
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if *output == "json" {
+		printJSON(report)
+	} else {
+		fmt.Print(tools.FormatBulkReport(report))
+	}
+	if report.Failed() > 0 {
+		os.Exit(1)
+	}
+}
+
 // ── get-issue-history ─────────────────────────────────────────────────────────
 
 func runGetIssueHistory(args []string) {
@@ -1305,6 +1760,7 @@ func runLinkIssues(args []string) {
 	outward := fs.String("outward-issue", "", "Outward issue key (required)")
 	linkType := fs.String("link-type", "", "Link type (required, e.g. Blocks, Duplicate, Relates)")
 	comment := fs.String("comment", "", "Optional comment")
+	format := fs.String("format", "markdown", "Markup of the comment: markdown, wiki or adf_json")
 	fs.Parse(args)
 
 	loadEnv(*env)
@@ -1328,7 +1784,7 @@ func runLinkIssues(args []string) {
 	}
 	if *comment != "" {
 		payload.Comment = &models.CommentPayloadScheme{
-			Body: util.MarkdownToADF(*comment),
+			Body: bodyADF("--comment", *comment, *format),
 		}
 	}
 
@@ -1547,6 +2003,47 @@ func runDownloadAttachment(args []string) {
 		filePath, metadata.Filename, metadata.Size, metadata.MimeType)
 }
 
+// ── upload-attachment ─────────────────────────────────────────────────────────
+
+func runUploadAttachment(args []string) {
+	fs := flag.NewFlagSet("upload-attachment", flag.ExitOnError)
+	env := fs.String("env", "", "Path to .env file")
+	output := fs.String("output", "text", "Output format: text or json")
+	issueKey := fs.String("issue-key", "", "Issue key (required)")
+	file := fs.String("file", "", "Path of the file to upload (required)")
+	filename := fs.String("filename", "", "Name of the attachment in Jira")
+	insertInto := fs.String("insert-into", "", "Also show it in the description or a new comment")
+	comment := fs.String("comment", "", "Text of the new comment")
+	format := fs.String("format", "markdown", "Markup of the comment: markdown, wiki or adf_json")
+	fs.Parse(args)
+
+	loadEnv(*env)
+	if *issueKey == "" {
+		fatal("--issue-key is required")
+	}
+	if *file == "" {
+		fatal("--file is required")
+	}
+
+	result, err := tools.UploadAttachment(context.Background(), tools.UploadAttachmentInput{
+		IssueKey:   *issueKey,
+		FilePath:   *file,
+		Filename:   *filename,
+		InsertInto: *insertInto,
+		Comment:    *comment,
+		Format:     *format,
+	})
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if *output == "json" {
+		printJSON(result)
+		return
+	}
+	fmt.Println(tools.FormatUploadAttachment(result))
+}
+
 // ── helpers ───────────────────────────────────────────────────────────────────
 
 func getBoardIDs(ctx context.Context, boardID, projectKey string) ([]int, error) {
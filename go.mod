@@ -5,6 +5,7 @@ go 1.23.2
 require (
 	github.com/ctreminiom/go-atlassian v1.6.1
 	github.com/joho/godotenv v1.5.1
+	github.com/ledongthuc/pdf v0.0.0-20240201131950-da5b75280b06
 	github.com/mark3labs/mcp-go v0.41.1
 	github.com/pkg/errors v0.9.1
 )
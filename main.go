@@ -6,8 +6,11 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/joho/godotenv"
 	"github.com/mark3labs/mcp-go/server"
@@ -71,6 +74,7 @@ func main() {
 
 	fmt.Println("✅ All required environment variables are set")
 	fmt.Printf("🔗 Connected to: %s\n", os.Getenv("ATLASSIAN_HOST"))
+	fmt.Printf("🏢 Deployment: %s\n", services.Deployment())
 
 	mcpServer := server.NewMCPServer(
 		"Jira MCP",
@@ -81,10 +85,71 @@ func main() {
 		server.WithRecovery(),
 	)
 
-	// Build the tool filter from ENABLED_TOOLS. When the env var is unset or
-	// empty, all tools are registered (backwards compatible). Otherwise only
-	// the comma-separated allowlist is exposed — useful for read-only agents.
+	// Build the tool filter from ENABLED_TOOLS, DISABLED_TOOLS and
+	// JIRA_MCP_MODE. When none is set, all tools are registered (backwards
+	// compatible). JIRA_MCP_MODE=readonly is the simplest way to hand Jira to
+	// an agent that must not change anything.
 	filter := tools.NewFilterFromEnv()
+	if invalid := filter.InvalidMode(); invalid != "" {
+		fmt.Printf("⚠️  Unknown JIRA_MCP_MODE %q — falling back to %s\n", invalid, tools.ModeReadOnly)
+	}
+	if invalid := filter.InvalidOutput(); invalid != "" {
+		fmt.Printf("⚠️  Unknown JIRA_MCP_OUTPUT %q — falling back to %s\n", invalid, tools.OutputText)
+	} else if output := filter.Output(); output != tools.OutputText {
+		fmt.Printf("🧾 JIRA_MCP_OUTPUT=%s — tools return structured content\n", output)
+	}
+
+	// Project restrictions from JIRA_MCP_POLICY_FILE or JIRA_MCP_PROJECTS.
+	// A policy that cannot be loaded is fatal: starting without it would
+	// silently widen access.
+	policy, err := tools.LoadProjectPolicyFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Project policy error: %v", err)
+	}
+	if policy != nil {
+		filter.UseProjectPolicy(policy)
+		fmt.Printf("🛡️  Project policy active — %s\n", policy.Describe())
+	}
+
+	// Audit trail of write tool calls (JIRA_MCP_AUDIT_LOG).
+	audit, err := tools.OpenAuditLogFromEnv(*httpPort == "")
+	if err != nil {
+		log.Fatalf("❌ Audit log error: %v", err)
+	}
+	if audit != nil {
+		defer audit.Close()
+		filter.UseAuditLog(audit)
+		fmt.Printf("📝 Auditing write tools to: %s\n", audit.Targets())
+	}
+
+	// Prior values of changed fields, for jira_undo_last_change
+	// (JIRA_MCP_JOURNAL).
+	if journal := tools.UndoJournal(); journal != nil {
+		fmt.Printf("🕘 Undo journal: %s\n", journal.Location())
+	}
+
+	// Largest file jira_upload_attachment accepts (JIRA_MCP_UPLOAD_LIMIT).
+	if _, err := tools.UploadLimit(); err != nil {
+		log.Fatalf("❌ Upload limit error: %v", err)
+	}
+
+	// Where jira_upload_attachment may read file_path from
+	// (JIRA_MCP_UPLOAD_DIR); off over HTTP unless set.
+	if dir, err := tools.ConfigureUploadDir(*httpPort != ""); err != nil {
+		log.Fatalf("❌ Upload directory error: %v", err)
+	} else if dir == "" {
+		fmt.Println("📎 Upload file_path: off (set JIRA_MCP_UPLOAD_DIR to allow it)")
+	} else if dir != tools.UploadAnywhere {
+		fmt.Printf("📎 Upload file_path restricted to: %s\n", dir)
+	}
+
+	// How long jira_download_attachment keeps its temporary files
+	// (JIRA_MCP_ATTACHMENT_TTL); expired ones from earlier runs go now.
+	if ttl, err := tools.AttachmentTTL(); err != nil {
+		log.Fatalf("❌ Attachment TTL error: %v", err)
+	} else {
+		tools.SweepAttachmentDownloads(ttl)
+	}
 
 	// Register all Jira tools
 	tools.RegisterJiraIssueTool(mcpServer, filter)
@@ -92,27 +157,55 @@ func main() {
 	tools.RegisterJiraSprintTool(mcpServer, filter)
 	tools.RegisterJiraStatusTool(mcpServer, filter)
 	tools.RegisterJiraTransitionTool(mcpServer, filter)
+	tools.RegisterJiraBulkTool(mcpServer, filter)
 	tools.RegisterJiraWorklogTool(mcpServer, filter)
+	tools.RegisterJiraTimesheetTool(mcpServer, filter)
 	tools.RegisterJiraCommentTools(mcpServer, filter)
 	tools.RegisterJiraHistoryTool(mcpServer, filter)
 	tools.RegisterJiraRelationshipTool(mcpServer, filter)
 	tools.RegisterJiraVersionTool(mcpServer, filter)
 	tools.RegisterJiraDevelopmentTool(mcpServer, filter)
 	tools.RegisterJiraAttachmentTool(mcpServer, filter)
+	tools.RegisterJiraUndoTools(mcpServer, filter)
 
 	if filter.IsRestricted() {
 		enabled := filter.EnabledNames()
-		fmt.Printf("🔒 ENABLED_TOOLS active — exposing %d tool(s): %s\n",
-			len(enabled), strings.Join(enabled, ", "))
+		fmt.Printf("🔒 Tool filter active (mode: %s) — exposing %d tool(s): %s\n",
+			filter.Mode(), len(enabled), strings.Join(enabled, ", "))
 		if unknown := filter.UnknownNames(); len(unknown) > 0 {
 			fmt.Printf("⚠️  Unknown tool name(s) in ENABLED_TOOLS (ignored): %s\n",
 				strings.Join(unknown, ", "))
 		}
+		if unknown := filter.UnknownDisabledNames(); len(unknown) > 0 {
+			fmt.Printf("⚠️  Unknown tool name(s) in DISABLED_TOOLS (nothing hidden): %s\n",
+				strings.Join(unknown, ", "))
+		}
+	}
+	if filter.ConfirmsWrites() {
+		fmt.Println("✋ JIRA_MCP_CONFIRM_WRITES active — write tools need confirm=true")
 	}
 
 	// Register all Jira prompts
 	prompts.RegisterJiraPrompts(mcpServer)
 
+	// jira:// resources, and change notifications for subscribed issues
+	// (polled every JIRA_MCP_RESOURCE_POLL).
+	tools.RegisterJiraResources(mcpServer, filter)
+	subscriptions, err := tools.NewResourceSubscriptionsFromEnv(mcpServer, filter)
+	if err != nil {
+		log.Fatalf("❌ Resource subscription error: %v", err)
+	}
+
+	// Jira webhook deliveries (JIRA_MCP_WEBHOOK_SECRET), HTTP mode only.
+	webhooks := tools.NewWebhookReceiverFromEnv(mcpServer, filter, subscriptions)
+	if webhooks != nil && *httpPort == "" {
+		fmt.Println("⚠️  JIRA_MCP_WEBHOOK_SECRET is set but webhooks are only received in HTTP mode (--http_port); only issue resources can be subscribed to")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	go subscriptions.Run(ctx)
+
 	if *httpPort != "" {
 		fmt.Println()
 		fmt.Println("🚀 Starting Jira MCP Server in HTTP mode...")
@@ -138,12 +231,24 @@ func main() {
 		fmt.Println()
 		fmt.Println("🔄 Server starting...")
 		
-		httpServer := server.NewStreamableHTTPServer(mcpServer, server.WithEndpointPath("/mcp"))
+		// The streamable handler is mounted behind the subscription handler,
+		// which answers resources/subscribe itself for the sessions its ID
+		// manager issued.
+		srv := &http.Server{}
+		httpServer := server.NewStreamableHTTPServer(mcpServer, server.WithEndpointPath("/mcp"), server.WithStreamableHTTPServer(srv), server.WithSessionIdManager(subscriptions.SessionIDManager()))
+		mux := http.NewServeMux()
+		mux.Handle("/mcp", subscriptions.HTTPHandler(httpServer))
+		if webhooks != nil {
+			webhooks.Mount(mux, "/webhook")
+			fmt.Printf("🪝 Receiving Jira webhooks at: http://localhost:%s/webhook\n", *httpPort)
+		}
+		srv.Handler = mux
 		if err := httpServer.Start(fmt.Sprintf(":%s", *httpPort)); err != nil && !isContextCanceled(err) {
 			log.Fatalf("❌ Server error: %v", err)
 		}
 	} else {
-		if err := server.ServeStdio(mcpServer); err != nil && !isContextCanceled(err) {
+		in, out := subscriptions.Stdio(os.Stdin, os.Stdout)
+		if err := server.NewStdioServer(mcpServer).Listen(ctx, in, out); err != nil && !isContextCanceled(err) {
 			log.Fatalf("❌ Server error: %v", err)
 		}
 	}
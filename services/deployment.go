@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeploymentType identifies which flavour of Jira the server talks to. Cloud
+// speaks REST v3 with ADF bodies; Server and Data Center only have REST v2,
+// whose rich-text fields are wiki markup strings.
+type DeploymentType string
+
+const (
+	DeploymentCloud  DeploymentType = "cloud"
+	DeploymentServer DeploymentType = "server"
+)
+
+// IsServer reports whether the deployment needs the REST v2 compatibility
+// layer (Server and Data Center behave identically for our purposes).
+func (d DeploymentType) IsServer() bool {
+	return d == DeploymentServer
+}
+
+// String returns a human-readable label for startup logs.
+func (d DeploymentType) String() string {
+	if d.IsServer() {
+		return "Server/Data Center (REST v2)"
+	}
+	return "Cloud (REST v3)"
+}
+
+// ParseDeploymentType maps a JIRA_DEPLOYMENT value (or the deploymentType
+// reported by /rest/api/2/serverInfo) onto a DeploymentType. The boolean is
+// false for empty, "auto", or unrecognised values.
+func ParseDeploymentType(raw string) (DeploymentType, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "cloud":
+		return DeploymentCloud, true
+	case "server", "datacenter", "data_center", "data-center", "dc":
+		return DeploymentServer, true
+	default:
+		return "", false
+	}
+}
+
+// serverInfoTimeout bounds the auto-detection probe so a slow or unreachable
+// host does not stall startup indefinitely.
+const serverInfoTimeout = 10 * time.Second
+
+// DetectDeployment asks /rest/api/2/serverInfo which deployment type the host
+// runs. That endpoint exists on every Jira flavour, unlike anything under
+// /rest/api/3.
+func DetectDeployment(ctx context.Context, client *RawClient) (DeploymentType, error) {
+	var info struct {
+		DeploymentType string `json:"deploymentType"`
+	}
+	if _, err := client.Do(ctx, http.MethodGet, "rest/api/2/serverInfo", nil, nil, &info); err != nil {
+		return "", fmt.Errorf("serverInfo request failed: %w", err)
+	}
+
+	deployment, ok := ParseDeploymentType(info.DeploymentType)
+	if !ok {
+		return "", fmt.Errorf("unrecognised deploymentType %q", info.DeploymentType)
+	}
+	return deployment, nil
+}
+
+// guessDeployment is the fallback when serverInfo cannot be reached: Atlassian
+// hosts are always Cloud, and a PAT without email/token only works on
+// Server/DC. Anything else defaults to Cloud, the historical behaviour.
+func guessDeployment(host, pat string) DeploymentType {
+	if strings.Contains(strings.ToLower(host), ".atlassian.net") {
+		return DeploymentCloud
+	}
+	if pat != "" {
+		return DeploymentServer
+	}
+	return DeploymentCloud
+}
+
+// Deployment resolves the deployment type once per process. JIRA_DEPLOYMENT
+// (cloud | server | datacenter) wins; otherwise ("auto" or unset) the host is
+// probed via serverInfo.
+var Deployment = sync.OnceValue(func() DeploymentType {
+	if deployment, ok := ParseDeploymentType(os.Getenv("JIRA_DEPLOYMENT")); ok {
+		return deployment
+	}
+
+	host, mail, token, pat := loadAtlassianCredentials()
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverInfoTimeout)
+	defer cancel()
+
+	// Probe with the plain HTTP client: JiraHTTPClient depends on the answer.
+	probe := &RawClient{Host: host, Mail: mail, Token: token, PAT: pat, HTTP: DefaultHttpClient()}
+	deployment, err := DetectDeployment(ctx, probe)
+	if err != nil {
+		deployment = guessDeployment(host, pat)
+		log.Printf("could not detect Jira deployment type (%v); assuming %s — set JIRA_DEPLOYMENT to override", err, deployment)
+	}
+	return deployment
+})
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseDeploymentType(t *testing.T) {
+	tests := []struct {
+		raw    string
+		want   DeploymentType
+		wantOK bool
+	}{
+		{"cloud", DeploymentCloud, true},
+		{"Cloud", DeploymentCloud, true},
+		{"server", DeploymentServer, true},
+		{"DataCenter", DeploymentServer, true},
+		{" dc ", DeploymentServer, true},
+		{"auto", "", false},
+		{"", "", false},
+		{"mainframe", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseDeploymentType(tt.raw)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseDeploymentType(%q) = (%q, %v), want (%q, %v)", tt.raw, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDetectDeployment(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment string
+		want       DeploymentType
+	}{
+		{"cloud", "Cloud", DeploymentCloud},
+		{"server", "Server", DeploymentServer},
+		{"data center", "DataCenter", DeploymentServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/rest/api/2/serverInfo" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer pat" {
+					t.Errorf("Authorization = %q, want Bearer pat", got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"deploymentType":"` + tt.deployment + `","version":"9.12.0"}`))
+			}))
+			defer srv.Close()
+
+			got, err := DetectDeployment(context.Background(), &RawClient{Host: srv.URL + "/", PAT: "pat", HTTP: srv.Client()})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectDeployment = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDeployment_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	if _, err := DetectDeployment(context.Background(), &RawClient{Host: srv.URL, Mail: "a@b.c", Token: "tkn", HTTP: srv.Client()}); err == nil {
+		t.Error("expected an error for a non-200 serverInfo response")
+	}
+}
+
+func TestGuessDeployment(t *testing.T) {
+	if got := guessDeployment("https://acme.atlassian.net", "pat"); got != DeploymentCloud {
+		t.Errorf("atlassian.net host should be Cloud, got %q", got)
+	}
+	if got := guessDeployment("https://jira.acme.com", "pat"); got != DeploymentServer {
+		t.Errorf("PAT on a custom host should be Server, got %q", got)
+	}
+	if got := guessDeployment("https://jira.acme.com", ""); got != DeploymentCloud {
+		t.Errorf("email/token on a custom host should default to Cloud, got %q", got)
+	}
+}
@@ -2,16 +2,30 @@ package services
 
 import (
 	"log"
+	"net/http"
 	"sync"
 
 	jira "github.com/ctreminiom/go-atlassian/jira/v3"
 	"github.com/pkg/errors"
 )
 
+// JiraHTTPClient is the HTTP client behind JiraClient and JiraRawClient. It
+// is built on DefaultHttpClient, so PROXY_URL applies. On Server/Data Center
+// it routes traffic through ServerTransport so the v3-shaped requests the
+// tools build are served by REST v2. Requests are reported to a CallRecorder
+// when the context carries one.
+var JiraHTTPClient = sync.OnceValue(func() *http.Client {
+	base := DefaultHttpClient().Transport
+	if Deployment().IsServer() {
+		base = &ServerTransport{Base: base}
+	}
+	return &http.Client{Transport: &RecordingTransport{Base: base}}
+})
+
 var JiraClient = sync.OnceValue[*jira.Client](func() *jira.Client {
 	host, mail, token, pat := loadAtlassianCredentials()
 
-	instance, err := jira.New(nil, host)
+	instance, err := jira.New(JiraHTTPClient(), host)
 	if err != nil {
 		log.Fatal(errors.WithMessage(err, "failed to create jira client"))
 	}
@@ -23,4 +37,4 @@ var JiraClient = sync.OnceValue[*jira.Client](func() *jira.Client {
 	}
 
 	return instance
-})
\ No newline at end of file
+})
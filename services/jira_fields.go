@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// FieldLoader returns every field defined on the Jira instance.
+type FieldLoader func(ctx context.Context) ([]*models.IssueFieldScheme, error)
+
+// FieldCatalog caches the result of /rest/api/3/field so tools can refer to
+// fields by their display name ("Story Points") instead of their ID
+// ("customfield_10016"). The list is loaded on first use and reloaded once
+// when a lookup misses, so fields created after startup are still found.
+type FieldCatalog struct {
+	load FieldLoader
+
+	mu     sync.Mutex
+	byID   map[string]*models.IssueFieldScheme
+	byName map[string][]*models.IssueFieldScheme
+}
+
+// NewFieldCatalog returns an empty catalog backed by load.
+func NewFieldCatalog(load FieldLoader) *FieldCatalog {
+	return &FieldCatalog{load: load}
+}
+
+// JiraFieldCatalog is the process-wide catalog backed by JiraClient.
+var JiraFieldCatalog = sync.OnceValue(func() *FieldCatalog {
+	return NewFieldCatalog(func(ctx context.Context) ([]*models.IssueFieldScheme, error) {
+		fields, response, err := JiraClient().Issue.Field.Gets(ctx)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to list fields: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return nil, fmt.Errorf("failed to list fields: %v", err)
+		}
+		return fields, nil
+	})
+})
+
+// Resolve finds a field by ID, key or case-insensitive display name. Display
+// names are not unique in Jira; an ambiguous name is an error listing the
+// candidate IDs so the caller can pick one.
+func (c *FieldCatalog) Resolve(ctx context.Context, nameOrID string) (*models.IssueFieldScheme, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.byID == nil {
+		if err := c.refreshLocked(ctx); err != nil {
+			return nil, err
+		}
+	} else if c.lookupLocked(nameOrID) == nil {
+		if err := c.refreshLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := c.lookupLocked(nameOrID)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown field %q", nameOrID)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, field := range matches {
+			ids = append(ids, field.ID)
+		}
+		return nil, fmt.Errorf("field name %q is ambiguous, use one of: %s", nameOrID, strings.Join(ids, ", "))
+	}
+}
+
+// Names returns a map from field ID to display name.
+func (c *FieldCatalog) Names(ctx context.Context) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.byID == nil {
+		if err := c.refreshLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make(map[string]string, len(c.byID))
+	for id, field := range c.byID {
+		names[id] = field.Name
+	}
+	return names, nil
+}
+
+func (c *FieldCatalog) lookupLocked(nameOrID string) []*models.IssueFieldScheme {
+	if field, ok := c.byID[nameOrID]; ok {
+		return []*models.IssueFieldScheme{field}
+	}
+	return c.byName[strings.ToLower(strings.TrimSpace(nameOrID))]
+}
+
+func (c *FieldCatalog) refreshLocked(ctx context.Context) error {
+	fields, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.byID = make(map[string]*models.IssueFieldScheme, len(fields))
+	c.byName = make(map[string][]*models.IssueFieldScheme, len(fields))
+	for _, field := range fields {
+		if field == nil || field.ID == "" {
+			continue
+		}
+		c.byID[field.ID] = field
+		if field.Key != "" && field.Key != field.ID {
+			c.byID[field.Key] = field
+		}
+		name := strings.ToLower(field.Name)
+		c.byName[name] = append(c.byName[name], field)
+	}
+	return nil
+}
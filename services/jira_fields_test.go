@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func staticFields(calls *int, fields ...*models.IssueFieldScheme) FieldLoader {
+	return func(ctx context.Context) ([]*models.IssueFieldScheme, error) {
+		*calls++
+		return fields, nil
+	}
+}
+
+func TestFieldCatalog_Resolve(t *testing.T) {
+	var calls int
+	catalog := NewFieldCatalog(staticFields(&calls,
+		&models.IssueFieldScheme{ID: "summary", Key: "summary", Name: "Summary"},
+		&models.IssueFieldScheme{ID: "customfield_10016", Key: "customfield_10016", Name: "Story Points", Custom: true},
+	))
+	ctx := context.Background()
+
+	for _, query := range []string{"Story Points", "story points", "customfield_10016"} {
+		field, err := catalog.Resolve(ctx, query)
+		if err != nil {
+			t.Fatalf("Resolve(%q): %v", query, err)
+		}
+		if field.ID != "customfield_10016" {
+			t.Errorf("Resolve(%q) = %s", query, field.ID)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("field list loaded %d times, want 1", calls)
+	}
+}
+
+func TestFieldCatalog_ReloadsOnMiss(t *testing.T) {
+	var calls int
+	fields := []*models.IssueFieldScheme{{ID: "summary", Name: "Summary"}}
+	catalog := NewFieldCatalog(func(ctx context.Context) ([]*models.IssueFieldScheme, error) {
+		calls++
+		return fields, nil
+	})
+	ctx := context.Background()
+
+	if _, err := catalog.Resolve(ctx, "Summary"); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	fields = append(fields, &models.IssueFieldScheme{ID: "customfield_1", Name: "Team"})
+	if _, err := catalog.Resolve(ctx, "Team"); err != nil {
+		t.Fatalf("newly created field should be found after a reload: %v", err)
+	}
+	if _, err := catalog.Resolve(ctx, "Nope"); err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("field list loaded %d times, want 3", calls)
+	}
+}
+
+func TestFieldCatalog_AmbiguousName(t *testing.T) {
+	var calls int
+	catalog := NewFieldCatalog(staticFields(&calls,
+		&models.IssueFieldScheme{ID: "customfield_1", Name: "Team"},
+		&models.IssueFieldScheme{ID: "customfield_2", Name: "Team"},
+	))
+
+	_, err := catalog.Resolve(context.Background(), "Team")
+	if err == nil || !strings.Contains(err.Error(), "customfield_1, customfield_2") {
+		t.Errorf("expected ambiguity error listing both IDs, got %v", err)
+	}
+}
+
+func TestFieldCatalog_LoadError(t *testing.T) {
+	catalog := NewFieldCatalog(func(ctx context.Context) ([]*models.IssueFieldScheme, error) {
+		return nil, errors.New("boom")
+	})
+	if _, err := catalog.Names(context.Background()); err == nil {
+		t.Error("expected load error to propagate")
+	}
+}
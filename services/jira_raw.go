@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// RawClient issues authenticated requests to Jira REST endpoints that the
+// go-atlassian SDK does not wrap (search/jql, dev-status, serverInfo, ...).
+// Every hand-rolled call goes through Do so authentication is applied the
+// same way everywhere: PAT as Bearer, otherwise email/token as Basic.
+type RawClient struct {
+	Host  string
+	Mail  string
+	Token string
+	PAT   string
+	HTTP  *http.Client
+}
+
+// JiraRawClient is the process-wide RawClient, built from the same
+// credentials and JiraHTTPClient as JiraClient, so PROXY_URL, ServerTransport
+// and call recording apply to both.
+var JiraRawClient = sync.OnceValue(func() *RawClient {
+	host, mail, token, pat := loadAtlassianCredentials()
+	return &RawClient{Host: host, Mail: mail, Token: token, PAT: pat, HTTP: JiraHTTPClient()}
+})
+
+// rateLimitRetries is how many times Do retries a request Jira rejected with
+// 429 Too Many Requests; maxRetryDelay caps the wait between attempts.
+const (
+	rateLimitRetries = 3
+	maxRetryDelay    = 30 * time.Second
+)
+
+// Do sends method to path (relative to Host, e.g. "rest/api/3/search/jql")
+// with the given query and JSON body, and decodes a JSON response into out
+// when out is non-nil. The returned ResponseScheme mirrors the SDK's, so
+// callers can report failures with response.Bytes and response.Endpoint.
+// Non-2xx responses yield both the response and an error. Rate-limited
+// requests are retried after the delay Jira asks for in Retry-After.
+func (c *RawClient) Do(ctx context.Context, method, path string, query url.Values, body any, out any) (*models.ResponseScheme, error) {
+	endpoint := strings.TrimSuffix(c.Host, "/") + "/" + strings.TrimPrefix(path, "/")
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var payload []byte
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		payload = raw
+	}
+
+	for attempt := 0; ; attempt++ {
+		response, err := c.send(ctx, method, endpoint, payload)
+		if err != nil {
+			return response, err
+		}
+
+		if response.Code == http.StatusTooManyRequests && attempt < rateLimitRetries {
+			select {
+			case <-ctx.Done():
+				return response, ctx.Err()
+			case <-time.After(retryDelay(response.Response, attempt)):
+			}
+			continue
+		}
+
+		if response.Code < 200 || response.Code >= 300 {
+			return response, fmt.Errorf("API request failed with status %d", response.Code)
+		}
+
+		if out != nil && response.Bytes.Len() > 0 {
+			if err := json.Unmarshal(response.Bytes.Bytes(), out); err != nil {
+				return response, fmt.Errorf("failed to decode response: %w", err)
+			}
+		}
+		return response, nil
+	}
+}
+
+// send performs a single request and reads the whole response body.
+func (c *RawClient) send(ctx context.Context, method, endpoint string, payload []byte) (*models.ResponseScheme, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	ApplyAtlassianAuth(req, c.Mail, c.Token, c.PAT)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = DefaultHttpClient()
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response := &models.ResponseScheme{
+		Response: resp,
+		Code:     resp.StatusCode,
+		Endpoint: req.URL.String(),
+		Method:   method,
+	}
+	if _, err := response.Bytes.ReadFrom(resp.Body); err != nil {
+		return response, fmt.Errorf("failed to read response: %w", err)
+	}
+	return response, nil
+}
+
+// retryDelay honours a Retry-After header given in seconds and otherwise
+// backs off exponentially from one second.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	delay := time.Second << attempt
+	if seconds, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
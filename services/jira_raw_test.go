@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRawClient_Do_AppliesAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		client RawClient
+		want   string
+	}{
+		{"pat", RawClient{PAT: "pat"}, "Bearer pat"},
+		{"basic", RawClient{Mail: "a@b.c", Token: "tkn"}, "Basic " + base64.StdEncoding.EncodeToString([]byte("a@b.c:tkn"))},
+		{"pat wins over basic", RawClient{Mail: "a@b.c", Token: "tkn", PAT: "pat"}, "Bearer pat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != tt.want {
+					t.Errorf("Authorization = %q, want %q", got, tt.want)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			client := tt.client
+			client.Host = srv.URL
+			client.HTTP = srv.Client()
+			if _, err := client.Do(context.Background(), http.MethodGet, "rest/api/3/myself", nil, nil, nil); err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+		})
+	}
+}
+
+func TestRawClient_Do_SendsQueryAndBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/3/search/approximate-count" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("x") != "1" {
+			t.Errorf("query = %v, want x=1", r.URL.Query())
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["jql"] != "project = X" {
+			t.Errorf("body = %v (%v)", body, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":7}`))
+	}))
+	defer srv.Close()
+
+	client := &RawClient{Host: srv.URL + "/", PAT: "pat", HTTP: srv.Client()}
+	var out struct {
+		Count int `json:"count"`
+	}
+	if _, err := client.Do(context.Background(), http.MethodPost, "/rest/api/3/search/approximate-count", url.Values{"x": {"1"}}, map[string]string{"jql": "project = X"}, &out); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if out.Count != 7 {
+		t.Errorf("count = %d, want 7", out.Count)
+	}
+}
+
+func TestRawClient_Do_ErrorStatusKeepsResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"errorMessages":["nope"]}`))
+	}))
+	defer srv.Close()
+
+	client := &RawClient{Host: srv.URL, PAT: "bad", HTTP: srv.Client()}
+	response, err := client.Do(context.Background(), http.MethodGet, "rest/api/3/myself", nil, nil, nil)
+	if err == nil {
+		t.Fatal("expected an error for a 401 response")
+	}
+	if response == nil || response.Code != http.StatusUnauthorized {
+		t.Fatalf("response = %+v, want code 401", response)
+	}
+	if response.Bytes.String() != `{"errorMessages":["nope"]}` {
+		t.Errorf("body = %q", response.Bytes.String())
+	}
+	if response.Endpoint != srv.URL+"/rest/api/3/myself" {
+		t.Errorf("endpoint = %q", response.Endpoint)
+	}
+}
+
+func TestRawClient_Do_RetriesRateLimitedRequests(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["jql"] != "project = X" {
+			t.Errorf("attempt %d: body = %v (%v)", attempts, body, err)
+		}
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"count":1}`))
+	}))
+	defer srv.Close()
+
+	client := &RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+	response, err := client.Do(context.Background(), http.MethodPost, "rest/api/3/search/approximate-count", nil, map[string]string{"jql": "project = X"}, nil)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if attempts != 3 || response.Code != http.StatusOK {
+		t.Errorf("attempts = %d, code = %d; want 3 attempts ending in 200", attempts, response.Code)
+	}
+}
+
+func TestRawClient_Do_GivesUpAfterRetries(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+	response, err := client.Do(context.Background(), http.MethodGet, "rest/api/3/myself", nil, nil, nil)
+	if err == nil || response == nil || response.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 error, got response %v, err %v", response, err)
+	}
+	if attempts != rateLimitRetries+1 {
+		t.Errorf("attempts = %d, want %d", attempts, rateLimitRetries+1)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// RecordedCall is one Jira request made while a CallRecorder was attached to
+// the request context.
+type RecordedCall struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Status int    `json:"status"`
+	// CreatedKey is the key Jira returned for an issue created by this call.
+	CreatedKey string `json:"createdKey,omitempty"`
+}
+
+// CallRecorder collects the Jira requests made on behalf of one tool call,
+// so the audit log can report what actually reached Jira.
+type CallRecorder struct {
+	mu    sync.Mutex
+	calls []RecordedCall
+}
+
+type callRecorderKey struct{}
+
+// WithCallRecorder returns a context whose Jira requests are recorded.
+func WithCallRecorder(ctx context.Context) (context.Context, *CallRecorder) {
+	recorder := &CallRecorder{}
+	return context.WithValue(ctx, callRecorderKey{}, recorder), recorder
+}
+
+// Calls returns the recorded requests in the order they completed.
+func (r *CallRecorder) Calls() []RecordedCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedCall(nil), r.calls...)
+}
+
+func (r *CallRecorder) add(call RecordedCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+// RecordingTransport reports every request whose context carries a
+// CallRecorder. Requests without one pass through untouched.
+type RecordingTransport struct {
+	Base http.RoundTripper
+}
+
+// maxCreatedBody bounds how much of an issue-create response is read to find
+// the new key.
+const maxCreatedBody = 64 << 10
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+
+	recorder, ok := req.Context().Value(callRecorderKey{}).(*CallRecorder)
+	if !ok {
+		return resp, err
+	}
+	call := RecordedCall{Method: req.Method, Path: req.URL.Path}
+	if err == nil {
+		call.Status = resp.StatusCode
+		if req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/issue") && resp.StatusCode < 300 {
+			call.CreatedKey = peekIssueKey(resp)
+		}
+	}
+	recorder.add(call)
+	return resp, err
+}
+
+// peekIssueKey reads the "key" of a created issue from the response body and
+// puts the body back for the caller.
+func peekIssueKey(resp *http.Response) string {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCreatedBody))
+	rest := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), rest), rest}
+	if err != nil {
+		return ""
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if json.Unmarshal(body, &created) != nil {
+		return ""
+	}
+	return created.Key
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordingTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"10001","key":"ABC-7"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := &RawClient{Host: srv.URL, PAT: "pat", HTTP: &http.Client{Transport: &RecordingTransport{}}}
+	ctx, recorder := WithCallRecorder(context.Background())
+
+	var created struct{ Key string }
+	if _, err := client.Do(ctx, http.MethodPost, "rest/api/3/issue", nil, map[string]any{}, &created); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if created.Key != "ABC-7" {
+		t.Errorf("the caller must still see the response body, got key %q", created.Key)
+	}
+	_, _ = client.Do(ctx, http.MethodGet, "rest/api/3/issue/ABC-8", nil, nil, nil)
+
+	calls := recorder.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("calls = %+v, want 2", calls)
+	}
+	if calls[0] != (RecordedCall{Method: "POST", Path: "/rest/api/3/issue", Status: 201, CreatedKey: "ABC-7"}) {
+		t.Errorf("calls[0] = %+v", calls[0])
+	}
+	if calls[1] != (RecordedCall{Method: "GET", Path: "/rest/api/3/issue/ABC-8", Status: 404}) {
+		t.Errorf("calls[1] = %+v", calls[1])
+	}
+}
+
+func TestRecordingTransport_WithoutRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"ABC-1"}`))
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/rest/api/3/issue", nil)
+	resp, err := (&RecordingTransport{}).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != `{"key":"ABC-1"}` {
+		t.Errorf("body = %q", body)
+	}
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// ServerTransport adapts the REST v3 traffic produced by the go-atlassian v3
+// client (and our hand-rolled v3 calls) to Jira Server / Data Center, which
+// only implements REST v2. Every tool keeps speaking v3 and ADF; this layer:
+//
+//   - rewrites /rest/api/3/... paths to /rest/api/2/...
+//   - translates /search/jql (nextPageToken) to /search (startAt) and back
+//   - serves /search/approximate-count from a maxResults=0 search
+//   - sends user searches as "username" instead of "query"
+//   - replaces ADF documents in request bodies with wiki markup
+//   - turns wiki-markup descriptions, comment bodies and worklog comments in
+//     responses back into ADF, so RenderADF shows them as markdown
+//
+// Agile (/rest/agile/1.0) and dev-status requests pass through untouched.
+type ServerTransport struct {
+	Base http.RoundTripper
+}
+
+const (
+	restV3Prefix = "/rest/api/3/"
+	restV2Prefix = "/rest/api/2/"
+)
+
+// RoundTrip implements http.RoundTripper.
+func (t *ServerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	idx := strings.Index(req.URL.Path, restV3Prefix)
+	if idx < 0 {
+		return base.RoundTrip(req)
+	}
+	resource := req.URL.Path[idx+len(restV3Prefix):]
+
+	out := req.Clone(req.Context())
+	out.URL.Path = req.URL.Path[:idx] + restV2Prefix + resource
+	out.URL.RawPath = ""
+
+	var rewriteResponse func(any) any
+	switch resource {
+	case "search/jql":
+		out.URL.Path = req.URL.Path[:idx] + restV2Prefix + "search"
+		out.URL.RawQuery = searchJQLQueryToV2(req.URL.Query()).Encode()
+		rewriteResponse = searchV2ResponseToJQL
+
+	case "user/search":
+		// Server/DC searches users by "username" (which also matches
+		// display names and emails) rather than Cloud's "query".
+		q := req.URL.Query()
+		if query := q.Get("query"); query != "" && q.Get("username") == "" {
+			q.Set("username", query)
+			q.Del("query")
+		}
+		out.URL.RawQuery = q.Encode()
+
+	case "search/approximate-count":
+		jql, err := readJQLBody(req)
+		if err != nil {
+			return nil, err
+		}
+		out.Method = http.MethodGet
+		out.URL.Path = req.URL.Path[:idx] + restV2Prefix + "search"
+		out.URL.RawQuery = url.Values{"jql": {jql}, "maxResults": {"0"}}.Encode()
+		setRequestBody(out, nil)
+		rewriteResponse = searchV2ResponseToCount
+
+	default:
+		if err := convertRequestBody(out); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := base.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeJSON(body)
+	if err != nil {
+		// Not something we understand — hand it back verbatim.
+		setResponseBody(resp, body)
+		return resp, nil
+	}
+
+	decoded = wikiBodiesToADF(decoded)
+	if rewriteResponse != nil {
+		decoded = rewriteResponse(decoded)
+	}
+
+	rewritten, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+	setResponseBody(resp, rewritten)
+	return resp, nil
+}
+
+// searchJQLQueryToV2 maps /search/jql query parameters onto /search. The v2
+// endpoint is offset-based, so the opaque page token we hand out is simply
+// the next startAt.
+func searchJQLQueryToV2(q url.Values) url.Values {
+	v2 := url.Values{}
+	for _, key := range []string{"jql", "fields", "expand", "maxResults"} {
+		if v := q.Get(key); v != "" {
+			v2.Set(key, v)
+		}
+	}
+	if token := q.Get("nextPageToken"); token != "" {
+		v2.Set("startAt", token)
+	}
+	return v2
+}
+
+// searchV2ResponseToJQL converts a v2 {startAt, total, issues} page into the
+// {issues, nextPageToken, isLast} shape of /search/jql.
+func searchV2ResponseToJQL(v any) any {
+	page, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	startAt := jsonInt(page["startAt"])
+	total := jsonInt(page["total"])
+	issues, _ := page["issues"].([]any)
+
+	next := startAt + len(issues)
+	isLast := len(issues) == 0 || next >= total
+	page["isLast"] = isLast
+	if !isLast {
+		page["nextPageToken"] = strconv.Itoa(next)
+	}
+	return page
+}
+
+// searchV2ResponseToCount converts a maxResults=0 search into the
+// {count} shape of /search/approximate-count.
+func searchV2ResponseToCount(v any) any {
+	page, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	return map[string]any{"count": jsonInt(page["total"])}
+}
+
+// adfBodiesToWiki walks a decoded JSON request body and replaces every ADF
+// document (an object with "type": "doc") with its wiki-markup rendering.
+func adfBodiesToWiki(v any) any {
+	switch node := v.(type) {
+	case map[string]any:
+		if node["type"] == "doc" {
+			if _, hasContent := node["content"]; hasContent || node["version"] != nil {
+				return adfMapToWiki(node)
+			}
+		}
+		for key, child := range node {
+			node[key] = adfBodiesToWiki(child)
+		}
+		return node
+	case []any:
+		for i, child := range node {
+			node[i] = adfBodiesToWiki(child)
+		}
+		return node
+	default:
+		return v
+	}
+}
+
+// wikiBodiesToADF walks a decoded v2 JSON response and converts the
+// rich-text strings that the v3 models expect as ADF: issue descriptions
+// (under "fields"), comment bodies, and worklog comments. Plain string
+// fields that share those names (status or version descriptions) are left
+// alone because they are not nested where the v3 schema uses ADF.
+func wikiBodiesToADF(v any) any {
+	switch node := v.(type) {
+	case map[string]any:
+		if fields, ok := node["fields"].(map[string]any); ok {
+			if desc, ok := fields["description"].(string); ok {
+				fields["description"] = wikiToADFMap(desc)
+			}
+		}
+		if body, ok := node["body"].(string); ok && node["author"] != nil {
+			node["body"] = wikiToADFMap(body)
+		}
+		if comment, ok := node["comment"].(string); ok && (node["timeSpentSeconds"] != nil || node["timeSpent"] != nil) {
+			node["comment"] = wikiToADFMap(comment)
+		}
+		for key, child := range node {
+			node[key] = wikiBodiesToADF(child)
+		}
+		return node
+	case []any:
+		for i, child := range node {
+			node[i] = wikiBodiesToADF(child)
+		}
+		return node
+	default:
+		return v
+	}
+}
+
+func adfMapToWiki(node map[string]any) string {
+	raw, err := json.Marshal(node)
+	if err != nil {
+		return ""
+	}
+	var doc models.CommentNodeScheme
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	return util.ADFToWiki(&doc)
+}
+
+func wikiToADFMap(wiki string) any {
+	raw, err := json.Marshal(util.WikiToADF(wiki))
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// convertRequestBody rewrites a JSON request body in place, swapping ADF
+// documents for wiki markup. Multipart uploads and empty bodies are skipped.
+func convertRequestBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if !strings.Contains(req.Header.Get("Content-Type"), "json") {
+		return nil
+	}
+	raw, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	decoded, err := decodeJSON(raw)
+	if err != nil {
+		setRequestBody(req, raw)
+		return nil
+	}
+	converted, err := json.Marshal(adfBodiesToWiki(decoded))
+	if err != nil {
+		return err
+	}
+	setRequestBody(req, converted)
+	return nil
+}
+
+func readJQLBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	defer req.Body.Close()
+	var payload struct {
+		JQL string `json:"jql"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil && err != io.EOF {
+		return "", err
+	}
+	return payload.JQL, nil
+}
+
+func decodeJSON(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	// Keep numbers as json.Number so large IDs survive the round trip.
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func jsonInt(v any) int {
+	switch n := v.(type) {
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+func setRequestBody(req *http.Request, body []byte) {
+	if body == nil {
+		req.Body = http.NoBody
+		req.GetBody = nil
+		req.ContentLength = 0
+		req.Header.Del("Content-Type")
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.ContentLength = int64(len(body))
+}
+
+func setResponseBody(resp *http.Response, body []byte) {
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// newServerTransportClient returns a v3 SDK client that talks to srv through
+// ServerTransport, the same wiring JiraClient uses on Server/DC.
+func newServerTransportClient(t *testing.T, srv *httptest.Server) *jira.Client {
+	t.Helper()
+	httpClient := &http.Client{Transport: &ServerTransport{Base: srv.Client().Transport}}
+	client, err := jira.New(httpClient, srv.URL+"/")
+	if err != nil {
+		t.Fatalf("jira.New: %v", err)
+	}
+	client.Auth.SetBearerToken("pat")
+	return client
+}
+
+func TestServerTransport_GetIssueRendersWikiDescription(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/DC-1" {
+			t.Errorf("path = %s, want /rest/api/2/issue/DC-1", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"key": "DC-1",
+			"fields": {
+				"summary": "On-prem issue",
+				"description": "h2. Steps\n\nRun *this* with {{flag}}",
+				"status": {"name": "Open", "description": "plain status text"},
+				"comment": {"total": 1, "comments": [
+					{"id": "10", "author": {"name": "jdoe"}, "body": "Looks _good_"}
+				]}
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := newServerTransportClient(t, srv)
+	issue, _, err := client.Issue.Get(context.Background(), "DC-1", nil, nil)
+	if err != nil {
+		t.Fatalf("Issue.Get: %v", err)
+	}
+
+	if got := util.RenderADF(issue.Fields.Description); got != "## Steps\n\nRun **this** with `flag`" {
+		t.Errorf("description = %q", got)
+	}
+	if issue.Fields.Status.Description != "plain status text" {
+		t.Errorf("status description must stay a plain string, got %q", issue.Fields.Status.Description)
+	}
+	if got := util.RenderADF(issue.Fields.Comment.Comments[0].Body); got != "Looks *good*" {
+		t.Errorf("comment body = %q", got)
+	}
+}
+
+func TestServerTransport_CreateIssueSendsWikiMarkup(t *testing.T) {
+	var sent map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue" {
+			t.Errorf("path = %s, want /rest/api/2/issue", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"10001","key":"DC-2"}`))
+	}))
+	defer srv.Close()
+
+	client := newServerTransportClient(t, srv)
+	payload := &models.IssueScheme{Fields: &models.IssueFieldsScheme{
+		Summary:     "New",
+		Project:     &models.ProjectScheme{Key: "DC"},
+		IssueType:   &models.IssueTypeScheme{Name: "Task"},
+		Description: util.MarkdownToADF("# Title\n\nSome **bold** text"),
+	}}
+	issue, _, err := client.Issue.Create(context.Background(), payload, nil)
+	if err != nil {
+		t.Fatalf("Issue.Create: %v", err)
+	}
+	if issue.Key != "DC-2" {
+		t.Errorf("key = %q, want DC-2", issue.Key)
+	}
+
+	fields := sent["fields"].(map[string]any)
+	if got := fields["description"]; got != "h1. Title\n\nSome *bold* text" {
+		t.Errorf("description sent as %#v, want wiki markup string", got)
+	}
+}
+
+func TestServerTransport_SearchJQLMapsToV2Search(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/search" {
+			t.Errorf("path = %s, want /rest/api/2/search", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("startAt") != "2" || q.Get("maxResults") != "2" || q.Get("jql") != "project = DC" {
+			t.Errorf("unexpected query %v", q)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"startAt":2,"maxResults":2,"total":5,"issues":[{"key":"DC-3"},{"key":"DC-4"}]}`))
+	}))
+	defer srv.Close()
+
+	httpClient := &http.Client{Transport: &ServerTransport{Base: srv.Client().Transport}}
+	resp, err := httpClient.Get(srv.URL + "/rest/api/3/search/jql?jql=project+%3D+DC&nextPageToken=2&maxResults=2")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var page util.SearchJQLResult
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Issues) != 2 || page.IsLast || page.NextPageToken != "4" {
+		t.Errorf("page = %+v, want 2 issues, isLast=false, nextPageToken=4", page)
+	}
+}
+
+func TestServerTransport_ApproximateCount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/api/2/search" || r.URL.Query().Get("maxResults") != "0" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"startAt":0,"maxResults":0,"total":42,"issues":[]}`))
+	}))
+	defer srv.Close()
+
+	httpClient := &http.Client{Transport: &ServerTransport{Base: srv.Client().Transport}}
+	resp, err := httpClient.Post(srv.URL+"/rest/api/3/search/approximate-count", "application/json", strings.NewReader(`{"jql":"project = DC"}`))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if strings.TrimSpace(string(body)) != `{"count":42}` {
+		t.Errorf("body = %s, want {\"count\":42}", body)
+	}
+}
+
+func TestServerTransport_PassesThroughNonV3(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/board" {
+			t.Errorf("agile path must not be rewritten, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"values":[]}`))
+	}))
+	defer srv.Close()
+
+	httpClient := &http.Client{Transport: &ServerTransport{Base: srv.Client().Transport}}
+	resp, err := httpClient.Get(srv.URL + "/rest/agile/1.0/board")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestServerTransport_UserSearchUsesUsername(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/rest/api/2/user/search" || q.Get("username") != "alice" || q.Has("query") {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"alice","displayName":"Alice"}]`))
+	}))
+	defer srv.Close()
+
+	client := newServerTransportClient(t, srv)
+	users, _, err := client.User.Search.Do(context.Background(), "", "alice", 0, 10)
+	if err != nil {
+		t.Fatalf("User.Search.Do: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "alice" {
+		t.Errorf("users = %+v", users)
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// CalendarLoader returns the working calendar configured on the Jira
+// instance.
+type CalendarLoader func(ctx context.Context) (util.WorkingCalendar, error)
+
+// calendarRetryDelay is how long CalendarCache serves the default calendar
+// after a failed load before it asks Jira again.
+const calendarRetryDelay = 5 * time.Minute
+
+// CalendarCache holds the instance's working calendar, so "1w" and "2d" mean
+// what they mean in Jira. It is loaded on first use; after a failed load,
+// Get falls back to util.DefaultWorkingCalendar for calendarRetryDelay and
+// then tries again.
+type CalendarCache struct {
+	load CalendarLoader
+	now  func() time.Time
+
+	mu       sync.Mutex
+	calendar *util.WorkingCalendar
+	retryAt  time.Time
+}
+
+// NewCalendarCache returns an empty cache backed by load.
+func NewCalendarCache(load CalendarLoader) *CalendarCache {
+	return &CalendarCache{load: load, now: time.Now}
+}
+
+// JiraCalendarCache is the process-wide cache backed by JiraRawClient.
+var JiraCalendarCache = sync.OnceValue(func() *CalendarCache {
+	return NewCalendarCache(func(ctx context.Context) (util.WorkingCalendar, error) {
+		return LoadWorkingCalendar(ctx, JiraRawClient())
+	})
+})
+
+// JiraWorkingCalendar returns the working calendar of the configured Jira
+// instance.
+func JiraWorkingCalendar(ctx context.Context) util.WorkingCalendar {
+	return JiraCalendarCache().Get(ctx)
+}
+
+// Get returns the cached calendar, loading it if needed. The load runs
+// without the lock held, so a slow Jira does not block other callers; they
+// may load it too, and the last answer wins.
+func (c *CalendarCache) Get(ctx context.Context) util.WorkingCalendar {
+	c.mu.Lock()
+	cached, retryAt := c.calendar, c.retryAt
+	c.mu.Unlock()
+	if cached != nil {
+		return *cached
+	}
+	if c.now().Before(retryAt) {
+		return util.DefaultWorkingCalendar
+	}
+
+	calendar, err := c.load(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.retryAt = c.now().Add(calendarRetryDelay)
+		return util.DefaultWorkingCalendar
+	}
+	c.calendar = &calendar
+	return calendar
+}
+
+// timeTrackingOptions is the body of /configuration/timetracking/options and
+// the timeTrackingConfiguration part of /configuration.
+type timeTrackingOptions struct {
+	WorkingHoursPerDay float64 `json:"workingHoursPerDay"`
+	WorkingDaysPerWeek float64 `json:"workingDaysPerWeek"`
+	DefaultUnit        string  `json:"defaultUnit"`
+}
+
+// LoadWorkingCalendar reads hours per day, days per week and the default
+// unit from /rest/api/3/configuration/timetracking/options. That endpoint
+// is for Jira administrators only, so for everyone else the same settings
+// are read from the global /rest/api/3/configuration.
+func LoadWorkingCalendar(ctx context.Context, client *RawClient) (util.WorkingCalendar, error) {
+	var options timeTrackingOptions
+	if _, err := client.Do(ctx, http.MethodGet, "rest/api/3/configuration/timetracking/options", nil, nil, &options); err != nil {
+		var configuration struct {
+			TimeTrackingConfiguration *timeTrackingOptions `json:"timeTrackingConfiguration"`
+		}
+		response, err := client.Do(ctx, http.MethodGet, "rest/api/3/configuration", nil, nil, &configuration)
+		if err != nil {
+			if response != nil {
+				return util.WorkingCalendar{}, fmt.Errorf("failed to get time tracking settings: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return util.WorkingCalendar{}, fmt.Errorf("failed to get time tracking settings: %v", err)
+		}
+		if configuration.TimeTrackingConfiguration == nil {
+			return util.WorkingCalendar{}, fmt.Errorf("failed to get time tracking settings: time tracking is disabled")
+		}
+		options = *configuration.TimeTrackingConfiguration
+	}
+	if options.WorkingHoursPerDay <= 0 || options.WorkingDaysPerWeek <= 0 {
+		return util.WorkingCalendar{}, fmt.Errorf("failed to get time tracking settings: %v hours per day, %v days per week", options.WorkingHoursPerDay, options.WorkingDaysPerWeek)
+	}
+	return util.WorkingCalendar{
+		HoursPerDay: options.WorkingHoursPerDay,
+		DaysPerWeek: options.WorkingDaysPerWeek,
+		DefaultUnit: options.DefaultUnit,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+func TestLoadWorkingCalendar(t *testing.T) {
+	admin := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/api/3/configuration/timetracking/options":
+			if !admin {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"errorMessages":["Only Jira administrators can access time tracking settings."]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"workingHoursPerDay":7.5,"workingDaysPerWeek":4,"timeFormat":"pretty","defaultUnit":"hour"}`))
+		case "/rest/api/3/configuration":
+			_, _ = w.Write([]byte(`{"timeTrackingEnabled":true,"timeTrackingConfiguration":{"workingHoursPerDay":6,"workingDaysPerWeek":5,"timeFormat":"pretty","defaultUnit":"minute"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := &RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+
+	calendar, err := LoadWorkingCalendar(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (util.WorkingCalendar{HoursPerDay: 7.5, DaysPerWeek: 4, DefaultUnit: "hour"}); calendar != want {
+		t.Errorf("calendar = %+v, want %+v", calendar, want)
+	}
+
+	admin = false
+	calendar, err = LoadWorkingCalendar(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calendar.HoursPerDay != 6 || calendar.DaysPerWeek != 5 {
+		t.Errorf("calendar from /configuration = %+v", calendar)
+	}
+}
+
+func TestCalendarCache_RetriesAfterDelay(t *testing.T) {
+	var calls int
+	cache := NewCalendarCache(func(ctx context.Context) (util.WorkingCalendar, error) {
+		calls++
+		if calls == 1 {
+			return util.WorkingCalendar{}, errors.New("offline")
+		}
+		return util.WorkingCalendar{HoursPerDay: 7, DaysPerWeek: 5}, nil
+	})
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		if got := cache.Get(ctx); got != util.DefaultWorkingCalendar {
+			t.Errorf("a failed load should give the default calendar, got %+v", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("calendar loaded %d times right after a failure, want 1", calls)
+	}
+
+	now = now.Add(calendarRetryDelay)
+	for range 2 {
+		if got := cache.Get(ctx); got.HoursPerDay != 7 {
+			t.Errorf("calendar = %+v", got)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("calendar loaded %d times, want 2", calls)
+	}
+}
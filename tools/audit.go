@@ -0,0 +1,273 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// AuditEntry is one line of the audit log: a single call of a write tool.
+type AuditEntry struct {
+	Time      time.Time               `json:"time"`
+	SessionID string                  `json:"sessionId,omitempty"`
+	Tool      string                  `json:"tool"`
+	Arguments map[string]any          `json:"arguments"`
+	IssueKeys []string                `json:"issueKeys,omitempty"`
+	Status    int                     `json:"status,omitempty"`
+	Requests  []services.RecordedCall `json:"requests,omitempty"`
+	Outcome   string                  `json:"outcome"`
+	Error     string                  `json:"error,omitempty"`
+	LatencyMS int64                   `json:"latencyMs"`
+}
+
+// AuditLog appends an AuditEntry for every call of a write or destructive
+// tool. It is attached with Filter.UseAuditLog and wraps handlers in
+// Filter.AddTool, so newly added tools are audited without extra wiring.
+type AuditLog struct {
+	mu      sync.Mutex
+	sinks   []io.Writer
+	closers []io.Closer
+	targets []string
+	now     func() time.Time
+}
+
+// NewAuditLog writes entries as JSON lines to every sink.
+func NewAuditLog(sinks ...io.Writer) *AuditLog {
+	return &AuditLog{sinks: sinks, now: time.Now}
+}
+
+// OpenAuditLogFromEnv builds the audit log from JIRA_MCP_AUDIT_LOG, a
+// comma-separated list of sinks: a file path (appended to), "stdout",
+// "stderr" or "syslog". stdout carries the MCP protocol in stdio mode, so it
+// is refused when stdio is true. It returns nil when auditing is off.
+func OpenAuditLogFromEnv(stdio bool) (*AuditLog, error) {
+	raw := strings.TrimSpace(os.Getenv("JIRA_MCP_AUDIT_LOG"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	audit := NewAuditLog()
+	for _, target := range splitPatterns(raw) {
+		audit.targets = append(audit.targets, target)
+		switch strings.ToLower(target) {
+		case "stdout":
+			if stdio {
+				audit.Close()
+				return nil, fmt.Errorf("audit sink stdout cannot be used in stdio mode, where stdout carries the MCP protocol; use stderr or a file")
+			}
+			audit.sinks = append(audit.sinks, os.Stdout)
+		case "stderr":
+			audit.sinks = append(audit.sinks, os.Stderr)
+		case "syslog":
+			sink, err := openSyslogSink()
+			if err != nil {
+				audit.Close()
+				return nil, fmt.Errorf("failed to open syslog audit sink: %w", err)
+			}
+			audit.sinks = append(audit.sinks, sink)
+			audit.closers = append(audit.closers, sink)
+		default:
+			file, err := os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+			if err != nil {
+				audit.Close()
+				return nil, fmt.Errorf("failed to open audit log: %w", err)
+			}
+			audit.sinks = append(audit.sinks, file)
+			audit.closers = append(audit.closers, file)
+		}
+	}
+	return audit, nil
+}
+
+// Close closes the file and syslog sinks.
+func (a *AuditLog) Close() error {
+	var firstErr error
+	for _, closer := range a.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Targets describes where entries go, for the startup log.
+func (a *AuditLog) Targets() string {
+	return strings.Join(a.targets, ", ")
+}
+
+// write appends entry to every sink. Sink failures are reported on stderr
+// rather than failing the tool call, which has already reached Jira.
+func (a *AuditLog) write(entry AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "audit: failed to encode entry for %s: %v\n", entry.Tool, err)
+		return
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, sink := range a.sinks {
+		if _, err := sink.Write(line); err != nil {
+			fmt.Fprintf(os.Stderr, "audit: failed to write entry for %s: %v\n", entry.Tool, err)
+		}
+	}
+}
+
+// wrap records every call of handler, including calls refused before they
+// reach Jira (by the project policy or the confirmation gate).
+func (a *AuditLog) wrap(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := a.now()
+		ctx, recorder := services.WithCallRecorder(ctx)
+
+		result, err := handler(ctx, request)
+
+		args := request.GetArguments()
+		entry := AuditEntry{
+			Time:      start.UTC(),
+			Tool:      toolName,
+			Arguments: redactArguments(args),
+			Requests:  recorder.Calls(),
+			Outcome:   "ok",
+			LatencyMS: a.now().Sub(start).Milliseconds(),
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			entry.SessionID = session.SessionID()
+		}
+		if n := len(entry.Requests); n > 0 {
+			entry.Status = entry.Requests[n-1].Status
+		}
+		entry.IssueKeys = auditIssueKeys(args, entry.Requests)
+		switch {
+		case err != nil:
+			entry.Outcome, entry.Error = "error", err.Error()
+		case result != nil && result.IsError:
+			entry.Outcome, entry.Error = "error", resultText(result)
+		}
+
+		a.write(entry)
+		return result, err
+	}
+}
+
+// secretArgument matches argument names whose values must not be logged.
+var secretArgument = regexp.MustCompile(`(?i)(token|password|passwd|secret|authorization|api_?key|credential)`)
+
+// blobArgument matches argument names holding encoded file content, such as
+// content_base64 and resource.blob, which are logged as a size and hash.
+var blobArgument = regexp.MustCompile(`(?i)(base64|blob)`)
+
+// auditTextLimit is the longest string logged as is; longer ones, such as
+// pasted descriptions and comments, are logged as a size and hash.
+const auditTextLimit = 2048
+
+// redactArguments copies args, replacing secret-looking values at any depth
+// and summarising blobs and long text.
+func redactArguments(args map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for name, value := range args {
+		if secretArgument.MatchString(name) {
+			out[name] = "[REDACTED]"
+			continue
+		}
+		if text, ok := value.(string); ok && blobArgument.MatchString(name) && text != "" {
+			out[name] = omittedPlaceholder(text, "base64")
+			continue
+		}
+		out[name] = redactValue(value)
+	}
+	return out
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactArguments(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item)
+		}
+		return out
+	case string:
+		if len(v) > auditTextLimit {
+			return omittedPlaceholder(v, "text")
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// omittedPlaceholder stands in for a value left out of the log, such as
+// "[4.2MB base64 omitted, sha256=…]", so it can still be matched against a
+// known file.
+func omittedPlaceholder(value, kind string) string {
+	sum := sha256.Sum256([]byte(value))
+	var size string
+	switch n := float64(len(value)); {
+	case n >= 1<<20:
+		size = fmt.Sprintf("%.1fMB", n/(1<<20))
+	case n >= 1<<10:
+		size = fmt.Sprintf("%.1fKB", n/(1<<10))
+	default:
+		size = fmt.Sprintf("%d bytes of", len(value))
+	}
+	return fmt.Sprintf("[%s %s omitted, sha256=%s]", size, kind, hex.EncodeToString(sum[:]))
+}
+
+var issuePathPattern = regexp.MustCompile(`/issue/([A-Za-z][A-Za-z0-9_]*-\d+)`)
+
+// auditIssueKeys lists the issues a call touched: keys named in its
+// arguments, keys in the paths of the Jira requests it made, and keys of
+// issues it created.
+func auditIssueKeys(args map[string]any, calls []services.RecordedCall) []string {
+	seen := map[string]bool{}
+	for _, name := range issueKeyArgs {
+		if value, _ := args[name].(string); issueKeyPattern.MatchString(strings.TrimSpace(value)) {
+			seen[strings.ToUpper(strings.TrimSpace(value))] = true
+		}
+	}
+	for _, call := range calls {
+		if match := issuePathPattern.FindStringSubmatch(call.Path); match != nil {
+			seen[strings.ToUpper(match[1])] = true
+		}
+		if call.CreatedKey != "" {
+			seen[call.CreatedKey] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resultText joins the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
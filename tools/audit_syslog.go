@@ -0,0 +1,14 @@
+//go:build !windows && !plan9
+
+package tools
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// openSyslogSink connects to the local syslog daemon; each audit entry is
+// sent as one message.
+func openSyslogSink() (io.WriteCloser, error) {
+	return syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, "jira-mcp")
+}
@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package tools
+
+import (
+	"errors"
+	"io"
+)
+
+func openSyslogSink() (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not available on this platform")
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func decodeAuditLines(t *testing.T, buf *bytes.Buffer) []AuditEntry {
+	t.Helper()
+	var entries []AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid audit line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditLog_RecordsJiraCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	client := &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: &http.Client{Transport: &services.RecordingTransport{}}}
+
+	var buf bytes.Buffer
+	audit := NewAuditLog(&buf)
+	handler := audit.wrap("jira_transition_issue", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := client.Do(ctx, http.MethodPost, "rest/api/3/issue/ABC-1/transitions", nil, map[string]any{}, nil); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"issue_key": "ABC-1",
+		"to_status": "Done",
+		"fields":    map[string]any{"api_token": "s3cret", "Resolution": "Fixed"},
+	}
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	entries := decodeAuditLines(t, &buf)
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Tool != "jira_transition_issue" || entry.Outcome != "ok" || entry.Status != http.StatusNoContent {
+		t.Errorf("entry = %+v", entry)
+	}
+	if len(entry.IssueKeys) != 1 || entry.IssueKeys[0] != "ABC-1" {
+		t.Errorf("issueKeys = %v", entry.IssueKeys)
+	}
+	if len(entry.Requests) != 1 || entry.Requests[0].Path != "/rest/api/3/issue/ABC-1/transitions" {
+		t.Errorf("requests = %+v", entry.Requests)
+	}
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("secret leaked into the audit log: %s", buf.String())
+	}
+	if fields := entry.Arguments["fields"].(map[string]any); fields["api_token"] != "[REDACTED]" || fields["Resolution"] != "Fixed" {
+		t.Errorf("fields = %v", fields)
+	}
+}
+
+func TestRedactArguments_OmitsBlobsAndLongText(t *testing.T) {
+	blob := strings.Repeat("QUJD", 1<<20)
+	long := strings.Repeat("word ", 1000)
+	got := redactArguments(map[string]any{
+		"issue_key":      "ABC-1",
+		"content_base64": blob,
+		"resource":       map[string]any{"uri": "file:///tmp/a.png", "blob": blob},
+		"description":    long,
+		"comment":        "short",
+	})
+
+	if got["issue_key"] != "ABC-1" || got["comment"] != "short" {
+		t.Errorf("short values should be kept, got %v", got)
+	}
+	want := "[4.0MB base64 omitted, sha256="
+	if text, _ := got["content_base64"].(string); !strings.HasPrefix(text, want) || len(text) != len(want)+64+1 {
+		t.Errorf("content_base64 = %q", text)
+	}
+	if resource := got["resource"].(map[string]any); resource["blob"] != got["content_base64"] || resource["uri"] != "file:///tmp/a.png" {
+		t.Errorf("resource = %v", resource)
+	}
+	if text, _ := got["description"].(string); !strings.HasPrefix(text, "[4.9KB text omitted, sha256=") {
+		t.Errorf("description = %q", text)
+	}
+}
+
+func TestAuditLog_RecordsRefusedCalls(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "")
+	t.Setenv("JIRA_MCP_CONFIRM_WRITES", "true")
+
+	var buf bytes.Buffer
+	filter := NewFilterFromEnv()
+	filter.UseAuditLog(NewAuditLog(&buf))
+	s := server.NewMCPServer("test", "0.0.0")
+	RegisterJiraIssueTool(s, filter)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "jira_delete_issue"
+	request.Params.Arguments = map[string]any{"issue_key": "ABC-9"}
+	if _, err := s.GetTool("jira_delete_issue").Handler(context.Background(), request); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	entries := decodeAuditLines(t, &buf)
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	if entries[0].Outcome != "error" || !strings.Contains(entries[0].Error, "confirm=true") || len(entries[0].Requests) != 0 {
+		t.Errorf("entry = %+v", entries[0])
+	}
+}
+
+func TestOpenAuditLogFromEnv(t *testing.T) {
+	t.Setenv("JIRA_MCP_AUDIT_LOG", "")
+	if audit, err := OpenAuditLogFromEnv(true); audit != nil || err != nil {
+		t.Fatalf("auditing should be off by default, got %v, %v", audit, err)
+	}
+
+	t.Setenv("JIRA_MCP_AUDIT_LOG", "stdout")
+	if _, err := OpenAuditLogFromEnv(true); err == nil {
+		t.Errorf("stdout must be refused in stdio mode")
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte("{\"tool\":\"earlier\"}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JIRA_MCP_AUDIT_LOG", path)
+	audit, err := OpenAuditLogFromEnv(true)
+	if err != nil {
+		t.Fatalf("OpenAuditLogFromEnv: %v", err)
+	}
+	audit.write(AuditEntry{Tool: "jira_add_comment", Outcome: "ok"})
+	if err := audit.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "jira_add_comment") {
+		t.Errorf("the file should be appended to, got:\n%s", data)
+	}
+}
@@ -1,7 +1,10 @@
 package tools
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path"
 	"sort"
 	"strings"
 
@@ -9,88 +12,349 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// ToolCategory says what a tool does to Jira. Every tool declares its
+// category when it is registered through Filter.AddTool; the filter uses it
+// to apply JIRA_MCP_MODE and to set the MCP tool annotations.
+type ToolCategory int
+
+const (
+	// ToolRead tools only read Jira.
+	ToolRead ToolCategory = iota
+	// ToolWrite tools create or change Jira data.
+	ToolWrite
+	// ToolDestructive tools delete Jira data irreversibly.
+	ToolDestructive
+)
+
+func (c ToolCategory) String() string {
+	switch c {
+	case ToolRead:
+		return "read"
+	case ToolWrite:
+		return "write"
+	case ToolDestructive:
+		return "destructive"
+	default:
+		return fmt.Sprintf("ToolCategory(%d)", int(c))
+	}
+}
+
+// FilterMode is the coarse access level set with JIRA_MCP_MODE.
+type FilterMode string
+
+const (
+	// ModeFull exposes every tool (the default).
+	ModeFull FilterMode = "full"
+	// ModeReadOnly exposes only read tools.
+	ModeReadOnly FilterMode = "readonly"
+	// ModeNoDelete exposes read and write tools but nothing destructive.
+	ModeNoDelete FilterMode = "no-delete"
+)
+
+// permits reports whether the mode allows tools of the given category.
+func (m FilterMode) permits(category ToolCategory) bool {
+	switch m {
+	case ModeReadOnly:
+		return category == ToolRead
+	case ModeNoDelete:
+		return category != ToolDestructive
+	default:
+		return true
+	}
+}
+
 // Filter decides which Jira MCP tools should be exposed to the client.
 //
-// It is driven by the ENABLED_TOOLS environment variable, a comma-separated
-// allowlist of tool names (e.g. "jira_get_issue,jira_search_issue"). When the
-// variable is empty or unset, every registered tool is exposed — this keeps
-// existing deployments working without configuration changes.
+// It is driven by environment variables:
+//
+//   - ENABLED_TOOLS: a comma-separated allowlist of tool names or glob
+//     patterns (e.g. "jira_get_*,jira_search_issue"). Empty or unset allows
+//     every tool, which keeps existing deployments working unchanged.
+//   - DISABLED_TOOLS: names or patterns to hide, even if ENABLED_TOOLS
+//     matches them.
+//   - JIRA_MCP_MODE: "readonly" exposes only read tools, "no-delete" hides
+//     destructive ones, "full" (the default) applies no restriction.
+//   - JIRA_MCP_CONFIRM_WRITES: when true, write and destructive tools refuse
+//     to run until called again with confirm=true, giving the agent a point
+//     to check with the user.
+//   - JIRA_MCP_OUTPUT: "text" (the default), "json" or "both"; see
+//     OutputMode.
 //
 // A typical use case is exposing only read-only tools to an AI agent, so the
 // agent cannot create, update, or delete Jira data.
 type Filter struct {
-	// allow is the set of tool names permitted. A nil map means "allow all".
-	allow map[string]bool
+	// allow holds the ENABLED_TOOLS patterns. A nil slice means "allow all".
+	allow []string
+
+	// deny holds the DISABLED_TOOLS patterns.
+	deny []string
+
+	mode FilterMode
+
+	// invalidMode keeps an unrecognised JIRA_MCP_MODE value for the startup
+	// warning. Such values fall back to ModeReadOnly.
+	invalidMode string
+
+	confirmWrites bool
+
+	output OutputMode
+
+	// invalidOutput keeps an unrecognised JIRA_MCP_OUTPUT value for the
+	// startup warning. Such values fall back to OutputText.
+	invalidOutput string
+
+	// policy, when set, limits the projects each tool may touch.
+	policy *ProjectPolicy
 
-	// registered records which entries in `allow` actually matched a real
-	// tool during startup. It is used to surface typos via UnknownNames.
+	// audit, when set, records every call of a write or destructive tool.
+	audit *AuditLog
+
+	// offered records every tool name passed to AddTool, and registered the
+	// ones that were actually exposed. They are used to surface typos via
+	// UnknownNames and to report what is enabled.
+	offered    map[string]bool
 	registered map[string]bool
 }
 
-// NewFilterFromEnv builds a Filter from the ENABLED_TOOLS environment variable.
-// Whitespace around names is trimmed and empty entries are ignored.
+// NewFilterFromEnv builds a Filter from ENABLED_TOOLS, DISABLED_TOOLS,
+// JIRA_MCP_MODE, JIRA_MCP_CONFIRM_WRITES and JIRA_MCP_OUTPUT. Whitespace around names is
+// trimmed and empty entries are ignored.
 func NewFilterFromEnv() *Filter {
-	raw := strings.TrimSpace(os.Getenv("ENABLED_TOOLS"))
-	if raw == "" {
-		return &Filter{allow: nil, registered: map[string]bool{}}
+	f := &Filter{
+		mode:       ModeFull,
+		offered:    map[string]bool{},
+		registered: map[string]bool{},
+	}
+
+	if raw := strings.TrimSpace(os.Getenv("ENABLED_TOOLS")); raw != "" {
+		// All names disabled (e.g. ENABLED_TOOLS=",  ,"): falling back to
+		// allow-all would be surprising. Treat as "no tools enabled" instead.
+		f.allow = splitPatterns(raw)
+	}
+	f.deny = splitPatterns(os.Getenv("DISABLED_TOOLS"))
+
+	switch mode := FilterMode(strings.ToLower(strings.TrimSpace(os.Getenv("JIRA_MCP_MODE")))); mode {
+	case "", ModeFull:
+	case ModeReadOnly, ModeNoDelete:
+		f.mode = mode
+	default:
+		// Fail safe: a mistyped restriction must not expose write tools.
+		f.mode = ModeReadOnly
+		f.invalidMode = string(mode)
 	}
 
-	allow := make(map[string]bool)
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("JIRA_MCP_CONFIRM_WRITES"))) {
+	case "1", "true", "yes", "on":
+		f.confirmWrites = true
+	}
+
+	switch output := OutputMode(strings.ToLower(strings.TrimSpace(os.Getenv("JIRA_MCP_OUTPUT")))); output {
+	case "", OutputText:
+		f.output = OutputText
+	case OutputJSON, OutputBoth:
+		f.output = output
+	default:
+		f.output = OutputText
+		f.invalidOutput = string(output)
+	}
+
+	return f
+}
+
+// splitPatterns parses a comma-separated list, dropping empty entries. It
+// returns a non-nil (possibly empty) slice.
+func splitPatterns(raw string) []string {
+	patterns := []string{}
 	for _, name := range strings.Split(raw, ",") {
-		name = strings.TrimSpace(name)
-		if name != "" {
-			allow[name] = true
+		if name = strings.TrimSpace(name); name != "" {
+			patterns = append(patterns, name)
+		}
+	}
+	return patterns
+}
+
+// matchesAny reports whether name equals or glob-matches one of patterns.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
 		}
 	}
-	// All names disabled (e.g. ENABLED_TOOLS=",  ,"): fall back to allow-all
-	// would be surprising. Treat as "no tools enabled" instead.
-	return &Filter{allow: allow, registered: map[string]bool{}}
+	return false
 }
 
-// Allowed reports whether a tool with the given name should be exposed.
+// Allowed reports whether a tool with the given name passes ENABLED_TOOLS and
+// DISABLED_TOOLS. JIRA_MCP_MODE depends on the tool's category and is
+// applied by AddTool.
 func (f *Filter) Allowed(name string) bool {
-	if f == nil || f.allow == nil {
+	if f == nil {
 		return true
 	}
-	return f.allow[name]
+	if matchesAny(f.deny, name) {
+		return false
+	}
+	return f.allow == nil || matchesAny(f.allow, name)
 }
 
-// AddTool registers the tool with the server only when the filter allows it.
-// It is a drop-in replacement for s.AddTool(...) inside Register*Tool functions.
-func (f *Filter) AddTool(s *server.MCPServer, tool mcp.Tool, handler server.ToolHandlerFunc) {
-	name := tool.Name
-	if f == nil || f.allow == nil {
+// Mode returns the JIRA_MCP_MODE in effect.
+func (f *Filter) Mode() FilterMode {
+	if f == nil || f.mode == "" {
+		return ModeFull
+	}
+	return f.mode
+}
+
+// InvalidMode returns an unrecognised JIRA_MCP_MODE value, or "" when the
+// mode was valid. The filter treats an invalid mode as readonly.
+func (f *Filter) InvalidMode() string {
+	if f == nil {
+		return ""
+	}
+	return f.invalidMode
+}
+
+// UseProjectPolicy enforces policy on every tool registered afterwards. A
+// nil policy leaves projects unrestricted.
+func (f *Filter) UseProjectPolicy(policy *ProjectPolicy) {
+	f.policy = policy
+}
+
+// guardResource applies the project policy, if any, to a resource read.
+// Resources are registered outside AddTool, so they are checked here.
+func (f *Filter) guardResource(ctx context.Context, name string, projects ...string) (context.Context, error) {
+	if f == nil || f.policy == nil {
+		return ctx, nil
+	}
+	return f.policy.guardResource(ctx, name, projects)
+}
+
+// UseAuditLog records every call of a write or destructive tool registered
+// afterwards in audit.
+func (f *Filter) UseAuditLog(audit *AuditLog) {
+	f.audit = audit
+}
+
+// ConfirmsWrites reports whether write tools require confirm=true.
+func (f *Filter) ConfirmsWrites() bool {
+	return f != nil && f.confirmWrites
+}
+
+// AddTool registers the tool with the server when the filter allows it. It
+// is a drop-in replacement for s.AddTool(...) inside Register*Tool functions
+// that also records what the tool does: the category decides whether the
+// mode exposes it and sets the tool's MCP annotations.
+func (f *Filter) AddTool(s *server.MCPServer, tool mcp.Tool, category ToolCategory, handler server.ToolHandlerFunc) {
+	annotateTool(&tool, category)
+
+	if f == nil {
 		s.AddTool(tool, handler)
 		return
 	}
-	if f.allow[name] {
-		f.registered[name] = true
-		s.AddTool(tool, handler)
+	f.offered[tool.Name] = true
+	if !f.Allowed(tool.Name) || !f.Mode().permits(category) {
+		return
+	}
+	tool, handler = shapeOutput(f.Output(), tool, handler)
+	if f.confirmWrites && category != ToolRead {
+		tool, handler = requireConfirmation(tool, handler)
+	}
+	if f.policy != nil {
+		// Outermost, so a call outside the policy is refused before it is
+		// even asked to be confirmed.
+		handler = f.policy.guard(tool.Name, category, handler)
 	}
+	if f.audit != nil && category != ToolRead {
+		// Outermost of all, so refused calls are audited too.
+		handler = f.audit.wrap(tool.Name, handler)
+	}
+	f.registered[tool.Name] = true
+	s.AddTool(tool, handler)
 }
 
-// UnknownNames returns names listed in ENABLED_TOOLS that did not match any
-// registered tool. Useful for warning the user about typos at startup.
+// annotateTool sets the MCP hints clients use to decide how carefully to
+// treat a tool. mcp.NewTool defaults to the most cautious values, so they
+// are set explicitly for every category.
+func annotateTool(tool *mcp.Tool, category ToolCategory) {
+	tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(category == ToolRead)
+	tool.Annotations.DestructiveHint = mcp.ToBoolPtr(category == ToolDestructive)
+	tool.Annotations.IdempotentHint = mcp.ToBoolPtr(category == ToolRead)
+	tool.Annotations.OpenWorldHint = mcp.ToBoolPtr(true)
+}
+
+// confirmParam is the argument a gated tool must receive as true to run.
+const confirmParam = "confirm"
+
+// requireConfirmation adds a confirm parameter to tool and wraps handler so
+// the call is refused until confirm is true.
+func requireConfirmation(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, schema := range tool.InputSchema.Properties {
+		properties[name] = schema
+	}
+	properties[confirmParam] = map[string]any{
+		"type":        "boolean",
+		"description": "Must be true for the change to be made. Check with the user before setting it.",
+	}
+	tool.InputSchema.Properties = properties
+
+	gated := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !request.GetBool(confirmParam, false) {
+			return mcp.NewToolResultError(fmt.Sprintf("%s changes Jira and this server requires confirmation for changes. Nothing was done. Describe the change to the user and, once they agree, call %s again with the same arguments and confirm=true.", request.Params.Name, request.Params.Name)), nil
+		}
+		return handler(ctx, request)
+	}
+	return tool, gated
+}
+
+// UnknownNames returns names or patterns listed in ENABLED_TOOLS that did
+// not match any tool. Useful for warning the user about typos at startup.
 // Returns nil when ENABLED_TOOLS is not set.
 func (f *Filter) UnknownNames() []string {
 	if f == nil || f.allow == nil {
 		return nil
 	}
+	return f.unmatched(f.allow)
+}
+
+// UnknownDisabledNames returns names or patterns listed in DISABLED_TOOLS
+// that did not match any tool, i.e. entries that hide nothing.
+func (f *Filter) UnknownDisabledNames() []string {
+	if f == nil || len(f.deny) == 0 {
+		return nil
+	}
+	return f.unmatched(f.deny)
+}
+
+func (f *Filter) unmatched(patterns []string) []string {
 	var unknown []string
-	for name := range f.allow {
-		if !f.registered[name] {
-			unknown = append(unknown, name)
+	for _, pattern := range patterns {
+		if !f.matchesKnownTool(pattern) {
+			unknown = append(unknown, pattern)
 		}
 	}
 	sort.Strings(unknown)
 	return unknown
 }
 
+func (f *Filter) matchesKnownTool(pattern string) bool {
+	for _, names := range []map[string]bool{f.offered, f.registered} {
+		for name := range names {
+			if matchesAny([]string{pattern}, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // EnabledNames returns the sorted list of tool names that were actually
-// registered after applying the filter. When ENABLED_TOOLS is unset this
-// still returns nil because the filter registers everything without tracking.
+// registered after applying the filter. When no restriction is configured
+// this returns nil because everything is registered.
 func (f *Filter) EnabledNames() []string {
-	if f == nil || f.allow == nil {
+	if !f.IsRestricted() {
 		return nil
 	}
 	names := make([]string, 0, len(f.registered))
@@ -101,7 +365,8 @@ func (f *Filter) EnabledNames() []string {
 	return names
 }
 
-// IsRestricted reports whether ENABLED_TOOLS imposed any restriction at all.
+// IsRestricted reports whether ENABLED_TOOLS, DISABLED_TOOLS or
+// JIRA_MCP_MODE imposed any restriction at all.
 func (f *Filter) IsRestricted() bool {
-	return f != nil && f.allow != nil
+	return f != nil && (f.allow != nil || len(f.deny) > 0 || f.Mode() != ModeFull)
 }
@@ -1,9 +1,12 @@
 package tools
 
 import (
+	"context"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -15,13 +18,16 @@ func registerAll(s *server.MCPServer, f *Filter) {
 	RegisterJiraSprintTool(s, f)
 	RegisterJiraStatusTool(s, f)
 	RegisterJiraTransitionTool(s, f)
+	RegisterJiraBulkTool(s, f)
 	RegisterJiraWorklogTool(s, f)
+	RegisterJiraTimesheetTool(s, f)
 	RegisterJiraCommentTools(s, f)
 	RegisterJiraHistoryTool(s, f)
 	RegisterJiraRelationshipTool(s, f)
 	RegisterJiraVersionTool(s, f)
 	RegisterJiraDevelopmentTool(s, f)
 	RegisterJiraAttachmentTool(s, f)
+	RegisterJiraUndoTools(s, f)
 }
 
 func registeredNames(s *server.MCPServer) []string {
@@ -41,10 +47,10 @@ func TestRegister_AllowAllWhenEnvUnset(t *testing.T) {
 	registerAll(s, filter)
 
 	got := registeredNames(s)
-	// 23 tools in total — if a tool is added later the assertion below will
+	// 31 tools in total — if a tool is added later the assertion below will
 	// remind the maintainer to update this test and any related docs.
-	if len(got) != 23 {
-		t.Errorf("expected 23 registered tools, got %d: %v", len(got), got)
+	if len(got) != 31 {
+		t.Errorf("expected 31 registered tools, got %d: %v", len(got), got)
 	}
 
 	// Spot-check both a read and a write tool appear.
@@ -123,6 +129,10 @@ func TestRegister_ReadOnlyAgentScenario(t *testing.T) {
 		"jira_add_comment",
 		"jira_add_worklog",
 		"jira_transition_issue",
+		"jira_bulk_update",
+		"jira_upload_attachment",
+		"jira_update_worklog",
+		"jira_delete_worklog",
 	}
 	for _, name := range forbidden {
 		for _, reg := range got {
@@ -151,6 +161,145 @@ func TestRegister_UnknownNameWarning(t *testing.T) {
 	}
 }
 
+// readTools are the tools that only read Jira; everything else registered by
+// registerAll changes it.
+var readTools = []string{
+	"jira_download_attachment",
+	"jira_get_active_sprint",
+	"jira_get_comments",
+	"jira_get_development_information",
+	"jira_get_issue",
+	"jira_get_issue_history",
+	"jira_get_related_issues",
+	"jira_get_sprint",
+	"jira_get_version",
+	"jira_get_worklogs",
+	"jira_list_issue_types",
+	"jira_list_project_versions",
+	"jira_list_recent_changes",
+	"jira_list_sprints",
+	"jira_list_statuses",
+	"jira_search_issue",
+	"jira_search_sprint_by_name",
+	"jira_timesheet",
+}
+
+func TestRegister_ReadOnlyMode(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "")
+	t.Setenv("JIRA_MCP_MODE", "readonly")
+
+	s := server.NewMCPServer("test", "0.0.0")
+	registerAll(s, NewFilterFromEnv())
+
+	if got := registeredNames(s); !equalStringSlices(got, readTools) {
+		t.Errorf("readonly mode registered = %v, want %v", got, readTools)
+	}
+}
+
+func TestRegister_NoDeleteMode(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "")
+	t.Setenv("JIRA_MCP_MODE", "no-delete")
+
+	s := server.NewMCPServer("test", "0.0.0")
+	registerAll(s, NewFilterFromEnv())
+
+	got := registeredNames(s)
+	if len(got) != 29 {
+		t.Errorf("expected every tool but jira_delete_issue and jira_delete_worklog, got %d: %v", len(got), got)
+	}
+	for _, name := range got {
+		if name == "jira_delete_issue" || name == "jira_delete_worklog" {
+			t.Errorf("%s must not be registered in no-delete mode", name)
+		}
+	}
+	assertContains(t, got, "jira_update_issue")
+}
+
+func TestRegister_ModeAndAllowlistCombine(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "jira_get_*,jira_create_issue")
+	t.Setenv("JIRA_MCP_MODE", "readonly")
+
+	s := server.NewMCPServer("test", "0.0.0")
+	filter := NewFilterFromEnv()
+	registerAll(s, filter)
+
+	for _, name := range registeredNames(s) {
+		if name == "jira_create_issue" {
+			t.Errorf("readonly mode must hide jira_create_issue even when allowlisted")
+		}
+	}
+	assertContains(t, registeredNames(s), "jira_get_sprint")
+	if unknown := filter.UnknownNames(); len(unknown) != 0 {
+		t.Errorf("a tool hidden by the mode is not a typo, got unknown %v", unknown)
+	}
+}
+
+func TestRegister_AnnotationsMatchCategory(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "")
+
+	s := server.NewMCPServer("test", "0.0.0")
+	registerAll(s, NewFilterFromEnv())
+
+	reads := map[string]bool{}
+	for _, name := range readTools {
+		reads[name] = true
+	}
+	for name, tool := range s.ListTools() {
+		annotations := tool.Tool.Annotations
+		if annotations.ReadOnlyHint == nil || *annotations.ReadOnlyHint != reads[name] {
+			t.Errorf("%s: readOnlyHint = %v, want %v", name, annotations.ReadOnlyHint, reads[name])
+		}
+		wantDestructive := name == "jira_delete_issue" || name == "jira_delete_worklog"
+		if annotations.DestructiveHint == nil || *annotations.DestructiveHint != wantDestructive {
+			t.Errorf("%s: destructiveHint = %v, want %v", name, annotations.DestructiveHint, wantDestructive)
+		}
+	}
+}
+
+func TestRegister_ConfirmWritesGate(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "")
+	t.Setenv("JIRA_MCP_CONFIRM_WRITES", "true")
+
+	s := server.NewMCPServer("test", "0.0.0")
+	registerAll(s, NewFilterFromEnv())
+
+	if _, ok := s.GetTool("jira_get_issue").Tool.InputSchema.Properties[confirmParam]; ok {
+		t.Errorf("read tools must not get a confirm parameter")
+	}
+	deleteTool := s.GetTool("jira_delete_issue")
+	if _, ok := deleteTool.Tool.InputSchema.Properties[confirmParam]; !ok {
+		t.Fatalf("jira_delete_issue should declare a confirm parameter")
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "jira_delete_issue"
+	request.Params.Arguments = map[string]any{"issue_key": "X-1"}
+	result, err := deleteTool.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "confirm=true") {
+		t.Errorf("unconfirmed call should be refused with a hint, got %+v", result)
+	}
+}
+
+func TestRegister_OutputSchemas(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "")
+
+	for _, mode := range []OutputMode{OutputText, OutputJSON, OutputBoth} {
+		t.Setenv("JIRA_MCP_OUTPUT", string(mode))
+		s := server.NewMCPServer("test", "0.0.0")
+		registerAll(s, NewFilterFromEnv())
+
+		for name, tool := range s.ListTools() {
+			declared := tool.Tool.OutputSchema.Type == "object"
+			if declared != (mode != OutputText) {
+				t.Errorf("%s mode: %s declares an output schema = %v", mode, name, declared)
+			}
+		}
+	}
+}
+
 // --- helpers ---
 
 func assertContains(t *testing.T, haystack []string, needle string) {
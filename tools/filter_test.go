@@ -121,3 +121,89 @@ func TestFilter_ExplicitEmptyAllowlistBlocksEverything(t *testing.T) {
 		t.Errorf("no tool should be allowed when allowlist is effectively empty")
 	}
 }
+
+func TestFilter_GlobPatterns(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "jira_get_*, jira_search_issue")
+	f := NewFilterFromEnv()
+
+	for _, name := range []string{"jira_get_issue", "jira_get_comments", "jira_search_issue"} {
+		if !f.Allowed(name) {
+			t.Errorf("%s should match the allowlist", name)
+		}
+	}
+	if f.Allowed("jira_delete_issue") {
+		t.Errorf("jira_delete_issue should NOT match the allowlist")
+	}
+}
+
+func TestFilter_DisabledToolsWinOverEnabled(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "")
+	t.Setenv("DISABLED_TOOLS", "jira_delete_issue,jira_create_*")
+	f := NewFilterFromEnv()
+
+	if !f.IsRestricted() {
+		t.Fatalf("DISABLED_TOOLS alone should restrict the filter")
+	}
+	if f.Allowed("jira_delete_issue") || f.Allowed("jira_create_child_issue") {
+		t.Errorf("denied tools must not be allowed")
+	}
+	if !f.Allowed("jira_get_issue") {
+		t.Errorf("tools outside the deny list should stay allowed")
+	}
+
+	t.Setenv("ENABLED_TOOLS", "jira_*")
+	if NewFilterFromEnv().Allowed("jira_delete_issue") {
+		t.Errorf("DISABLED_TOOLS must win over a matching ENABLED_TOOLS pattern")
+	}
+}
+
+func TestFilter_Mode(t *testing.T) {
+	tests := []struct {
+		env         string
+		want        FilterMode
+		invalid     string
+		destructive bool
+		write       bool
+	}{
+		{"", ModeFull, "", true, true},
+		{"full", ModeFull, "", true, true},
+		{"READONLY", ModeReadOnly, "", false, false},
+		{"no-delete", ModeNoDelete, "", false, true},
+		{"read-only", ModeReadOnly, "read-only", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("JIRA_MCP_MODE", tt.env)
+			f := NewFilterFromEnv()
+			if f.Mode() != tt.want || f.InvalidMode() != tt.invalid {
+				t.Errorf("Mode() = %q, InvalidMode() = %q; want %q, %q", f.Mode(), f.InvalidMode(), tt.want, tt.invalid)
+			}
+			if f.IsRestricted() != (tt.want != ModeFull) {
+				t.Errorf("IsRestricted() = %v", f.IsRestricted())
+			}
+			if !f.Mode().permits(ToolRead) {
+				t.Errorf("read tools must always be permitted")
+			}
+			if got := f.Mode().permits(ToolWrite); got != tt.write {
+				t.Errorf("permits(write) = %v, want %v", got, tt.write)
+			}
+			if got := f.Mode().permits(ToolDestructive); got != tt.destructive {
+				t.Errorf("permits(destructive) = %v, want %v", got, tt.destructive)
+			}
+		})
+	}
+}
+
+func TestFilter_UnknownPatterns(t *testing.T) {
+	t.Setenv("ENABLED_TOOLS", "jira_get_*,jira_nothing_*")
+	t.Setenv("DISABLED_TOOLS", "jira_typo")
+	f := NewFilterFromEnv()
+	f.offered["jira_get_issue"] = true
+
+	if unknown := f.UnknownNames(); !reflect.DeepEqual(unknown, []string{"jira_nothing_*"}) {
+		t.Errorf("UnknownNames = %v, want [jira_nothing_*]", unknown)
+	}
+	if unknown := f.UnknownDisabledNames(); !reflect.DeepEqual(unknown, []string{"jira_typo"}) {
+		t.Errorf("UnknownDisabledNames = %v, want [jira_typo]", unknown)
+	}
+}
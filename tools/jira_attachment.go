@@ -1,54 +1,250 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
 )
 
 type DownloadAttachmentInput struct {
 	AttachmentID string `json:"attachment_id" validate:"required"`
+	Mode         string `json:"mode,omitempty"`
+	MaxChars     int    `json:"max_chars,omitempty"`
+}
+
+// DownloadAttachmentOutput is the structured result of
+// jira_download_attachment. Path is set in path mode; Kind, Content and
+// Truncated in inline mode, where an image comes as image content instead.
+type DownloadAttachmentOutput struct {
+	AttachmentID string `json:"attachment_id"`
+	Mode         string `json:"mode"`
+	Path         string `json:"path,omitempty"`
+	Filename     string `json:"filename"`
+	Size         int    `json:"size"`
+	MimeType     string `json:"mime_type,omitempty"`
+	Kind         string `json:"kind,omitempty"`
+	Content      string `json:"content,omitempty"`
+	Truncated    bool   `json:"truncated,omitempty"`
+}
+
+type UploadAttachmentInput struct {
+	IssueKey      string            `json:"issue_key" validate:"required"`
+	FilePath      string            `json:"file_path,omitempty"`
+	ContentBase64 string            `json:"content_base64,omitempty"`
+	Resource      *EmbeddedResource `json:"resource,omitempty"`
+	Filename      string            `json:"filename,omitempty"`
+	InsertInto    string            `json:"insert_into,omitempty"`
+	Comment       string            `json:"comment,omitempty"`
+	Format        string            `json:"format,omitempty"`
+}
+
+// EmbeddedResource is the contents of an MCP embedded resource: a base64
+// blob or text, named by its URI.
+type EmbeddedResource struct {
+	URI      string `json:"uri,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+// UploadAttachmentOutput is the structured result of jira_upload_attachment.
+type UploadAttachmentOutput struct {
+	IssueKey     string `json:"issue_key"`
+	AttachmentID string `json:"attachment_id"`
+	Filename     string `json:"filename"`
+	Size         int    `json:"size"`
+	MimeType     string `json:"mime_type,omitempty"`
+	URL          string `json:"url,omitempty"`
+	InsertedInto string `json:"inserted_into,omitempty"`
+	CommentID    string `json:"comment_id,omitempty"`
+	ChangeID     int    `json:"change_id,omitempty"`
+	Message      string `json:"message"`
 }
 
 func RegisterJiraAttachmentTool(s *server.MCPServer, filter *Filter) {
 	tool := mcp.NewTool("jira_download_attachment",
-		mcp.WithDescription("Download a Jira attachment to a local temporary file and return the absolute file path. Use attachment IDs from jira_get_issue output."),
+		mcp.WithDescription("Download a Jira attachment. In path mode it is saved to a temporary file on the server's machine and the path is returned. In inline mode the content is returned instead: images as image content, text files as text, PDF and Word/Excel/PowerPoint files as their extracted text. Use attachment IDs from jira_get_issue output."),
 		mcp.WithString("attachment_id", mcp.Required(), mcp.Description("The ID of the attachment to download (e.g., 10010)")),
+		mcp.WithString("mode", mcp.Description(`"path" (default) to save a temporary file, or "inline" to return the content. Use inline when the server runs remotely (HTTP transport).`)),
+		mcp.WithNumber("max_chars", mcp.Description(fmt.Sprintf("Inline mode: cut text content to this many characters (default %d)", defaultInlineChars))),
+		mcp.WithOutputSchema[DownloadAttachmentOutput](),
+	)
+	filter.AddTool(s, tool, ToolRead, mcp.NewTypedToolHandler(jiraDownloadAttachmentHandler))
+
+	uploadTool := mcp.NewTool("jira_upload_attachment",
+		mcp.WithDescription("Attach a file to a Jira issue, from a path on the server's machine, base64 content or an MCP embedded resource (give exactly one). Optionally shows it in the description or in a new comment: images inline, other files as a link."),
+		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		mcp.WithString("file_path", mcp.Description("Path of the file to upload, on the machine the server runs on, inside JIRA_MCP_UPLOAD_DIR when that is set. Off over HTTP unless JIRA_MCP_UPLOAD_DIR is set.")),
+		mcp.WithString("content_base64", mcp.Description("File content, base64-encoded (needs filename)")),
+		mcp.WithObject("resource", mcp.Description("An MCP embedded resource to upload: its blob (base64) or text. The filename defaults to the last part of its URI."),
+			mcp.Properties(map[string]any{
+				"uri":      map[string]any{"type": "string"},
+				"mimeType": map[string]any{"type": "string"},
+				"blob":     map[string]any{"type": "string", "description": "Base64-encoded content"},
+				"text":     map[string]any{"type": "string"},
+			})),
+		mcp.WithString("filename", mcp.Description("Name of the attachment in Jira (defaults to the file or resource name)")),
+		mcp.WithString("insert_into", mcp.Description(`Also show the attachment in the "description" (appended) or in a new "comment"`)),
+		mcp.WithString("comment", mcp.Description(`Text of the new comment, above the attachment (with insert_into "comment")`)),
+		mcp.WithString("format", mcp.Description("Markup of the comment. "+bodyFormatParamDescription)),
+		mcp.WithOutputSchema[UploadAttachmentOutput](),
 	)
-	filter.AddTool(s, tool, mcp.NewTypedToolHandler(jiraDownloadAttachmentHandler))
+	filter.AddTool(s, uploadTool, ToolWrite, mcp.NewTypedToolHandler(jiraUploadAttachmentHandler))
+}
+
+// Inline downloads: how much text is returned by default, and the largest
+// attachment and image that are fetched at all.
+const (
+	defaultInlineChars   = 20000
+	maxInlineBytes       = 20 << 20
+	maxInlineImageBytes  = 5 << 20
+	defaultAttachmentTTL = 24 * time.Hour
+)
+
+// AttachmentTTL is how long path-mode downloads are kept, from
+// JIRA_MCP_ATTACHMENT_TTL: a Go duration (default 24h), or "off" to keep
+// them.
+var AttachmentTTL = sync.OnceValues(func() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv("JIRA_MCP_ATTACHMENT_TTL"))
+	switch strings.ToLower(raw) {
+	case "":
+		return defaultAttachmentTTL, nil
+	case "off", "0":
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid JIRA_MCP_ATTACHMENT_TTL %q: expected a duration such as 24h, or off", raw)
+	}
+	return ttl, nil
+})
+
+// attachmentDownloadDir is where jira_download_attachment writes files in
+// path mode.
+func attachmentDownloadDir() string {
+	return filepath.Join(os.TempDir(), "jira-mcp-attachments")
+}
+
+// SweepAttachmentDownloads removes path-mode downloads older than ttl, so
+// files left by a previous run do not wait for the next download. A ttl of 0
+// keeps them.
+func SweepAttachmentDownloads(ttl time.Duration) {
+	if ttl > 0 {
+		sweepAttachmentDir(attachmentDownloadDir(), time.Now().Add(-ttl))
+	}
 }
 
 func jiraDownloadAttachmentHandler(ctx context.Context, request mcp.CallToolRequest, input DownloadAttachmentInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	ttl, err := AttachmentTTL()
+	if err != nil {
+		return nil, err
+	}
+	output, image, err := downloadAttachment(ctx, services.JiraClient(), attachmentDownloadDir(), ttl, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mcp.NewToolResultStructured(output, formatDownloadAttachment(output))
+	if image != nil {
+		result.Content = append(result.Content, *image)
+	}
+	return result, nil
+}
+
+// downloadAttachment fetches an attachment and either writes it under dir,
+// after removing files older than ttl, or returns its content: an image as
+// image content, anything with text in output.Content.
+func downloadAttachment(ctx context.Context, client *jira.Client, dir string, ttl time.Duration, input DownloadAttachmentInput) (*DownloadAttachmentOutput, *mcp.ImageContent, error) {
+	mode := strings.ToLower(strings.TrimSpace(input.Mode))
+	if mode == "" {
+		mode = "path"
+	}
+	if mode != "path" && mode != "inline" {
+		return nil, nil, fmt.Errorf(`invalid mode %q: use "path" or "inline"`, input.Mode)
+	}
 
 	// Get attachment metadata to know the filename
 	metadata, response, err := client.Issue.Attachment.Metadata(ctx, input.AttachmentID)
 	if err != nil {
 		if response != nil {
-			return nil, fmt.Errorf("failed to get attachment metadata: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			return nil, nil, fmt.Errorf("failed to get attachment metadata: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
 		}
-		return nil, fmt.Errorf("failed to get attachment metadata: %v", err)
+		return nil, nil, fmt.Errorf("failed to get attachment metadata: %v", err)
+	}
+	if mode == "inline" && metadata.Size > maxInlineBytes {
+		return nil, nil, fmt.Errorf("attachment %s is %s, too large to return inline (limit %s); use mode \"path\"",
+			metadata.Filename, formatByteSize(int64(metadata.Size)), formatByteSize(maxInlineBytes))
 	}
 
 	// Download the attachment content (redirect=true to follow redirect and get actual bytes)
 	dlResponse, err := client.Issue.Attachment.Download(ctx, input.AttachmentID, true)
 	if err != nil {
 		if dlResponse != nil {
-			return nil, fmt.Errorf("failed to download attachment: %s (endpoint: %s)", dlResponse.Bytes.String(), dlResponse.Endpoint)
+			return nil, nil, fmt.Errorf("failed to download attachment: %s (endpoint: %s)", dlResponse.Bytes.String(), dlResponse.Endpoint)
+		}
+		return nil, nil, fmt.Errorf("failed to download attachment: %v", err)
+	}
+	content := dlResponse.Bytes.Bytes()
+
+	output := &DownloadAttachmentOutput{
+		AttachmentID: input.AttachmentID,
+		Mode:         mode,
+		Filename:     metadata.Filename,
+		Size:         metadata.Size,
+		MimeType:     metadata.MimeType,
+	}
+
+	if mode == "inline" {
+		kind := util.ClassifyAttachment(metadata.Filename, metadata.MimeType, content)
+		output.Kind = string(kind)
+		switch kind {
+		case util.AttachmentImage:
+			if len(content) > maxInlineImageBytes {
+				return nil, nil, fmt.Errorf("image %s is %s, too large to return inline (limit %s); use mode \"path\"",
+					metadata.Filename, formatByteSize(int64(len(content))), formatByteSize(maxInlineImageBytes))
+			}
+			image := mcp.NewImageContent(base64.StdEncoding.EncodeToString(content), metadata.MimeType)
+			return output, &image, nil
+		case util.AttachmentBinary:
+			return nil, nil, fmt.Errorf("attachment %s (%s) has no text or image to return inline; use mode \"path\"", metadata.Filename, metadata.MimeType)
+		}
+		text, err := util.ExtractAttachmentText(metadata.Filename, metadata.MimeType, content)
+		if err != nil {
+			return nil, nil, err
 		}
-		return nil, fmt.Errorf("failed to download attachment: %v", err)
+		maxChars := input.MaxChars
+		if maxChars <= 0 {
+			maxChars = defaultInlineChars
+		}
+		output.Content = util.TruncateText(text, maxChars)
+		output.Truncated = output.Content != text
+		return output, nil, nil
 	}
 
 	// Create temp directory for jira attachments
-	tmpDir := filepath.Join(os.TempDir(), "jira-mcp-attachments")
-	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %v", err)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create temp directory: %v", err)
+	}
+	if ttl > 0 {
+		sweepAttachmentDir(dir, time.Now().Add(-ttl))
 	}
 
 	// Sanitize filename
@@ -60,13 +256,483 @@ func jiraDownloadAttachmentHandler(ctx context.Context, request mcp.CallToolRequ
 	filename = strings.ReplaceAll(filename, "\\", "_")
 
 	// Write to temp file with attachment ID prefix to avoid collisions
-	filePath := filepath.Join(tmpDir, fmt.Sprintf("%s_%s", input.AttachmentID, filename))
-	if err := os.WriteFile(filePath, dlResponse.Bytes.Bytes(), 0o644); err != nil {
-		return nil, fmt.Errorf("failed to write attachment to file: %v", err)
+	output.Path = filepath.Join(dir, fmt.Sprintf("%s_%s", input.AttachmentID, filename))
+	if err := os.WriteFile(output.Path, content, 0o644); err != nil {
+		return nil, nil, fmt.Errorf("failed to write attachment to file: %v", err)
 	}
+	return output, nil, nil
+}
 
-	result := fmt.Sprintf("Attachment downloaded successfully!\nFile: %s\nFilename: %s\nSize: %d bytes\nMIME Type: %s",
-		filePath, metadata.Filename, metadata.Size, metadata.MimeType)
+// sweepAttachmentDir removes the downloads in dir last written before
+// cutoff. Failures are ignored: a file left behind is swept next time.
+func sweepAttachmentDir(dir string, cutoff time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		_ = os.Remove(filepath.Join(dir, entry.Name()))
+	}
+}
 
-	return mcp.NewToolResultText(result), nil
+func formatDownloadAttachment(output *DownloadAttachmentOutput) string {
+	if output.Path != "" {
+		return fmt.Sprintf("Attachment downloaded successfully!\nFile: %s\nFilename: %s\nSize: %d bytes\nMIME Type: %s",
+			output.Path, output.Filename, output.Size, output.MimeType)
+	}
+	header := fmt.Sprintf("Attachment: %s (ID: %s)\nSize: %d bytes\nMIME Type: %s", output.Filename, output.AttachmentID, output.Size, output.MimeType)
+	if output.Kind == string(util.AttachmentImage) {
+		return header + "\nThe image follows."
+	}
+	if output.Content == "" {
+		return header + "\n\n(no text found)"
+	}
+	return header + "\n\n" + output.Content
+}
+
+// defaultUploadLimit is the largest upload unless JIRA_MCP_UPLOAD_LIMIT says
+// otherwise.
+const defaultUploadLimit = 10 << 20
+
+// UploadLimit is the largest file jira_upload_attachment accepts, from
+// JIRA_MCP_UPLOAD_LIMIT: bytes, or a number with a KB, MB or GB suffix
+// (default 10MB).
+var UploadLimit = sync.OnceValues(func() (int64, error) {
+	raw := strings.TrimSpace(os.Getenv("JIRA_MCP_UPLOAD_LIMIT"))
+	if raw == "" {
+		return defaultUploadLimit, nil
+	}
+	limit, err := parseByteSize(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid JIRA_MCP_UPLOAD_LIMIT %q: %v", raw, err)
+	}
+	return limit, nil
+})
+
+// UploadAnywhere is the upload root that lets file_path name any file the
+// process can read. Only UploadAttachment, for the CLI, uses it.
+const UploadAnywhere = "*"
+
+// uploadRoot is where jira_upload_attachment may read file_path from, as set
+// by ConfigureUploadDir: a resolved directory, UploadAnywhere, or "" when
+// file_path is off.
+var uploadRoot struct {
+	sync.Mutex
+	dir string
+}
+
+// ConfigureUploadDir sets where jira_upload_attachment may read file_path
+// from, from JIRA_MCP_UPLOAD_DIR: a directory the file must lie in (after
+// resolving symlinks), or "off". When it is unset, any file may be read in
+// stdio mode, while over HTTP (httpMode), where the caller may be remote,
+// file_path is off. It returns the directory, UploadAnywhere or "".
+func ConfigureUploadDir(httpMode bool) (string, error) {
+	raw := strings.TrimSpace(os.Getenv("JIRA_MCP_UPLOAD_DIR"))
+	var dir string
+	switch {
+	case strings.EqualFold(raw, "off"):
+	case raw != "":
+		resolved, err := filepath.Abs(raw)
+		if err == nil {
+			resolved, err = filepath.EvalSymlinks(resolved)
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid JIRA_MCP_UPLOAD_DIR %q: %v", raw, err)
+		}
+		if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("invalid JIRA_MCP_UPLOAD_DIR %q: not a directory", raw)
+		}
+		dir = resolved
+	case !httpMode:
+		dir = UploadAnywhere
+	}
+
+	uploadRoot.Lock()
+	defer uploadRoot.Unlock()
+	uploadRoot.dir = dir
+	return dir, nil
+}
+
+// resolveUploadPath returns the real path of file, refusing it unless it
+// lies within root.
+func resolveUploadPath(file, root string) (string, error) {
+	switch root {
+	case "":
+		return "", fmt.Errorf("file_path is off on this server: use content_base64 or resource, or set JIRA_MCP_UPLOAD_DIR")
+	case UploadAnywhere:
+		return file, nil
+	}
+	resolved, err := filepath.Abs(file)
+	if err == nil {
+		resolved, err = filepath.EvalSymlinks(resolved)
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot read file_path: %v", err)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("file_path %s is outside the upload directory %s (JIRA_MCP_UPLOAD_DIR)", file, root)
+	}
+	return resolved, nil
+}
+
+// parseByteSize reads "512", "500KB", "10MB" or "1GB" (1024-based).
+func parseByteSize(value string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSpace(strings.TrimSuffix(upper, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+	number, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("expected a positive size such as 10MB")
+	}
+	return number * multiplier, nil
+}
+
+// formatByteSize is the inverse of parseByteSize, for messages.
+func formatByteSize(size int64) string {
+	switch {
+	case size >= 1<<20 && size%(1<<20) == 0:
+		return fmt.Sprintf("%dMB", size>>20)
+	case size >= 1<<10 && size%(1<<10) == 0:
+		return fmt.Sprintf("%dKB", size>>10)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
+
+func jiraUploadAttachmentHandler(ctx context.Context, request mcp.CallToolRequest, input UploadAttachmentInput) (*mcp.CallToolResult, error) {
+	limit, err := UploadLimit()
+	if err != nil {
+		return nil, err
+	}
+	uploadRoot.Lock()
+	root := uploadRoot.dir
+	uploadRoot.Unlock()
+	output, err := uploadAttachment(ctx, services.JiraClient(), services.JiraRawClient(), limit, root, input)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(output, FormatUploadAttachment(output)), nil
+}
+
+// UploadAttachment attaches a file to input.IssueKey, within UploadLimit,
+// and inserts it into the description or a new comment when asked to. It is
+// for the CLI, whose user names their own files: input.FilePath may be any
+// file they can read.
+func UploadAttachment(ctx context.Context, input UploadAttachmentInput) (*UploadAttachmentOutput, error) {
+	limit, err := UploadLimit()
+	if err != nil {
+		return nil, err
+	}
+	return uploadAttachment(ctx, services.JiraClient(), services.JiraRawClient(), limit, UploadAnywhere, input)
+}
+
+// uploadAttachment uploads input's file; file_path must lie within root (see
+// resolveUploadPath).
+func uploadAttachment(ctx context.Context, client *jira.Client, raw *services.RawClient, limit int64, root string, input UploadAttachmentInput) (*UploadAttachmentOutput, error) {
+	insertInto := strings.ToLower(strings.TrimSpace(input.InsertInto))
+	if insertInto != "" && insertInto != "description" && insertInto != "comment" {
+		return nil, fmt.Errorf(`invalid insert_into %q: use "description" or "comment"`, input.InsertInto)
+	}
+	var commentBody *models.CommentNodeScheme
+	if insertInto == "comment" {
+		commentBody = &models.CommentNodeScheme{Version: 1, Type: "doc"}
+	}
+	if insertInto == "comment" && input.Comment != "" {
+		body, err := util.BodyToADF(input.Comment, input.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comment: %v", err)
+		}
+		commentBody = body
+	}
+
+	filename, content, err := readUploadSource(input, limit, root)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, response, err := client.Issue.Attachment.Add(ctx, input.IssueKey, filename, bytes.NewReader(content))
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to upload attachment: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to upload attachment: %v", err)
+	}
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("failed to upload attachment: Jira returned no attachment")
+	}
+	attachment := attachments[0]
+	output := &UploadAttachmentOutput{
+		IssueKey:     input.IssueKey,
+		AttachmentID: attachment.ID,
+		Filename:     attachment.Filename,
+		Size:         attachment.Size,
+		MimeType:     attachment.MimeType,
+		URL:          attachment.Content,
+		Message:      "Attachment uploaded successfully!",
+	}
+
+	var node *models.CommentNodeScheme
+	if insertInto != "" {
+		mediaID, collection, _ := attachmentMedia(ctx, raw, attachment.ID)
+		node = attachmentNode(attachment, mediaID, collection)
+	}
+	switch insertInto {
+	case "description":
+		changeID, err := appendToDescription(ctx, client, input.IssueKey, node)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s uploaded, but %v", attachment.ID, err)
+		}
+		output.InsertedInto = "description"
+		output.ChangeID = changeID
+	case "comment":
+		commentBody.AppendNode(node)
+		comment, response, err := client.Issue.Comment.Add(ctx, input.IssueKey, &models.CommentPayloadScheme{Body: commentBody}, nil)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("attachment %s uploaded, but failed to add comment: %s (endpoint: %s)", attachment.ID, response.Bytes.String(), response.Endpoint)
+			}
+			return nil, fmt.Errorf("attachment %s uploaded, but failed to add comment: %v", attachment.ID, err)
+		}
+		output.InsertedInto = "comment"
+		output.CommentID = comment.ID
+		output.ChangeID = recordChange(ChangeEntry{
+			Tool:      "jira_upload_attachment",
+			IssueKey:  input.IssueKey,
+			Kind:      ChangeComment,
+			Summary:   "added comment " + comment.ID,
+			CommentID: comment.ID,
+		})
+	}
+	return output, nil
+}
+
+// FormatUploadAttachment describes an upload for the text result.
+func FormatUploadAttachment(output *UploadAttachmentOutput) string {
+	result := fmt.Sprintf("%s\nID: %s\nFilename: %s\nSize: %d bytes\nMIME Type: %s\nURL: %s",
+		output.Message, output.AttachmentID, output.Filename, output.Size, output.MimeType, output.URL)
+	switch output.InsertedInto {
+	case "description":
+		result += "\nAdded to the description of " + output.IssueKey
+	case "comment":
+		result += "\nAdded in comment " + output.CommentID
+	}
+	return result + changeNote(output.ChangeID)
+}
+
+// readUploadSource returns the filename and content of the one source
+// input names, refusing content over limit bytes and file_path outside root.
+func readUploadSource(input UploadAttachmentInput, limit int64, root string) (string, []byte, error) {
+	sources := 0
+	for _, given := range []bool{input.FilePath != "", input.ContentBase64 != "", input.Resource != nil} {
+		if given {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return "", nil, fmt.Errorf("give exactly one of file_path, content_base64 or resource")
+	}
+	tooLarge := func(size int64) error {
+		return fmt.Errorf("the file is %s, over the upload limit of %s (JIRA_MCP_UPLOAD_LIMIT)", formatByteSize(size), formatByteSize(limit))
+	}
+
+	filename := strings.TrimSpace(input.Filename)
+	var content []byte
+	switch {
+	case input.FilePath != "":
+		file, err := resolveUploadPath(input.FilePath, root)
+		if err != nil {
+			return "", nil, err
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot read file_path: %v", err)
+		}
+		if !info.Mode().IsRegular() {
+			return "", nil, fmt.Errorf("file_path %s is not a regular file", input.FilePath)
+		}
+		if info.Size() > limit {
+			return "", nil, tooLarge(info.Size())
+		}
+		if content, err = os.ReadFile(file); err != nil {
+			return "", nil, fmt.Errorf("cannot read file_path: %v", err)
+		}
+		if filename == "" {
+			filename = filepath.Base(input.FilePath)
+		}
+
+	case input.ContentBase64 != "":
+		decoded, err := decodeBase64(input.ContentBase64, limit)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid content_base64: %v", err)
+		}
+		content = decoded
+
+	default:
+		resource := input.Resource
+		switch {
+		case resource.Blob != "":
+			decoded, err := decodeBase64(resource.Blob, limit)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid resource blob: %v", err)
+			}
+			content = decoded
+		case resource.Text != "":
+			content = []byte(resource.Text)
+		default:
+			return "", nil, fmt.Errorf("resource has neither blob nor text")
+		}
+		if filename == "" {
+			filename = resourceFilename(resource.URI)
+		}
+	}
+
+	if int64(len(content)) > limit {
+		return "", nil, tooLarge(int64(len(content)))
+	}
+	if filename == "" {
+		return "", nil, fmt.Errorf("filename is required")
+	}
+	return filename, content, nil
+}
+
+// decodeBase64 decodes standard base64, ignoring line breaks, and refuses
+// content that would decode to more than limit bytes before decoding it.
+func decodeBase64(encoded string, limit int64) ([]byte, error) {
+	encoded = strings.Join(strings.Fields(encoded), "")
+	if size := int64(base64.StdEncoding.DecodedLen(len(encoded))); size > limit+2 {
+		return nil, fmt.Errorf("the content is about %s, over the upload limit of %s (JIRA_MCP_UPLOAD_LIMIT)", formatByteSize(size), formatByteSize(limit))
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+// resourceFilename takes the last path element of a resource URI.
+func resourceFilename(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	name := parsed.Path
+	if name == "" {
+		name = parsed.Opaque
+	}
+	name = path.Base(name)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// attachmentMedia finds the Media Services file behind an attachment, which
+// ADF media nodes refer to. Jira Cloud answers the attachment's content URL
+// with a redirect to .../file/{id}/binary?collection=..., which is read here
+// without being followed.
+func attachmentMedia(ctx context.Context, raw *services.RawClient, attachmentID string) (id, collection string, err error) {
+	httpClient := *services.DefaultHttpClient()
+	if raw.HTTP != nil {
+		httpClient = *raw.HTTP
+	}
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	noRedirect := *raw
+	noRedirect.HTTP = &httpClient
+
+	response, err := noRedirect.Do(ctx, http.MethodGet, "rest/api/3/attachment/content/"+url.PathEscape(attachmentID), nil, nil, nil)
+	if response == nil {
+		return "", "", fmt.Errorf("failed to find attachment media: %v", err)
+	}
+	if response.Code < 300 || response.Code >= 400 {
+		return "", "", fmt.Errorf("failed to find attachment media: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+	}
+	location, err := url.Parse(response.Response.Header.Get("Location"))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find attachment media: %v", err)
+	}
+	parts := strings.Split(strings.Trim(location.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "file" && parts[i+1] != "" {
+			return parts[i+1], location.Query().Get("collection"), nil
+		}
+	}
+	return "", "", fmt.Errorf("failed to find attachment media: unexpected redirect to %s", location.Redacted())
+}
+
+// attachmentNode shows an uploaded attachment in ADF as Jira does: a file
+// media node for mediaID in collection, inside an image block for images
+// and a file card group for other files. When the media file could not be
+// found (mediaID is empty), images fall back to an image block of their
+// content URL and other files to a link.
+func attachmentNode(attachment *models.IssueAttachmentScheme, mediaID, collection string) *models.CommentNodeScheme {
+	image := strings.HasPrefix(attachment.MimeType, "image/")
+	if mediaID != "" {
+		media := &models.CommentNodeScheme{Type: "media", Attrs: map[string]interface{}{"id": mediaID, "type": "file", "collection": collection}}
+		if image {
+			return &models.CommentNodeScheme{Type: "mediaSingle", Attrs: map[string]interface{}{"layout": "center"}, Content: []*models.CommentNodeScheme{media}}
+		}
+		return &models.CommentNodeScheme{Type: "mediaGroup", Content: []*models.CommentNodeScheme{media}}
+	}
+	if image {
+		return util.ExternalMediaNode(attachment.Content, attachment.Filename)
+	}
+	link := &models.CommentNodeScheme{
+		Type:  "text",
+		Text:  attachment.Filename,
+		Marks: []*models.MarkScheme{{Type: "link", Attrs: map[string]interface{}{"href": attachment.Content}}},
+	}
+	return &models.CommentNodeScheme{Type: "paragraph", Content: []*models.CommentNodeScheme{link}}
+}
+
+// appendToDescription adds node at the end of the issue's description and
+// records the previous description in the undo journal.
+func appendToDescription(ctx context.Context, client *jira.Client, issueKey string, node *models.CommentNodeScheme) (int, error) {
+	issue, response, err := client.Issue.Get(ctx, issueKey, []string{"description"}, nil)
+	if err != nil {
+		if response != nil {
+			return 0, fmt.Errorf("failed to get description: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return 0, fmt.Errorf("failed to get description: %v", err)
+	}
+	var before map[string]json.RawMessage
+	if UndoJournal() != nil {
+		if before, err = extractFieldValues(response.Bytes.Bytes(), []string{"description"}); err != nil {
+			return 0, err
+		}
+	}
+
+	description := &models.CommentNodeScheme{Version: 1, Type: "doc"}
+	if issue.Fields != nil && issue.Fields.Description != nil {
+		description = issue.Fields.Description
+	}
+	description.AppendNode(node)
+
+	payload := &models.IssueScheme{Fields: &models.IssueFieldsScheme{Description: description}}
+	response, err = client.Issue.Update(ctx, issueKey, true, payload, nil, nil)
+	if err != nil {
+		if response != nil {
+			return 0, fmt.Errorf("failed to update description: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return 0, fmt.Errorf("failed to update description: %v", err)
+	}
+
+	if before == nil {
+		return 0, nil
+	}
+	return recordChange(ChangeEntry{
+		Tool:     "jira_upload_attachment",
+		IssueKey: issueKey,
+		Kind:     ChangeFields,
+		Summary:  "updated description",
+		Before:   before,
+	}), nil
 }
@@ -0,0 +1,319 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for value, want := range map[string]int64{"512": 512, "500KB": 500 << 10, "10 mb": 10 << 20, "1GB": 1 << 30, "64B": 64} {
+		if got, err := parseByteSize(value); err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v, want %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "-1MB", "ten", "1TB"} {
+		if _, err := parseByteSize(value); err == nil {
+			t.Errorf("parseByteSize(%q) should fail", value)
+		}
+	}
+}
+
+func TestReadUploadSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "build.log")
+	if err := os.WriteFile(path, []byte("line one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte("a,b\n1,2\n"))
+
+	tests := []struct {
+		name     string
+		input    UploadAttachmentInput
+		filename string
+		content  string
+	}{
+		{"path", UploadAttachmentInput{FilePath: path}, "build.log", "line one\n"},
+		{"path renamed", UploadAttachmentInput{FilePath: path, Filename: "ci.log"}, "ci.log", "line one\n"},
+		{"base64", UploadAttachmentInput{ContentBase64: encoded, Filename: "report.csv"}, "report.csv", "a,b\n1,2\n"},
+		{"resource blob", UploadAttachmentInput{Resource: &EmbeddedResource{URI: "file:///tmp/out/report.csv", Blob: encoded}}, "report.csv", "a,b\n1,2\n"},
+		{"resource text", UploadAttachmentInput{Resource: &EmbeddedResource{URI: "mem://notes.md", Text: "# Notes"}, Filename: "notes.md"}, "notes.md", "# Notes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename, content, err := readUploadSource(tt.input, 1024, UploadAnywhere)
+			if err != nil || filename != tt.filename || string(content) != tt.content {
+				t.Errorf("got %q, %q, %v", filename, content, err)
+			}
+		})
+	}
+
+	for name, input := range map[string]UploadAttachmentInput{
+		"no source":      {},
+		"two sources":    {FilePath: path, ContentBase64: encoded},
+		"no filename":    {ContentBase64: encoded},
+		"directory":      {FilePath: dir},
+		"bad base64":     {ContentBase64: "not base64!", Filename: "x"},
+		"over the limit": {FilePath: path, Filename: "x"},
+		"empty resource": {Resource: &EmbeddedResource{URI: "mem://x"}},
+		"large resource": {Resource: &EmbeddedResource{URI: "mem://x", Text: strings.Repeat("x", 20)}},
+	} {
+		limit := int64(1024)
+		if name == "over the limit" || name == "large resource" {
+			limit = 5
+		}
+		if _, _, err := readUploadSource(input, limit, UploadAnywhere); err == nil {
+			t.Errorf("%s: should fail", name)
+		}
+	}
+}
+
+func TestReadUploadSource_UploadDir(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	inside := filepath.Join(root, "build.log")
+	outside := filepath.Join(t.TempDir(), "secret.env")
+	for _, path := range []string{inside, outside} {
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(root, "link.env")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, content, err := readUploadSource(UploadAttachmentInput{FilePath: inside}, 1024, root); err != nil || string(content) != "x" {
+		t.Errorf("file inside the upload dir: %q, %v", content, err)
+	}
+	for name, path := range map[string]string{
+		"outside": outside,
+		"dot-dot": filepath.Join(root, "..", filepath.Base(filepath.Dir(outside)), "secret.env"),
+		"symlink": link,
+		"missing": filepath.Join(root, "missing.log"),
+	} {
+		if _, _, err := readUploadSource(UploadAttachmentInput{FilePath: path}, 1024, root); err == nil {
+			t.Errorf("%s: should fail", name)
+		}
+	}
+	if _, _, err := readUploadSource(UploadAttachmentInput{FilePath: inside}, 1024, ""); err == nil || !strings.Contains(err.Error(), "JIRA_MCP_UPLOAD_DIR") {
+		t.Errorf("file_path should be off without a root, got %v", err)
+	}
+}
+
+func TestConfigureUploadDir(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		env      string
+		httpMode bool
+		want     string
+	}{
+		{"", false, UploadAnywhere},
+		{"", true, ""},
+		{"off", false, ""},
+		{dir, true, dir},
+	}
+	for _, tt := range tests {
+		t.Setenv("JIRA_MCP_UPLOAD_DIR", tt.env)
+		got, err := ConfigureUploadDir(tt.httpMode)
+		if tt.want == dir {
+			tt.want, _ = filepath.EvalSymlinks(dir)
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ConfigureUploadDir(%v) with %q = %q, %v, want %q", tt.httpMode, tt.env, got, err, tt.want)
+		}
+	}
+	t.Setenv("JIRA_MCP_UPLOAD_DIR", filepath.Join(dir, "missing"))
+	if _, err := ConfigureUploadDir(false); err == nil {
+		t.Error("a missing upload dir should fail")
+	}
+}
+
+func TestUploadAttachment_InsertIntoComment(t *testing.T) {
+	// Keep the new comment out of the user's undo journal.
+	t.Setenv("JIRA_MCP_JOURNAL", "off")
+	var uploaded, comment string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/3/issue/ABC-1/attachments":
+			uploaded = string(body)
+			_, _ = w.Write([]byte(`[{"id":"10010","filename":"shot.png","size":4,"mimeType":"image/png","content":"https://jira.example/rest/api/3/attachment/content/10010"}]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/attachment/content/10010":
+			w.Header().Set("Location", "https://api.media.atlassian.com/file/2f4e6a1c-90b1-4c55-8f4e-3d1c0a7b9e21/binary?token=t&client=c&collection=&dl=true")
+			w.WriteHeader(http.StatusSeeOther)
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/3/issue/ABC-1/comment":
+			comment = string(body)
+			_, _ = w.Write([]byte(`{"id":"500","author":{"displayName":"Bot"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := jira.New(srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+
+	output, err := uploadAttachment(context.Background(), client, raw, 1024, UploadAnywhere, UploadAttachmentInput{
+		IssueKey:      "ABC-1",
+		ContentBase64: base64.StdEncoding.EncodeToString([]byte("\x89PNG")),
+		Filename:      "shot.png",
+		InsertInto:    "comment",
+		Comment:       "Failing *screen*",
+		Format:        "wiki",
+	})
+	if err != nil {
+		t.Fatalf("uploadAttachment: %v", err)
+	}
+	if output.AttachmentID != "10010" || output.CommentID != "500" || output.InsertedInto != "comment" {
+		t.Errorf("output = %+v", output)
+	}
+	if !strings.Contains(uploaded, `filename="shot.png"`) || !strings.Contains(uploaded, "\x89PNG") {
+		t.Errorf("upload body = %q", uploaded)
+	}
+	for _, want := range []string{`"type":"strong"`, `{"type":"mediaSingle","content":[{"type":"media","attrs":{"collection":"","id":"2f4e6a1c-90b1-4c55-8f4e-3d1c0a7b9e21","type":"file"}}]`} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("comment lacks %s: %s", want, comment)
+		}
+	}
+
+	if _, err := uploadAttachment(context.Background(), client, raw, 1024, UploadAnywhere, UploadAttachmentInput{IssueKey: "ABC-1", InsertInto: "summary"}); err == nil {
+		t.Error("an unknown insert_into should be refused")
+	}
+}
+
+func TestAttachmentNode(t *testing.T) {
+	document := &models.IssueAttachmentScheme{Filename: "spec.pdf", MimeType: "application/pdf", Content: "https://jira.example/rest/api/3/attachment/content/7"}
+	tests := []struct {
+		name       string
+		attachment *models.IssueAttachmentScheme
+		mediaID    string
+		want       string
+	}{
+		{"file", document, "m-1", `{"type":"mediaGroup","content":[{"type":"media","attrs":{"collection":"jira-1","id":"m-1","type":"file"}}]}`},
+		{"link without media", document, "", `{"type":"paragraph","content":[{"type":"text","text":"spec.pdf","marks":[{"type":"link","attrs":{"href":"https://jira.example/rest/api/3/attachment/content/7"}}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := json.Marshal(attachmentNode(tt.attachment, tt.mediaID, "jira-1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(encoded) != tt.want {
+				t.Errorf("attachmentNode =\n%s\nwant\n%s", encoded, tt.want)
+			}
+		})
+	}
+}
+
+// attachmentServer serves one attachment's metadata and content.
+func attachmentServer(t *testing.T, filename, mimeType string, content []byte) *jira.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/attachment/10010":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"id":10010,"filename":%q,"size":%d,"mimeType":%q}`, filename, len(content), mimeType)
+		case "/rest/api/3/attachment/content/10010":
+			w.Header().Set("Content-Type", mimeType)
+			_, _ = w.Write(content)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := jira.New(srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestDownloadAttachment_Inline(t *testing.T) {
+	ctx := context.Background()
+	input := DownloadAttachmentInput{AttachmentID: "10010", Mode: "inline"}
+
+	client := attachmentServer(t, "shot.png", "image/png", []byte("\x89PNG"))
+	output, image, err := downloadAttachment(ctx, client, t.TempDir(), 0, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image == nil || image.MIMEType != "image/png" || image.Data != base64.StdEncoding.EncodeToString([]byte("\x89PNG")) {
+		t.Errorf("image = %+v", image)
+	}
+	if output.Kind != "image" || output.Path != "" {
+		t.Errorf("output = %+v", output)
+	}
+
+	client = attachmentServer(t, "server.log", "application/octet-stream", []byte(strings.Repeat("error line\n", 50)))
+	input.MaxChars = 100
+	output, image, err = downloadAttachment(ctx, client, t.TempDir(), 0, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image != nil || output.Kind != "text" || !output.Truncated || !strings.HasPrefix(output.Content, "error line\nerror line") {
+		t.Errorf("output = %+v", output)
+	}
+
+	client = attachmentServer(t, "core.dump", "application/octet-stream", []byte("\x00\x01"))
+	if _, _, err := downloadAttachment(ctx, client, t.TempDir(), 0, input); err == nil {
+		t.Error("a binary attachment should not be returned inline")
+	}
+	if _, _, err := downloadAttachment(ctx, client, t.TempDir(), 0, DownloadAttachmentInput{AttachmentID: "10010", Mode: "url"}); err == nil {
+		t.Error("an unknown mode should be refused")
+	}
+}
+
+func TestDownloadAttachment_PathSweepsOldFiles(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "1_old.txt")
+	fresh := filepath.Join(dir, "2_new.txt")
+	for _, name := range []string{stale, fresh} {
+		if err := os.WriteFile(name, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	client := attachmentServer(t, "notes/today.txt", "text/plain", []byte("hello"))
+	output, _, err := downloadAttachment(context.Background(), client, dir, 24*time.Hour, DownloadAttachmentInput{AttachmentID: "10010"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Mode != "path" || output.Path != filepath.Join(dir, "10010_notes_today.txt") {
+		t.Errorf("output = %+v", output)
+	}
+	if content, err := os.ReadFile(output.Path); err != nil || string(content) != "hello" {
+		t.Errorf("written file = %q, %v", content, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("a download older than the TTL should be removed")
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Error("a recent download should be kept")
+	}
+}
@@ -0,0 +1,407 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Limits for jira_bulk_update. Concurrency is kept low on purpose: Jira Cloud
+// rate-limits per user, and RawClient already backs off on 429 responses.
+const (
+	BulkDefaultMaxIssues   = 50
+	BulkMaxIssues          = 500
+	BulkDefaultConcurrency = 4
+	BulkMaxConcurrency     = 10
+)
+
+// Input types for typed tools
+type BulkUpdateInput struct {
+	JQL               string `json:"jql" validate:"required"`
+	ToStatus          string `json:"to_status,omitempty"`
+	Assignee          string `json:"assignee,omitempty"`
+	AddLabels         string `json:"add_labels,omitempty"`
+	RemoveLabels      string `json:"remove_labels,omitempty"`
+	AddFixVersions    string `json:"add_fix_versions,omitempty"`
+	RemoveFixVersions string `json:"remove_fix_versions,omitempty"`
+	Comment           string `json:"comment,omitempty"`
+	Format            string `json:"format,omitempty"`
+	MaxIssues         int    `json:"max_issues,omitempty"`
+	Concurrency       int    `json:"concurrency,omitempty"`
+	DryRun            bool   `json:"dry_run,omitempty"`
+}
+
+// BulkResult is the outcome of a bulk update for one issue.
+type BulkResult struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	OK      bool   `json:"ok"`
+	Detail  string `json:"detail"`
+	// ChangeIDs are the undo journal entries of the changes made, in order.
+	ChangeIDs []int `json:"changeIds,omitempty"`
+}
+
+// BulkReport is everything a bulk update did, in the order of the search.
+type BulkReport struct {
+	JQL       string       `json:"jql"`
+	Changes   string       `json:"changes"`
+	DryRun    bool         `json:"dryRun"`
+	Truncated bool         `json:"truncated"`
+	Results   []BulkResult `json:"results"`
+}
+
+// Failed counts the issues that could not be updated.
+func (r *BulkReport) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.OK {
+			failed++
+		}
+	}
+	return failed
+}
+
+func RegisterJiraBulkTool(s *server.MCPServer, filter *Filter) {
+	jiraBulkUpdateTool := mcp.NewTool("jira_bulk_update",
+		mcp.WithDescription("Apply the same changes to every issue matching a JQL query: transition to a status, assign, add/remove labels and fix versions, and comment. Issues are updated in parallel and the result is a per-issue table of what succeeded or failed. Use dry_run first to see which issues match."),
+		mcp.WithString("jql", mcp.Required(), mcp.Description("JQL selecting the issues to change (e.g., 'sprint = 42 AND status = \"In Review\"')")),
+		mcp.WithString("to_status", mcp.Description("Move each issue to this status (e.g., 'Done'). Issues already there are left alone.")),
+		mcp.WithString("assignee", mcp.Description(assigneeParamDescription+` "none" unassigns.`)),
+		mcp.WithString("add_labels", mcp.Description("Comma-separated labels to add")),
+		mcp.WithString("remove_labels", mcp.Description("Comma-separated labels to remove")),
+		mcp.WithString("add_fix_versions", mcp.Description("Comma-separated fix version names to add")),
+		mcp.WithString("remove_fix_versions", mcp.Description("Comma-separated fix version names to remove")),
+		mcp.WithString("comment", mcp.Description("Comment to add to every issue")),
+		mcp.WithString("format", mcp.Description("Markup of the comment. "+bodyFormatParamDescription)),
+		mcp.WithNumber("max_issues", mcp.Description(fmt.Sprintf("Maximum number of matching issues to change (default %d, at most %d)", BulkDefaultMaxIssues, BulkMaxIssues))),
+		mcp.WithNumber("concurrency", mcp.Description(fmt.Sprintf("Issues updated in parallel (default %d, at most %d)", BulkDefaultConcurrency, BulkMaxConcurrency))),
+		mcp.WithBoolean("dry_run", mcp.Description("Only list the matching issues and check that each can be moved to to_status; change nothing")),
+		mcp.WithOutputSchema[BulkReport](),
+	)
+	filter.AddTool(s, jiraBulkUpdateTool, ToolWrite, mcp.NewTypedToolHandler(jiraBulkUpdateHandler))
+}
+
+func jiraBulkUpdateHandler(ctx context.Context, request mcp.CallToolRequest, input BulkUpdateInput) (*mcp.CallToolResult, error) {
+	report, err := RunBulkUpdate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(report, FormatBulkReport(report)), nil
+}
+
+// RunBulkUpdate selects issues with input.JQL and applies the requested
+// changes to each of them. Per-issue failures are recorded in the report;
+// an error is returned only when nothing could be attempted. Each change
+// made is recorded in UndoJournal, so it can be undone issue by issue.
+func RunBulkUpdate(ctx context.Context, input BulkUpdateInput) (*BulkReport, error) {
+	return runBulkUpdate(ctx, services.JiraRawClient(), services.JiraClient(), UndoJournal(), input)
+}
+
+func runBulkUpdate(ctx context.Context, client *services.RawClient, jiraClient *jira.Client, journal *ChangeJournal, input BulkUpdateInput) (*BulkReport, error) {
+	plan, err := newBulkPlan(ctx, jiraClient, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare bulk update: %v", err)
+	}
+
+	maxIssues := input.MaxIssues
+	if maxIssues <= 0 {
+		maxIssues = BulkDefaultMaxIssues
+	}
+	if maxIssues > BulkMaxIssues {
+		maxIssues = BulkMaxIssues
+	}
+	fetchPage := func(ctx context.Context, pageToken string, size int) (*util.SearchJQLResult, error) {
+		return searchIssuesJQL(ctx, client, input.JQL, []string{"summary", "status"}, nil, pageToken, size)
+	}
+	issues, nextPageToken, err := util.FetchSearchPages(ctx, fetchPage, "", maxIssues, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %v", err)
+	}
+
+	report := &BulkReport{
+		JQL:       input.JQL,
+		Changes:   plan.describe(),
+		DryRun:    input.DryRun,
+		Truncated: nextPageToken != "",
+		Results:   make([]BulkResult, len(issues)),
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = BulkDefaultConcurrency
+	}
+	if concurrency > BulkMaxConcurrency {
+		concurrency = BulkMaxConcurrency
+	}
+
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, issue := range issues {
+		result := &report.Results[i]
+		result.Key = issue.Key
+		var status string
+		if issue.Fields != nil {
+			result.Summary = issue.Fields.Summary
+			if issue.Fields.Status != nil {
+				status = issue.Fields.Status.Name
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if err := ctx.Err(); err != nil {
+				result.Detail = fmt.Sprintf("skipped: %v", err)
+				return
+			}
+			detail, err := plan.apply(ctx, client, journal, result, status, input.DryRun)
+			result.OK = err == nil
+			result.Detail = detail
+			if err != nil {
+				result.Detail = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report, nil
+}
+
+// bulkPlan holds the changes shared by every issue of a bulk update. The
+// field edits are resolved once (e.g. the assignee lookup) and reused.
+type bulkPlan struct {
+	edit     *issueEdit
+	toStatus string
+	comment  *models.CommentNodeScheme
+	steps    []string
+}
+
+func newBulkPlan(ctx context.Context, jiraClient *jira.Client, input BulkUpdateInput) (*bulkPlan, error) {
+	plan := &bulkPlan{edit: newIssueEdit(), toStatus: strings.TrimSpace(input.ToStatus)}
+	if input.Comment != "" {
+		comment, err := util.BodyToADF(input.Comment, input.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comment: %v", err)
+		}
+		plan.comment = comment
+	}
+
+	if err := plan.edit.applyAssignee(ctx, jiraClient, input.Assignee); err != nil {
+		return nil, err
+	}
+	if input.Assignee != "" {
+		plan.steps = append(plan.steps, "assign to "+input.Assignee)
+	}
+	if err := plan.edit.applyList("labels", "labels", "", input.AddLabels, input.RemoveLabels, nil); err != nil {
+		return nil, err
+	}
+	if err := plan.edit.applyList("fix_versions", "fixVersions", "", input.AddFixVersions, input.RemoveFixVersions, byName); err != nil {
+		return nil, err
+	}
+	for _, change := range []struct{ verb, what, list string }{
+		{"add", "labels", input.AddLabels},
+		{"remove", "labels", input.RemoveLabels},
+		{"add", "fix versions", input.AddFixVersions},
+		{"remove", "fix versions", input.RemoveFixVersions},
+	} {
+		if items := splitList(change.list); len(items) > 0 {
+			plan.steps = append(plan.steps, fmt.Sprintf("%s %s %s", change.verb, change.what, strings.Join(items, ", ")))
+		}
+	}
+	if plan.toStatus != "" {
+		plan.steps = append(plan.steps, "transition to "+plan.toStatus)
+	}
+	if plan.comment != nil {
+		plan.steps = append(plan.steps, "add comment")
+	}
+
+	if len(plan.steps) == 0 {
+		return nil, fmt.Errorf("nothing to do: set to_status, assignee, labels, fix versions or comment")
+	}
+	return plan, nil
+}
+
+func (p *bulkPlan) describe() string {
+	return strings.Join(p.steps, "; ")
+}
+
+// apply makes the planned changes to one issue: field edits first, then the
+// transition, then the comment. The transition is looked up before anything
+// is written, so an issue that cannot reach the target status is left as is.
+// status is the issue's current status name as returned by the search. Each
+// change is recorded in journal and its ID added to result.ChangeIDs.
+func (p *bulkPlan) apply(ctx context.Context, client *services.RawClient, journal *ChangeJournal, result *BulkResult, status string, dryRun bool) (string, error) {
+	issueKey := result.Key
+	record := func(entry ChangeEntry) {
+		entry.Tool = "jira_bulk_update"
+		entry.IssueKey = issueKey
+		if changeID := recordChangeIn(journal, entry); changeID != 0 {
+			result.ChangeIDs = append(result.ChangeIDs, changeID)
+		}
+	}
+	var done []string
+	fail := func(err error) (string, error) {
+		if len(done) > 0 {
+			return "", fmt.Errorf("%v (already done: %s)", err, strings.Join(done, ", "))
+		}
+		return "", err
+	}
+
+	var transition *workflowTransition
+	var transitionBody *issueEdit
+	if p.toStatus != "" && !strings.EqualFold(status, p.toStatus) {
+		transitions, err := fetchTransitions(ctx, client, issueKey)
+		if err != nil {
+			return fail(err)
+		}
+		if transition, err = findTransitionTo(transitions, p.toStatus); err != nil {
+			return fail(err)
+		}
+		// Bulk updates carry no per-issue screen values, so the field catalog
+		// is never consulted here.
+		edit, missing, err := transitionEdit(ctx, nil, transition, nil)
+		if err != nil {
+			return fail(err)
+		}
+		if len(missing) > 0 {
+			names := make([]string, 0, len(missing))
+			for _, field := range missing {
+				names = append(names, field.Name)
+			}
+			return fail(fmt.Errorf("transition %q requires fields: %s; use jira_transition_issue for this issue", transition.Name, strings.Join(names, ", ")))
+		}
+		transitionBody = edit
+	}
+
+	if dryRun {
+		switch {
+		case transition != nil:
+			return fmt.Sprintf("would transition via %q from %s to %s", transition.Name, status, transitionTarget(transition)), nil
+		case p.toStatus != "":
+			return fmt.Sprintf("already in %s", status), nil
+		default:
+			return "matches", nil
+		}
+	}
+
+	if fields := p.edit.body(); len(fields) > 0 {
+		path := fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(issueKey))
+		var before map[string]json.RawMessage
+		if journal != nil {
+			fieldIDs := p.edit.fieldIDs()
+			response, err := client.Do(ctx, http.MethodGet, path, url.Values{"fields": {strings.Join(fieldIDs, ",")}}, nil, nil)
+			if err != nil {
+				if response != nil {
+					return fail(fmt.Errorf("failed to snapshot issue before update: %s", response.Bytes.String()))
+				}
+				return fail(fmt.Errorf("failed to snapshot issue before update: %v", err))
+			}
+			if before, err = extractFieldValues(response.Bytes.Bytes(), fieldIDs); err != nil {
+				return fail(err)
+			}
+		}
+		response, err := client.Do(ctx, http.MethodPut, path, url.Values{"notifyUsers": {"true"}}, fields, nil)
+		if err != nil {
+			if fieldErrors := describeFieldErrors(ctx, response); fieldErrors != "" {
+				return fail(fmt.Errorf("failed to update issue: %s", fieldErrors))
+			}
+			if response != nil {
+				return fail(fmt.Errorf("failed to update issue: %s", response.Bytes.String()))
+			}
+			return fail(fmt.Errorf("failed to update issue: %v", err))
+		}
+		done = append(done, "fields updated")
+		if before != nil {
+			record(ChangeEntry{Kind: ChangeFields, Summary: "updated " + strings.Join(sortedKeys(before), ", "), Before: before})
+		}
+	}
+
+	if transition != nil {
+		if err := performTransition(ctx, client, issueKey, transition.ID, transitionBody); err != nil {
+			return fail(err)
+		}
+		target := transitionTarget(transition)
+		done = append(done, fmt.Sprintf("%s -> %s", status, target))
+		if status != "" {
+			record(ChangeEntry{Kind: ChangeTransition, Summary: fmt.Sprintf("%s -> %s", status, target), FromStatus: status, ToStatus: target})
+		}
+	} else if p.toStatus != "" {
+		done = append(done, "already in "+status)
+	}
+
+	if p.comment != nil {
+		path := fmt.Sprintf("rest/api/3/issue/%s/comment", url.PathEscape(issueKey))
+		var comment struct {
+			ID string `json:"id"`
+		}
+		response, err := client.Do(ctx, http.MethodPost, path, nil, map[string]interface{}{"body": p.comment}, &comment)
+		if err != nil {
+			if response != nil {
+				return fail(fmt.Errorf("failed to add comment: %s", response.Bytes.String()))
+			}
+			return fail(fmt.Errorf("failed to add comment: %v", err))
+		}
+		done = append(done, "commented")
+		if comment.ID != "" {
+			record(ChangeEntry{Kind: ChangeComment, Summary: "added comment " + comment.ID, CommentID: comment.ID})
+		}
+	}
+
+	return strings.Join(done, ", "), nil
+}
+
+// FormatBulkReport renders a bulk update as a summary line followed by a
+// markdown table with one row per issue.
+func FormatBulkReport(report *BulkReport) string {
+	var sb strings.Builder
+	if report.DryRun {
+		sb.WriteString(fmt.Sprintf("Dry run, no changes made. %d issue(s) match `%s`.\n", len(report.Results), report.JQL))
+	} else {
+		failed := report.Failed()
+		sb.WriteString(fmt.Sprintf("Bulk update of %d issue(s) matching `%s`: %d succeeded, %d failed.\n", len(report.Results), report.JQL, len(report.Results)-failed, failed))
+	}
+	sb.WriteString(fmt.Sprintf("Changes: %s\n", report.Changes))
+	for _, result := range report.Results {
+		if len(result.ChangeIDs) > 0 {
+			sb.WriteString("Each change is in the undo journal: jira_undo_last_change with an issue_key reverts them one issue at a time.\n")
+			break
+		}
+	}
+	if report.Truncated {
+		sb.WriteString("More issues match than were processed; raise max_issues or narrow the JQL and run again.\n")
+	}
+	if len(report.Results) == 0 {
+		sb.WriteString("\nNo issues matched.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("\n| Issue | Summary | Result | Details |\n|---|---|---|---|\n")
+	for _, result := range report.Results {
+		outcome := "ok"
+		if !result.OK {
+			outcome = "FAILED"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", result.Key, tableCell(result.Summary), outcome, tableCell(result.Detail)))
+	}
+	return sb.String()
+}
+
+// tableCell keeps a value on one markdown table row.
+func tableCell(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	return strings.ReplaceAll(value, "|", `\|`)
+}
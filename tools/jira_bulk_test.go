@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// fakeBulkServer serves a search matching B-1 (To Do), B-2 (already Done)
+// and B-3 (To Do, but with no transition to Done), and records every write.
+func fakeBulkServer(t *testing.T) (*services.RawClient, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var writes []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			encoded, _ := json.Marshal(body)
+			mu.Lock()
+			writes = append(writes, r.Method+" "+r.URL.Path+" "+string(encoded))
+			mu.Unlock()
+		}
+
+		switch {
+		case r.URL.Path == "/rest/api/3/search/jql":
+			if got := r.URL.Query().Get("jql"); got != "sprint = 42" {
+				t.Errorf("jql = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"isLast":true,"issues":[
+				{"key":"B-1","fields":{"summary":"First","status":{"name":"To Do"}}},
+				{"key":"B-2","fields":{"summary":"Second","status":{"name":"Done"}}},
+				{"key":"B-3","fields":{"summary":"Third","status":{"name":"To Do"}}}]}`))
+		case r.URL.Path == "/rest/api/3/issue/B-1/transitions" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"31","name":"Close","to":{"name":"Done"}}]}`))
+		case r.URL.Path == "/rest/api/3/issue/B-3/transitions" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"11","name":"Start","to":{"name":"In Progress"}}]}`))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/B-"):
+			_, _ = w.Write([]byte(`{"fields":{"labels":["old"]}}`))
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/comment"):
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"900"}`))
+		case r.Method == http.MethodPut, r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}, &writes
+}
+
+func TestRunBulkUpdate(t *testing.T) {
+	client, writes := fakeBulkServer(t)
+
+	report, err := runBulkUpdate(context.Background(), client, nil, nil, BulkUpdateInput{
+		JQL:       "sprint = 42",
+		ToStatus:  "Done",
+		AddLabels: "reviewed",
+		Comment:   "Closed in cleanup",
+	})
+	if err != nil {
+		t.Fatalf("runBulkUpdate: %v", err)
+	}
+
+	if len(report.Results) != 3 || report.Failed() != 1 {
+		t.Fatalf("results = %+v, want 3 with 1 failure", report.Results)
+	}
+	byKey := map[string]BulkResult{}
+	for _, result := range report.Results {
+		byKey[result.Key] = result
+	}
+	if r := byKey["B-1"]; !r.OK || r.Detail != "fields updated, To Do -> Done, commented" {
+		t.Errorf("B-1 = %+v", r)
+	}
+	if r := byKey["B-2"]; !r.OK || !strings.Contains(r.Detail, "already in Done") {
+		t.Errorf("B-2 = %+v", r)
+	}
+	if r := byKey["B-3"]; r.OK || !strings.Contains(r.Detail, `no transition leads to status "Done"`) {
+		t.Errorf("B-3 = %+v", r)
+	}
+
+	joined := strings.Join(*writes, "\n")
+	for _, want := range []string{
+		`PUT /rest/api/3/issue/B-1 {"update":{"labels":[{"add":"reviewed"}]}}`,
+		`POST /rest/api/3/issue/B-1/transitions {"transition":{"id":"31"}}`,
+		`POST /rest/api/3/issue/B-1/comment`,
+		`PUT /rest/api/3/issue/B-2 `,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing write %q in:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "B-3") {
+		t.Errorf("B-3 has no route to Done and must not be changed:\n%s", joined)
+	}
+
+	table := FormatBulkReport(report)
+	if !strings.Contains(table, "2 succeeded, 1 failed") || !strings.Contains(table, "| B-3 | Third | FAILED |") {
+		t.Errorf("unexpected report:\n%s", table)
+	}
+}
+
+func TestRunBulkUpdate_JournalsEachChange(t *testing.T) {
+	client, _ := fakeBulkServer(t)
+	journal := NewChangeJournal("", "")
+
+	report, err := runBulkUpdate(context.Background(), client, nil, journal, BulkUpdateInput{
+		JQL:       "sprint = 42",
+		ToStatus:  "Done",
+		AddLabels: "reviewed",
+		Comment:   "Closed in cleanup",
+	})
+	if err != nil {
+		t.Fatalf("runBulkUpdate: %v", err)
+	}
+
+	entries, _ := journal.Recent("B-1", 0)
+	if len(entries) != 3 {
+		t.Fatalf("B-1 entries = %+v, want fields, transition and comment", entries)
+	}
+	byKind := map[ChangeKind]ChangeEntry{}
+	for _, entry := range entries {
+		byKind[entry.Kind] = entry
+	}
+	if e := byKind[ChangeFields]; e.Tool != "jira_bulk_update" || string(e.Before["labels"]) != `["old"]` {
+		t.Errorf("fields entry = %+v", e)
+	}
+	if e := byKind[ChangeTransition]; e.FromStatus != "To Do" || e.ToStatus != "Done" {
+		t.Errorf("transition entry = %+v", e)
+	}
+	if e := byKind[ChangeComment]; e.CommentID != "900" {
+		t.Errorf("comment entry = %+v", e)
+	}
+	if ids := report.Results[0].ChangeIDs; len(ids) != 3 {
+		t.Errorf("B-1 change IDs = %v", ids)
+	}
+	if b3, _ := journal.Recent("B-3", 0); len(b3) != 0 {
+		t.Errorf("B-3 was not changed but has entries %+v", b3)
+	}
+	if !strings.Contains(FormatBulkReport(report), "jira_undo_last_change") {
+		t.Errorf("report should point at undo:\n%s", FormatBulkReport(report))
+	}
+}
+
+func TestRunBulkUpdate_DryRunWritesNothing(t *testing.T) {
+	client, writes := fakeBulkServer(t)
+
+	report, err := runBulkUpdate(context.Background(), client, nil, nil, BulkUpdateInput{JQL: "sprint = 42", ToStatus: "Done", DryRun: true})
+	if err != nil {
+		t.Fatalf("runBulkUpdate: %v", err)
+	}
+	if len(*writes) != 0 {
+		t.Errorf("dry run wrote: %v", *writes)
+	}
+	if r := report.Results[0]; !r.OK || r.Detail != `would transition via "Close" from To Do to Done` {
+		t.Errorf("B-1 = %+v", r)
+	}
+	if !strings.HasPrefix(FormatBulkReport(report), "Dry run, no changes made. 3 issue(s)") {
+		t.Errorf("unexpected report:\n%s", FormatBulkReport(report))
+	}
+}
+
+func TestRunBulkUpdate_RequiresAChange(t *testing.T) {
+	if _, err := runBulkUpdate(context.Background(), &services.RawClient{}, nil, nil, BulkUpdateInput{JQL: "sprint = 42"}); err == nil || !strings.Contains(err.Error(), "nothing to do") {
+		t.Errorf("err = %v, want a nothing-to-do error", err)
+	}
+}
+
+func TestRunBulkUpdate_CommentFormat(t *testing.T) {
+	client, writes := fakeBulkServer(t)
+
+	if _, err := runBulkUpdate(context.Background(), client, nil, nil, BulkUpdateInput{JQL: "sprint = 42", Comment: "*done* [~jdoe]", Format: "wiki"}); err != nil {
+		t.Fatalf("runBulkUpdate: %v", err)
+	}
+	joined := strings.Join(*writes, "\n")
+	for _, want := range []string{`"marks":[{"type":"strong"}],"text":"done"`, `"attrs":{"id":"jdoe","text":"@jdoe"},"type":"mention"`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %s in:\n%s", want, joined)
+		}
+	}
+
+	if _, err := runBulkUpdate(context.Background(), &services.RawClient{}, nil, nil, BulkUpdateInput{JQL: "sprint = 42", Comment: "{}", Format: "adf_json"}); err == nil || !strings.Contains(err.Error(), "invalid comment") {
+		t.Errorf("err = %v, want an invalid comment error", err)
+	}
+}
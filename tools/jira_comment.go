@@ -16,6 +16,7 @@ import (
 type AddCommentInput struct {
 	IssueKey string `json:"issue_key" validate:"required"`
 	Comment  string `json:"comment" validate:"required"`
+	Format   string `json:"format,omitempty"`
 }
 
 type GetCommentsInput struct {
@@ -25,13 +26,41 @@ type GetCommentsInput struct {
 	OrderBy     string `json:"order_by,omitempty"`
 }
 
+// CommentOutput is one comment, with its body rendered as markdown.
+type CommentOutput struct {
+	ID      string `json:"id"`
+	Author  string `json:"author"`
+	Created string `json:"created"`
+	Updated string `json:"updated,omitempty"`
+	Body    string `json:"body,omitempty"`
+}
+
+// AddCommentOutput is the structured result of jira_add_comment.
+type AddCommentOutput struct {
+	IssueKey string        `json:"issue_key"`
+	Comment  CommentOutput `json:"comment"`
+	ChangeID int           `json:"change_id,omitempty"`
+	Message  string        `json:"message"`
+}
+
+// GetCommentsOutput is the structured result of jira_get_comments.
+type GetCommentsOutput struct {
+	IssueKey  string          `json:"issue_key"`
+	Total     int             `json:"total"`
+	StartAt   int             `json:"start_at"`
+	Truncated bool            `json:"truncated"`
+	Comments  []CommentOutput `json:"comments"`
+}
+
 func RegisterJiraCommentTools(s *server.MCPServer, filter *Filter) {
 	jiraAddCommentTool := mcp.NewTool("jira_add_comment",
 		mcp.WithDescription("Add a comment to a Jira issue"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
 		mcp.WithString("comment", mcp.Required(), mcp.Description("The comment text to add to the issue")),
+		mcp.WithString("format", mcp.Description("Markup of the comment. "+bodyFormatParamDescription)),
+		mcp.WithOutputSchema[AddCommentOutput](),
 	)
-	filter.AddTool(s, jiraAddCommentTool, mcp.NewTypedToolHandler(jiraAddCommentHandler))
+	filter.AddTool(s, jiraAddCommentTool, ToolWrite, mcp.NewTypedToolHandler(jiraAddCommentHandler))
 
 	jiraGetCommentsTool := mcp.NewTool("jira_get_comments",
 		mcp.WithDescription("Retrieve comments from a Jira issue. Paginates through every comment by default — pass max_comments to cap the result or start_at to skip ahead."),
@@ -39,15 +68,20 @@ func RegisterJiraCommentTools(s *server.MCPServer, filter *Filter) {
 		mcp.WithNumber("start_at", mcp.Description("Zero-based index of the first comment to return (default 0)")),
 		mcp.WithNumber("max_comments", mcp.Description("Maximum number of comments to return across all pages. 0 (default) means return every comment on the issue.")),
 		mcp.WithString("order_by", mcp.Description("Sort order passed to Jira, e.g. 'created' or '-created' for newest-first")),
+		mcp.WithOutputSchema[GetCommentsOutput](),
 	)
-	filter.AddTool(s, jiraGetCommentsTool, mcp.NewTypedToolHandler(jiraGetCommentsHandler))
+	filter.AddTool(s, jiraGetCommentsTool, ToolRead, mcp.NewTypedToolHandler(jiraGetCommentsHandler))
 }
 
 func jiraAddCommentHandler(ctx context.Context, request mcp.CallToolRequest, input AddCommentInput) (*mcp.CallToolResult, error) {
 	client := services.JiraClient()
 
+	body, err := util.BodyToADF(input.Comment, input.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comment: %v", err)
+	}
 	commentPayload := &models.CommentPayloadScheme{
-		Body: util.MarkdownToADF(input.Comment),
+		Body: body,
 	}
 
 	comment, response, err := client.Issue.Comment.Add(ctx, input.IssueKey, commentPayload, nil)
@@ -62,8 +96,21 @@ func jiraAddCommentHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		comment.ID,
 		comment.Author.DisplayName,
 		comment.Created)
-
-	return mcp.NewToolResultText(result), nil
+	output := AddCommentOutput{
+		IssueKey: input.IssueKey,
+		Comment:  CommentOutput{ID: comment.ID, Author: comment.Author.DisplayName, Created: comment.Created, Body: util.RenderADF(body)},
+		Message:  "Comment added successfully!",
+	}
+	output.ChangeID = recordChange(ChangeEntry{
+		Tool:      "jira_add_comment",
+		IssueKey:  input.IssueKey,
+		Kind:      ChangeComment,
+		Summary:   "added comment " + comment.ID,
+		CommentID: comment.ID,
+	})
+	result += changeNote(output.ChangeID)
+
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func jiraGetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, input GetCommentsInput) (*mcp.CallToolResult, error) {
@@ -83,8 +130,9 @@ func jiraGetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, in
 
 	header := util.FormatCommentsHeader(input.IssueKey, total, len(comments), input.StartAt, truncated)
 
+	output := GetCommentsOutput{IssueKey: input.IssueKey, Total: total, StartAt: input.StartAt, Truncated: truncated, Comments: []CommentOutput{}}
 	if len(comments) == 0 {
-		return mcp.NewToolResultText(header + "\n\nNo comments found for this issue."), nil
+		return mcp.NewToolResultStructured(output, header+"\n\nNo comments found for this issue."), nil
 	}
 
 	var result strings.Builder
@@ -101,7 +149,8 @@ func jiraGetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, in
 
 		fmt.Fprintf(&result, "ID: %s\nAuthor: %s\nCreated: %s\nUpdated: %s\nBody:\n%s\n\n",
 			comment.ID, authorName, comment.Created, comment.Updated, bodyText)
+		output.Comments = append(output.Comments, CommentOutput{ID: comment.ID, Author: authorName, Created: comment.Created, Updated: comment.Updated, Body: bodyText})
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
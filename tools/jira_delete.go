@@ -0,0 +1,364 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// deleteTokenTTL is how long a confirmation token from a delete preview
+// stays valid.
+const deleteTokenTTL = 5 * time.Minute
+
+// defaultArchiveLabel is added to archived issues unless
+// JIRA_MCP_ARCHIVE_LABEL names another label.
+const defaultArchiveLabel = "archived"
+
+// DeleteIssueOutput is the structured result of jira_delete_issue. Action
+// is "preview" for the first call, then "deleted" or "archived". A preview
+// without a confirmation token is one Jira would refuse.
+type DeleteIssueOutput struct {
+	IssueKey          string                `json:"issue_key"`
+	Action            string                `json:"action"`
+	Summary           string                `json:"summary,omitempty"`
+	Subtasks          []util.IssueRefOutput `json:"subtasks,omitempty"`
+	Links             []string              `json:"links,omitempty"`
+	AttachmentCount   int                   `json:"attachment_count,omitempty"`
+	ConfirmationToken string                `json:"confirmation_token,omitempty"`
+	DeleteSubtasks    bool                  `json:"delete_subtasks,omitempty"`
+	ChangeID          int                   `json:"change_id,omitempty"`
+	Message           string                `json:"message"`
+}
+
+// deleteRequest is what a confirmation token authorises. The second call
+// must repeat it exactly, so a token from a preview of one action cannot be
+// spent on another.
+type deleteRequest struct {
+	issueKey       string
+	deleteSubtasks bool
+	archive        bool
+}
+
+func (r deleteRequest) String() string {
+	if r.archive {
+		return fmt.Sprintf("archive %s", r.issueKey)
+	}
+	if r.deleteSubtasks {
+		return fmt.Sprintf("delete %s with its subtasks", r.issueKey)
+	}
+	return fmt.Sprintf("delete %s", r.issueKey)
+}
+
+// confirmationTokens hands out single-use tokens for previewed deletes.
+type confirmationTokens struct {
+	mu      sync.Mutex
+	pending map[string]pendingDelete
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+type pendingDelete struct {
+	request deleteRequest
+	expires time.Time
+}
+
+func newConfirmationTokens(ttl time.Duration) *confirmationTokens {
+	return &confirmationTokens{pending: map[string]pendingDelete{}, ttl: ttl, now: time.Now}
+}
+
+// deleteTokens holds the tokens issued by jira_delete_issue previews. They
+// live in memory only, so a restart invalidates them.
+var deleteTokens = newConfirmationTokens(deleteTokenTTL)
+
+// issue returns a new token for request.
+func (c *confirmationTokens) issue(request deleteRequest) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %v", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+	c.pending[token] = pendingDelete{request: request, expires: c.now().Add(c.ttl)}
+	return token, nil
+}
+
+// redeem spends token on request. A token issued for a different request is
+// left in place so the caller can retry with the previewed arguments.
+func (c *confirmationTokens) redeem(token string, request deleteRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+
+	pending, ok := c.pending[token]
+	if !ok {
+		return fmt.Errorf("confirmation token is unknown, expired or already used; call jira_delete_issue without confirmation_token for a new preview")
+	}
+	if !strings.EqualFold(pending.request.issueKey, request.issueKey) || pending.request.deleteSubtasks != request.deleteSubtasks || pending.request.archive != request.archive {
+		return fmt.Errorf("confirmation token was issued to %s, not to %s; repeat the previewed arguments", pending.request, request)
+	}
+	delete(c.pending, token)
+	return nil
+}
+
+// sweep drops expired tokens. The caller holds mu.
+func (c *confirmationTokens) sweep() {
+	now := c.now()
+	for token, pending := range c.pending {
+		if now.After(pending.expires) {
+			delete(c.pending, token)
+		}
+	}
+}
+
+// archiveSettings are the status and label jira_delete_issue uses with
+// archive=true, from JIRA_MCP_ARCHIVE_STATUS and JIRA_MCP_ARCHIVE_LABEL.
+type archiveSettings struct {
+	status string
+	label  string
+}
+
+func archiveSettingsFromEnv() archiveSettings {
+	settings := archiveSettings{
+		status: strings.TrimSpace(os.Getenv("JIRA_MCP_ARCHIVE_STATUS")),
+		label:  strings.TrimSpace(os.Getenv("JIRA_MCP_ARCHIVE_LABEL")),
+	}
+	if settings.label == "" {
+		settings.label = defaultArchiveLabel
+	}
+	return settings
+}
+
+func jiraDeleteIssueHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteIssueInput) (*mcp.CallToolResult, error) {
+	return deleteIssue(ctx, services.JiraClient(), services.JiraRawClient(), deleteTokens, UndoJournal(), archiveSettingsFromEnv(), input)
+}
+
+// deleteIssue runs one phase of the two-phase delete: without a token it
+// previews the issue and issues a token; with one it deletes or archives.
+// An archive is recorded in journal, when there is one, so it can be undone.
+func deleteIssue(ctx context.Context, client *jira.Client, raw *services.RawClient, tokens *confirmationTokens, journal *ChangeJournal, archive archiveSettings, input DeleteIssueInput) (*mcp.CallToolResult, error) {
+	request := deleteRequest{issueKey: input.IssueKey, deleteSubtasks: input.DeleteSubtasks, archive: input.Archive}
+	if input.Archive && archive.status == "" {
+		return nil, fmt.Errorf("archive mode is not configured: set JIRA_MCP_ARCHIVE_STATUS to the status archived issues move to")
+	}
+
+	if input.ConfirmationToken == "" {
+		return previewDelete(ctx, client, tokens, archive, request)
+	}
+	if err := tokens.redeem(input.ConfirmationToken, request); err != nil {
+		return nil, err
+	}
+
+	if input.Archive {
+		return archiveIssue(ctx, raw, journal, archive, input.IssueKey)
+	}
+
+	response, err := client.Issue.Delete(ctx, input.IssueKey, input.DeleteSubtasks)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to delete issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to delete issue: %v", err)
+	}
+
+	output := DeleteIssueOutput{IssueKey: input.IssueKey, Action: "deleted", DeleteSubtasks: input.DeleteSubtasks}
+	if input.DeleteSubtasks {
+		output.Message = fmt.Sprintf("Issue %s and its subtasks deleted successfully!", input.IssueKey)
+	} else {
+		output.Message = fmt.Sprintf("Issue %s deleted successfully!", input.IssueKey)
+	}
+	return mcp.NewToolResultStructured(output, output.Message), nil
+}
+
+// previewDelete describes what the delete would remove and, unless Jira
+// would refuse it, issues the token for the second call.
+func previewDelete(ctx context.Context, client *jira.Client, tokens *confirmationTokens, archive archiveSettings, request deleteRequest) (*mcp.CallToolResult, error) {
+	issue, response, err := client.Issue.Get(ctx, request.issueKey, []string{"summary", "status", "issuetype", "subtasks", "issuelinks", "attachment"}, nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get issue: %v", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Preview of %s — nothing has been changed yet.\n\n", request))
+	sb.WriteString(fmt.Sprintf("Issue: %s %q", issue.Key, issue.Fields.Summary))
+	if issue.Fields.IssueType != nil && issue.Fields.Status != nil {
+		sb.WriteString(fmt.Sprintf(" (%s, %s)", issue.Fields.IssueType.Name, issue.Fields.Status.Name))
+	}
+	sb.WriteString("\n")
+
+	output := DeleteIssueOutput{
+		IssueKey:        issue.Key,
+		Action:          "preview",
+		Summary:         issue.Fields.Summary,
+		AttachmentCount: len(issue.Fields.Attachment),
+		DeleteSubtasks:  request.deleteSubtasks,
+	}
+	subtasks := issue.Fields.Subtasks
+	sb.WriteString(fmt.Sprintf("Subtasks: %d\n", len(subtasks)))
+	for _, subtask := range subtasks {
+		ref := util.NewIssueRefOutput(subtask.Key, subtask.Fields)
+		output.Subtasks = append(output.Subtasks, ref)
+		status := ""
+		if ref.Status != "" {
+			status = " [" + ref.Status + "]"
+		}
+		summary := ""
+		if subtask.Fields != nil {
+			summary = " " + ref.Summary
+		}
+		sb.WriteString(fmt.Sprintf("  - %s%s%s\n", subtask.Key, summary, status))
+	}
+	sb.WriteString(fmt.Sprintf("Links: %d\n", len(issue.Fields.IssueLinks)))
+	for _, link := range issue.Fields.IssueLinks {
+		sb.WriteString(fmt.Sprintf("  - %s\n", describeIssueLink(link)))
+		output.Links = append(output.Links, describeIssueLink(link))
+	}
+	sb.WriteString(fmt.Sprintf("Attachments: %d\n\n", len(issue.Fields.Attachment)))
+
+	switch {
+	case request.archive:
+		sb.WriteString(fmt.Sprintf("Archiving moves %s to status %q and adds the label %q; nothing is deleted, and jira_undo_last_change can reverse it.\n", issue.Key, archive.status, archive.label))
+	case len(subtasks) > 0 && !request.deleteSubtasks:
+		sb.WriteString(fmt.Sprintf("Jira will not delete an issue that has subtasks. Call jira_delete_issue again with delete_subtasks=true to preview deleting %s together with its %d subtask(s).\n", issue.Key, len(subtasks)))
+		output.Message = sb.String()
+		return mcp.NewToolResultStructured(output, output.Message), nil
+	case len(subtasks) > 0:
+		sb.WriteString(fmt.Sprintf("Deleting removes %s, its %d subtask(s), their comments, worklogs and attachments permanently. This cannot be undone.\n", issue.Key, len(subtasks)))
+	default:
+		sb.WriteString(fmt.Sprintf("Deleting removes %s, its comments, worklogs and attachments permanently. This cannot be undone.\n", issue.Key))
+	}
+
+	token, err := tokens.issue(request)
+	if err != nil {
+		return nil, err
+	}
+	sb.WriteString(fmt.Sprintf("\nConfirm with the user, then call jira_delete_issue again within %s with the same issue_key, delete_subtasks and archive values and confirmation_token=%s\n", tokens.ttl, token))
+	output.ConfirmationToken = token
+	output.Message = sb.String()
+	return mcp.NewToolResultStructured(output, output.Message), nil
+}
+
+// describeIssueLink renders a link from the point of view of the issue
+// holding it, e.g. "blocks ABC-2".
+func describeIssueLink(link *models.IssueLinkScheme) string {
+	if link.Type == nil {
+		return "linked issue"
+	}
+	if link.OutwardIssue != nil {
+		return fmt.Sprintf("%s %s", link.Type.Outward, link.OutwardIssue.Key)
+	}
+	if link.InwardIssue != nil {
+		return fmt.Sprintf("%s %s", link.Type.Inward, link.InwardIssue.Key)
+	}
+	return link.Type.Name
+}
+
+// archiveIssue labels the issue and moves it to the archive status. The
+// label goes first, since workflows often make closed issues read-only.
+// What was changed, even when the move then fails, is recorded in journal
+// as one ChangeArchive entry.
+func archiveIssue(ctx context.Context, raw *services.RawClient, journal *ChangeJournal, archive archiveSettings, issueKey string) (*mcp.CallToolResult, error) {
+	endpoint := fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(issueKey))
+	var before struct {
+		Fields struct {
+			Labels []string `json:"labels"`
+		} `json:"fields"`
+	}
+	response, err := raw.Do(ctx, http.MethodGet, endpoint, url.Values{"fields": {"labels"}}, nil, &before)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get labels: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get labels: %v", err)
+	}
+
+	edit := newIssueEdit()
+	edit.verb("labels", "add", archive.label)
+	response, err = raw.Do(ctx, http.MethodPut, endpoint, nil, edit.body(), nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to archive issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to archive issue: %v", err)
+	}
+
+	entry := ChangeEntry{Tool: "jira_delete_issue", IssueKey: issueKey, Kind: ChangeArchive, Label: archive.label}
+	for _, label := range before.Fields.Labels {
+		if label == archive.label {
+			entry.Label = ""
+		}
+	}
+	// record journals entry once the issue has moved, or failed to.
+	record := func(fromStatus, toStatus string) int {
+		entry.FromStatus, entry.ToStatus = fromStatus, toStatus
+		var parts []string
+		if entry.Label != "" {
+			parts = append(parts, fmt.Sprintf("labelled %q", entry.Label))
+		}
+		if toStatus != "" {
+			parts = append(parts, fmt.Sprintf("%s -> %s", fromStatus, toStatus))
+		}
+		if len(parts) == 0 {
+			return 0
+		}
+		entry.Summary = "archived: " + strings.Join(parts, ", ")
+		return recordChangeIn(journal, entry)
+	}
+
+	current, err := fetchIssueWorkflowContext(ctx, raw, issueKey)
+	if err != nil {
+		record("", "")
+		return nil, err
+	}
+	if strings.EqualFold(current.StatusName, archive.status) {
+		output := DeleteIssueOutput{IssueKey: issueKey, Action: "archived", ChangeID: record("", "")}
+		output.Message = fmt.Sprintf("Issue %s labelled %q; it was already in status %s.", issueKey, archive.label, current.StatusName) + changeNote(output.ChangeID)
+		return mcp.NewToolResultStructured(output, output.Message), nil
+	}
+
+	transitions, err := fetchTransitions(ctx, raw, issueKey)
+	if err != nil {
+		record("", "")
+		return nil, err
+	}
+	transition, err := findTransitionTo(transitions, archive.status)
+	if err != nil {
+		record("", "")
+		return nil, fmt.Errorf("labelled %s %q but could not archive it: %v", issueKey, archive.label, err)
+	}
+	transitionFields, missing, err := transitionEdit(ctx, nil, transition, nil)
+	if err != nil {
+		record("", "")
+		return nil, err
+	}
+	if len(missing) > 0 {
+		record("", "")
+		return missingFieldsResult(issueKey, transition, missing)
+	}
+	if err := performTransition(ctx, raw, issueKey, transition.ID, transitionFields); err != nil {
+		record("", "")
+		return nil, err
+	}
+
+	output := DeleteIssueOutput{IssueKey: issueKey, Action: "archived", ChangeID: record(current.StatusName, transitionTarget(transition))}
+	output.Message = fmt.Sprintf("Issue %s archived: labelled %q and moved from %s to %s.", issueKey, archive.label, current.StatusName, transitionTarget(transition)) + changeNote(output.ChangeID)
+	return mcp.NewToolResultStructured(output, output.Message), nil
+}
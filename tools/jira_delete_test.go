@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestConfirmationTokens(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tokens := newConfirmationTokens(time.Minute)
+	tokens.now = func() time.Time { return now }
+	request := deleteRequest{issueKey: "ABC-1"}
+
+	token, err := tokens.issue(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.redeem(token, deleteRequest{issueKey: "ABC-1", deleteSubtasks: true}); err == nil {
+		t.Errorf("a token must not authorise different options")
+	}
+	if err := tokens.redeem(token, deleteRequest{issueKey: "abc-1"}); err != nil {
+		t.Errorf("redeem: %v", err)
+	}
+	if err := tokens.redeem(token, request); err == nil {
+		t.Errorf("a token must be single-use")
+	}
+
+	token, _ = tokens.issue(request)
+	now = now.Add(2 * time.Minute)
+	if err := tokens.redeem(token, request); err == nil {
+		t.Errorf("an expired token must be refused")
+	}
+}
+
+// fakeDeleteServer serves ABC-1, a story with one subtask, one link and two
+// attachments, and records every write.
+func fakeDeleteServer(t *testing.T) (*jira.Client, *services.RawClient, *[]string) {
+	t.Helper()
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			var body any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			encoded, _ := json.Marshal(body)
+			writes = append(writes, r.Method+" "+r.URL.RequestURI()+" "+string(encoded))
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/ABC-1":
+			_, _ = w.Write([]byte(`{"key":"ABC-1","fields":{
+				"summary":"Old story","status":{"id":"3","name":"In Progress"},"issuetype":{"id":"10","name":"Story"},"project":{"id":"100"},
+				"subtasks":[{"key":"ABC-2","fields":{"summary":"Sub","status":{"name":"To Do"}}}],
+				"issuelinks":[{"type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"outwardIssue":{"key":"DEF-9"}}],
+				"attachment":[{"id":"1"},{"id":"2"}]}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/ABC-1/transitions":
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"91","name":"Archive","to":{"name":"Archived"}}]}`))
+		case r.Method == http.MethodGet:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := jira.New(srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatalf("jira.New: %v", err)
+	}
+	return client, &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}, &writes
+}
+
+var tokenPattern = regexp.MustCompile(`confirmation_token=([0-9a-f]+)`)
+
+func resultBody(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if result == nil || len(result.Content) == 0 {
+		t.Fatalf("empty result")
+	}
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestDeleteIssue_TwoPhase(t *testing.T) {
+	client, raw, writes := fakeDeleteServer(t)
+	tokens := newConfirmationTokens(time.Minute)
+	ctx := context.Background()
+
+	result, err := deleteIssue(ctx, client, raw, tokens, nil, archiveSettings{}, DeleteIssueInput{IssueKey: "ABC-1"})
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if text := resultBody(t, result); tokenPattern.MatchString(text) || !strings.Contains(text, "delete_subtasks=true") {
+		t.Errorf("an issue with subtasks should get no token without delete_subtasks:\n%s", text)
+	}
+
+	input := DeleteIssueInput{IssueKey: "ABC-1", DeleteSubtasks: true}
+	result, err = deleteIssue(ctx, client, raw, tokens, nil, archiveSettings{}, input)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	text := resultBody(t, result)
+	for _, want := range []string{"ABC-2 Sub [To Do]", "blocks DEF-9", "Attachments: 2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("preview lacks %q:\n%s", want, text)
+		}
+	}
+	match := tokenPattern.FindStringSubmatch(text)
+	if match == nil {
+		t.Fatalf("preview has no token:\n%s", text)
+	}
+	if len(*writes) != 0 {
+		t.Fatalf("the preview must not change anything, got %v", *writes)
+	}
+
+	input.ConfirmationToken = match[1]
+	if _, err := deleteIssue(ctx, client, raw, tokens, nil, archiveSettings{}, input); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if want := []string{"DELETE /rest/api/3/issue/ABC-1?deleteSubtasks=true null"}; !reflect.DeepEqual(*writes, want) {
+		t.Errorf("writes = %v, want %v", *writes, want)
+	}
+}
+
+func TestDeleteIssue_Archive(t *testing.T) {
+	client, raw, writes := fakeDeleteServer(t)
+	tokens := newConfirmationTokens(time.Minute)
+	ctx := context.Background()
+	input := DeleteIssueInput{IssueKey: "ABC-1", Archive: true}
+
+	if _, err := deleteIssue(ctx, client, raw, tokens, nil, archiveSettings{}, input); err == nil {
+		t.Fatalf("archive without a configured status must fail")
+	}
+
+	settings := archiveSettings{status: "Archived", label: "archived"}
+	journal := NewChangeJournal("", "")
+	result, err := deleteIssue(ctx, client, raw, tokens, journal, settings, input)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	match := tokenPattern.FindStringSubmatch(resultBody(t, result))
+	if match == nil {
+		t.Fatalf("archiving does not need delete_subtasks and should get a token")
+	}
+
+	input.ConfirmationToken = match[1]
+	if _, err := deleteIssue(ctx, client, raw, tokens, journal, settings, input); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	want := []string{
+		`PUT /rest/api/3/issue/ABC-1 {"update":{"labels":[{"add":"archived"}]}}`,
+		`POST /rest/api/3/issue/ABC-1/transitions {"transition":{"id":"91"}}`,
+	}
+	if !reflect.DeepEqual(*writes, want) {
+		t.Errorf("writes = %v, want %v", *writes, want)
+	}
+
+	entries, err := journal.Recent("ABC-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("the archive should be journaled once, got %+v", entries)
+	}
+	if entry := entries[0]; entry.Kind != ChangeArchive || entry.Label != "archived" || entry.FromStatus != "In Progress" || entry.ToStatus != "Archived" {
+		t.Errorf("journaled %+v", entry)
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
@@ -196,6 +199,18 @@ type RefInfo struct {
 	URI  string `json:"uri,omitempty"`
 }
 
+// DevelopmentInfoOutput is the structured result of
+// jira_get_development_information. Message or Error explain empty results.
+type DevelopmentInfoOutput struct {
+	IssueKey     string        `json:"issueKey"`
+	Message      string        `json:"message,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	Branches     []Branch      `json:"branches"`
+	PullRequests []PullRequest `json:"pullRequests"`
+	Repositories []Repository  `json:"repositories"`
+	Builds       []Build       `json:"builds"`
+}
+
 // RegisterJiraDevelopmentTool registers the jira_get_development_information tool
 func RegisterJiraDevelopmentTool(s *server.MCPServer, filter *Filter) {
 	tool := mcp.NewTool("jira_get_development_information",
@@ -211,8 +226,58 @@ func RegisterJiraDevelopmentTool(s *server.MCPServer, filter *Filter) {
 			mcp.Description("Include commits in the response (default: true)")),
 		mcp.WithBoolean("include_builds",
 			mcp.Description("Include CI/CD builds in the response (default: true)")),
+		mcp.WithOutputSchema[DevelopmentInfoOutput](),
 	)
-	filter.AddTool(s, tool, mcp.NewTypedToolHandler(jiraGetDevelopmentInfoHandler))
+	filter.AddTool(s, tool, ToolRead, mcp.NewTypedToolHandler(jiraGetDevelopmentInfoHandler))
+}
+
+// fetchDevStatusDetails calls the dev-status summary endpoint to discover
+// which (applicationType, dataType) pairs are configured for issueID, then
+// fetches the detail for each pair. It returns the collected details, the
+// number of pairs found, and the summary response so callers can react to its
+// status code. Detail requests that fail are skipped.
+func fetchDevStatusDetails(ctx context.Context, client *services.RawClient, issueID string) ([]DevStatusDetail, int, *models.ResponseScheme, error) {
+	var summaryRespBytes json.RawMessage
+	summaryQuery := url.Values{"issueId": {issueID}}
+	summaryCallResp, err := client.Do(ctx, http.MethodGet, "rest/dev-status/latest/issue/summary", summaryQuery, nil, &summaryRespBytes)
+	if err != nil {
+		return nil, 0, summaryCallResp, err
+	}
+
+	// Parse summary with gjson and extract (appType, dataType) pairs
+	parsed := gjson.ParseBytes(summaryRespBytes)
+
+	type endpointPair struct {
+		appType  string
+		dataType string
+	}
+	var endpointsToFetch []endpointPair
+
+	for _, dataType := range []string{"repository", "branch", "pullrequest", "build"} {
+		parsed.Get(fmt.Sprintf("summary.%s.byInstanceType", dataType)).ForEach(func(appType, value gjson.Result) bool {
+			endpointsToFetch = append(endpointsToFetch, endpointPair{appType.String(), dataType})
+			return true // continue iteration
+		})
+	}
+
+	var allDetails []DevStatusDetail
+	for _, ep := range endpointsToFetch {
+		detailQuery := url.Values{
+			"issueId":         {issueID},
+			"applicationType": {ep.appType},
+			"dataType":        {ep.dataType},
+		}
+		var devStatusResponse DevStatusResponse
+		if _, err := client.Do(ctx, http.MethodGet, "rest/dev-status/latest/issue/detail", detailQuery, nil, &devStatusResponse); err != nil {
+			continue
+		}
+
+		if len(devStatusResponse.Errors) == 0 {
+			allDetails = append(allDetails, devStatusResponse.Detail...)
+		}
+	}
+
+	return allDetails, len(endpointsToFetch), summaryCallResp, nil
 }
 
 // jiraGetDevelopmentInfoHandler retrieves development information for a Jira issue.
@@ -253,15 +318,8 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 		return nil, fmt.Errorf("failed to retrieve issue: %w", err)
 	}
 
-	// Step 2: Call summary endpoint to discover which application types are configured
-	summaryEndpoint := fmt.Sprintf("/rest/dev-status/latest/issue/summary?issueId=%s", issue.ID)
-	summaryReq, err := client.NewRequest(ctx, "GET", summaryEndpoint, "", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create summary request: %w", err)
-	}
-
-	var summaryRespBytes json.RawMessage
-	summaryCallResp, err := client.Call(summaryReq, &summaryRespBytes)
+	// Steps 2-3: discover configured application types, then fetch their details
+	allDetails, pairs, summaryCallResp, err := fetchDevStatusDetails(ctx, services.JiraRawClient(), issue.ID)
 	if err != nil {
 		if summaryCallResp != nil && summaryCallResp.Code == 401 {
 			return nil, fmt.Errorf("authentication failed")
@@ -275,28 +333,14 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal error response to YAML: %w", err)
 			}
-			return mcp.NewToolResultText(string(yamlBytes)), nil
+			output := emptyDevelopmentInfo(input.IssueKey)
+			output.Error = "Dev-status API endpoint not found"
+			return mcp.NewToolResultStructured(output, string(yamlBytes)), nil
 		}
 		return nil, fmt.Errorf("failed to retrieve development summary: %w", err)
 	}
 
-	// Parse summary with gjson and extract (appType, dataType) pairs
-	parsed := gjson.ParseBytes(summaryRespBytes)
-
-	type endpointPair struct {
-		appType  string
-		dataType string
-	}
-	var endpointsToFetch []endpointPair
-
-	for _, dataType := range []string{"repository", "branch", "pullrequest", "build"} {
-		parsed.Get(fmt.Sprintf("summary.%s.byInstanceType", dataType)).ForEach(func(appType, value gjson.Result) bool {
-			endpointsToFetch = append(endpointsToFetch, endpointPair{appType.String(), dataType})
-			return true // continue iteration
-		})
-	}
-
-	if len(endpointsToFetch) == 0 {
+	if pairs == 0 {
 		emptyResp := map[string]interface{}{
 			"issueKey":     input.IssueKey,
 			"message":      "No development integrations found",
@@ -305,27 +349,9 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal empty response to YAML: %w", err)
 		}
-		return mcp.NewToolResultText(string(yamlBytes)), nil
-	}
-
-	// Step 3: Call detail endpoint for each (appType, dataType) pair from summary
-	var allDetails []DevStatusDetail
-	for _, ep := range endpointsToFetch {
-		endpoint := fmt.Sprintf("/rest/dev-status/latest/issue/detail?issueId=%s&applicationType=%s&dataType=%s", issue.ID, ep.appType, ep.dataType)
-		req, err := client.NewRequest(ctx, "GET", endpoint, "", nil)
-		if err != nil {
-			continue
-		}
-
-		var devStatusResponse DevStatusResponse
-		_, err = client.Call(req, &devStatusResponse)
-		if err != nil {
-			continue
-		}
-
-		if len(devStatusResponse.Errors) == 0 {
-			allDetails = append(allDetails, devStatusResponse.Detail...)
-		}
+		output := emptyDevelopmentInfo(input.IssueKey)
+		output.Message = "No development integrations found"
+		return mcp.NewToolResultStructured(output, string(yamlBytes)), nil
 	}
 
 	// Step 4: Aggregate data from all VCS integrations
@@ -379,5 +405,22 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 		return nil, fmt.Errorf("failed to marshal result to YAML: %w", err)
 	}
 
-	return mcp.NewToolResultText(string(yamlBytes)), nil
+	output := DevelopmentInfoOutput{
+		IssueKey:     input.IssueKey,
+		Branches:     filteredBranches,
+		PullRequests: filteredPullRequests,
+		Repositories: filteredRepositories,
+		Builds:       filteredBuilds,
+	}
+	return mcp.NewToolResultStructured(output, string(yamlBytes)), nil
+}
+
+func emptyDevelopmentInfo(issueKey string) DevelopmentInfoOutput {
+	return DevelopmentInfoOutput{
+		IssueKey:     issueKey,
+		Branches:     []Branch{},
+		PullRequests: []PullRequest{},
+		Repositories: []Repository{},
+		Builds:       []Build{},
+	}
 }
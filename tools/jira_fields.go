@@ -0,0 +1,281 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// fieldsParamDescription documents the free-form `fields` object shared by
+// the create and update tools.
+const fieldsParamDescription = `Additional fields to set, as a JSON object keyed by field name or ID (e.g. {"Story Points": 5, "Team": "<team id>", "customfield_10020": 42}). Values are converted to the field's type: numbers, option values, user account IDs, dates (YYYY-MM-DD), and lists. Pass an object to send Jira's exact payload. Project, issue type and parent cannot be set here; use the dedicated arguments.`
+
+// assigneeParamDescription documents how the assignee parameter is resolved.
+const assigneeParamDescription = `Who to assign: an email address, display name, account ID, or "me".`
+
+// issueEdit collects everything a create or update call writes besides the
+// typed summary/description: plain field values ("fields") and add/remove
+// verbs ("update"). It is handed to the SDK as CustomFields, which merges
+// both keys into the request body.
+type issueEdit struct {
+	fields map[string]interface{}
+	update map[string][]map[string]interface{}
+}
+
+func newIssueEdit() *issueEdit {
+	return &issueEdit{
+		fields: map[string]interface{}{},
+		update: map[string][]map[string]interface{}{},
+	}
+}
+
+// set writes value to fieldID, replacing what is there. A nil value clears
+// the field.
+func (e *issueEdit) set(fieldID string, value interface{}) {
+	e.fields[fieldID] = value
+}
+
+// verb appends an update operation such as {"add": "backend"} for fieldID.
+func (e *issueEdit) verb(fieldID, operation string, value interface{}) {
+	e.update[fieldID] = append(e.update[fieldID], map[string]interface{}{operation: value})
+}
+
+// reservedFields are the fields that decide which project an issue lives
+// in. They are only set through their own arguments, which the project
+// policy checks; the free-form fields object could otherwise move an issue
+// past it. Each maps to the hint given instead.
+var reservedFields = map[string]string{
+	"project":   "set it with project_key",
+	"issuetype": "set it with issue_type",
+	"parent":    "create sub-tasks with jira_create_child_issue",
+}
+
+// applyFields resolves each key of fields through the field catalog and
+// coerces its value to the field's schema type. All problems are reported
+// together, one line per field, so an agent can fix them in one go.
+func (e *issueEdit) applyFields(ctx context.Context, catalog *services.FieldCatalog, fields map[string]any) error {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		if hint, ok := reservedFields[strings.ToLower(strings.TrimSpace(key))]; ok {
+			problems = append(problems, fmt.Sprintf("- %s: cannot be set through fields; %s", key, hint))
+			continue
+		}
+		field, err := catalog.Resolve(ctx, key)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("- %s: %v", key, err))
+			continue
+		}
+		if hint, ok := reservedFields[field.ID]; ok {
+			problems = append(problems, fmt.Sprintf("- %s (%s): cannot be set through fields; %s", field.Name, field.ID, hint))
+			continue
+		}
+		value, err := util.CoerceFieldValue(field, fields[key])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("- %s (%s): %v", field.Name, field.ID, err))
+			continue
+		}
+		e.set(field.ID, value)
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid fields:\n" + strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// body returns the collected edits as the "fields" and "update" members of
+// a Jira request body. Empty members are omitted.
+func (e *issueEdit) body() map[string]interface{} {
+	node := map[string]interface{}{}
+	if len(e.fields) > 0 {
+		node["fields"] = e.fields
+	}
+	if len(e.update) > 0 {
+		update := make(map[string]interface{}, len(e.update))
+		for fieldID, operations := range e.update {
+			update[fieldID] = operations
+		}
+		node["update"] = update
+	}
+	return node
+}
+
+// fieldIDs lists, sorted, every field the collected edits write or modify.
+func (e *issueEdit) fieldIDs() []string {
+	seen := make(map[string]bool, len(e.fields)+len(e.update))
+	for fieldID := range e.fields {
+		seen[fieldID] = true
+	}
+	for fieldID := range e.update {
+		seen[fieldID] = true
+	}
+	ids := make([]string, 0, len(seen))
+	for fieldID := range seen {
+		ids = append(ids, fieldID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// customFields returns the collected edits in the shape the SDK merges into
+// its payload, or nil when there is nothing to add.
+func (e *issueEdit) customFields() *models.CustomFields {
+	node := e.body()
+	if len(node) == 0 {
+		return nil
+	}
+	return &models.CustomFields{Fields: []map[string]interface{}{node}}
+}
+
+// describeFieldErrors turns a Jira 400 response into per-field messages
+// labelled with display names. It returns "" when the response has none.
+func describeFieldErrors(ctx context.Context, response *models.ResponseScheme) string {
+	if response == nil || response.Code != 400 {
+		return ""
+	}
+	names, _ := services.JiraFieldCatalog().Names(ctx)
+	return util.FormatFieldErrors(response.Bytes.Bytes(), names)
+}
+
+// issueCustomFields renders the custom fields present in a raw issue
+// document, labelled with display names. Name lookup is best effort: when
+// the field list cannot be loaded the IDs are shown instead.
+func issueCustomFields(ctx context.Context, rawIssue []byte) []util.CustomFieldValue {
+	if !strings.Contains(string(rawIssue), "customfield_") {
+		return nil
+	}
+	names, _ := services.JiraFieldCatalog().Names(ctx)
+	return util.ExtractCustomFields(rawIssue, names)
+}
+
+// clearValues are the inputs that clear a single-valued field on update.
+var clearValues = map[string]bool{"none": true, "unassigned": true, "null": true}
+
+// applyAssignee resolves who (see resolveUser) and sets the assignee. "none"
+// or "unassigned" clears it.
+func (e *issueEdit) applyAssignee(ctx context.Context, client *jira.Client, who string) error {
+	if who == "" {
+		return nil
+	}
+	if clearValues[strings.ToLower(strings.TrimSpace(who))] {
+		e.set("assignee", nil)
+		return nil
+	}
+	user, err := resolveUser(ctx, client, who)
+	if err != nil {
+		return fmt.Errorf("assignee: %v", err)
+	}
+	e.set("assignee", userPayload(user))
+	return nil
+}
+
+// applyDueDate sets the due date from a YYYY-MM-DD string; "none" clears it.
+func (e *issueEdit) applyDueDate(dueDate string) error {
+	if dueDate == "" {
+		return nil
+	}
+	if clearValues[strings.ToLower(strings.TrimSpace(dueDate))] {
+		e.set("duedate", nil)
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", dueDate); err != nil {
+		return fmt.Errorf("due_date: expected YYYY-MM-DD, got %q", dueDate)
+	}
+	e.set("duedate", dueDate)
+	return nil
+}
+
+// applyPriority sets the priority by name.
+func (e *issueEdit) applyPriority(priority string) {
+	if priority != "" {
+		e.set("priority", map[string]interface{}{"name": priority})
+	}
+}
+
+// applyEstimates sets the original and remaining estimates, given in Jira's
+// notation ("1w 2d", "4h 30m") and read with calendar. On update they are
+// edited one by one, so giving only one leaves the other as it is.
+func (e *issueEdit) applyEstimates(original, remaining string, calendar util.WorkingCalendar, update bool) error {
+	estimates := map[string]interface{}{}
+	for _, estimate := range []struct{ param, key, value string }{
+		{"original_estimate", "originalEstimate", original},
+		{"remaining_estimate", "remainingEstimate", remaining},
+	} {
+		if estimate.value == "" {
+			continue
+		}
+		minutes, err := jiraEstimate(estimate.param, estimate.value, calendar)
+		if err != nil {
+			return err
+		}
+		estimates[estimate.key] = minutes
+	}
+	if len(estimates) == 0 {
+		return nil
+	}
+	if update {
+		e.verb("timetracking", "edit", estimates)
+	} else {
+		e.set("timetracking", estimates)
+	}
+	return nil
+}
+
+// applyList handles a multi-valued field given as comma-separated names:
+// set replaces the whole list, while add and remove become "update" verbs
+// that leave the other values alone. Jira rejects a field that appears in
+// both "fields" and "update", so set cannot be combined with add/remove.
+// wrap turns a name into the item Jira expects (nil for plain strings).
+func (e *issueEdit) applyList(param, fieldID, set, add, remove string, wrap func(string) interface{}) error {
+	if wrap == nil {
+		wrap = func(name string) interface{} { return name }
+	}
+	toAdd, toRemove := splitList(add), splitList(remove)
+	if set != "" {
+		if len(toAdd) > 0 || len(toRemove) > 0 {
+			return fmt.Errorf("%s replaces the whole list and cannot be combined with add_%s/remove_%s", param, param, param)
+		}
+		items := make([]interface{}, 0)
+		for _, name := range splitList(set) {
+			items = append(items, wrap(name))
+		}
+		e.set(fieldID, items)
+		return nil
+	}
+	for _, name := range toAdd {
+		e.verb(fieldID, "add", wrap(name))
+	}
+	for _, name := range toRemove {
+		e.verb(fieldID, "remove", wrap(name))
+	}
+	return nil
+}
+
+// byName wraps a component or version name in the reference Jira expects.
+func byName(name string) interface{} {
+	return map[string]interface{}{"name": name}
+}
+
+// splitList parses a comma-separated parameter, dropping blank entries.
+func splitList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
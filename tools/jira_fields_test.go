@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+func testFieldCatalog() *services.FieldCatalog {
+	return services.NewFieldCatalog(func(ctx context.Context) ([]*models.IssueFieldScheme, error) {
+		return []*models.IssueFieldScheme{
+			{ID: "customfield_10016", Name: "Story Points", Schema: &models.IssueFieldSchemaScheme{Type: "number"}},
+			{ID: "customfield_10001", Name: "Team", Schema: &models.IssueFieldSchemaScheme{Type: "team"}},
+			{ID: "duedate", Name: "Due date", Schema: &models.IssueFieldSchemaScheme{Type: "date"}},
+		}, nil
+	})
+}
+
+func TestIssueEdit_ApplyFields(t *testing.T) {
+	edit := newIssueEdit()
+	err := edit.applyFields(context.Background(), testFieldCatalog(), map[string]any{
+		"Story Points": "8",
+		"team":         "t-1",
+		"duedate":      "2024-06-30",
+	})
+	if err != nil {
+		t.Fatalf("applyFields: %v", err)
+	}
+	want := map[string]interface{}{
+		"customfield_10016": 8.0,
+		"customfield_10001": "t-1",
+		"duedate":           "2024-06-30",
+	}
+	got := edit.customFields()
+	if len(got.Fields) != 1 || !reflect.DeepEqual(got.Fields[0]["fields"], want) {
+		t.Errorf("got %#v", got.Fields)
+	}
+}
+
+func TestIssueEdit_ApplyFieldsReportsEveryProblem(t *testing.T) {
+	err := newIssueEdit().applyFields(context.Background(), testFieldCatalog(), map[string]any{
+		"Story Points": "lots",
+		"Velocity":     1,
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"Story Points (customfield_10016): expected a number", `Velocity: unknown field "Velocity"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestIssueEdit_ApplyFieldsRejectsProjectFields(t *testing.T) {
+	catalog := services.NewFieldCatalog(func(ctx context.Context) ([]*models.IssueFieldScheme, error) {
+		return []*models.IssueFieldScheme{
+			{ID: "project", Name: "Project", Schema: &models.IssueFieldSchemaScheme{Type: "project"}},
+			{ID: "issuetype", Name: "Issue Type", Schema: &models.IssueFieldSchemaScheme{Type: "issuetype"}},
+		}, nil
+	})
+	edit := newIssueEdit()
+	err := edit.applyFields(context.Background(), catalog, map[string]any{
+		"project":    map[string]any{"key": "OTHER"},
+		"Parent":     map[string]any{"key": "OTHER-1"},
+		"Issue Type": map[string]any{"name": "Epic"},
+	})
+	if err == nil {
+		t.Fatal("expected project, parent and issue type to be refused")
+	}
+	for _, want := range []string{"project: cannot be set through fields; set it with project_key", "Parent: cannot be set through fields", "Issue Type (issuetype): cannot be set through fields; set it with issue_type"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if len(edit.fields) != 0 {
+		t.Errorf("nothing should be set, got %v", edit.fields)
+	}
+}
+
+func TestIssueEdit_EmptyHasNoCustomFields(t *testing.T) {
+	edit := newIssueEdit()
+	if err := edit.applyFields(context.Background(), testFieldCatalog(), nil); err != nil {
+		t.Fatalf("applyFields: %v", err)
+	}
+	if got := edit.customFields(); got != nil {
+		t.Errorf("got %#v, want nil", got)
+	}
+}
+
+func TestIssueEdit_ApplyList(t *testing.T) {
+	edit := newIssueEdit()
+	if err := edit.applyList("labels", "labels", "", "backend, api", "legacy", nil); err != nil {
+		t.Fatalf("applyList labels: %v", err)
+	}
+	if err := edit.applyList("components", "components", "UI,Core", "", "", byName); err != nil {
+		t.Fatalf("applyList components: %v", err)
+	}
+
+	node := edit.customFields().Fields[0]
+	wantUpdate := map[string]interface{}{
+		"labels": []map[string]interface{}{{"add": "backend"}, {"add": "api"}, {"remove": "legacy"}},
+	}
+	if !reflect.DeepEqual(node["update"], wantUpdate) {
+		t.Errorf("update = %#v", node["update"])
+	}
+	wantFields := map[string]interface{}{
+		"components": []interface{}{map[string]interface{}{"name": "UI"}, map[string]interface{}{"name": "Core"}},
+	}
+	if !reflect.DeepEqual(node["fields"], wantFields) {
+		t.Errorf("fields = %#v", node["fields"])
+	}
+}
+
+func TestIssueEdit_ApplyListRejectsSetWithVerbs(t *testing.T) {
+	err := newIssueEdit().applyList("labels", "labels", "a", "b", "", nil)
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+}
+
+func TestIssueEdit_DueDateAndPriority(t *testing.T) {
+	edit := newIssueEdit()
+	if err := edit.applyDueDate("31/12/2024"); err == nil {
+		t.Error("expected an error for a non ISO date")
+	}
+	if err := edit.applyDueDate("none"); err != nil {
+		t.Fatalf("applyDueDate: %v", err)
+	}
+	edit.applyPriority("High")
+
+	fields := edit.customFields().Fields[0]["fields"].(map[string]interface{})
+	if v, ok := fields["duedate"]; !ok || v != nil {
+		t.Errorf("duedate = %#v, want explicit null", v)
+	}
+	if !reflect.DeepEqual(fields["priority"], map[string]interface{}{"name": "High"}) {
+		t.Errorf("priority = %#v", fields["priority"])
+	}
+}
+
+func TestIssueEdit_ApplyEstimates(t *testing.T) {
+	calendar := util.WorkingCalendar{HoursPerDay: 7.5, DaysPerWeek: 5}
+
+	create := newIssueEdit()
+	if err := create.applyEstimates("1w 2d", "1.5h", calendar, false); err != nil {
+		t.Fatalf("applyEstimates: %v", err)
+	}
+	want := map[string]interface{}{"originalEstimate": "3150m", "remainingEstimate": "90m"}
+	if got := create.fields["timetracking"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("timetracking = %#v, want %#v", got, want)
+	}
+
+	update := newIssueEdit()
+	if err := update.applyEstimates("", "2d", calendar, true); err != nil {
+		t.Fatalf("applyEstimates: %v", err)
+	}
+	wantUpdate := []map[string]interface{}{{"edit": map[string]interface{}{"remainingEstimate": "900m"}}}
+	if got := update.update["timetracking"]; !reflect.DeepEqual(got, wantUpdate) || len(update.fields) != 0 {
+		t.Errorf("update = %#v, fields = %#v", got, update.fields)
+	}
+
+	if err := newIssueEdit().applyEstimates("two days", "", calendar, false); err == nil || !strings.Contains(err.Error(), "original_estimate") {
+		t.Errorf("expected an original_estimate error, got %v", err)
+	}
+}
@@ -40,8 +40,9 @@ func RegisterJiraHistoryTool(s *server.MCPServer, filter *Filter) {
 	jiraGetIssueHistoryTool := mcp.NewTool("jira_get_issue_history",
 		mcp.WithDescription("Retrieve the complete change history of a Jira issue"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		mcp.WithOutputSchema[GetIssueHistoryOutput](),
 	)
-	filter.AddTool(s, jiraGetIssueHistoryTool, mcp.NewTypedToolHandler(jiraGetIssueHistoryHandler))
+	filter.AddTool(s, jiraGetIssueHistoryTool, ToolRead, mcp.NewTypedToolHandler(jiraGetIssueHistoryHandler))
 }
 
 func jiraGetIssueHistoryHandler(ctx context.Context, request mcp.CallToolRequest, input GetIssueHistoryInput) (*mcp.CallToolResult, error) {
@@ -57,7 +58,8 @@ func jiraGetIssueHistoryHandler(ctx context.Context, request mcp.CallToolRequest
 	}
 
 	if len(issue.Changelog.Histories) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No history found for issue %s", input.IssueKey)), nil
+		output := GetIssueHistoryOutput{IssueKey: input.IssueKey, History: []HistoryEntry{}}
+		return mcp.NewToolResultStructured(output, fmt.Sprintf("No history found for issue %s", input.IssueKey)), nil
 	}
 
 	// Build structured output
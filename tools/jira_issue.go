@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -17,26 +18,55 @@ type GetIssueInput struct {
 	IssueKey string `json:"issue_key" validate:"required"`
 	Fields   string `json:"fields,omitempty"`
 	Expand   string `json:"expand,omitempty"`
+	Profile  string `json:"profile,omitempty"`
+	MaxChars int    `json:"max_chars,omitempty"`
 }
 
 type CreateIssueInput struct {
-	ProjectKey  string `json:"project_key" validate:"required"`
-	Summary     string `json:"summary" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	IssueType   string `json:"issue_type" validate:"required"`
+	ProjectKey        string         `json:"project_key" validate:"required"`
+	Summary           string         `json:"summary" validate:"required"`
+	Description       string         `json:"description" validate:"required"`
+	Format            string         `json:"format,omitempty"`
+	IssueType         string         `json:"issue_type" validate:"required"`
+	Assignee          string         `json:"assignee,omitempty"`
+	Priority          string         `json:"priority,omitempty"`
+	Labels            string         `json:"labels,omitempty"`
+	Components        string         `json:"components,omitempty"`
+	FixVersions       string         `json:"fix_versions,omitempty"`
+	DueDate           string         `json:"due_date,omitempty"`
+	Fields            map[string]any `json:"fields,omitempty"`
+	OriginalEstimate  string         `json:"original_estimate,omitempty"`
+	RemainingEstimate string         `json:"remaining_estimate,omitempty"`
 }
 
 type CreateChildIssueInput struct {
 	ParentIssueKey string `json:"parent_issue_key" validate:"required"`
 	Summary        string `json:"summary" validate:"required"`
 	Description    string `json:"description" validate:"required"`
+	Format         string `json:"format,omitempty"`
 	IssueType      string `json:"issue_type,omitempty"`
 }
 
 type UpdateIssueInput struct {
-	IssueKey    string `json:"issue_key" validate:"required"`
-	Summary     string `json:"summary,omitempty"`
-	Description string `json:"description,omitempty"`
+	IssueKey          string         `json:"issue_key" validate:"required"`
+	Summary           string         `json:"summary,omitempty"`
+	Description       string         `json:"description,omitempty"`
+	Format            string         `json:"format,omitempty"`
+	Assignee          string         `json:"assignee,omitempty"`
+	Priority          string         `json:"priority,omitempty"`
+	DueDate           string         `json:"due_date,omitempty"`
+	Labels            string         `json:"labels,omitempty"`
+	AddLabels         string         `json:"add_labels,omitempty"`
+	RemoveLabels      string         `json:"remove_labels,omitempty"`
+	Components        string         `json:"components,omitempty"`
+	AddComponents     string         `json:"add_components,omitempty"`
+	RemoveComponents  string         `json:"remove_components,omitempty"`
+	FixVersions       string         `json:"fix_versions,omitempty"`
+	AddFixVersions    string         `json:"add_fix_versions,omitempty"`
+	RemoveFixVersions string         `json:"remove_fix_versions,omitempty"`
+	Fields            map[string]any `json:"fields,omitempty"`
+	OriginalEstimate  string         `json:"original_estimate,omitempty"`
+	RemainingEstimate string         `json:"remaining_estimate,omitempty"`
 }
 
 type ListIssueTypesInput struct {
@@ -44,68 +74,159 @@ type ListIssueTypesInput struct {
 }
 
 type DeleteIssueInput struct {
-	IssueKey string `json:"issue_key" validate:"required"`
+	IssueKey          string `json:"issue_key" validate:"required"`
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+	DeleteSubtasks    bool   `json:"delete_subtasks,omitempty"`
+	Archive           bool   `json:"archive,omitempty"`
+}
+
+// CreateIssueOutput is the structured result of jira_create_issue and
+// jira_create_child_issue.
+type CreateIssueOutput struct {
+	Key     string `json:"key"`
+	ID      string `json:"id"`
+	URL     string `json:"url"`
+	Parent  string `json:"parent,omitempty"`
+	Message string `json:"message"`
+}
+
+// UpdateIssueOutput is the structured result of jira_update_issue.
+type UpdateIssueOutput struct {
+	IssueKey string `json:"issue_key"`
+	ChangeID int    `json:"change_id,omitempty"`
+	Message  string `json:"message"`
 }
 
+// IssueTypeOutput describes one issue type.
+type IssueTypeOutput struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Subtask     bool   `json:"subtask"`
+	IconURL     string `json:"icon_url,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// ListIssueTypesOutput is the structured result of jira_list_issue_types.
+type ListIssueTypesOutput struct {
+	IssueTypes []IssueTypeOutput `json:"issue_types"`
+}
+
+// profileParamDescription and maxCharsParamDescription document the render
+// parameters shared by the tools that return whole issues.
+const profileParamDescription = `How much of each issue to render: "minimal" (summary, type, status, priority, assignee, parent), "standard" (the default: adds the description, people, dates, lists, the last 3 comments, custom fields and transitions, with long text cut), "full" (everything, untruncated, including the changelog), or a comma-separated list of sections for a custom profile (e.g. "summary,status,comments").`
+
+const maxCharsParamDescription = `Character budget for the text result. Over budget, comments and the description are shortened first, then lists, and the rest is cut with a "… [N more chars]" marker. Default: no budget.`
+
+// bodyFormatParamDescription documents the markup of description and comment
+// parameters.
+const bodyFormatParamDescription = `Markup of the text: "markdown" (the default), "wiki" for Jira wiki markup (h1., {code}, ||table||, [~user]), or "adf_json" for an Atlassian Document Format document as JSON, sent unchanged.`
+
+// estimateParamDescription documents the original_estimate parameters.
+const estimateParamDescription = `Original estimate in Jira's notation (e.g., 2d, 1w 2d 3h 30m, 1.5h); days and weeks follow the instance's working hours per day and days per week. Needs time tracking on the issue's screen.`
+
 func RegisterJiraIssueTool(s *server.MCPServer, filter *Filter) {
 	jiraGetIssueTool := mcp.NewTool("jira_get_issue",
 		mcp.WithDescription("Retrieve detailed information about a specific Jira issue including its status, assignee, description, subtasks, and available transitions"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
-		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to retrieve (e.g., 'summary,status,assignee'). If not specified, all fields are returned.")),
-		mcp.WithString("expand", mcp.Description("Comma-separated list of fields to expand for additional details (e.g., 'transitions,changelog,subtasks'). Default: 'transitions,changelog'")),
+		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to retrieve (e.g., 'summary,status,assignee'). If not specified, the fields the profile renders are retrieved.")),
+		mcp.WithString("expand", mcp.Description("Comma-separated list of fields to expand for additional details (e.g., 'transitions,changelog'). If not specified, the profile decides: transitions, plus changelog for full.")),
+		mcp.WithString("profile", mcp.Description(profileParamDescription)),
+		mcp.WithNumber("max_chars", mcp.Description(maxCharsParamDescription)),
+		mcp.WithOutputSchema[util.IssueOutput](),
 	)
-	filter.AddTool(s, jiraGetIssueTool, mcp.NewTypedToolHandler(jiraGetIssueHandler))
+	filter.AddTool(s, jiraGetIssueTool, ToolRead, mcp.NewTypedToolHandler(jiraGetIssueHandler))
 
 	jiraCreateIssueTool := mcp.NewTool("jira_create_issue",
 		mcp.WithDescription("Create a new Jira issue with specified details. Returns the created issue's key, ID, and URL"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier where the issue will be created (e.g., KP, PROJ)")),
 		mcp.WithString("summary", mcp.Required(), mcp.Description("Brief title or headline of the issue")),
 		mcp.WithString("description", mcp.Required(), mcp.Description("Detailed explanation of the issue")),
+		mcp.WithString("format", mcp.Description("Markup of the description. "+bodyFormatParamDescription)),
 		mcp.WithString("issue_type", mcp.Required(), mcp.Description("Type of issue to create (common types: Bug, Task, Subtask, Story, Epic)")),
+		mcp.WithString("assignee", mcp.Description(assigneeParamDescription)),
+		mcp.WithString("priority", mcp.Description("Priority name (e.g., High, Medium, Low)")),
+		mcp.WithString("labels", mcp.Description("Comma-separated labels")),
+		mcp.WithString("components", mcp.Description("Comma-separated component names")),
+		mcp.WithString("fix_versions", mcp.Description("Comma-separated fix version names")),
+		mcp.WithString("due_date", mcp.Description("Due date in YYYY-MM-DD format")),
+		mcp.WithString("original_estimate", mcp.Description(estimateParamDescription)),
+		mcp.WithString("remaining_estimate", mcp.Description("Remaining estimate, in the same notation (defaults to the original estimate)")),
+		mcp.WithObject("fields", mcp.Description(fieldsParamDescription)),
+		mcp.WithOutputSchema[CreateIssueOutput](),
 	)
-	filter.AddTool(s, jiraCreateIssueTool, mcp.NewTypedToolHandler(jiraCreateIssueHandler))
+	filter.AddTool(s, jiraCreateIssueTool, ToolWrite, mcp.NewTypedToolHandler(jiraCreateIssueHandler))
 
 	jiraCreateChildIssueTool := mcp.NewTool("jira_create_child_issue",
 		mcp.WithDescription("Create a child issue (sub-task) linked to a parent issue in Jira. Returns the created issue's key, ID, and URL"),
 		mcp.WithString("parent_issue_key", mcp.Required(), mcp.Description("The parent issue key to which this child issue will be linked (e.g., KP-2)")),
 		mcp.WithString("summary", mcp.Required(), mcp.Description("Brief title or headline of the child issue")),
 		mcp.WithString("description", mcp.Required(), mcp.Description("Detailed explanation of the child issue")),
+		mcp.WithString("format", mcp.Description("Markup of the description. "+bodyFormatParamDescription)),
 		mcp.WithString("issue_type", mcp.Description("Type of child issue to create (defaults to 'Subtask' if not specified)")),
+		mcp.WithOutputSchema[CreateIssueOutput](),
 	)
-	filter.AddTool(s, jiraCreateChildIssueTool, mcp.NewTypedToolHandler(jiraCreateChildIssueHandler))
+	filter.AddTool(s, jiraCreateChildIssueTool, ToolWrite, mcp.NewTypedToolHandler(jiraCreateChildIssueHandler))
 
 	jiraUpdateIssueTool := mcp.NewTool("jira_update_issue",
 		mcp.WithDescription("Modify an existing Jira issue's details. Supports partial updates - only specified fields will be changed"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the issue to update (e.g., KP-2)")),
 		mcp.WithString("summary", mcp.Description("New title for the issue (optional)")),
 		mcp.WithString("description", mcp.Description("New description for the issue (optional)")),
+		mcp.WithString("format", mcp.Description("Markup of the description. "+bodyFormatParamDescription)),
+		mcp.WithString("assignee", mcp.Description(assigneeParamDescription+` Use "none" to unassign.`)),
+		mcp.WithString("priority", mcp.Description("New priority name (e.g., High, Medium, Low)")),
+		mcp.WithString("due_date", mcp.Description(`New due date in YYYY-MM-DD format, or "none" to clear it`)),
+		mcp.WithString("original_estimate", mcp.Description(estimateParamDescription)),
+		mcp.WithString("remaining_estimate", mcp.Description("New remaining estimate, in the same notation")),
+		mcp.WithString("labels", mcp.Description("Comma-separated labels that replace the current ones")),
+		mcp.WithString("add_labels", mcp.Description("Comma-separated labels to add, keeping the existing ones")),
+		mcp.WithString("remove_labels", mcp.Description("Comma-separated labels to remove")),
+		mcp.WithString("components", mcp.Description("Comma-separated component names that replace the current ones")),
+		mcp.WithString("add_components", mcp.Description("Comma-separated component names to add")),
+		mcp.WithString("remove_components", mcp.Description("Comma-separated component names to remove")),
+		mcp.WithString("fix_versions", mcp.Description("Comma-separated fix version names that replace the current ones")),
+		mcp.WithString("add_fix_versions", mcp.Description("Comma-separated fix version names to add")),
+		mcp.WithString("remove_fix_versions", mcp.Description("Comma-separated fix version names to remove")),
+		mcp.WithObject("fields", mcp.Description(fieldsParamDescription)),
+		mcp.WithOutputSchema[UpdateIssueOutput](),
 	)
-	filter.AddTool(s, jiraUpdateIssueTool, mcp.NewTypedToolHandler(jiraUpdateIssueHandler))
+	filter.AddTool(s, jiraUpdateIssueTool, ToolWrite, mcp.NewTypedToolHandler(jiraUpdateIssueHandler))
 
 	jiraListIssueTypesTool := mcp.NewTool("jira_list_issue_types",
 		mcp.WithDescription("List all available issue types in a Jira project with their IDs, names, descriptions, and other attributes"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier to list issue types for (e.g., KP, PROJ)")),
+		mcp.WithOutputSchema[ListIssueTypesOutput](),
 	)
-	filter.AddTool(s, jiraListIssueTypesTool, mcp.NewTypedToolHandler(jiraListIssueTypesHandler))
+	filter.AddTool(s, jiraListIssueTypesTool, ToolRead, mcp.NewTypedToolHandler(jiraListIssueTypesHandler))
 
 	jiraDeleteIssueTool := mcp.NewTool("jira_delete_issue",
-		mcp.WithDescription("Delete a Jira issue permanently. This action cannot be undone."),
+		mcp.WithDescription("Delete a Jira issue permanently, in two calls. The first call changes nothing: it previews the issue (summary, subtasks, links, attachment count) and returns a confirmation token valid for a few minutes. Show the preview to the user; only a second call with the same arguments and that confirmation_token deletes. Set archive to label the issue and move it to the configured archive status instead of deleting it."),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the issue to delete (e.g., SHTP-6216, PROJ-123)")),
+		mcp.WithString("confirmation_token", mcp.Description("Token from the preview returned by the first call. Omit to get the preview.")),
+		mcp.WithBoolean("delete_subtasks", mcp.Description("Also delete the issue's subtasks. Jira refuses to delete an issue with subtasks unless this is true.")),
+		mcp.WithBoolean("archive", mcp.Description("Archive instead of deleting: add the archive label and transition to the archive status (needs JIRA_MCP_ARCHIVE_STATUS on the server)")),
+		mcp.WithOutputSchema[DeleteIssueOutput](),
 	)
-	filter.AddTool(s, jiraDeleteIssueTool, mcp.NewTypedToolHandler(jiraDeleteIssueHandler))
+	filter.AddTool(s, jiraDeleteIssueTool, ToolDestructive, mcp.NewTypedToolHandler(jiraDeleteIssueHandler))
 }
 
 func jiraGetIssueHandler(ctx context.Context, request mcp.CallToolRequest, input GetIssueInput) (*mcp.CallToolResult, error) {
 	client := services.JiraClient()
 
+	profile, err := util.ParseRenderProfile(input.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile: %v", err)
+	}
+
 	// Parse fields parameter
-	var fields []string
+	fields := profile.JiraFields()
 	if input.Fields != "" {
 		fields = strings.Split(strings.ReplaceAll(input.Fields, " ", ""), ",")
 	}
 
-	// Parse expand parameter with default values
-	expand := []string{"transitions", "changelog", "subtasks", "description"}
+	// Parse expand parameter, defaulting to what the profile renders
+	expand := profile.Expand()
 	if input.Expand != "" {
 		expand = strings.Split(strings.ReplaceAll(input.Expand, " ", ""), ",")
 	}
@@ -118,26 +239,60 @@ func jiraGetIssueHandler(ctx context.Context, request mcp.CallToolRequest, input
 		return nil, fmt.Errorf("failed to get issue: %v", err)
 	}
 
-	// Use the new util function to format the issue
-	formattedIssue := util.FormatJiraIssue(issue)
+	customFields := issueCustomFields(ctx, response.Bytes.Bytes())
+	formattedIssue := util.RenderIssueBudget(issue, profile, input.MaxChars, customFields...)
 
-	return mcp.NewToolResultText(formattedIssue), nil
+	return mcp.NewToolResultStructured(profile.IssueOutput(issue, customFields...), formattedIssue), nil
 }
 
 func jiraCreateIssueHandler(ctx context.Context, request mcp.CallToolRequest, input CreateIssueInput) (*mcp.CallToolResult, error) {
 	client := services.JiraClient()
 
+	description, err := util.BodyToADF(input.Description, input.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid description: %v", err)
+	}
+
 	var payload = models.IssueScheme{
 		Fields: &models.IssueFieldsScheme{
 			Summary:     input.Summary,
 			Project:     &models.ProjectScheme{Key: input.ProjectKey},
-			Description: util.MarkdownToADF(input.Description),
+			Description: description,
 			IssueType:   &models.IssueTypeScheme{Name: input.IssueType},
 		},
 	}
 
-	issue, response, err := client.Issue.Create(ctx, &payload, nil)
+	edit := newIssueEdit()
+	if err := edit.applyFields(ctx, services.JiraFieldCatalog(), input.Fields); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %v", err)
+	}
+	if err := edit.applyAssignee(ctx, client, input.Assignee); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %v", err)
+	}
+	if err := edit.applyDueDate(input.DueDate); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %v", err)
+	}
+	if input.OriginalEstimate != "" || input.RemainingEstimate != "" {
+		if err := edit.applyEstimates(input.OriginalEstimate, input.RemainingEstimate, services.JiraWorkingCalendar(ctx), false); err != nil {
+			return nil, fmt.Errorf("failed to create issue: %v", err)
+		}
+	}
+	edit.applyPriority(input.Priority)
+	if err := edit.applyList("labels", "labels", input.Labels, "", "", nil); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %v", err)
+	}
+	if err := edit.applyList("components", "components", input.Components, "", "", byName); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %v", err)
+	}
+	if err := edit.applyList("fix_versions", "fixVersions", input.FixVersions, "", "", byName); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %v", err)
+	}
+
+	issue, response, err := client.Issue.Create(ctx, &payload, edit.customFields())
 	if err != nil {
+		if fieldErrors := describeFieldErrors(ctx, response); fieldErrors != "" {
+			return nil, fmt.Errorf("failed to create issue: %s (endpoint: %s)", fieldErrors, response.Endpoint)
+		}
 		if response != nil {
 			return nil, fmt.Errorf("failed to create issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
 		}
@@ -145,12 +300,18 @@ func jiraCreateIssueHandler(ctx context.Context, request mcp.CallToolRequest, in
 	}
 
 	result := fmt.Sprintf("Issue created successfully!\nKey: %s\nID: %s\nURL: %s", issue.Key, issue.ID, issue.Self)
-	return mcp.NewToolResultText(result), nil
+	output := CreateIssueOutput{Key: issue.Key, ID: issue.ID, URL: issue.Self, Message: "Issue created successfully!"}
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func jiraCreateChildIssueHandler(ctx context.Context, request mcp.CallToolRequest, input CreateChildIssueInput) (*mcp.CallToolResult, error) {
 	client := services.JiraClient()
 
+	description, err := util.BodyToADF(input.Description, input.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid description: %v", err)
+	}
+
 	// Get the parent issue to retrieve its project
 	parentIssue, response, err := client.Issue.Get(ctx, input.ParentIssueKey, nil, nil)
 	if err != nil {
@@ -170,7 +331,7 @@ func jiraCreateChildIssueHandler(ctx context.Context, request mcp.CallToolReques
 		Fields: &models.IssueFieldsScheme{
 			Summary:     input.Summary,
 			Project:     &models.ProjectScheme{Key: parentIssue.Fields.Project.Key},
-			Description: util.MarkdownToADF(input.Description),
+			Description: description,
 			IssueType:   &models.IssueTypeScheme{Name: issueType},
 			Parent:      &models.ParentScheme{Key: input.ParentIssueKey},
 		},
@@ -187,10 +348,13 @@ func jiraCreateChildIssueHandler(ctx context.Context, request mcp.CallToolReques
 	result := fmt.Sprintf("Child issue created successfully!\nKey: %s\nID: %s\nURL: %s\nParent: %s", 
 		issue.Key, issue.ID, issue.Self, input.ParentIssueKey)
 
+	output := CreateIssueOutput{Key: issue.Key, ID: issue.ID, URL: issue.Self, Parent: input.ParentIssueKey, Message: "Child issue created successfully!"}
 	if issueType == "Bug" {
-		result += "\n\nA bug should be linked to a Story or Task. Next step should be to create relationship between the bug and the story or task."
+		hint := "A bug should be linked to a Story or Task. Next step should be to create relationship between the bug and the story or task."
+		result += "\n\n" + hint
+		output.Message += " " + hint
 	}
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func jiraUpdateIssueHandler(ctx context.Context, request mcp.CallToolRequest, input UpdateIssueInput) (*mcp.CallToolResult, error) {
@@ -205,18 +369,79 @@ func jiraUpdateIssueHandler(ctx context.Context, request mcp.CallToolRequest, in
 	}
 
 	if input.Description != "" {
-		payload.Fields.Description = util.MarkdownToADF(input.Description)
+		description, err := util.BodyToADF(input.Description, input.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid description: %v", err)
+		}
+		payload.Fields.Description = description
 	}
 
-	response, err := client.Issue.Update(ctx, input.IssueKey, true, payload, nil, nil)
+	edit := newIssueEdit()
+	if err := edit.applyFields(ctx, services.JiraFieldCatalog(), input.Fields); err != nil {
+		return nil, fmt.Errorf("failed to update issue: %v", err)
+	}
+	if err := edit.applyAssignee(ctx, client, input.Assignee); err != nil {
+		return nil, fmt.Errorf("failed to update issue: %v", err)
+	}
+	if err := edit.applyDueDate(input.DueDate); err != nil {
+		return nil, fmt.Errorf("failed to update issue: %v", err)
+	}
+	if input.OriginalEstimate != "" || input.RemainingEstimate != "" {
+		if err := edit.applyEstimates(input.OriginalEstimate, input.RemainingEstimate, services.JiraWorkingCalendar(ctx), true); err != nil {
+			return nil, fmt.Errorf("failed to update issue: %v", err)
+		}
+	}
+	edit.applyPriority(input.Priority)
+	if err := edit.applyList("labels", "labels", input.Labels, input.AddLabels, input.RemoveLabels, nil); err != nil {
+		return nil, fmt.Errorf("failed to update issue: %v", err)
+	}
+	if err := edit.applyList("components", "components", input.Components, input.AddComponents, input.RemoveComponents, byName); err != nil {
+		return nil, fmt.Errorf("failed to update issue: %v", err)
+	}
+	if err := edit.applyList("fix_versions", "fixVersions", input.FixVersions, input.AddFixVersions, input.RemoveFixVersions, byName); err != nil {
+		return nil, fmt.Errorf("failed to update issue: %v", err)
+	}
+
+	// Snapshot what is about to be overwritten so jira_undo_last_change can
+	// put it back.
+	fieldIDs := edit.fieldIDs()
+	if input.Summary != "" {
+		fieldIDs = append(fieldIDs, "summary")
+	}
+	if input.Description != "" {
+		fieldIDs = append(fieldIDs, "description")
+	}
+	var before map[string]json.RawMessage
+	if UndoJournal() != nil && len(fieldIDs) > 0 {
+		snapshot, err := snapshotFields(ctx, client, input.IssueKey, fieldIDs)
+		if err != nil {
+			return nil, err
+		}
+		before = snapshot
+	}
+
+	response, err := client.Issue.Update(ctx, input.IssueKey, true, payload, edit.customFields(), nil)
 	if err != nil {
+		if fieldErrors := describeFieldErrors(ctx, response); fieldErrors != "" {
+			return nil, fmt.Errorf("failed to update issue: %s (endpoint: %s)", fieldErrors, response.Endpoint)
+		}
 		if response != nil {
 			return nil, fmt.Errorf("failed to update issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
 		}
 		return nil, fmt.Errorf("failed to update issue: %v", err)
 	}
 
-	return mcp.NewToolResultText("Issue updated successfully!"), nil
+	output := UpdateIssueOutput{IssueKey: input.IssueKey, Message: "Issue updated successfully!"}
+	if before != nil {
+		output.ChangeID = recordChange(ChangeEntry{
+			Tool:     "jira_update_issue",
+			IssueKey: input.IssueKey,
+			Kind:     ChangeFields,
+			Summary:  "updated " + strings.Join(sortedKeys(before), ", "),
+			Before:   before,
+		})
+	}
+	return mcp.NewToolResultStructured(output, output.Message+changeNote(output.ChangeID)), nil
 }
 
 func jiraListIssueTypesHandler(ctx context.Context, request mcp.CallToolRequest, input ListIssueTypesInput) (*mcp.CallToolResult, error) {
@@ -230,8 +455,9 @@ func jiraListIssueTypesHandler(ctx context.Context, request mcp.CallToolRequest,
 		return nil, fmt.Errorf("failed to get issue types: %v", err)
 	}
 
+	output := ListIssueTypesOutput{IssueTypes: []IssueTypeOutput{}}
 	if len(issueTypes) == 0 {
-		return mcp.NewToolResultText("No issue types found for this project."), nil
+		return mcp.NewToolResultStructured(output, "No issue types found for this project."), nil
 	}
 
 	var result strings.Builder
@@ -254,21 +480,13 @@ func jiraListIssueTypesHandler(ctx context.Context, request mcp.CallToolRequest,
 			result.WriteString(fmt.Sprintf("Scope: %s\n", issueType.Scope.Type))
 		}
 		result.WriteString("\n")
-	}
 
-	return mcp.NewToolResultText(result.String()), nil
-}
-
-func jiraDeleteIssueHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
-
-	response, err := client.Issue.Delete(ctx, input.IssueKey, false)
-	if err != nil {
-		if response != nil {
-			return nil, fmt.Errorf("failed to delete issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		converted := IssueTypeOutput{ID: issueType.ID, Name: issueType.Name, Description: issueType.Description, Subtask: issueType.Subtask, IconURL: issueType.IconURL}
+		if issueType.Scope != nil {
+			converted.Scope = issueType.Scope.Type
 		}
-		return nil, fmt.Errorf("failed to delete issue: %v", err)
+		output.IssueTypes = append(output.IssueTypes, converted)
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Issue %s deleted successfully!", input.IssueKey)), nil
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// authCases covers both credential styles every hand-rolled request must
+// support: a PAT (Server/DC) sent as Bearer, and email/token sent as Basic.
+var authCases = []struct {
+	name   string
+	client services.RawClient
+	want   string
+}{
+	{"pat", services.RawClient{PAT: "pat"}, "Bearer pat"},
+	{"basic", services.RawClient{Mail: "a@b.c", Token: "tkn"}, "Basic " + base64.StdEncoding.EncodeToString([]byte("a@b.c:tkn"))},
+}
+
+// newAuthCheckingClient points a copy of base at a test server that fails
+// the test whenever a request arrives without the expected Authorization.
+func newAuthCheckingClient(t *testing.T, base services.RawClient, want string, handler http.HandlerFunc) *services.RawClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != want {
+			t.Errorf("%s %s: Authorization = %q, want %q", r.Method, r.URL.Path, got, want)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := base
+	client.Host = srv.URL
+	client.HTTP = srv.Client()
+	return &client
+}
+
+func TestSearchIssuesJQL_Auth(t *testing.T) {
+	for _, tt := range authCases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newAuthCheckingClient(t, tt.client, tt.want, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/rest/api/3/search/jql" || r.URL.Query().Get("jql") != "project = X" {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				_, _ = w.Write([]byte(`{"issues":[{"key":"X-1"}],"isLast":true}`))
+			})
+
+			page, err := searchIssuesJQL(context.Background(), client, "project = X", nil, nil, "", 10)
+			if err != nil {
+				t.Fatalf("searchIssuesJQL: %v", err)
+			}
+			if len(page.Issues) != 1 || page.Issues[0].Key != "X-1" {
+				t.Errorf("issues = %+v", page.Issues)
+			}
+		})
+	}
+}
+
+func TestApproximateIssueCount_Auth(t *testing.T) {
+	for _, tt := range authCases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newAuthCheckingClient(t, tt.client, tt.want, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"count":12}`))
+			})
+
+			if got := approximateIssueCount(context.Background(), client, "project = X"); got != 12 {
+				t.Errorf("approximateIssueCount = %d, want 12", got)
+			}
+		})
+	}
+}
+
+func TestFetchDevStatusDetails_Auth(t *testing.T) {
+	for _, tt := range authCases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newAuthCheckingClient(t, tt.client, tt.want, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/rest/dev-status/latest/issue/summary":
+					_, _ = w.Write([]byte(`{"summary":{"branch":{"byInstanceType":{"GitHub":{"count":1}}}}}`))
+				case "/rest/dev-status/latest/issue/detail":
+					q := r.URL.Query()
+					if q.Get("issueId") != "10001" || q.Get("applicationType") != "GitHub" || q.Get("dataType") != "branch" {
+						t.Errorf("unexpected detail query %v", q)
+					}
+					_, _ = w.Write([]byte(`{"errors":[],"detail":[{"branches":[{"name":"feature/x"}]}]}`))
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			})
+
+			details, pairs, _, err := fetchDevStatusDetails(context.Background(), client, "10001")
+			if err != nil {
+				t.Fatalf("fetchDevStatusDetails: %v", err)
+			}
+			if pairs != 1 || len(details) != 1 || len(details[0].Branches) != 1 {
+				t.Errorf("pairs = %d, details = %+v", pairs, details)
+			}
+		})
+	}
+}
+
+func TestFetchDevStatusDetails_SummaryStatus(t *testing.T) {
+	client := newAuthCheckingClient(t, services.RawClient{PAT: "pat"}, "Bearer pat", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, response, err := fetchDevStatusDetails(context.Background(), client, "10001")
+	if err == nil {
+		t.Fatal("expected an error for a 404 summary")
+	}
+	if response == nil || response.Code != http.StatusNotFound {
+		t.Errorf("response = %+v, want code 404", response)
+	}
+}
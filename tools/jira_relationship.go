@@ -22,14 +22,32 @@ type LinkIssuesInput struct {
 	OutwardIssue string `json:"outward_issue" validate:"required"`
 	LinkType     string `json:"link_type" validate:"required"`
 	Comment      string `json:"comment,omitempty"`
+	Format       string `json:"format,omitempty"`
+}
+
+// RelatedIssuesOutput is the structured result of jira_get_related_issues.
+type RelatedIssuesOutput struct {
+	IssueKey string                 `json:"issue_key"`
+	Links    []util.IssueLinkOutput `json:"links"`
+}
+
+// LinkIssuesOutput is the structured result of jira_link_issues.
+type LinkIssuesOutput struct {
+	InwardIssue  string `json:"inward_issue"`
+	OutwardIssue string `json:"outward_issue"`
+	LinkType     string `json:"link_type"`
+	LinkID       string `json:"link_id,omitempty"`
+	ChangeID     int    `json:"change_id,omitempty"`
+	Message      string `json:"message"`
 }
 
 func RegisterJiraRelationshipTool(s *server.MCPServer, filter *Filter) {
 	jiraRelationshipTool := mcp.NewTool("jira_get_related_issues",
 		mcp.WithDescription("Retrieve issues that have a relationship with a given issue, such as blocks, is blocked by, relates to, etc."),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		mcp.WithOutputSchema[RelatedIssuesOutput](),
 	)
-	filter.AddTool(s, jiraRelationshipTool, mcp.NewTypedToolHandler(jiraRelationshipHandler))
+	filter.AddTool(s, jiraRelationshipTool, ToolRead, mcp.NewTypedToolHandler(jiraRelationshipHandler))
 
 	jiraLinkTool := mcp.NewTool("jira_link_issues",
 		mcp.WithDescription("Create a link between two Jira issues, defining their relationship (e.g., blocks, duplicates, relates to)"),
@@ -37,8 +55,10 @@ func RegisterJiraRelationshipTool(s *server.MCPServer, filter *Filter) {
 		mcp.WithString("outward_issue", mcp.Required(), mcp.Description("The key of the outward issue (e.g., KP-2, PROJ-123)")),
 		mcp.WithString("link_type", mcp.Required(), mcp.Description("The type of link between issues (e.g., Duplicate, Blocks, Relates)")),
 		mcp.WithString("comment", mcp.Description("Optional comment to add when creating the link")),
+		mcp.WithString("format", mcp.Description("Markup of the comment. "+bodyFormatParamDescription)),
+		mcp.WithOutputSchema[LinkIssuesOutput](),
 	)
-	filter.AddTool(s, jiraLinkTool, mcp.NewTypedToolHandler(jiraLinkHandler))
+	filter.AddTool(s, jiraLinkTool, ToolWrite, mcp.NewTypedToolHandler(jiraLinkHandler))
 }
 
 func jiraRelationshipHandler(ctx context.Context, request mcp.CallToolRequest, input GetRelatedIssuesInput) (*mcp.CallToolResult, error) {
@@ -53,8 +73,9 @@ func jiraRelationshipHandler(ctx context.Context, request mcp.CallToolRequest, i
 		return nil, fmt.Errorf("failed to get issue: %v", err)
 	}
 
+	output := RelatedIssuesOutput{IssueKey: input.IssueKey, Links: []util.IssueLinkOutput{}}
 	if issue.Fields.IssueLinks == nil || len(issue.Fields.IssueLinks) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("Issue %s has no linked issues.", input.IssueKey)), nil
+		return mcp.NewToolResultStructured(output, fmt.Sprintf("Issue %s has no linked issues.", input.IssueKey)), nil
 	}
 
 	var sb strings.Builder
@@ -99,9 +120,15 @@ func jiraRelationshipHandler(ctx context.Context, request mcp.CallToolRequest, i
 		sb.WriteString(fmt.Sprintf("Summary: %s\n", summary))
 		sb.WriteString(fmt.Sprintf("Status: %s\n", status))
 		sb.WriteString("\n")
+
+		output.Links = append(output.Links, util.IssueLinkOutput{
+			ID:       link.ID,
+			Relation: relationshipType,
+			Issue:    util.IssueRefOutput{Key: relatedIssue, Summary: summary, Status: status},
+		})
 	}
 
-	return mcp.NewToolResultText(sb.String()), nil
+	return mcp.NewToolResultStructured(output, sb.String()), nil
 } 
 
 
@@ -123,8 +150,12 @@ func jiraLinkHandler(ctx context.Context, request mcp.CallToolRequest, input Lin
 
 	// Add comment if provided
 	if input.Comment != "" {
+		body, err := util.BodyToADF(input.Comment, input.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comment: %v", err)
+		}
 		payload.Comment = &models.CommentPayloadScheme{
-			Body: util.MarkdownToADF(input.Comment),
+			Body: body,
 		}
 	}
 
@@ -137,5 +168,19 @@ func jiraLinkHandler(ctx context.Context, request mcp.CallToolRequest, input Lin
 		return nil, fmt.Errorf("failed to link issues: %v", err)
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully linked issues %s and %s with link type \"%s\"", input.InwardIssue, input.OutwardIssue, input.LinkType)), nil
+	result := fmt.Sprintf("Successfully linked issues %s and %s with link type \"%s\"", input.InwardIssue, input.OutwardIssue, input.LinkType)
+	output := LinkIssuesOutput{InwardIssue: input.InwardIssue, OutwardIssue: input.OutwardIssue, LinkType: input.LinkType, Message: result}
+	if linkID := createdLinkID(response); linkID != "" {
+		output.LinkID = linkID
+		output.ChangeID = recordChange(ChangeEntry{
+			Tool:     "jira_link_issues",
+			IssueKey: input.InwardIssue,
+			Kind:     ChangeLink,
+			Summary:  fmt.Sprintf("linked %s to %s (%s, link %s)", input.InwardIssue, input.OutwardIssue, input.LinkType, linkID),
+			LinkID:   linkID,
+		})
+		result += changeNote(output.ChangeID)
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
 } 
\ No newline at end of file
@@ -0,0 +1,300 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Resource URI templates. Clients fill them in to attach Jira data as
+// context without a tool call.
+const (
+	issueResourceTemplate   = "jira://issue/{key}"
+	projectResourceTemplate = "jira://project/{key}"
+	sprintResourceTemplate  = "jira://sprint/{id}"
+	backlogResourceTemplate = "jira://board/{id}/backlog"
+	filterResourceTemplate  = "jira://filter/{id}"
+)
+
+// resourceIssueLimit caps the issues listed by the sprint, backlog and
+// filter resources.
+const resourceIssueLimit = 100
+
+// resourceIssueFields are the fields fetched for issue lists, enough for
+// util.FormatJiraIssueCompact.
+var resourceIssueFields = []string{"summary", "status", "assignee", "priority"}
+
+// RegisterJiraResources registers the jira:// resource templates. Reads go
+// through the filter's project policy like read tools do; JIRA_MCP_MODE and
+// the tool lists do not apply, since resources never change Jira.
+func RegisterJiraResources(s *server.MCPServer, filter *Filter) {
+	s.AddResourceTemplate(mcp.NewResourceTemplate(issueResourceTemplate, "Jira issue",
+		mcp.WithTemplateDescription("A Jira issue with its status, people, dates, description, subtasks, links, custom fields and available transitions. Subscribe to be notified when it changes."),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), resourceHandler(filter, readIssueResource))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(projectResourceTemplate, "Jira project",
+		mcp.WithTemplateDescription("A Jira project: name, lead, description and issue types"),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), resourceHandler(filter, readProjectResource))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(sprintResourceTemplate, "Jira sprint",
+		mcp.WithTemplateDescription(fmt.Sprintf("A sprint's dates and goal with the issues in it (up to %d)", resourceIssueLimit)),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), resourceHandler(filter, readSprintResource))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(backlogResourceTemplate, "Jira board backlog",
+		mcp.WithTemplateDescription(fmt.Sprintf("The backlog of an agile board, in rank order (up to %d issues)", resourceIssueLimit)),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), resourceHandler(filter, readBacklogResource))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(filterResourceTemplate, "Jira filter",
+		mcp.WithTemplateDescription(fmt.Sprintf("A saved filter's JQL and the issues it matches (up to %d)", resourceIssueLimit)),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), resourceHandler(filter, readFilterResource))
+}
+
+// resourceReader renders one resource. It gets the values matched from the
+// URI template and the filter, to check the project policy once it knows
+// which projects the resource belongs to.
+type resourceReader func(ctx context.Context, client *services.RawClient, filter *Filter, args map[string]string) (string, error)
+
+// resourceHandler adapts a resourceReader to mcp-go.
+func resourceHandler(filter *Filter, read resourceReader) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		text, err := read(ctx, services.JiraRawClient(), filter, resourceArguments(request))
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/plain",
+			Text:     text,
+		}}, nil
+	}
+}
+
+// resourceArguments flattens the template values mcp-go matched, which
+// arrive as string lists.
+func resourceArguments(request mcp.ReadResourceRequest) map[string]string {
+	args := make(map[string]string, len(request.Params.Arguments))
+	for name, value := range request.Params.Arguments {
+		switch v := value.(type) {
+		case string:
+			args[name] = v
+		case []string:
+			args[name] = strings.Join(v, ",")
+		}
+	}
+	return args
+}
+
+func readIssueResource(ctx context.Context, client *services.RawClient, filter *Filter, args map[string]string) (string, error) {
+	key := strings.ToUpper(strings.TrimSpace(args["key"]))
+	match := issueKeyPattern.FindStringSubmatch(key)
+	if match == nil {
+		return "", fmt.Errorf("invalid issue key %q in resource URI", args["key"])
+	}
+	ctx, err := filter.guardResource(ctx, issueResourceTemplate, match[1])
+	if err != nil {
+		return "", err
+	}
+
+	// Resources are read whole into the context, so they use the standard
+	// profile rather than dumping the changelog.
+	profile := util.ProfileStandard
+	query := url.Values{
+		"fields": {strings.Join(profile.JiraFields(), ",")},
+		"expand": {strings.Join(profile.Expand(), ",")},
+	}
+	var issue models.IssueScheme
+	path := fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(key))
+	response, err := client.Do(ctx, http.MethodGet, path, query, nil, &issue)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get issue: %v", err)
+	}
+	return util.RenderIssue(&issue, profile, issueCustomFields(ctx, response.Bytes.Bytes())...), nil
+}
+
+func readProjectResource(ctx context.Context, client *services.RawClient, filter *Filter, args map[string]string) (string, error) {
+	key := strings.ToUpper(strings.TrimSpace(args["key"]))
+	if key == "" {
+		return "", fmt.Errorf("missing project key in resource URI")
+	}
+	ctx, err := filter.guardResource(ctx, projectResourceTemplate, key)
+	if err != nil {
+		return "", err
+	}
+
+	var project models.ProjectScheme
+	path := fmt.Sprintf("rest/api/3/project/%s", url.PathEscape(key))
+	response, err := client.Do(ctx, http.MethodGet, path, url.Values{"expand": {"description,lead,issueTypes"}}, nil, &project)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to get project: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get project: %v", err)
+	}
+	return formatProject(&project), nil
+}
+
+// formatProject renders a project's details, one field per line.
+func formatProject(project *models.ProjectScheme) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Key: %s\nName: %s\nID: %s\n", project.Key, project.Name, project.ID))
+	if project.ProjectTypeKey != "" {
+		sb.WriteString(fmt.Sprintf("Type: %s\n", project.ProjectTypeKey))
+	}
+	if project.Lead != nil {
+		sb.WriteString(fmt.Sprintf("Lead: %s\n", project.Lead.DisplayName))
+	}
+	if project.Category != nil {
+		sb.WriteString(fmt.Sprintf("Category: %s\n", project.Category.Name))
+	}
+	if len(project.IssueTypes) > 0 {
+		names := make([]string, 0, len(project.IssueTypes))
+		for _, issueType := range project.IssueTypes {
+			names = append(names, issueType.Name)
+		}
+		sb.WriteString(fmt.Sprintf("Issue Types: %s\n", strings.Join(names, ", ")))
+	}
+	if project.Description != "" {
+		sb.WriteString(fmt.Sprintf("Description:\n%s\n", project.Description))
+	}
+	return sb.String()
+}
+
+func readSprintResource(ctx context.Context, client *services.RawClient, filter *Filter, args map[string]string) (string, error) {
+	sprintID, err := strconv.Atoi(args["id"])
+	if err != nil {
+		return "", fmt.Errorf("invalid sprint id %q in resource URI", args["id"])
+	}
+	ctx, err = filter.guardResource(ctx, sprintResourceTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var sprint models.SprintScheme
+	path := fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID)
+	response, err := client.Do(ctx, http.MethodGet, path, nil, nil, &sprint)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to get sprint: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get sprint: %v", err)
+	}
+
+	issues, err := fetchAgileIssues(ctx, client, path+"/issue")
+	if err != nil {
+		return "", fmt.Errorf("failed to get sprint issues: %v", err)
+	}
+	return "Sprint Details:\n" + util.FormatSprint(&sprint) + "\n\n" + formatIssueList("Issues", issues), nil
+}
+
+func readBacklogResource(ctx context.Context, client *services.RawClient, filter *Filter, args map[string]string) (string, error) {
+	boardID, err := strconv.Atoi(args["id"])
+	if err != nil {
+		return "", fmt.Errorf("invalid board id %q in resource URI", args["id"])
+	}
+	ctx, err = filter.guardResource(ctx, backlogResourceTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	issues, err := fetchAgileIssues(ctx, client, fmt.Sprintf("rest/agile/1.0/board/%d/backlog", boardID))
+	if err != nil {
+		return "", fmt.Errorf("failed to get backlog: %v", err)
+	}
+	return formatIssueList(fmt.Sprintf("Backlog of board %d", boardID), issues), nil
+}
+
+func readFilterResource(ctx context.Context, client *services.RawClient, filter *Filter, args map[string]string) (string, error) {
+	filterID, err := strconv.Atoi(args["id"])
+	if err != nil {
+		return "", fmt.Errorf("invalid filter id %q in resource URI", args["id"])
+	}
+	ctx, err = filter.guardResource(ctx, filterResourceTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var saved models.FilterScheme
+	path := fmt.Sprintf("rest/api/3/filter/%d", filterID)
+	response, err := client.Do(ctx, http.MethodGet, path, nil, nil, &saved)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to get filter: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get filter: %v", err)
+	}
+
+	result, err := searchIssuesJQL(ctx, client, saved.Jql, resourceIssueFields, nil, "", resourceIssueLimit)
+	if err != nil {
+		return "", fmt.Errorf("failed to run filter: %v", err)
+	}
+	list := agileIssuePage{Issues: result.Issues, Total: -1}
+	return fmt.Sprintf("Filter: %s (ID: %s)\nJQL: %s\n\n", saved.Name, saved.ID, saved.Jql) + formatIssueList("Issues", list), nil
+}
+
+// agileIssuePage is a page of issues from the Agile API. Total is -1 when
+// the endpoint does not report one.
+type agileIssuePage struct {
+	Total  int                   `json:"total"`
+	Issues []*models.IssueScheme `json:"issues"`
+}
+
+// fetchAgileIssues lists the first page of issues from an Agile endpoint
+// (sprint issues, board backlog). The endpoints accept JQL, which is how the
+// project policy's scope is applied.
+func fetchAgileIssues(ctx context.Context, client *services.RawClient, path string) (agileIssuePage, error) {
+	query := url.Values{
+		"fields":     {strings.Join(resourceIssueFields, ",")},
+		"maxResults": {strconv.Itoa(resourceIssueLimit)},
+	}
+	var page agileIssuePage
+	jql, err := scopeJQL(ctx, "")
+	if err != nil {
+		return page, err
+	}
+	if jql != "" {
+		query.Set("jql", jql)
+	}
+
+	response, err := client.Do(ctx, http.MethodGet, path, query, nil, &page)
+	if err != nil {
+		if response != nil {
+			return page, fmt.Errorf("%s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return page, err
+	}
+	return page, nil
+}
+
+// formatIssueList renders a titled list of issues, one compact line each.
+func formatIssueList(title string, page agileIssuePage) string {
+	var sb strings.Builder
+	if page.Total > len(page.Issues) {
+		sb.WriteString(fmt.Sprintf("%s (%d of %d):\n", title, len(page.Issues), page.Total))
+	} else {
+		sb.WriteString(fmt.Sprintf("%s (%d):\n", title, len(page.Issues)))
+	}
+	if len(page.Issues) == 0 {
+		sb.WriteString("No issues.\n")
+	}
+	for _, issue := range page.Issues {
+		sb.WriteString("- " + util.FormatJiraIssueCompact(issue) + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// fakeResourceServer serves issue ABC-1, sprint 7 and filter 9, recording
+// the JQL sent with each request.
+func fakeResourceServer(t *testing.T) (*services.RawClient, *[]string) {
+	t.Helper()
+	var jql []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if q := r.URL.Query().Get("jql"); q != "" {
+			jql = append(jql, r.URL.Path+" "+q)
+		}
+		switch r.URL.Path {
+		case "/rest/api/3/issue/ABC-1":
+			_, _ = w.Write([]byte(`{"key":"ABC-1","fields":{"summary":"Fix login","status":{"name":"To Do"}}}`))
+		case "/rest/agile/1.0/sprint/7":
+			_, _ = w.Write([]byte(`{"id":7,"name":"Sprint 7","state":"active","goal":"Ship it"}`))
+		case "/rest/agile/1.0/sprint/7/issue":
+			_, _ = w.Write([]byte(`{"total":3,"issues":[{"key":"ABC-1","fields":{"summary":"Fix login"}},{"key":"ABC-2","fields":{"summary":"Add logout"}}]}`))
+		case "/rest/api/3/filter/9":
+			_, _ = w.Write([]byte(`{"id":"9","name":"Mine","jql":"assignee = currentUser()"}`))
+		case "/rest/api/3/search/jql":
+			_, _ = w.Write([]byte(`{"issues":[{"key":"ABC-3","fields":{"summary":"Review"}}],"isLast":true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}, &jql
+}
+
+func TestResources(t *testing.T) {
+	client, jql := fakeResourceServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		read resourceReader
+		args map[string]string
+		want []string
+	}{
+		{"issue", readIssueResource, map[string]string{"key": "abc-1"}, []string{"Key: ABC-1", "Fix login"}},
+		{"sprint", readSprintResource, map[string]string{"id": "7"}, []string{"Name: Sprint 7", "Goal: Ship it", "Issues (2 of 3):", "Key: ABC-2"}},
+		{"filter", readFilterResource, map[string]string{"id": "9"}, []string{"Filter: Mine (ID: 9)", "JQL: assignee = currentUser()", "Key: ABC-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.read(ctx, client, nil, tt.args)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("resource lacks %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+
+	if _, err := readIssueResource(ctx, client, nil, map[string]string{"key": "not a key"}); err == nil {
+		t.Errorf("an invalid issue key must be rejected")
+	}
+	if len(*jql) != 1 || !strings.HasSuffix((*jql)[0], "assignee = currentUser()") {
+		t.Errorf("without a policy only the filter's own JQL should be sent, got %v", *jql)
+	}
+}
+
+func TestResources_ProjectPolicy(t *testing.T) {
+	client, jql := fakeResourceServer(t)
+	ctx := context.Background()
+	policy, err := ParseProjectPolicy([]byte("read: [DEF]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := &Filter{}
+	filter.UseProjectPolicy(policy)
+
+	if _, err := readIssueResource(ctx, client, filter, map[string]string{"key": "ABC-1"}); err == nil || !strings.Contains(err.Error(), "Blocked by project policy") {
+		t.Errorf("an issue outside the policy must be blocked, got %v", err)
+	}
+	if _, err := readSprintResource(ctx, client, filter, map[string]string{"id": "7"}); err == nil {
+		t.Errorf("a sprint names no project and needs allow_unscoped")
+	}
+
+	policy, _ = ParseProjectPolicy([]byte("read: [DEF]\nallow_unscoped: true\n"))
+	filter.UseProjectPolicy(policy)
+	if _, err := readSprintResource(ctx, client, filter, map[string]string{"id": "7"}); err != nil {
+		t.Fatalf("read sprint: %v", err)
+	}
+	if len(*jql) != 1 || (*jql)[0] != `/rest/agile/1.0/sprint/7/issue project in ("DEF")` {
+		t.Errorf("sprint issues should be scoped to the allowed projects, got %v", *jql)
+	}
+}
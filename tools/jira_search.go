@@ -2,33 +2,55 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
 	"github.com/nguyenvanduocit/jira-mcp/util"
-	jira "github.com/ctreminiom/go-atlassian/jira/v3"
-	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 )
 
 // Input types for typed tools
 type SearchIssueInput struct {
-	JQL    string `json:"jql" validate:"required"`
-	Fields string `json:"fields,omitempty"`
-	Expand string `json:"expand,omitempty"`
+	JQL        string `json:"jql" validate:"required"`
+	Fields     string `json:"fields,omitempty"`
+	Expand     string `json:"expand,omitempty"`
+	PageToken  string `json:"page_token,omitempty"`
+	MaxResults int    `json:"max_results,omitempty"`
+	FetchAll   bool   `json:"fetch_all,omitempty"`
+	Profile    string `json:"profile,omitempty"`
+	MaxChars   int    `json:"max_chars,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Columns    string `json:"columns,omitempty"`
 }
 
-// searchIssuesJQL performs JQL search using the new /rest/api/3/search/jql endpoint
-func searchIssuesJQL(ctx context.Context, client *jira.Client, jql string, fields []string, expand []string, startAt, maxResults int) (*models.IssueSearchScheme, error) {
+// SearchIssueOutput is the structured result of jira_search_issue.
+type SearchIssueOutput struct {
+	// Total is Jira's approximate count of matching issues, absent when
+	// unavailable.
+	Total         *int               `json:"total,omitempty"`
+	NextPageToken string             `json:"next_page_token,omitempty"`
+	Issues        []util.IssueOutput `json:"issues"`
+}
+
+// searchIssuesJQL fetches one page from the /rest/api/3/search/jql endpoint.
+// Pass the nextPageToken of the previous page to continue; an empty token
+// starts from the first result. When a project policy is active the query is
+// narrowed to the projects the calling tool may access.
+func searchIssuesJQL(ctx context.Context, client *services.RawClient, jql string, fields []string, expand []string, pageToken string, maxResults int) (*util.SearchJQLResult, error) {
+	scoped, err := scopeJQL(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare query parameters
 	params := url.Values{}
-	params.Set("jql", jql)
+	params.Set("jql", scoped)
 
 	if len(fields) > 0 {
 		params.Set("fields", strings.Join(fields, ","))
@@ -38,94 +60,149 @@ func searchIssuesJQL(ctx context.Context, client *jira.Client, jql string, field
 		params.Set("expand", strings.Join(expand, ","))
 	}
 
-	if startAt > 0 {
-		params.Set("startAt", strconv.Itoa(startAt))
+	if pageToken != "" {
+		params.Set("nextPageToken", pageToken)
 	}
 
 	if maxResults > 0 {
 		params.Set("maxResults", strconv.Itoa(maxResults))
 	}
 
-	// Build the URL
-	endpoint := fmt.Sprintf("%s/rest/api/3/search/jql?%s", client.Site.String(), params.Encode())
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	var searchResult util.SearchJQLResult
+	response, err := client.Do(ctx, http.MethodGet, "rest/api/3/search/jql", params, nil, &searchResult)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add authentication headers - the client already has basic auth configured
-	if client.Auth != nil && client.Auth.HasBasicAuth() {
-		username, password := client.Auth.GetBasicAuth()
-		req.SetBasicAuth(username, password)
+		if response != nil {
+			return nil, fmt.Errorf("%v: %s (endpoint: %s)", err, response.Bytes.String(), response.Endpoint)
+		}
+		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/json")
+	return &searchResult, nil
+}
 
-	// Perform the request
-	resp, err := client.HTTP.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
+// approximateIssueCount asks Jira for an estimate of how many issues match
+// jql. The /search/jql endpoint no longer reports a total, so this is the
+// only cheap way to tell an agent how large the result set is. Returns -1
+// when the count is unavailable.
+func approximateIssueCount(ctx context.Context, client *services.RawClient, jql string) int {
+	var result struct {
+		Count int `json:"count"`
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, resp.Status)
+	scoped, err := scopeJQL(ctx, jql)
+	if err != nil {
+		return -1
 	}
-
-	// Parse the response
-	var searchResult models.IssueSearchScheme
-	if err := json.NewDecoder(resp.Body).Decode(&searchResult); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	body := map[string]string{"jql": scoped}
+	if _, err := client.Do(ctx, http.MethodPost, "rest/api/3/search/approximate-count", nil, body, &result); err != nil {
+		return -1
 	}
-
-	return &searchResult, nil
+	return result.Count
 }
 
 func RegisterJiraSearchTool(s *server.MCPServer, filter *Filter) {
 	jiraSearchTool := mcp.NewTool("jira_search_issue",
-		mcp.WithDescription("Search for Jira issues using JQL (Jira Query Language). Returns key details like summary, status, assignee, and priority for matching issues"),
+		mcp.WithDescription("Search for Jira issues using JQL (Jira Query Language). Returns the matching issues in full, as compact lines, as a markdown table or as CSV with chosen columns. Results are paginated — the header reports the page_token to pass for the next page."),
 		mcp.WithString("jql", mcp.Required(), mcp.Description("JQL query string (e.g., 'project = SHTP AND status = \"In Progress\"')")),
-		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to retrieve (e.g., 'summary,status,assignee'). If not specified, all fields are returned.")),
-		mcp.WithString("expand", mcp.Description("Comma-separated list of fields to expand for additional details (e.g., 'transitions,changelog,subtasks,description').")),
+		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to retrieve (e.g., 'summary,status,assignee'). If not specified, the fields the profile renders are retrieved.")),
+		mcp.WithString("expand", mcp.Description("Comma-separated list of fields to expand for additional details (e.g., 'transitions,changelog'). If not specified, the profile decides.")),
+		mcp.WithString("page_token", mcp.Description("Token from a previous response's header to continue where it stopped. Omit to start from the first result.")),
+		mcp.WithNumber("max_results", mcp.Description(fmt.Sprintf("Maximum number of issues to return (default %d). Values above %d are fetched across several pages.", util.SearchDefaultMaxResults, util.SearchMaxPageSize))),
+		mcp.WithBoolean("fetch_all", mcp.Description(fmt.Sprintf("Follow every page until the result set is exhausted, up to a safety cap of %d issues. Overrides max_results.", util.SearchFetchAllCap))),
+		mcp.WithString("format", mcp.Description(`How to list the issues: "full" (default; each issue rendered with the profile), "compact" (one line per issue), "markdown_table" or "csv". The last three show the chosen columns and fetch only the fields they need.`)),
+		mcp.WithString("columns", mcp.Description(fmt.Sprintf("Comma-separated columns for the compact, markdown_table and csv formats (default %q). Built-in: key, summary, status, assignee, reporter, priority, type, resolution, created, updated, resolved, labels, components, fix_versions, parent, project. Custom fields can be named or given by ID (e.g. 'Story Points', customfield_10016).", util.DefaultIssueColumns))),
+		mcp.WithString("profile", mcp.Description(profileParamDescription+" Applies to the full format.")),
+		mcp.WithNumber("max_chars", mcp.Description(maxCharsParamDescription+" In the full format the budget is shared across the issues; in compact and markdown_table rows stop at the budget. Issues that no longer fit are counted at the end. csv is never cut.")),
+		mcp.WithOutputSchema[SearchIssueOutput](),
 	)
-	filter.AddTool(s, jiraSearchTool, mcp.NewTypedToolHandler(jiraSearchHandler))
+	filter.AddTool(s, jiraSearchTool, ToolRead, mcp.NewTypedToolHandler(jiraSearchHandler))
 }
 
 func jiraSearchHandler(ctx context.Context, request mcp.CallToolRequest, input SearchIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraRawClient()
+
+	profile, err := util.ParseRenderProfile(input.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile: %v", err)
+	}
+	format, err := util.ParseSearchFormat(input.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format: %v", err)
+	}
+
+	// Tabular formats fetch only the fields their columns show.
+	fields := profile.JiraFields()
+	expand := profile.Expand()
+	var columns []util.IssueColumn
+	if format != util.SearchFormatFull {
+		names, _ := services.JiraFieldCatalog().Names(ctx)
+		if columns, err = util.ParseIssueColumns(input.Columns, names); err != nil {
+			return nil, fmt.Errorf("invalid columns: %v", err)
+		}
+		fields, expand = util.IssueColumnFields(columns), nil
+	}
 
 	// Parse fields parameter
-	var fields []string
 	if input.Fields != "" {
 		fields = strings.Split(strings.ReplaceAll(input.Fields, " ", ""), ",")
 	}
 
 	// Parse expand parameter
-	var expand []string = []string{"transitions", "changelog", "subtasks", "description"}
 	if input.Expand != "" {
 		expand = strings.Split(strings.ReplaceAll(input.Expand, " ", ""), ",")
 	}
-	
-	searchResult, err := searchIssuesJQL(ctx, client, input.JQL, fields, expand, 0, 30)
+
+	rawIssues := make(map[string][]byte)
+	fetchPage := func(ctx context.Context, pageToken string, maxResults int) (*util.SearchJQLResult, error) {
+		page, err := searchIssuesJQL(ctx, client, input.JQL, fields, expand, pageToken, maxResults)
+		if err != nil {
+			return nil, err
+		}
+		for i, issue := range page.Issues {
+			if i < len(page.RawIssues) {
+				rawIssues[issue.Key] = page.RawIssues[i]
+			}
+		}
+		return page, nil
+	}
+	issues, nextPageToken, err := util.FetchSearchPages(ctx, fetchPage, input.PageToken, input.MaxResults, input.FetchAll)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search issues: %v", err)
 	}
 
-	if len(searchResult.Issues) == 0 {
-		return mcp.NewToolResultText("No issues found matching the search criteria."), nil
+	output := SearchIssueOutput{NextPageToken: nextPageToken, Issues: []util.IssueOutput{}}
+	if len(issues) == 0 && input.PageToken == "" {
+		return mcp.NewToolResultStructured(output, "No issues found matching the search criteria."), nil
 	}
 
-	var sb strings.Builder	
-	for index, issue := range searchResult.Issues {
-		// Use the comprehensive formatter for each issue
-		formattedIssue := util.FormatJiraIssue(issue)
-		sb.WriteString(formattedIssue)
-		if index < len(searchResult.Issues) - 1 {
-			sb.WriteString("\n===\n")
-		}
+	// The count only changes with the query, so it is asked for on the first
+	// page and not again for every page that follows.
+	total := -1
+	if input.PageToken == "" {
+		total = approximateIssueCount(ctx, client, input.JQL)
+	}
+	if total >= 0 {
+		output.Total = &total
+	}
+
+	customFields := make(map[string][]util.CustomFieldValue, len(issues))
+	for _, issue := range issues {
+		customFields[issue.Key] = issueCustomFields(ctx, rawIssues[issue.Key])
+		output.Issues = append(output.Issues, profile.IssueOutput(issue, customFields[issue.Key]...))
 	}
+	issueFields := func(issue *models.IssueScheme) []util.CustomFieldValue {
+		return customFields[issue.Key]
+	}
+	var rendered string
+	if format == util.SearchFormatFull {
+		rendered = util.RenderIssueList(issues, profile, input.MaxChars, issueFields)
+	} else if rendered, err = util.FormatIssueTable(format, issues, columns, issueFields, input.MaxChars); err != nil {
+		return nil, fmt.Errorf("failed to search issues: %v", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(util.FormatSearchHeader(total, len(issues), nextPageToken, input.FetchAll))
+	sb.WriteString("\n\n")
+	sb.WriteString(rendered)
 
-	return mcp.NewToolResultText(sb.String()), nil
+	return mcp.NewToolResultStructured(output, sb.String()), nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
 )
 
 // Input types for typed tools
@@ -34,26 +35,41 @@ type SearchSprintByNameInput struct {
 	ExactMatch bool   `json:"exact_match,omitempty"`
 }
 
+// ListSprintsOutput is the structured result of jira_list_sprints and
+// jira_search_sprint_by_name.
+type ListSprintsOutput struct {
+	Sprints []util.SprintOutput `json:"sprints"`
+}
+
+// ActiveSprintOutput is the structured result of jira_get_active_sprint.
+// Sprint is absent when no board has an active sprint.
+type ActiveSprintOutput struct {
+	Sprint *util.SprintOutput `json:"sprint,omitempty"`
+}
+
 func RegisterJiraSprintTool(s *server.MCPServer, filter *Filter) {
 	jiraListSprintTool := mcp.NewTool("jira_list_sprints",
 		mcp.WithDescription("List all active and future sprints for a specific Jira board or project. Requires either board_id or project_key."),
 		mcp.WithString("board_id", mcp.Description("Numeric ID of the Jira board (can be found in board URL). Optional if project_key is provided.")),
 		mcp.WithString("project_key", mcp.Description("The project key (e.g., KP, PROJ, DEV). Optional if board_id is provided.")),
+		mcp.WithOutputSchema[ListSprintsOutput](),
 	)
-	filter.AddTool(s, jiraListSprintTool, mcp.NewTypedToolHandler(jiraListSprintHandler))
+	filter.AddTool(s, jiraListSprintTool, ToolRead, mcp.NewTypedToolHandler(jiraListSprintHandler))
 
 	jiraGetSprintTool := mcp.NewTool("jira_get_sprint",
 		mcp.WithDescription("Retrieve detailed information about a specific Jira sprint by its ID"),
 		mcp.WithString("sprint_id", mcp.Required(), mcp.Description("Numeric ID of the sprint to retrieve")),
+		mcp.WithOutputSchema[util.SprintOutput](),
 	)
-	filter.AddTool(s, jiraGetSprintTool, mcp.NewTypedToolHandler(jiraGetSprintHandler))
+	filter.AddTool(s, jiraGetSprintTool, ToolRead, mcp.NewTypedToolHandler(jiraGetSprintHandler))
 
 	jiraGetActiveSprintTool := mcp.NewTool("jira_get_active_sprint",
 		mcp.WithDescription("Get the currently active sprint for a given board or project. Requires either board_id or project_key."),
 		mcp.WithString("board_id", mcp.Description("Numeric ID of the Jira board. Optional if project_key is provided.")),
 		mcp.WithString("project_key", mcp.Description("The project key (e.g., KP, PROJ, DEV). Optional if board_id is provided.")),
+		mcp.WithOutputSchema[ActiveSprintOutput](),
 	)
-	filter.AddTool(s, jiraGetActiveSprintTool, mcp.NewTypedToolHandler(jiraGetActiveSprintHandler))
+	filter.AddTool(s, jiraGetActiveSprintTool, ToolRead, mcp.NewTypedToolHandler(jiraGetActiveSprintHandler))
 
 	jiraSearchSprintByNameTool := mcp.NewTool("jira_search_sprint_by_name",
 		mcp.WithDescription("Search for sprints by name across boards or projects. Supports both exact and partial name matching."),
@@ -61,8 +77,9 @@ func RegisterJiraSprintTool(s *server.MCPServer, filter *Filter) {
 		mcp.WithString("board_id", mcp.Description("Numeric ID of the Jira board to search in. Optional if project_key is provided.")),
 		mcp.WithString("project_key", mcp.Description("The project key (e.g., KP, PROJ, DEV) to search in. Optional if board_id is provided.")),
 		mcp.WithBoolean("exact_match", mcp.Description("If true, only return sprints with exact name match. Default is false (partial matching).")),
+		mcp.WithOutputSchema[ListSprintsOutput](),
 	)
-	filter.AddTool(s, jiraSearchSprintByNameTool, mcp.NewTypedToolHandler(searchSprintByNameHandler))
+	filter.AddTool(s, jiraSearchSprintByNameTool, ToolRead, mcp.NewTypedToolHandler(searchSprintByNameHandler))
 }
 
 // Helper function to get board IDs either from direct board_id or by finding boards for a project
@@ -118,26 +135,9 @@ func jiraGetSprintHandler(ctx context.Context, request mcp.CallToolRequest, inpu
 		return nil, fmt.Errorf("failed to get sprint: %v", err)
 	}
 
-	result := fmt.Sprintf(`Sprint Details:
-ID: %d
-Name: %s
-State: %s
-Start Date: %s
-End Date: %s
-Complete Date: %s
-Origin Board ID: %d
-Goal: %s`,
-		sprint.ID,
-		sprint.Name,
-		sprint.State,
-		sprint.StartDate,
-		sprint.EndDate,
-		sprint.CompleteDate,
-		sprint.OriginBoardID,
-		sprint.Goal,
-	)
+	result := "Sprint Details:\n" + util.FormatSprint(sprint)
 
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(util.NewSprintOutput(sprint), result), nil
 }
 
 func jiraListSprintHandler(ctx context.Context, request mcp.CallToolRequest, input ListSprintsInput) (*mcp.CallToolResult, error) {
@@ -147,6 +147,7 @@ func jiraListSprintHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	}
 
 	var allSprints []string
+	output := ListSprintsOutput{Sprints: []util.SprintOutput{}}
 	for _, boardID := range boardIDs {
 		sprints, response, err := services.AgileClient().Board.Sprints(ctx, boardID, 0, 50, []string{"active", "future"})
 		if err != nil {
@@ -159,15 +160,18 @@ func jiraListSprintHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		for _, sprint := range sprints.Values {
 			allSprints = append(allSprints, fmt.Sprintf("ID: %d\nName: %s\nState: %s\nStartDate: %s\nEndDate: %s\nBoard ID: %d\n", 
 				sprint.ID, sprint.Name, sprint.State, sprint.StartDate, sprint.EndDate, boardID))
+			converted := util.NewSprintOutput((*models.SprintScheme)(sprint))
+			converted.BoardID = boardID
+			output.Sprints = append(output.Sprints, converted)
 		}
 	}
 
 	if len(allSprints) == 0 {
-		return mcp.NewToolResultText("No sprints found."), nil
+		return mcp.NewToolResultStructured(output, "No sprints found."), nil
 	}
 
 	result := strings.Join(allSprints, "\n")
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
 
 func jiraGetActiveSprintHandler(ctx context.Context, request mcp.CallToolRequest, input GetActiveSprintInput) (*mcp.CallToolResult, error) {
@@ -202,11 +206,13 @@ Board ID: %d`,
 				sprint.EndDate,
 				boardID,
 			)
-			return mcp.NewToolResultText(result), nil
+			converted := util.NewSprintOutput((*models.SprintScheme)(sprint))
+			converted.BoardID = boardID
+			return mcp.NewToolResultStructured(ActiveSprintOutput{Sprint: &converted}, result), nil
 		}
 	}
 
-	return mcp.NewToolResultText("No active sprint found."), nil
+	return mcp.NewToolResultStructured(ActiveSprintOutput{}, "No active sprint found."), nil
 }
 
 func searchSprintByNameHandler(ctx context.Context, request mcp.CallToolRequest, input SearchSprintByNameInput) (*mcp.CallToolResult, error) {
@@ -216,6 +222,7 @@ func searchSprintByNameHandler(ctx context.Context, request mcp.CallToolRequest,
 	}
 
 	var matchingSprints []string
+	output := ListSprintsOutput{Sprints: []util.SprintOutput{}}
 	searchTerm := strings.ToLower(input.Name)
 
 	for _, boardID := range boardIDs {
@@ -255,6 +262,9 @@ Goal: %s`,
 					sprint.CompleteDate,
 					boardID,
 					sprint.Goal))
+				converted := util.NewSprintOutput((*models.SprintScheme)(sprint))
+				converted.BoardID = boardID
+				output.Sprints = append(output.Sprints, converted)
 			}
 		}
 	}
@@ -264,9 +274,9 @@ Goal: %s`,
 		if input.ExactMatch {
 			matchType = "with exact name"
 		}
-		return mcp.NewToolResultText(fmt.Sprintf("No sprints found %s '%s'.", matchType, input.Name)), nil
+		return mcp.NewToolResultStructured(output, fmt.Sprintf("No sprints found %s '%s'.", matchType, input.Name)), nil
 	}
 
 	result := strings.Join(matchingSprints, "\n\n")
-	return mcp.NewToolResultText(result), nil
+	return mcp.NewToolResultStructured(output, result), nil
 }
@@ -15,12 +15,30 @@ type ListStatusesInput struct {
 	ProjectKey string `json:"project_key" validate:"required"`
 }
 
+// StatusOutput is one workflow status.
+type StatusOutput struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// IssueTypeStatusesOutput lists the statuses of one issue type.
+type IssueTypeStatusesOutput struct {
+	IssueType string         `json:"issue_type"`
+	Statuses  []StatusOutput `json:"statuses"`
+}
+
+// ListStatusesOutput is the structured result of jira_list_statuses.
+type ListStatusesOutput struct {
+	IssueTypes []IssueTypeStatusesOutput `json:"issue_types"`
+}
+
 func RegisterJiraStatusTool(s *server.MCPServer, filter *Filter) {
 	jiraStatusListTool := mcp.NewTool("jira_list_statuses",
 		mcp.WithDescription("Retrieve all available issue status IDs and their names for a specific Jira project"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier (e.g., KP, PROJ)")),
+		mcp.WithOutputSchema[ListStatusesOutput](),
 	)
-	filter.AddTool(s, jiraStatusListTool, mcp.NewTypedToolHandler(jiraGetStatusesHandler))
+	filter.AddTool(s, jiraStatusListTool, ToolRead, mcp.NewTypedToolHandler(jiraGetStatusesHandler))
 }
 
 func jiraGetStatusesHandler(ctx context.Context, request mcp.CallToolRequest, input ListStatusesInput) (*mcp.CallToolResult, error) {
@@ -34,18 +52,22 @@ func jiraGetStatusesHandler(ctx context.Context, request mcp.CallToolRequest, in
 		return nil, fmt.Errorf("failed to get statuses: %v", err)
 	}
 
+	output := ListStatusesOutput{IssueTypes: []IssueTypeStatusesOutput{}}
 	if len(issueTypes) == 0 {
-		return mcp.NewToolResultText("No issue types found for this project."), nil
+		return mcp.NewToolResultStructured(output, "No issue types found for this project."), nil
 	}
 
 	var result strings.Builder
 	result.WriteString("Available Statuses:\n")
 	for _, issueType := range issueTypes {
 		result.WriteString(fmt.Sprintf("\nIssue Type: %s\n", issueType.Name))
+		converted := IssueTypeStatusesOutput{IssueType: issueType.Name, Statuses: []StatusOutput{}}
 		for _, status := range issueType.Statuses {
 			result.WriteString(fmt.Sprintf("  - %s: %s\n", status.Name, status.ID))
+			converted.Statuses = append(converted.Statuses, StatusOutput{ID: status.ID, Name: status.Name})
 		}
+		output.IssueTypes = append(output.IssueTypes, converted)
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
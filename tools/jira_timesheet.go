@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+type TimesheetInput struct {
+	JQL    string `json:"jql" validate:"required"`
+	From   string `json:"from" validate:"required"`
+	To     string `json:"to,omitempty"`
+	Author string `json:"author,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+// Limits of jira_timesheet: the longest date range, the most worklogs
+// changed since its start that are looked at, and how many worklogs are
+// fetched per request.
+const (
+	maxTimesheetDays     = 366
+	maxTimesheetWorklogs = 100000
+	worklogListBatch     = 1000
+)
+
+func RegisterJiraTimesheetTool(s *server.MCPServer, filter *Filter) {
+	jiraTimesheetTool := mcp.NewTool("jira_timesheet",
+		mcp.WithDescription("Report the time logged on the issues matching a JQL query within a date range, summed by user, day and issue with totals. Answers questions like \"how many hours did the team log last week on PROJ?\" in one call."),
+		mcp.WithString("jql", mcp.Required(), mcp.Description("JQL selecting the issues (e.g., 'project = PROJ', 'sprint in openSprints()')")),
+		mcp.WithString("from", mcp.Required(), mcp.Description("First day of the range (e.g., 2024-05-06)")),
+		mcp.WithString("to", mcp.Description("Last day of the range, included (default today)")),
+		mcp.WithString("author", mcp.Description("Only worklogs by this user: account ID, username, email or part of the display name")),
+		mcp.WithString("format", mcp.Description(`How to render the report: "markdown_table" (default; hours per user and day, then per user, day and issue), "csv" (one row per user, day and issue) or "json"`)),
+		mcp.WithOutputSchema[util.Timesheet](),
+	)
+	filter.AddTool(s, jiraTimesheetTool, ToolRead, mcp.NewTypedToolHandler(jiraTimesheetHandler))
+}
+
+func jiraTimesheetHandler(ctx context.Context, request mcp.CallToolRequest, input TimesheetInput) (*mcp.CallToolResult, error) {
+	format, err := util.ParseTimesheetFormat(input.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format: %v", err)
+	}
+	sheet, err := Timesheet(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	text, err := util.FormatTimesheet(sheet, format)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(sheet, text), nil
+}
+
+// Timesheet sums the time logged between input.From and input.To on the
+// issues matching input.JQL.
+func Timesheet(ctx context.Context, input TimesheetInput) (*util.Timesheet, error) {
+	return buildTimesheet(ctx, services.JiraRawClient(), input, time.Now())
+}
+
+// buildTimesheet finds the issues matching the JQL, then the worklogs
+// changed since the start of the range (/worklog/updated), fetches those
+// (/worklog/list) and keeps the ones on those issues that started within
+// the range. A worklog's day is the date its author logged it on.
+func buildTimesheet(ctx context.Context, client *services.RawClient, input TimesheetInput, now time.Time) (*util.Timesheet, error) {
+	from, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(input.From), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: expected a date like 2024-05-06, got %q", input.From)
+	}
+	to := now
+	if input.To != "" {
+		if to, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(input.To), time.Local); err != nil {
+			return nil, fmt.Errorf("invalid to: expected a date like 2024-05-12, got %q", input.To)
+		}
+	}
+	fromDay, toDay := from.Format("2006-01-02"), to.Format("2006-01-02")
+	if toDay < fromDay {
+		return nil, fmt.Errorf("invalid date range: to (%s) is before from (%s)", toDay, fromDay)
+	}
+	if to.Sub(from) > maxTimesheetDays*24*time.Hour {
+		return nil, fmt.Errorf("invalid date range: at most %d days", maxTimesheetDays)
+	}
+
+	issues, err := timesheetIssues(ctx, client, input.JQL)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return util.BuildTimesheet(fromDay, toDay, nil), nil
+	}
+
+	// Authors in other time zones may have logged work on fromDay up to a
+	// day before it started here.
+	ids, err := changedWorklogIDs(ctx, client, from.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []util.TimesheetWorklog
+	for start := 0; start < len(ids); start += worklogListBatch {
+		batch := ids[start:min(start+worklogListBatch, len(ids))]
+		var worklogs []*models.IssueWorklogADFScheme
+		response, err := client.Do(ctx, http.MethodPost, "rest/api/3/worklog/list", nil, map[string]any{"ids": batch}, &worklogs)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to get worklogs: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return nil, fmt.Errorf("failed to get worklogs: %v", err)
+		}
+		for _, worklog := range worklogs {
+			issue, ok := issues[worklog.IssueID]
+			if !ok || len(worklog.Started) < len("2006-01-02") {
+				continue
+			}
+			day := worklog.Started[:len("2006-01-02")]
+			if day < fromDay || day > toDay {
+				continue
+			}
+			if input.Author != "" && !worklogByAuthor(worklog, input.Author) {
+				continue
+			}
+			entry := worklogOutput(worklog)
+			entries = append(entries, util.TimesheetWorklog{
+				Author:          entry.Author,
+				AuthorAccountID: entry.AuthorAccountID,
+				Day:             day,
+				IssueKey:        issue.Key,
+				Summary:         issue.Fields.Summary,
+				Seconds:         worklog.TimeSpentSeconds,
+			})
+		}
+	}
+	return util.BuildTimesheet(fromDay, toDay, entries), nil
+}
+
+// timesheetIssues returns the issues matching jql by ID, with their
+// summaries.
+func timesheetIssues(ctx context.Context, client *services.RawClient, jql string) (map[string]*models.IssueScheme, error) {
+	fetchPage := func(ctx context.Context, pageToken string, maxResults int) (*util.SearchJQLResult, error) {
+		return searchIssuesJQL(ctx, client, jql, []string{"summary"}, nil, pageToken, maxResults)
+	}
+	found, nextPageToken, err := util.FetchSearchPages(ctx, fetchPage, "", 0, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %v", err)
+	}
+	if nextPageToken != "" {
+		return nil, fmt.Errorf("the JQL matches more than %d issues: narrow it down", util.SearchFetchAllCap)
+	}
+	issues := make(map[string]*models.IssueScheme, len(found))
+	for _, issue := range found {
+		if issue.Fields == nil {
+			issue.Fields = &models.IssueFieldsScheme{}
+		}
+		issues[issue.ID] = issue
+	}
+	return issues, nil
+}
+
+// changedWorklogIDs lists the IDs of the worklogs created or updated since
+// the given time, following /worklog/updated page by page.
+func changedWorklogIDs(ctx context.Context, client *services.RawClient, since time.Time) ([]int, error) {
+	var ids []int
+	sinceMillis := since.UnixMilli()
+	for {
+		var page models.ChangedWorklogPageScheme
+		query := url.Values{"since": {strconv.FormatInt(sinceMillis, 10)}}
+		response, err := client.Do(ctx, http.MethodGet, "rest/api/3/worklog/updated", query, nil, &page)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to list changed worklogs: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return nil, fmt.Errorf("failed to list changed worklogs: %v", err)
+		}
+		for _, value := range page.Values {
+			ids = append(ids, value.WorklogID)
+		}
+		if len(ids) > maxTimesheetWorklogs {
+			return nil, fmt.Errorf("more than %d worklogs changed since %s: use a shorter date range", maxTimesheetWorklogs, since.Format("2006-01-02"))
+		}
+		if page.LastPage || len(page.Values) == 0 || int64(page.Until) <= sinceMillis {
+			return ids, nil
+		}
+		sinceMillis = int64(page.Until)
+	}
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestBuildTimesheet(t *testing.T) {
+	var listed [][]int
+	// The range starts on 2024-05-06; worklogs are looked up from a day
+	// earlier.
+	firstSince := time.Date(2024, 5, 5, 0, 0, 0, 0, time.Local).UnixMilli()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			if r.URL.Query().Get("jql") != "project = PROJ" {
+				t.Errorf("jql = %q", r.URL.Query().Get("jql"))
+			}
+			_, _ = w.Write([]byte(`{"isLast":true,"issues":[{"id":"100","key":"PROJ-1","fields":{"summary":"Login"}},{"id":"101","key":"PROJ-2","fields":{"summary":"Search"}}]}`))
+		case "/rest/api/3/worklog/updated":
+			since, _ := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+			if since != firstSince {
+				_, _ = fmt.Fprintf(w, `{"since":%d,"until":%d,"lastPage":true,"values":[{"worklogId":4}]}`, since, since+1000)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"since":%d,"until":%d,"lastPage":false,"values":[{"worklogId":1},{"worklogId":2},{"worklogId":3}]}`, since, since+1000)
+		case "/rest/api/3/worklog/list":
+			var body struct {
+				IDs []int `json:"ids"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			listed = append(listed, body.IDs)
+			_, _ = w.Write([]byte(`[
+				{"id":"1","issueId":"100","author":{"accountId":"a1","displayName":"Ann"},"started":"2024-05-06T09:00:00.000+0700","timeSpentSeconds":3600},
+				{"id":"2","issueId":"101","author":{"accountId":"b2","displayName":"Bob"},"started":"2024-05-07T09:00:00.000+0000","timeSpentSeconds":1800},
+				{"id":"3","issueId":"999","author":{"accountId":"a1","displayName":"Ann"},"started":"2024-05-06T09:00:00.000+0000","timeSpentSeconds":7200},
+				{"id":"4","issueId":"100","author":{"accountId":"a1","displayName":"Ann"},"started":"2024-05-13T09:00:00.000+0000","timeSpentSeconds":7200}
+			]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client := &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+
+	now := time.Date(2024, 5, 20, 12, 0, 0, 0, time.Local)
+	sheet, err := buildTimesheet(context.Background(), client, TimesheetInput{JQL: "project = PROJ", From: "2024-05-06", To: "2024-05-12"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || len(listed[0]) != 4 {
+		t.Errorf("listed worklogs = %v, want the 4 changed ones in one batch", listed)
+	}
+	// Worklog 3 is on an issue outside the JQL, worklog 4 after the range.
+	if sheet.Worklogs != 2 || sheet.TotalSeconds != 5400 || len(sheet.Entries) != 2 {
+		t.Fatalf("sheet = %+v", sheet)
+	}
+	if entry := sheet.Entries[0]; entry.Author != "Ann" || entry.Day != "2024-05-06" || entry.IssueKey != "PROJ-1" || entry.Summary != "Login" {
+		t.Errorf("first entry = %+v", entry)
+	}
+
+	sheet, err = buildTimesheet(context.Background(), client, TimesheetInput{JQL: "project = PROJ", From: "2024-05-06", Author: "b2"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheet.To != "2024-05-20" || sheet.TotalSeconds != 1800 {
+		t.Errorf("by author = %+v", sheet)
+	}
+
+	for _, input := range []TimesheetInput{
+		{JQL: "project = PROJ", From: "last week"},
+		{JQL: "project = PROJ", From: "2024-05-06", To: "2024-05-01"},
+		{JQL: "project = PROJ", From: "2022-01-01", To: "2024-01-01"},
+	} {
+		if _, err := buildTimesheet(context.Background(), client, input, now); err == nil {
+			t.Errorf("%+v should be refused", input)
+		}
+	}
+}
@@ -3,49 +3,371 @@ package tools
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+	"gopkg.in/yaml.v3"
 )
 
 // Input types for typed tools
 type TransitionIssueInput struct {
-	IssueKey     string `json:"issue_key" validate:"required"`
-	TransitionID string `json:"transition_id" validate:"required"`
-	Comment      string `json:"comment,omitempty"`
+	IssueKey     string         `json:"issue_key" validate:"required"`
+	TransitionID string         `json:"transition_id,omitempty"`
+	ToStatus     string         `json:"to_status,omitempty"`
+	Fields       map[string]any `json:"fields,omitempty"`
+	Comment      string         `json:"comment,omitempty"`
+	Format       string         `json:"format,omitempty"`
+	FindPath     bool           `json:"find_path,omitempty"`
+	DryRun       bool           `json:"dry_run,omitempty"`
+}
+
+// TransitionIssueOutput is the structured result of jira_transition_issue.
+type TransitionIssueOutput struct {
+	IssueKey   string                 `json:"issue_key"`
+	FromStatus string                 `json:"from_status,omitempty"`
+	ToStatus   string                 `json:"to_status"`
+	Steps      []TransitionStepOutput `json:"steps,omitempty"`
+	DryRun     bool                   `json:"dry_run,omitempty"`
+	ChangeID   int                    `json:"change_id,omitempty"`
+	Message    string                 `json:"message"`
+}
+
+// TransitionStepOutput is one transition executed, or planned with dry_run.
+type TransitionStepOutput struct {
+	TransitionID string `json:"transition_id"`
+	Name         string `json:"name"`
+	From         string `json:"from,omitempty"`
+	To           string `json:"to"`
+}
+
+// workflowTransition is one entry of GET /issue/{key}/transitions expanded
+// with transitions.fields, i.e. including the transition screen's fields.
+type workflowTransition struct {
+	ID     string                         `json:"id"`
+	Name   string                         `json:"name"`
+	To     *models.StatusScheme           `json:"to"`
+	Fields map[string]transitionFieldMeta `json:"fields"`
+}
+
+// transitionFieldMeta describes a field on a transition screen.
+type transitionFieldMeta struct {
+	Required        bool                           `json:"required"`
+	Name            string                         `json:"name"`
+	HasDefaultValue bool                           `json:"hasDefaultValue"`
+	Schema          *models.IssueFieldSchemaScheme `json:"schema"`
+	AllowedValues   []map[string]any               `json:"allowedValues"`
+}
+
+// missingTransitionField is reported back when a required screen field has
+// no value, so the agent can retry with `fields` filled in.
+type missingTransitionField struct {
+	ID            string   `yaml:"id"`
+	Name          string   `yaml:"name"`
+	Type          string   `yaml:"type,omitempty"`
+	AllowedValues []string `yaml:"allowedValues,omitempty"`
 }
 
 func RegisterJiraTransitionTool(s *server.MCPServer, filter *Filter) {
 	jiraTransitionTool := mcp.NewTool("jira_transition_issue",
-		mcp.WithDescription("Transition an issue through its workflow using a valid transition ID. Get available transitions from jira_get_issue"),
+		mcp.WithDescription("Transition an issue through its workflow, either to a target status by name (to_status) or with an explicit transition ID. Required fields on the transition screen (resolution, fix version, ...) are filled from `fields`; if any are missing, the tool lists them with their allowed values instead of transitioning."),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The issue to transition (e.g., KP-123)")),
-		mcp.WithString("transition_id", mcp.Required(), mcp.Description("Transition ID from available transitions list")),
+		mcp.WithString("to_status", mcp.Description("Name of the status to move the issue to (e.g., 'In Progress', 'Done'). The matching transition is looked up automatically.")),
+		mcp.WithString("transition_id", mcp.Description("Transition ID from the available transitions list. Use instead of to_status.")),
+		mcp.WithObject("fields", mcp.Description(`Values for fields on the transition screen, keyed by field name or ID (e.g. {"Resolution": "Done", "Fix versions": ["1.2"]})`)),
 		mcp.WithString("comment", mcp.Description("Optional comment to add with transition")),
+		mcp.WithString("format", mcp.Description("Markup of the comment. "+bodyFormatParamDescription)),
+		mcp.WithBoolean("find_path", mcp.Description("When to_status is not reachable in one transition, find the shortest path through the workflow and execute every step (needs Jira admin permission on Jira Cloud to read the workflow; otherwise only a direct transition is found)")),
+		mcp.WithBoolean("dry_run", mcp.Description("Only report the planned transition (or, with find_path, every planned step) without transitioning")),
+		mcp.WithOutputSchema[TransitionIssueOutput](),
 	)
-	filter.AddTool(s, jiraTransitionTool, mcp.NewTypedToolHandler(jiraTransitionIssueHandler))
+	filter.AddTool(s, jiraTransitionTool, ToolWrite, mcp.NewTypedToolHandler(jiraTransitionIssueHandler))
 }
 
 func jiraTransitionIssueHandler(ctx context.Context, request mcp.CallToolRequest, input TransitionIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	return transitionIssue(ctx, services.JiraRawClient(), input)
+}
+
+func transitionIssue(ctx context.Context, client *services.RawClient, input TransitionIssueInput) (*mcp.CallToolResult, error) {
+	if (input.TransitionID == "") == (input.ToStatus == "") {
+		return nil, fmt.Errorf("provide exactly one of to_status or transition_id")
+	}
 
-	var options *models.IssueMoveOptionsV3
-	if input.Comment != "" {
-		options = &models.IssueMoveOptionsV3{
-			Fields: &models.IssueScheme{},
+	if input.FindPath && input.ToStatus == "" {
+		return nil, fmt.Errorf("find_path requires to_status")
+	}
+	comment, err := transitionComment(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remember the starting status so jira_undo_last_change can move the
+	// issue back.
+	var fromStatus string
+	if UndoJournal() != nil && !input.DryRun {
+		issue, err := fetchIssueWorkflowContext(ctx, client, input.IssueKey)
+		if err != nil {
+			return nil, err
+		}
+		fromStatus = issue.StatusName
+	}
+
+	if input.FindPath {
+		// A path that stops partway still moved the issue: journal the
+		// status it reached so the completed steps can be undone.
+		result, reached, err := transitionAlongPath(ctx, client, input)
+		changeID := recordTransition(input.IssueKey, fromStatus, reached)
+		if err != nil {
+			return nil, fmt.Errorf("%v%s", err, changeNote(changeID))
+		}
+		if changeID != 0 {
+			if output, ok := result.StructuredContent.(TransitionIssueOutput); ok {
+				output.ChangeID = changeID
+				result.StructuredContent = output
+			}
+			result.Content = append(result.Content, mcp.NewTextContent(strings.TrimSpace(changeNote(changeID))))
 		}
+		return result, nil
 	}
 
-	response, err := client.Issue.Move(ctx, input.IssueKey, input.TransitionID, options)
+	transitions, err := fetchTransitions(ctx, client, input.IssueKey)
 	if err != nil {
-		if response != nil {
-			return nil, fmt.Errorf("transition failed: %s (endpoint: %s)",
-				response.Bytes.String(),
-				response.Endpoint)
+		return nil, err
+	}
+
+	var transition *workflowTransition
+	if input.ToStatus != "" {
+		transition, err = findTransitionTo(transitions, input.ToStatus)
+	} else {
+		transition, err = findTransitionByID(transitions, input.TransitionID)
+	}
+	if err != nil {
+		if input.ToStatus != "" {
+			return nil, fmt.Errorf("transition failed: %v (set find_path to go through intermediate statuses)", err)
 		}
 		return nil, fmt.Errorf("transition failed: %v", err)
 	}
 
-	return mcp.NewToolResultText("Issue transition completed successfully"), nil
+	edit, missing, err := transitionEdit(ctx, services.JiraFieldCatalog(), transition, input.Fields)
+	if err != nil {
+		return nil, fmt.Errorf("transition failed: %v", err)
+	}
+	if len(missing) > 0 {
+		return missingFieldsResult(input.IssueKey, transition, missing)
+	}
+	if comment != nil {
+		edit.verb("comment", "add", map[string]interface{}{"body": comment})
+	}
+
+	if input.DryRun {
+		output := TransitionIssueOutput{
+			IssueKey: input.IssueKey,
+			ToStatus: transitionTarget(transition),
+			Steps:    []TransitionStepOutput{{TransitionID: transition.ID, Name: transition.Name, To: transitionTarget(transition)}},
+			DryRun:   true,
+			Message:  fmt.Sprintf("Dry run, no changes made.\nIssue %s would transition via %q (ID: %s) to %s", input.IssueKey, transition.Name, transition.ID, transitionTarget(transition)),
+		}
+		return mcp.NewToolResultStructured(output, output.Message), nil
+	}
+
+	if err := performTransition(ctx, client, input.IssueKey, transition.ID, edit); err != nil {
+		return nil, err
+	}
+
+	output := TransitionIssueOutput{
+		IssueKey:   input.IssueKey,
+		FromStatus: fromStatus,
+		ToStatus:   transitionTarget(transition),
+		Steps:      []TransitionStepOutput{{TransitionID: transition.ID, Name: transition.Name, From: fromStatus, To: transitionTarget(transition)}},
+		Message:    fmt.Sprintf("Issue %s transitioned via %q (ID: %s) to %s", input.IssueKey, transition.Name, transition.ID, transitionTarget(transition)),
+	}
+	result := output.Message
+	if transition.To != nil {
+		output.ChangeID = recordTransition(input.IssueKey, fromStatus, transition.To.Name)
+		result += changeNote(output.ChangeID)
+	}
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// fetchTransitions lists the transitions currently available on an issue,
+// including the fields on each transition screen.
+func fetchTransitions(ctx context.Context, client *services.RawClient, issueKey string) ([]*workflowTransition, error) {
+	var result struct {
+		Transitions []*workflowTransition `json:"transitions"`
+	}
+	path := fmt.Sprintf("rest/api/3/issue/%s/transitions", url.PathEscape(issueKey))
+	query := url.Values{"expand": {"transitions.fields"}}
+	response, err := client.Do(ctx, http.MethodGet, path, query, nil, &result)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get transitions: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get transitions: %v", err)
+	}
+	return result.Transitions, nil
+}
+
+// performTransition executes transitionID on the issue, sending the screen
+// fields and update verbs collected in edit.
+func performTransition(ctx context.Context, client *services.RawClient, issueKey, transitionID string, edit *issueEdit) error {
+	body := edit.body()
+	body["transition"] = map[string]interface{}{"id": transitionID}
+
+	path := fmt.Sprintf("rest/api/3/issue/%s/transitions", url.PathEscape(issueKey))
+	response, err := client.Do(ctx, http.MethodPost, path, nil, body, nil)
+	if err != nil {
+		if fieldErrors := describeFieldErrors(ctx, response); fieldErrors != "" {
+			return fmt.Errorf("transition failed: %s (endpoint: %s)", fieldErrors, response.Endpoint)
+		}
+		if response != nil {
+			return fmt.Errorf("transition failed: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return fmt.Errorf("transition failed: %v", err)
+	}
+	return nil
+}
+
+// findTransitionTo picks the transition that lands on status (matched
+// case-insensitively). A transition whose own name matches is accepted as a
+// fallback, since workflows often name transitions after their target.
+func findTransitionTo(transitions []*workflowTransition, status string) (*workflowTransition, error) {
+	for _, transition := range transitions {
+		if transition.To != nil && strings.EqualFold(transition.To.Name, status) {
+			return transition, nil
+		}
+	}
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.Name, status) {
+			return transition, nil
+		}
+	}
+	return nil, fmt.Errorf("no transition leads to status %q; available: %s", status, describeTransitions(transitions))
+}
+
+func findTransitionByID(transitions []*workflowTransition, id string) (*workflowTransition, error) {
+	for _, transition := range transitions {
+		if transition.ID == id {
+			return transition, nil
+		}
+	}
+	return nil, fmt.Errorf("transition %s is not available; available: %s", id, describeTransitions(transitions))
+}
+
+// transitionEdit maps the caller's field values onto the transition screen
+// and reports required screen fields that are still empty. Keys are matched
+// against the screen's field IDs and names first, then the global field
+// catalog, so fields outside the screen can still be set by name.
+func transitionEdit(ctx context.Context, catalog *services.FieldCatalog, transition *workflowTransition, fields map[string]any) (*issueEdit, []missingTransitionField, error) {
+	edit := newIssueEdit()
+
+	screenByName := make(map[string]string, len(transition.Fields))
+	for id, meta := range transition.Fields {
+		screenByName[strings.ToLower(meta.Name)] = id
+	}
+
+	offScreen := map[string]any{}
+	for key, value := range fields {
+		id := key
+		if _, onScreen := transition.Fields[id]; !onScreen {
+			id = screenByName[strings.ToLower(strings.TrimSpace(key))]
+		}
+		meta, onScreen := transition.Fields[id]
+		if !onScreen {
+			offScreen[key] = value
+			continue
+		}
+		coerced, err := util.CoerceFieldValue(&models.IssueFieldScheme{ID: id, Name: meta.Name, Schema: meta.Schema}, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s (%s): %v", meta.Name, id, err)
+		}
+		edit.set(id, coerced)
+	}
+	if len(offScreen) > 0 {
+		if err := edit.applyFields(ctx, catalog, offScreen); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var missing []missingTransitionField
+	for id, meta := range transition.Fields {
+		if !meta.Required || meta.HasDefaultValue {
+			continue
+		}
+		if _, provided := edit.fields[id]; provided {
+			continue
+		}
+		entry := missingTransitionField{ID: id, Name: meta.Name}
+		if meta.Schema != nil {
+			entry.Type = meta.Schema.Type
+		}
+		for _, allowed := range meta.AllowedValues {
+			for _, key := range []string{"name", "value"} {
+				if label, ok := allowed[key].(string); ok && label != "" {
+					entry.AllowedValues = append(entry.AllowedValues, label)
+					break
+				}
+			}
+		}
+		missing = append(missing, entry)
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].ID < missing[j].ID })
+
+	return edit, missing, nil
+}
+
+// missingFieldsResult reports required fields as a YAML error result the
+// agent can act on without another lookup.
+func missingFieldsResult(issueKey string, transition *workflowTransition, missing []missingTransitionField) (*mcp.CallToolResult, error) {
+	report := map[string]interface{}{
+		"error":    "transition requires fields that were not provided; retry with `fields` set",
+		"issueKey": issueKey,
+		"transition": map[string]string{
+			"id":   transition.ID,
+			"name": transition.Name,
+			"to":   transitionTarget(transition),
+		},
+		"missingFields": missing,
+	}
+	yamlBytes, err := yaml.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal missing fields to YAML: %w", err)
+	}
+	return mcp.NewToolResultError(string(yamlBytes)), nil
+}
+
+func transitionTarget(transition *workflowTransition) string {
+	if transition.To == nil {
+		return "(unknown status)"
+	}
+	return transition.To.Name
+}
+
+func describeTransitions(transitions []*workflowTransition) string {
+	if len(transitions) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(transitions))
+	for _, transition := range transitions {
+		parts = append(parts, fmt.Sprintf("%q -> %s (ID: %s)", transition.Name, transitionTarget(transition), transition.ID))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// transitionComment converts input.Comment to ADF; it is nil when there is no
+// comment.
+func transitionComment(input TransitionIssueInput) (*models.CommentNodeScheme, error) {
+	if input.Comment == "" {
+		return nil, nil
+	}
+	body, err := util.BodyToADF(input.Comment, input.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comment: %v", err)
+	}
+	return body, nil
 }
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+const transitionsFixture = `{"transitions": [
+	{"id": "11", "name": "Start", "to": {"name": "In Progress"}, "fields": {}},
+	{"id": "31", "name": "Close", "to": {"name": "Done"}, "fields": {
+		"resolution": {"required": true, "name": "Resolution", "hasDefaultValue": false,
+			"schema": {"type": "resolution", "system": "resolution"},
+			"allowedValues": [{"id": "1", "name": "Fixed"}, {"id": "2", "name": "Won't Do"}]},
+		"fixVersions": {"required": false, "name": "Fix versions",
+			"schema": {"type": "array", "items": "version"}},
+		"assignee": {"required": true, "name": "Assignee", "hasDefaultValue": true,
+			"schema": {"type": "user"}}
+	}}
+]}`
+
+func newTransitionServer(t *testing.T, posted *map[string]any) *services.RawClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/X-1/transitions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("expand") != "transitions.fields" {
+				t.Errorf("transitions must be expanded with their fields, got %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(transitionsFixture))
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(posted); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+}
+
+func TestFindTransitionTo(t *testing.T) {
+	var posted map[string]any
+	client := newTransitionServer(t, &posted)
+	transitions, err := fetchTransitions(context.Background(), client, "X-1")
+	if err != nil {
+		t.Fatalf("fetchTransitions: %v", err)
+	}
+
+	got, err := findTransitionTo(transitions, "done")
+	if err != nil || got.ID != "31" {
+		t.Errorf("status match: got (%v, %v), want transition 31", got, err)
+	}
+	got, err = findTransitionTo(transitions, "Start")
+	if err != nil || got.ID != "11" {
+		t.Errorf("transition-name fallback: got (%v, %v), want transition 11", got, err)
+	}
+	if _, err := findTransitionTo(transitions, "Blocked"); err == nil || !strings.Contains(err.Error(), `"Close" -> Done (ID: 31)`) {
+		t.Errorf("expected error listing available transitions, got %v", err)
+	}
+}
+
+func TestTransitionEdit_ReportsMissingRequiredFields(t *testing.T) {
+	var posted map[string]any
+	transitions, err := fetchTransitions(context.Background(), newTransitionServer(t, &posted), "X-1")
+	if err != nil {
+		t.Fatalf("fetchTransitions: %v", err)
+	}
+	closeTransition, _ := findTransitionTo(transitions, "Done")
+
+	_, missing, err := transitionEdit(context.Background(), testFieldCatalog(), closeTransition, nil)
+	if err != nil {
+		t.Fatalf("transitionEdit: %v", err)
+	}
+	want := []missingTransitionField{{ID: "resolution", Name: "Resolution", Type: "resolution", AllowedValues: []string{"Fixed", "Won't Do"}}}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %+v, want %+v", missing, want)
+	}
+
+	result, err := missingFieldsResult("X-1", closeTransition, missing)
+	if err != nil || !result.IsError {
+		t.Fatalf("missingFieldsResult = (%v, %v), want an error result", result, err)
+	}
+}
+
+func TestTransitionEdit_FillsScreenAndCatalogFields(t *testing.T) {
+	var posted map[string]any
+	client := newTransitionServer(t, &posted)
+	transitions, err := fetchTransitions(context.Background(), client, "X-1")
+	if err != nil {
+		t.Fatalf("fetchTransitions: %v", err)
+	}
+	closeTransition, _ := findTransitionTo(transitions, "Done")
+
+	edit, missing, err := transitionEdit(context.Background(), testFieldCatalog(), closeTransition, map[string]any{
+		"Resolution":   "Fixed",
+		"fixVersions":  "1.2",
+		"Story Points": 3,
+	})
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("transitionEdit = (%v, %v)", missing, err)
+	}
+	edit.verb("comment", "add", map[string]interface{}{"body": "done"})
+
+	if err := performTransition(context.Background(), client, "X-1", closeTransition.ID, edit); err != nil {
+		t.Fatalf("performTransition: %v", err)
+	}
+
+	want := map[string]any{
+		"transition": map[string]any{"id": "31"},
+		"fields": map[string]any{
+			"resolution":        map[string]any{"name": "Fixed"},
+			"fixVersions":       []any{map[string]any{"name": "1.2"}},
+			"customfield_10016": 3.0,
+		},
+		"update": map[string]any{
+			"comment": []any{map[string]any{"add": map[string]any{"body": "done"}}},
+		},
+	}
+	if !reflect.DeepEqual(posted, want) {
+		t.Errorf("posted %#v\nwant   %#v", posted, want)
+	}
+}
+
+func TestTransitionIssue_DryRunWithoutFindPath(t *testing.T) {
+	var posted map[string]any
+	client := newTransitionServer(t, &posted)
+
+	for _, input := range []TransitionIssueInput{
+		{IssueKey: "X-1", ToStatus: "In Progress", DryRun: true},
+		{IssueKey: "X-1", TransitionID: "11", DryRun: true},
+	} {
+		result, err := transitionIssue(context.Background(), client, input)
+		if err != nil {
+			t.Fatalf("transitionIssue(%+v): %v", input, err)
+		}
+		output, ok := result.StructuredContent.(TransitionIssueOutput)
+		if !ok || !output.DryRun || len(output.Steps) != 1 || output.Steps[0].TransitionID != "11" {
+			t.Errorf("output = %+v", result.StructuredContent)
+		}
+	}
+	if posted != nil {
+		t.Errorf("dry run posted a transition: %v", posted)
+	}
+}
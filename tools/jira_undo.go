@@ -0,0 +1,509 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// Input types for typed tools
+type ListRecentChangesInput struct {
+	IssueKey string `json:"issue_key,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type UndoLastChangeInput struct {
+	IssueKey string `json:"issue_key,omitempty"`
+	ChangeID int    `json:"change_id,omitempty"`
+}
+
+// RecentChangeOutput is one journal entry, without the recorded values.
+type RecentChangeOutput struct {
+	ChangeID int        `json:"change_id"`
+	Time     string     `json:"time"`
+	Tool     string     `json:"tool"`
+	IssueKey string     `json:"issue_key"`
+	Kind     ChangeKind `json:"kind"`
+	Summary  string     `json:"summary"`
+	Undone   bool       `json:"undone"`
+}
+
+// ListRecentChangesOutput is the structured result of
+// jira_list_recent_changes.
+type ListRecentChangesOutput struct {
+	Changes []RecentChangeOutput `json:"changes"`
+}
+
+// UndoChangeOutput is the structured result of jira_undo_last_change.
+type UndoChangeOutput struct {
+	ChangeID int        `json:"change_id"`
+	Tool     string     `json:"tool"`
+	IssueKey string     `json:"issue_key"`
+	Kind     ChangeKind `json:"kind"`
+	Message  string     `json:"message"`
+}
+
+// recentChangesDefaultLimit is how many entries jira_list_recent_changes
+// shows when no limit is given.
+const recentChangesDefaultLimit = 20
+
+func RegisterJiraUndoTools(s *server.MCPServer, filter *Filter) {
+	jiraListRecentChangesTool := mcp.NewTool("jira_list_recent_changes",
+		mcp.WithDescription("List recent changes made through this server that can be undone: field updates, transitions, created links, added comments and archived issues, newest first, with the change ID to pass to jira_undo_last_change."),
+		mcp.WithString("issue_key", mcp.Description("Only list changes to this issue (e.g., KP-123)")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of changes to list (default %d)", recentChangesDefaultLimit))),
+		mcp.WithOutputSchema[ListRecentChangesOutput](),
+	)
+	filter.AddTool(s, jiraListRecentChangesTool, ToolRead, mcp.NewTypedToolHandler(jiraListRecentChangesHandler))
+
+	jiraUndoLastChangeTool := mcp.NewTool("jira_undo_last_change",
+		mcp.WithDescription("Undo a change made through this server: restore the field values a jira_update_issue overwrote, move a transitioned issue back to its previous status, delete a link or comment it created, or unarchive an issue archived by jira_delete_issue. Undoes the most recent change not yet undone unless change_id is given. Restored fields overwrite any edits made since."),
+		mcp.WithString("issue_key", mcp.Description("Undo the latest change to this issue (e.g., KP-123)")),
+		mcp.WithNumber("change_id", mcp.Description("ID of a specific change, from jira_list_recent_changes")),
+		mcp.WithOutputSchema[UndoChangeOutput](),
+	)
+	filter.AddTool(s, jiraUndoLastChangeTool, ToolWrite, mcp.NewTypedToolHandler(jiraUndoLastChangeHandler))
+}
+
+func jiraListRecentChangesHandler(ctx context.Context, request mcp.CallToolRequest, input ListRecentChangesInput) (*mcp.CallToolResult, error) {
+	journal := UndoJournal()
+	if journal == nil {
+		return nil, fmt.Errorf("the undo journal is disabled (JIRA_MCP_JOURNAL=off)")
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = recentChangesDefaultLimit
+	}
+	entries, err := journal.Recent(input.IssueKey, limit)
+	if err != nil {
+		return nil, err
+	}
+	output := ListRecentChangesOutput{Changes: []RecentChangeOutput{}}
+	for _, entry := range entries {
+		output.Changes = append(output.Changes, RecentChangeOutput{
+			ChangeID: entry.ID,
+			Time:     entry.Time.Format(time.RFC3339),
+			Tool:     entry.Tool,
+			IssueKey: entry.IssueKey,
+			Kind:     entry.Kind,
+			Summary:  entry.Summary,
+			Undone:   entry.UndoneAt != nil,
+		})
+	}
+	if len(entries) == 0 {
+		return mcp.NewToolResultStructured(output, "No changes recorded."), nil
+	}
+	return mcp.NewToolResultStructured(output, formatChangeEntries(entries)), nil
+}
+
+func jiraUndoLastChangeHandler(ctx context.Context, request mcp.CallToolRequest, input UndoLastChangeInput) (*mcp.CallToolResult, error) {
+	journal := UndoJournal()
+	if journal == nil {
+		return nil, fmt.Errorf("the undo journal is disabled (JIRA_MCP_JOURNAL=off)")
+	}
+	output, err := undoChange(ctx, services.JiraRawClient(), journal, input)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(output, output.Message), nil
+}
+
+// formatChangeEntries renders one line per entry, newest first.
+func formatChangeEntries(entries []ChangeEntry) string {
+	var sb strings.Builder
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("#%d %s %s %s: %s", entry.ID, entry.Time.Format("2006-01-02 15:04:05Z"), entry.Tool, entry.IssueKey, entry.Summary))
+		if entry.UndoneAt != nil {
+			sb.WriteString(" (undone)")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// undoChange reverses the change input selects and marks it undone.
+func undoChange(ctx context.Context, client *services.RawClient, journal *ChangeJournal, input UndoLastChangeInput) (UndoChangeOutput, error) {
+	entry, err := pickChange(journal, input)
+	if err != nil {
+		return UndoChangeOutput{}, err
+	}
+
+	var done string
+	switch entry.Kind {
+	case ChangeFields:
+		done, err = undoFields(ctx, client, entry)
+	case ChangeTransition:
+		done, err = undoTransition(ctx, client, entry)
+	case ChangeLink:
+		done, err = undoLink(ctx, client, entry)
+	case ChangeComment:
+		done, err = undoComment(ctx, client, entry)
+	case ChangeArchive:
+		done, err = undoArchive(ctx, client, entry)
+	default:
+		err = fmt.Errorf("change #%d has unknown kind %q", entry.ID, entry.Kind)
+	}
+	if err != nil {
+		return UndoChangeOutput{}, fmt.Errorf("failed to undo change #%d: %v", entry.ID, err)
+	}
+
+	if err := journal.MarkUndone(entry.ID); err != nil {
+		return UndoChangeOutput{}, fmt.Errorf("undid change #%d but could not mark it in the journal: %v", entry.ID, err)
+	}
+	return UndoChangeOutput{
+		ChangeID: entry.ID,
+		Tool:     entry.Tool,
+		IssueKey: entry.IssueKey,
+		Kind:     entry.Kind,
+		Message:  fmt.Sprintf("Undid change #%d (%s on %s): %s", entry.ID, entry.Tool, entry.IssueKey, done),
+	}, nil
+}
+
+// pickChange finds the change to undo: change_id if given, otherwise the
+// newest change not yet undone (to issue_key, if given). Field updates and
+// transitions must be undone newest first, so an older one is refused while
+// a later change to the same fields or status is still in place.
+func pickChange(journal *ChangeJournal, input UndoLastChangeInput) (ChangeEntry, error) {
+	var entry ChangeEntry
+	if input.ChangeID > 0 {
+		found, ok, err := journal.Get(input.ChangeID)
+		if err != nil {
+			return entry, err
+		}
+		if !ok {
+			return entry, fmt.Errorf("change #%d is not in the journal", input.ChangeID)
+		}
+		if input.IssueKey != "" && !strings.EqualFold(found.IssueKey, input.IssueKey) {
+			return entry, fmt.Errorf("change #%d was made to %s, not %s", found.ID, found.IssueKey, input.IssueKey)
+		}
+		if found.UndoneAt != nil {
+			return entry, fmt.Errorf("change #%d was already undone", found.ID)
+		}
+		entry = found
+	} else {
+		recent, err := journal.Recent(input.IssueKey, 0)
+		if err != nil {
+			return entry, err
+		}
+		for _, candidate := range recent {
+			if candidate.UndoneAt == nil {
+				entry = candidate
+				break
+			}
+		}
+		if entry.ID == 0 {
+			if input.IssueKey != "" {
+				return entry, fmt.Errorf("no changes to %s left to undo", input.IssueKey)
+			}
+			return entry, fmt.Errorf("no changes left to undo")
+		}
+	}
+
+	later, err := journal.Recent(entry.IssueKey, 0)
+	if err != nil {
+		return entry, err
+	}
+	for _, other := range later {
+		if other.ID > entry.ID && other.UndoneAt == nil && changesOverlap(entry, other) {
+			return entry, fmt.Errorf("change #%d to %s was overwritten by change #%d; undo #%d first", entry.ID, entry.IssueKey, other.ID, other.ID)
+		}
+	}
+	return entry, nil
+}
+
+// changesOverlap reports whether undoing a would clobber b.
+func changesOverlap(a, b ChangeEntry) bool {
+	if movesStatus(a) && movesStatus(b) {
+		return true
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case ChangeFields:
+		for fieldID := range a.Before {
+			if _, ok := b.Before[fieldID]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// undoFields writes the recorded values back.
+func undoFields(ctx context.Context, client *services.RawClient, entry ChangeEntry) (string, error) {
+	fields := make(map[string]any, len(entry.Before))
+	for fieldID, raw := range entry.Before {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", fmt.Errorf("recorded value of %s is not valid JSON: %v", fieldID, err)
+		}
+		fields[fieldID] = restorableValue(value)
+	}
+
+	endpoint := fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(entry.IssueKey))
+	response, err := client.Do(ctx, http.MethodPut, endpoint, nil, map[string]any{"fields": fields}, nil)
+	if err != nil {
+		if fieldErrors := describeFieldErrors(ctx, response); fieldErrors != "" {
+			return "", fmt.Errorf("failed to restore fields: %s (endpoint: %s)", fieldErrors, response.Endpoint)
+		}
+		if response != nil {
+			return "", fmt.Errorf("failed to restore fields: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to restore fields: %v", err)
+	}
+	return "restored " + strings.Join(sortedKeys(entry.Before), ", "), nil
+}
+
+// restorableValue turns a field value as Jira returns it into one it accepts
+// on update: references to users, options, versions, components and the like
+// are cut down to the identifying property, since the read form carries
+// properties (self, avatarUrls, ...) an update rejects. Time tracking keeps
+// only the estimates in Jira's notation, without their seconds.
+func restorableValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if v["type"] == "doc" {
+			return v
+		}
+		if isTimeTracking(v) {
+			estimates := map[string]any{}
+			for _, key := range []string{"originalEstimate", "remainingEstimate"} {
+				if estimate, ok := v[key]; ok {
+					estimates[key] = estimate
+				}
+			}
+			return estimates
+		}
+		for _, key := range []string{"accountId", "id", "name", "value"} {
+			if id, ok := v[key]; ok {
+				ref := map[string]any{key: id}
+				if child, ok := v["child"]; ok {
+					ref["child"] = restorableValue(child)
+				}
+				return ref
+			}
+		}
+		return v
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = restorableValue(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// isTimeTracking reports whether v is a timetracking field as Jira returns
+// it. Any of its parts may be missing: an issue with only a remaining
+// estimate has no originalEstimateSeconds.
+func isTimeTracking(v map[string]any) bool {
+	for _, key := range []string{"originalEstimateSeconds", "remainingEstimateSeconds", "timeSpentSeconds"} {
+		if _, ok := v[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// undoTransition moves the issue back to the status it left, if a single
+// transition leads there without screen fields.
+func undoTransition(ctx context.Context, client *services.RawClient, entry ChangeEntry) (string, error) {
+	transitions, err := fetchTransitions(ctx, client, entry.IssueKey)
+	if err != nil {
+		return "", err
+	}
+	transition, err := findTransitionTo(transitions, entry.FromStatus)
+	if err != nil {
+		return "", fmt.Errorf("%v (use jira_transition_issue with to_status %q and find_path)", err, entry.FromStatus)
+	}
+	edit, missing, err := transitionEdit(ctx, nil, transition, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for _, field := range missing {
+			names = append(names, field.Name)
+		}
+		return "", fmt.Errorf("the transition back to %s requires %s (use jira_transition_issue with to_status %q and fields)", entry.FromStatus, strings.Join(names, ", "), entry.FromStatus)
+	}
+	if err := performTransition(ctx, client, entry.IssueKey, transition.ID, edit); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("moved back to %s", entry.FromStatus), nil
+}
+
+// movesStatus reports whether undoing entry moves its issue back to an
+// earlier status.
+func movesStatus(entry ChangeEntry) bool {
+	return entry.Kind == ChangeTransition || (entry.Kind == ChangeArchive && entry.ToStatus != "")
+}
+
+// undoArchive moves an archived issue back to its previous status, then
+// removes the label the archive added; closed issues are often read-only.
+func undoArchive(ctx context.Context, client *services.RawClient, entry ChangeEntry) (string, error) {
+	var done []string
+	if entry.ToStatus != "" {
+		moved, err := undoTransition(ctx, client, entry)
+		if err != nil {
+			return "", err
+		}
+		done = append(done, moved)
+	}
+	if entry.Label != "" {
+		endpoint := fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(entry.IssueKey))
+		body := map[string]any{"update": map[string]any{"labels": []map[string]any{{"remove": entry.Label}}}}
+		response, err := client.Do(ctx, http.MethodPut, endpoint, nil, body, nil)
+		if err != nil {
+			if response != nil {
+				return "", fmt.Errorf("failed to remove label: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return "", fmt.Errorf("failed to remove label: %v", err)
+		}
+		done = append(done, fmt.Sprintf("removed label %q", entry.Label))
+	}
+	return "unarchived: " + strings.Join(done, ", "), nil
+}
+
+// undoLink deletes the link the change created.
+func undoLink(ctx context.Context, client *services.RawClient, entry ChangeEntry) (string, error) {
+	endpoint := fmt.Sprintf("rest/api/3/issueLink/%s", url.PathEscape(entry.LinkID))
+	response, err := client.Do(ctx, http.MethodDelete, endpoint, nil, nil, nil)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to delete link: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to delete link: %v", err)
+	}
+	return fmt.Sprintf("deleted link %s", entry.LinkID), nil
+}
+
+// undoComment deletes the comment the change added.
+func undoComment(ctx context.Context, client *services.RawClient, entry ChangeEntry) (string, error) {
+	endpoint := fmt.Sprintf("rest/api/3/issue/%s/comment/%s", url.PathEscape(entry.IssueKey), url.PathEscape(entry.CommentID))
+	response, err := client.Do(ctx, http.MethodDelete, endpoint, nil, nil, nil)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to delete comment: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to delete comment: %v", err)
+	}
+	return fmt.Sprintf("deleted comment %s", entry.CommentID), nil
+}
+
+// snapshotFields reads the current values of fieldIDs on issueKey, before an
+// update overwrites them.
+func snapshotFields(ctx context.Context, client *jira.Client, issueKey string, fieldIDs []string) (map[string]json.RawMessage, error) {
+	_, response, err := client.Issue.Get(ctx, issueKey, fieldIDs, nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to snapshot issue before update: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to snapshot issue before update: %v", err)
+	}
+	return extractFieldValues(response.Bytes.Bytes(), fieldIDs)
+}
+
+// extractFieldValues picks fieldIDs out of a raw issue document. Jira leaves
+// empty fields out, so a missing field is recorded as null.
+func extractFieldValues(rawIssue []byte, fieldIDs []string) (map[string]json.RawMessage, error) {
+	var issue struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(rawIssue, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse issue snapshot: %v", err)
+	}
+	values := make(map[string]json.RawMessage, len(fieldIDs))
+	for _, fieldID := range fieldIDs {
+		value, ok := issue.Fields[fieldID]
+		if !ok {
+			value = json.RawMessage("null")
+		}
+		values[fieldID] = value
+	}
+	return values, nil
+}
+
+// recordChange adds entry to the undo journal and returns its change ID, or
+// 0 when nothing was recorded. The change has already reached Jira, so a
+// journal failure is reported on stderr instead of failing the tool call.
+func recordChange(entry ChangeEntry) int {
+	return recordChangeIn(UndoJournal(), entry)
+}
+
+// recordChangeIn is recordChange for a given journal.
+func recordChangeIn(journal *ChangeJournal, entry ChangeEntry) int {
+	if journal == nil {
+		return 0
+	}
+	stored, err := journal.Record(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "undo journal: failed to record %s on %s: %v\n", entry.Tool, entry.IssueKey, err)
+		return 0
+	}
+	return stored.ID
+}
+
+// changeNote tells the agent how to reverse a recorded change, or returns ""
+// when changeID is 0.
+func changeNote(changeID int) string {
+	if changeID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nRecorded as change #%d; jira_undo_last_change reverts it.", changeID)
+}
+
+// recordTransition journals a move from one status to another. Nothing is
+// recorded when the starting status is unknown or the issue did not move.
+func recordTransition(issueKey, fromStatus, toStatus string) int {
+	if fromStatus == "" || strings.EqualFold(fromStatus, toStatus) {
+		return 0
+	}
+	return recordChange(ChangeEntry{
+		Tool:       "jira_transition_issue",
+		IssueKey:   issueKey,
+		Kind:       ChangeTransition,
+		Summary:    fmt.Sprintf("%s -> %s", fromStatus, toStatus),
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+	})
+}
+
+// createdLinkID reads the ID of a new issue link from the Location header
+// of the create response.
+func createdLinkID(response *models.ResponseScheme) string {
+	if response == nil || response.Response == nil {
+		return ""
+	}
+	location := response.Header.Get("Location")
+	if location == "" {
+		return ""
+	}
+	return path.Base(location)
+}
+
+func sortedKeys(values map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestRestorableValue(t *testing.T) {
+	var value any
+	_ = json.Unmarshal([]byte(`{
+		"assignee": {"accountId": "abc", "displayName": "Ann", "self": "https://x"},
+		"fixVersions": [{"id": "10", "name": "1.0", "self": "https://x"}],
+		"select": {"id": "7", "value": "Red", "child": {"id": "8", "value": "Dark", "self": "https://x"}},
+		"serverUser": {"name": "jdoe", "key": "JIRAUSER1", "emailAddress": "j@x"},
+		"description": {"type": "doc", "version": 1, "content": []},
+		"labels": ["a", "b"],
+		"points": 5,
+		"timetracking": {"originalEstimate": "1d", "remainingEstimate": "4h", "timeSpent": "4h", "originalEstimateSeconds": 28800, "remainingEstimateSeconds": 14400},
+		"remainingOnly": {"remainingEstimate": "2h", "timeSpent": "1h", "remainingEstimateSeconds": 7200, "timeSpentSeconds": 3600},
+		"spentOnly": {"timeSpent": "1h", "timeSpentSeconds": 3600}
+	}`), &value)
+
+	want := map[string]any{
+		"assignee":      map[string]any{"accountId": "abc"},
+		"fixVersions":   []any{map[string]any{"id": "10"}},
+		"select":        map[string]any{"id": "7", "child": map[string]any{"id": "8"}},
+		"serverUser":    map[string]any{"name": "jdoe"},
+		"description":   map[string]any{"type": "doc", "version": float64(1), "content": []any{}},
+		"labels":        []any{"a", "b"},
+		"points":        float64(5),
+		"timetracking":  map[string]any{"originalEstimate": "1d", "remainingEstimate": "4h"},
+		"remainingOnly": map[string]any{"remainingEstimate": "2h"},
+		"spentOnly":     map[string]any{},
+	}
+	fields := map[string]any{}
+	for key, member := range value.(map[string]any) {
+		fields[key] = restorableValue(member)
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("restorableValue = %#v, want %#v", fields, want)
+	}
+}
+
+func TestExtractFieldValues(t *testing.T) {
+	values, err := extractFieldValues([]byte(`{"key":"ABC-1","fields":{"summary":"Old title","labels":["x"]}}`), []string{"summary", "labels", "duedate"})
+	if err != nil {
+		t.Fatalf("extractFieldValues: %v", err)
+	}
+	got := map[string]string{}
+	for fieldID, raw := range values {
+		got[fieldID] = string(raw)
+	}
+	want := map[string]string{"summary": `"Old title"`, "labels": `["x"]`, "duedate": "null"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractFieldValues = %v, want %v (an absent field was empty and is restored as null)", got, want)
+	}
+}
+
+// fakeUndoServer answers the requests an undo makes and records the writes.
+func fakeUndoServer(t *testing.T) (*services.RawClient, *[]string) {
+	t.Helper()
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			var body any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			encoded, _ := json.Marshal(body)
+			writes = append(writes, r.Method+" "+r.URL.Path+" "+string(encoded))
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/ABC-1/transitions":
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"21","name":"Reopen","to":{"name":"To Do"}}]}`))
+		case r.Method == http.MethodGet:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}, &writes
+}
+
+func TestUndoChange(t *testing.T) {
+	client, writes := fakeUndoServer(t)
+	journal := NewChangeJournal("", "")
+	record := func(entry ChangeEntry) {
+		if _, err := journal.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record(ChangeEntry{Tool: "jira_update_issue", IssueKey: "ABC-1", Kind: ChangeFields, Before: map[string]json.RawMessage{
+		"summary":  json.RawMessage(`"Old title"`),
+		"assignee": json.RawMessage(`{"accountId":"abc","displayName":"Ann"}`),
+	}})
+	record(ChangeEntry{Tool: "jira_transition_issue", IssueKey: "ABC-1", Kind: ChangeTransition, FromStatus: "To Do", ToStatus: "Done"})
+	record(ChangeEntry{Tool: "jira_link_issues", IssueKey: "ABC-1", Kind: ChangeLink, LinkID: "555"})
+	record(ChangeEntry{Tool: "jira_add_comment", IssueKey: "DEF-2", Kind: ChangeComment, CommentID: "900"})
+
+	ctx := context.Background()
+	for _, input := range []UndoLastChangeInput{{}, {IssueKey: "ABC-1"}, {IssueKey: "ABC-1"}, {}} {
+		if _, err := undoChange(ctx, client, journal, input); err != nil {
+			t.Fatalf("undoChange(%+v): %v", input, err)
+		}
+	}
+
+	want := []string{
+		`DELETE /rest/api/3/issue/DEF-2/comment/900 null`,
+		`DELETE /rest/api/3/issueLink/555 null`,
+		`POST /rest/api/3/issue/ABC-1/transitions {"transition":{"id":"21"}}`,
+		`PUT /rest/api/3/issue/ABC-1 {"fields":{"assignee":{"accountId":"abc"},"summary":"Old title"}}`,
+	}
+	if !reflect.DeepEqual(*writes, want) {
+		t.Errorf("writes =\n%s\nwant\n%s", strings.Join(*writes, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := undoChange(ctx, client, journal, UndoLastChangeInput{}); err == nil || !strings.Contains(err.Error(), "no changes left") {
+		t.Errorf("undoing with nothing left should fail, got %v", err)
+	}
+	if _, err := undoChange(ctx, client, journal, UndoLastChangeInput{ChangeID: 1}); err == nil || !strings.Contains(err.Error(), "already undone") {
+		t.Errorf("undoing twice should fail, got %v", err)
+	}
+}
+
+func TestUndoChange_Archive(t *testing.T) {
+	client, writes := fakeUndoServer(t)
+	journal := NewChangeJournal("", "")
+	for _, entry := range []ChangeEntry{
+		{Tool: "jira_transition_issue", IssueKey: "ABC-1", Kind: ChangeTransition, FromStatus: "Backlog", ToStatus: "To Do"},
+		{Tool: "jira_delete_issue", IssueKey: "ABC-1", Kind: ChangeArchive, FromStatus: "To Do", ToStatus: "Archived", Label: "archived"},
+	} {
+		if _, err := journal.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	if _, err := undoChange(ctx, client, journal, UndoLastChangeInput{ChangeID: 1}); err == nil || !strings.Contains(err.Error(), "undo #2 first") {
+		t.Errorf("a transition must wait for the archive that moved the issue on, got %v", err)
+	}
+	output, err := undoChange(ctx, client, journal, UndoLastChangeInput{IssueKey: "ABC-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.ChangeID != 2 || !strings.Contains(output.Message, `unarchived: moved back to To Do, removed label "archived"`) {
+		t.Errorf("output = %+v", output)
+	}
+	want := []string{
+		`POST /rest/api/3/issue/ABC-1/transitions {"transition":{"id":"21"}}`,
+		`PUT /rest/api/3/issue/ABC-1 {"update":{"labels":[{"remove":"archived"}]}}`,
+	}
+	if !reflect.DeepEqual(*writes, want) {
+		t.Errorf("writes =\n%s\nwant\n%s", strings.Join(*writes, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPickChange_NewestFirst(t *testing.T) {
+	journal := NewChangeJournal("", "")
+	for _, entry := range []ChangeEntry{
+		{IssueKey: "ABC-1", Kind: ChangeFields, Before: map[string]json.RawMessage{"summary": json.RawMessage(`"v1"`)}},
+		{IssueKey: "ABC-1", Kind: ChangeFields, Before: map[string]json.RawMessage{"summary": json.RawMessage(`"v2"`)}},
+		{IssueKey: "ABC-1", Kind: ChangeFields, Before: map[string]json.RawMessage{"labels": json.RawMessage(`[]`)}},
+	} {
+		if _, err := journal.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := pickChange(journal, UndoLastChangeInput{ChangeID: 1}); err == nil || !strings.Contains(err.Error(), "undo #2 first") {
+		t.Errorf("an older update to the same field must wait for the newer one, got %v", err)
+	}
+	if entry, err := pickChange(journal, UndoLastChangeInput{ChangeID: 2}); err != nil || entry.ID != 2 {
+		t.Errorf("a change to other fields should not block, got %+v, %v", entry, err)
+	}
+	if _, err := pickChange(journal, UndoLastChangeInput{ChangeID: 2, IssueKey: "DEF-1"}); err == nil {
+		t.Errorf("a change_id from another issue must be refused")
+	}
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// accountIDPattern matches Jira Cloud account IDs: the legacy 24-character
+// hex form and the newer "<number>:<uuid>" form.
+var accountIDPattern = regexp.MustCompile(`^([0-9a-f]{24}|\d+:[0-9a-f-]{36})$`)
+
+// userSearchLimit bounds the candidates listed when a lookup is ambiguous.
+const userSearchLimit = 10
+
+// resolveUser finds the Jira user an agent refers to. It accepts "me", an
+// account ID, an email address, a display name, or (on Server/DC) a
+// username. Exact matches on email, display name or username win; a search
+// that returns a single user is accepted as-is; anything else is reported as
+// ambiguous with the candidates listed.
+func resolveUser(ctx context.Context, client *jira.Client, query string) (*models.UserScheme, error) {
+	query = strings.TrimSpace(query)
+	if strings.EqualFold(query, "me") {
+		user, response, err := client.MySelf.Details(ctx, nil)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to get current user: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return nil, fmt.Errorf("failed to get current user: %v", err)
+		}
+		return user, nil
+	}
+	if accountIDPattern.MatchString(query) {
+		return &models.UserScheme{AccountID: query}, nil
+	}
+
+	users, response, err := client.User.Search.Do(ctx, "", query, 0, userSearchLimit)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to search users: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to search users: %v", err)
+	}
+
+	var exact []*models.UserScheme
+	for _, user := range users {
+		if strings.EqualFold(user.EmailAddress, query) || strings.EqualFold(user.DisplayName, query) || strings.EqualFold(user.Name, query) {
+			exact = append(exact, user)
+		}
+	}
+
+	switch {
+	case len(exact) == 1:
+		return exact[0], nil
+	case len(exact) > 1:
+		return nil, fmt.Errorf("%q matches several users, use an account ID: %s", query, describeUsers(exact))
+	case len(users) == 1:
+		return users[0], nil
+	case len(users) == 0:
+		return nil, fmt.Errorf("no user matches %q", query)
+	default:
+		return nil, fmt.Errorf("%q matches several users, be more specific: %s", query, describeUsers(users))
+	}
+}
+
+// userPayload returns the reference Jira expects when a user is written to
+// a field: the account ID on Cloud, the username on Server/DC (where users
+// have no account ID).
+func userPayload(user *models.UserScheme) map[string]interface{} {
+	if user.AccountID != "" {
+		return map[string]interface{}{"accountId": user.AccountID}
+	}
+	return map[string]interface{}{"name": user.Name}
+}
+
+func describeUsers(users []*models.UserScheme) string {
+	parts := make([]string, 0, len(users))
+	for _, user := range users {
+		label := user.DisplayName
+		if user.EmailAddress != "" {
+			label += " <" + user.EmailAddress + ">"
+		}
+		id := user.AccountID
+		if id == "" {
+			id = user.Name
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", label, id))
+	}
+	return strings.Join(parts, "; ")
+}
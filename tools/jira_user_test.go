@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// newTestJiraClient returns an SDK client pointed at a test server that
+// answers every request with handler.
+func newTestJiraClient(t *testing.T, handler http.HandlerFunc) *jira.Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := jira.New(srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatalf("jira.New: %v", err)
+	}
+	client.Auth.SetBasicAuth("a@b.c", "tkn")
+	return client
+}
+
+func userSearchServer(t *testing.T, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/user/search" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestResolveUser_ExactEmailWins(t *testing.T) {
+	client := newTestJiraClient(t, userSearchServer(t, `[
+		{"accountId":"a1","displayName":"Alice Smith","emailAddress":"alice@corp.com"},
+		{"accountId":"a2","displayName":"Alice Jones","emailAddress":"alice.jones@corp.com"}
+	]`))
+
+	user, err := resolveUser(context.Background(), client, "Alice@corp.com")
+	if err != nil {
+		t.Fatalf("resolveUser: %v", err)
+	}
+	if user.AccountID != "a1" {
+		t.Errorf("accountId = %s, want a1", user.AccountID)
+	}
+}
+
+func TestResolveUser_SingleResult(t *testing.T) {
+	client := newTestJiraClient(t, userSearchServer(t, `[{"accountId":"b1","displayName":"Bob"}]`))
+
+	user, err := resolveUser(context.Background(), client, "bob@corp")
+	if err != nil {
+		t.Fatalf("resolveUser: %v", err)
+	}
+	if user.AccountID != "b1" {
+		t.Errorf("accountId = %s, want b1", user.AccountID)
+	}
+}
+
+func TestResolveUser_Ambiguous(t *testing.T) {
+	client := newTestJiraClient(t, userSearchServer(t, `[
+		{"accountId":"a1","displayName":"Alice Smith"},
+		{"accountId":"a2","displayName":"Alice Jones"}
+	]`))
+
+	_, err := resolveUser(context.Background(), client, "alice")
+	if err == nil || !strings.Contains(err.Error(), "Alice Smith (a1); Alice Jones (a2)") {
+		t.Errorf("expected ambiguity error listing candidates, got %v", err)
+	}
+}
+
+func TestResolveUser_NoMatch(t *testing.T) {
+	client := newTestJiraClient(t, userSearchServer(t, `[]`))
+
+	if _, err := resolveUser(context.Background(), client, "nobody"); err == nil {
+		t.Error("expected an error when no user matches")
+	}
+}
+
+func TestResolveUser_AccountIDSkipsSearch(t *testing.T) {
+	client := newTestJiraClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("no request expected, got %s", r.URL)
+	})
+
+	id := "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077"
+	user, err := resolveUser(context.Background(), client, id)
+	if err != nil || user.AccountID != id {
+		t.Errorf("got (%+v, %v)", user, err)
+	}
+}
+
+func TestResolveUser_Me(t *testing.T) {
+	client := newTestJiraClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/myself" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"accountId":"me1","displayName":"Me"}`))
+	})
+
+	user, err := resolveUser(context.Background(), client, "me")
+	if err != nil || user.AccountID != "me1" {
+		t.Errorf("got (%+v, %v)", user, err)
+	}
+}
+
+func TestUserPayload(t *testing.T) {
+	if got := userPayload(&models.UserScheme{AccountID: "a1", Name: "alice"}); !reflect.DeepEqual(got, map[string]interface{}{"accountId": "a1"}) {
+		t.Errorf("cloud payload = %#v", got)
+	}
+	if got := userPayload(&models.UserScheme{Name: "alice"}); !reflect.DeepEqual(got, map[string]interface{}{"name": "alice"}) {
+		t.Errorf("server payload = %#v", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
@@ -19,18 +20,52 @@ type ListProjectVersionsInput struct {
 	ProjectKey string `json:"project_key" validate:"required"`
 }
 
+// VersionOutput describes a project version.
+type VersionOutput struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ProjectID   int    `json:"project_id,omitempty"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// ListProjectVersionsOutput is the structured result of
+// jira_list_project_versions.
+type ListProjectVersionsOutput struct {
+	ProjectKey string          `json:"project_key"`
+	Versions   []VersionOutput `json:"versions"`
+}
+
+func newVersionOutput(version *models.VersionScheme) VersionOutput {
+	return VersionOutput{
+		ID:          version.ID,
+		Name:        version.Name,
+		Description: version.Description,
+		ProjectID:   version.ProjectID,
+		Released:    version.Released,
+		Archived:    version.Archived,
+		ReleaseDate: version.ReleaseDate,
+		URL:         version.Self,
+	}
+}
+
 func RegisterJiraVersionTool(s *server.MCPServer, filter *Filter) {
 	jiraGetVersionTool := mcp.NewTool("jira_get_version",
 		mcp.WithDescription("Retrieve detailed information about a specific Jira project version including its name, description, release date, and status"),
 		mcp.WithString("version_id", mcp.Required(), mcp.Description("The unique identifier of the version to retrieve (e.g., 10000)")),
+		mcp.WithOutputSchema[VersionOutput](),
 	)
-	filter.AddTool(s, jiraGetVersionTool, mcp.NewTypedToolHandler(jiraGetVersionHandler))
+	filter.AddTool(s, jiraGetVersionTool, ToolRead, mcp.NewTypedToolHandler(jiraGetVersionHandler))
 
 	jiraListProjectVersionsTool := mcp.NewTool("jira_list_project_versions",
 		mcp.WithDescription("List all versions in a Jira project with their details including names, descriptions, release dates, and statuses"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier to list versions for (e.g., KP, PROJ)")),
+		mcp.WithOutputSchema[ListProjectVersionsOutput](),
 	)
-	filter.AddTool(s, jiraListProjectVersionsTool, mcp.NewTypedToolHandler(jiraListProjectVersionsHandler))
+	filter.AddTool(s, jiraListProjectVersionsTool, ToolRead, mcp.NewTypedToolHandler(jiraListProjectVersionsHandler))
 }
 
 func jiraGetVersionHandler(ctx context.Context, request mcp.CallToolRequest, input GetVersionInput) (*mcp.CallToolResult, error) {
@@ -68,7 +103,7 @@ func jiraGetVersionHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		result.WriteString(fmt.Sprintf("URL: %s\n", version.Self))
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return mcp.NewToolResultStructured(newVersionOutput(version), result.String()), nil
 }
 
 func jiraListProjectVersionsHandler(ctx context.Context, request mcp.CallToolRequest, input ListProjectVersionsInput) (*mcp.CallToolResult, error) {
@@ -82,8 +117,9 @@ func jiraListProjectVersionsHandler(ctx context.Context, request mcp.CallToolReq
 		return nil, fmt.Errorf("failed to list project versions: %v", err)
 	}
 
+	output := ListProjectVersionsOutput{ProjectKey: input.ProjectKey, Versions: []VersionOutput{}}
 	if len(versions) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No versions found for project %s.", input.ProjectKey)), nil
+		return mcp.NewToolResultStructured(output, fmt.Sprintf("No versions found for project %s.", input.ProjectKey)), nil
 	}
 
 	var result strings.Builder
@@ -117,7 +153,8 @@ func jiraListProjectVersionsHandler(ctx context.Context, request mcp.CallToolReq
 		if version.Released {
 			result.WriteString(fmt.Sprintf("Start Date: %t\n", version.Released))
 		}
+		output.Versions = append(output.Versions, newVersionOutput(version))
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return mcp.NewToolResultStructured(output, result.String()), nil
 }
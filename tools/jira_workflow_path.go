@@ -0,0 +1,380 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"gopkg.in/yaml.v3"
+)
+
+// workflowEdge is one transition of a workflow definition. From is empty for
+// global transitions, which are available from every status.
+type workflowEdge struct {
+	TransitionID string
+	Name         string
+	From         string
+	To           string
+}
+
+// workflowGraph is a workflow reduced to what path-finding needs: status
+// names by ID and the transitions between them.
+type workflowGraph struct {
+	StatusNames map[string]string
+	Edges       []workflowEdge
+}
+
+// statusName returns the display name of a status ID, or the ID itself.
+func (g *workflowGraph) statusName(id string) string {
+	if name, ok := g.StatusNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+// shortestPath runs a breadth-first search from the status fromID to the
+// status named target (or with that ID) and returns the transitions to take.
+// An empty path means the issue is already there.
+func (g *workflowGraph) shortestPath(fromID, target string) ([]workflowEdge, error) {
+	isTarget := func(id string) bool {
+		return id == target || strings.EqualFold(g.statusName(id), target)
+	}
+	if isTarget(fromID) {
+		return nil, nil
+	}
+
+	outgoing := make(map[string][]workflowEdge)
+	var global []workflowEdge
+	for _, edge := range g.Edges {
+		if edge.From == "" {
+			global = append(global, edge)
+		} else {
+			outgoing[edge.From] = append(outgoing[edge.From], edge)
+		}
+	}
+
+	cameBy := map[string]workflowEdge{}
+	visited := map[string]bool{fromID: true}
+	queue := []string{fromID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		candidates := append(append([]workflowEdge{}, outgoing[current]...), global...)
+		for _, edge := range candidates {
+			if visited[edge.To] {
+				continue
+			}
+			visited[edge.To] = true
+			edge.From = current
+			cameBy[edge.To] = edge
+
+			if isTarget(edge.To) {
+				var path []workflowEdge
+				for at := edge.To; at != fromID; at = cameBy[at].From {
+					path = append([]workflowEdge{cameBy[at]}, path...)
+				}
+				return path, nil
+			}
+			queue = append(queue, edge.To)
+		}
+	}
+	return nil, fmt.Errorf("status %q is not reachable from %q in this workflow", target, g.statusName(fromID))
+}
+
+// issueWorkflowContext is what path-finding needs to know about an issue.
+type issueWorkflowContext struct {
+	StatusID    string
+	StatusName  string
+	ProjectID   string
+	IssueTypeID string
+}
+
+func fetchIssueWorkflowContext(ctx context.Context, client *services.RawClient, issueKey string) (*issueWorkflowContext, error) {
+	var issue struct {
+		Fields struct {
+			Status    struct{ ID, Name string } `json:"status"`
+			Project   struct{ ID string }       `json:"project"`
+			IssueType struct{ ID string }       `json:"issuetype"`
+		} `json:"fields"`
+	}
+	path := fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(issueKey))
+	response, err := client.Do(ctx, http.MethodGet, path, url.Values{"fields": {"status,project,issuetype"}}, nil, &issue)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get issue: %v", err)
+	}
+	return &issueWorkflowContext{
+		StatusID:    issue.Fields.Status.ID,
+		StatusName:  issue.Fields.Status.Name,
+		ProjectID:   issue.Fields.Project.ID,
+		IssueTypeID: issue.Fields.IssueType.ID,
+	}, nil
+}
+
+// errWorkflowUnreadable is returned by fetchWorkflowGraph when the account
+// may not read workflow definitions, or the instance has no such endpoint
+// (Server/Data Center lacks workflow search).
+var errWorkflowUnreadable = errors.New("the workflow definition is not readable: it needs Jira admin permission and Jira Cloud")
+
+// workflowReadError wraps a failed workflow request, marking 401, 403 and
+// 404 responses as errWorkflowUnreadable.
+func workflowReadError(what string, response *models.ResponseScheme, err error) error {
+	if response == nil {
+		return fmt.Errorf("failed to get %s: %v", what, err)
+	}
+	switch response.Code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return fmt.Errorf("%w (failed to get %s: %s (endpoint: %s))", errWorkflowUnreadable, what, response.Bytes.String(), response.Endpoint)
+	}
+	return fmt.Errorf("failed to get %s: %s (endpoint: %s)", what, response.Bytes.String(), response.Endpoint)
+}
+
+// fetchWorkflowGraph loads the workflow an issue type uses in a project: the
+// project's workflow scheme names the workflow, and workflow search returns
+// its statuses and transitions. Both endpoints need Jira admin permission
+// and only cover company-managed projects; see liveWorkflowGraph for when
+// they cannot be read.
+func fetchWorkflowGraph(ctx context.Context, client *services.RawClient, projectID, issueTypeID string) (*workflowGraph, error) {
+	var schemes struct {
+		Values []struct {
+			WorkflowScheme struct {
+				DefaultWorkflow   string            `json:"defaultWorkflow"`
+				IssueTypeMappings map[string]string `json:"issueTypeMappings"`
+			} `json:"workflowScheme"`
+		} `json:"values"`
+	}
+	response, err := client.Do(ctx, http.MethodGet, "rest/api/3/workflowscheme/project", url.Values{"projectId": {projectID}}, nil, &schemes)
+	if err != nil {
+		return nil, workflowReadError("workflow scheme", response, err)
+	}
+	if len(schemes.Values) == 0 {
+		return nil, fmt.Errorf("project %s has no workflow scheme (team-managed projects are not supported)", projectID)
+	}
+	scheme := schemes.Values[0].WorkflowScheme
+	workflowName := scheme.IssueTypeMappings[issueTypeID]
+	if workflowName == "" {
+		workflowName = scheme.DefaultWorkflow
+	}
+
+	var workflows struct {
+		Values []struct {
+			Transitions []struct {
+				ID   string   `json:"id"`
+				Name string   `json:"name"`
+				From []string `json:"from"`
+				To   string   `json:"to"`
+				Type string   `json:"type"`
+			} `json:"transitions"`
+			Statuses []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"statuses"`
+		} `json:"values"`
+	}
+	query := url.Values{"workflowName": {workflowName}, "expand": {"transitions,statuses"}}
+	response, err = client.Do(ctx, http.MethodGet, "rest/api/3/workflow/search", query, nil, &workflows)
+	if err != nil {
+		return nil, workflowReadError(fmt.Sprintf("workflow %q", workflowName), response, err)
+	}
+	if len(workflows.Values) == 0 {
+		return nil, fmt.Errorf("workflow %q not found", workflowName)
+	}
+
+	workflow := workflows.Values[0]
+	graph := &workflowGraph{StatusNames: make(map[string]string, len(workflow.Statuses))}
+	for _, status := range workflow.Statuses {
+		graph.StatusNames[status.ID] = status.Name
+	}
+	for _, transition := range workflow.Transitions {
+		if transition.Type == "initial" {
+			// Creates the issue; it cannot be taken from any status.
+			continue
+		}
+		if len(transition.From) == 0 {
+			graph.Edges = append(graph.Edges, workflowEdge{TransitionID: transition.ID, Name: transition.Name, To: transition.To})
+			continue
+		}
+		for _, from := range transition.From {
+			graph.Edges = append(graph.Edges, workflowEdge{TransitionID: transition.ID, Name: transition.Name, From: from, To: transition.To})
+		}
+	}
+	return graph, nil
+}
+
+// liveWorkflowGraph is the part of the workflow visible without admin
+// permission: the transitions the issue can take from its current status.
+// It only finds single-step paths.
+func liveWorkflowGraph(issue *issueWorkflowContext, transitions []*workflowTransition) *workflowGraph {
+	graph := &workflowGraph{StatusNames: map[string]string{issue.StatusID: issue.StatusName}}
+	for _, transition := range transitions {
+		if transition.To == nil {
+			continue
+		}
+		graph.StatusNames[transition.To.ID] = transition.To.Name
+		graph.Edges = append(graph.Edges, workflowEdge{TransitionID: transition.ID, Name: transition.Name, From: issue.StatusID, To: transition.To.ID})
+	}
+	return graph
+}
+
+// describePath renders a plan, one numbered hop per line.
+func describePath(graph *workflowGraph, path []workflowEdge) string {
+	var sb strings.Builder
+	for i, hop := range path {
+		sb.WriteString(fmt.Sprintf("%d. %q (ID: %s): %s -> %s\n", i+1, hop.Name, hop.TransitionID, graph.statusName(hop.From), graph.statusName(hop.To)))
+	}
+	return sb.String()
+}
+
+// transitionAlongPath moves an issue to input.ToStatus through as many
+// transitions as the workflow requires. Each hop is re-validated against the
+// issue's live transitions before it runs, so conditions and validators
+// still apply. Screen fields from input.Fields are sent on the hop whose
+// screen has them; fields off every screen and the comment go with the last
+// hop. With dry_run the plan is returned without changing anything.
+//
+// It also returns the status the issue was left in, or "" when it did not
+// move, including when a later hop failed or stopped for missing fields.
+func transitionAlongPath(ctx context.Context, client *services.RawClient, input TransitionIssueInput) (*mcp.CallToolResult, string, error) {
+	comment, err := transitionComment(input)
+	if err != nil {
+		return nil, "", err
+	}
+	issue, err := fetchIssueWorkflowContext(ctx, client, input.IssueKey)
+	if err != nil {
+		return nil, "", err
+	}
+	graph, err := fetchWorkflowGraph(ctx, client, issue.ProjectID, issue.IssueTypeID)
+	if errors.Is(err, errWorkflowUnreadable) {
+		// Without the definition only a direct transition can be planned.
+		transitions, liveErr := fetchTransitions(ctx, client, input.IssueKey)
+		if liveErr != nil {
+			return nil, "", liveErr
+		}
+		graph = liveWorkflowGraph(issue, transitions)
+		if _, pathErr := graph.shortestPath(issue.StatusID, input.ToStatus); pathErr != nil {
+			return nil, "", fmt.Errorf("cannot plan a multi-step transition: %q is not one transition away from %s, and %v. Move the issue step by step; available transitions: %s", input.ToStatus, issue.StatusName, err, describeTransitions(transitions))
+		}
+	} else if err != nil {
+		return nil, "", fmt.Errorf("cannot plan a multi-step transition: %v", err)
+	}
+	path, err := graph.shortestPath(issue.StatusID, input.ToStatus)
+	if err != nil {
+		return nil, "", fmt.Errorf("transition failed: %v", err)
+	}
+	output := TransitionIssueOutput{IssueKey: input.IssueKey, FromStatus: issue.StatusName, ToStatus: issue.StatusName, DryRun: input.DryRun}
+	if len(path) == 0 {
+		output.Message = fmt.Sprintf("Issue %s is already in status %s", input.IssueKey, issue.StatusName)
+		return mcp.NewToolResultStructured(output, output.Message), "", nil
+	}
+	output.ToStatus = graph.statusName(path[len(path)-1].To)
+	for _, hop := range path {
+		output.Steps = append(output.Steps, TransitionStepOutput{TransitionID: hop.TransitionID, Name: hop.Name, From: graph.statusName(hop.From), To: graph.statusName(hop.To)})
+	}
+
+	plan := fmt.Sprintf("Path for %s from %s to %s (%d steps):\n%s", input.IssueKey, issue.StatusName, output.ToStatus, len(path), describePath(graph, path))
+	if input.DryRun {
+		output.Message = "Dry run, no changes made.\n" + plan
+		return mcp.NewToolResultStructured(output, output.Message), "", nil
+	}
+
+	var reached string
+	stopped := func(err error) error {
+		if reached == "" {
+			return err
+		}
+		return fmt.Errorf("%v; the issue was left in %s", err, reached)
+	}
+	for i, hop := range path {
+		last := i == len(path)-1
+
+		transitions, err := fetchTransitions(ctx, client, input.IssueKey)
+		if err != nil {
+			return nil, reached, stopped(fmt.Errorf("stopped before step %d: %v", i+1, err))
+		}
+		transition, err := findTransitionByID(transitions, hop.TransitionID)
+		if err != nil {
+			if transition, err = findTransitionTo(transitions, graph.statusName(hop.To)); err != nil {
+				return nil, reached, stopped(fmt.Errorf("stopped before step %d: %v", i+1, err))
+			}
+		}
+
+		fields := input.Fields
+		if !last {
+			fields = screenFields(transition, input.Fields)
+		}
+		edit, missing, err := transitionEdit(ctx, services.JiraFieldCatalog(), transition, fields)
+		if err != nil {
+			return nil, reached, stopped(fmt.Errorf("stopped before step %d: %v", i+1, err))
+		}
+		if len(missing) > 0 {
+			result, err := stoppedOnPathResult(input.IssueKey, graph, path, i, missing)
+			return result, reached, err
+		}
+		if last && comment != nil {
+			edit.verb("comment", "add", map[string]interface{}{"body": comment})
+		}
+
+		if err := performTransition(ctx, client, input.IssueKey, transition.ID, edit); err != nil {
+			return nil, reached, stopped(fmt.Errorf("stopped at step %d of %d: %v", i+1, len(path), err))
+		}
+		reached = graph.statusName(hop.To)
+	}
+
+	output.Message = fmt.Sprintf("Issue %s moved to %s in %d steps:\n%s", input.IssueKey, output.ToStatus, len(path), describePath(graph, path))
+	return mcp.NewToolResultStructured(output, output.Message), reached, nil
+}
+
+// screenFields keeps only the entries of fields that appear on the
+// transition's screen, matched by field ID or name.
+func screenFields(transition *workflowTransition, fields map[string]any) map[string]any {
+	out := map[string]any{}
+	for key, value := range fields {
+		if _, ok := transition.Fields[key]; ok {
+			out[key] = value
+			continue
+		}
+		for _, meta := range transition.Fields {
+			if strings.EqualFold(meta.Name, strings.TrimSpace(key)) {
+				out[key] = value
+				break
+			}
+		}
+	}
+	return out
+}
+
+// stoppedOnPathResult reports a multi-step transition that halted because a
+// hop's screen needs fields the caller did not provide.
+func stoppedOnPathResult(issueKey string, graph *workflowGraph, path []workflowEdge, step int, missing []missingTransitionField) (*mcp.CallToolResult, error) {
+	completed := make([]string, 0, step)
+	for _, hop := range path[:step] {
+		completed = append(completed, fmt.Sprintf("%s -> %s", graph.statusName(hop.From), graph.statusName(hop.To)))
+	}
+	hop := path[step]
+	report := map[string]interface{}{
+		"error":          "multi-step transition stopped: a step requires fields that were not provided; retry with `fields` set",
+		"issueKey":       issueKey,
+		"currentStatus":  graph.statusName(hop.From),
+		"completedSteps": completed,
+		"blockedStep": map[string]string{
+			"id":   hop.TransitionID,
+			"name": hop.Name,
+			"to":   graph.statusName(hop.To),
+		},
+		"missingFields": missing,
+	}
+	yamlBytes, err := yaml.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal missing fields to YAML: %w", err)
+	}
+	return mcp.NewToolResultError(string(yamlBytes)), nil
+}
@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func testWorkflowGraph() *workflowGraph {
+	return &workflowGraph{
+		StatusNames: map[string]string{"1": "To Do", "3": "In Progress", "4": "In Review", "5": "Done", "6": "Blocked"},
+		Edges: []workflowEdge{
+			{TransitionID: "11", Name: "Start", From: "1", To: "3"},
+			{TransitionID: "21", Name: "Review", From: "3", To: "4"},
+			{TransitionID: "31", Name: "Approve", From: "4", To: "5"},
+			{TransitionID: "41", Name: "Reopen", From: "5", To: "1"},
+			{TransitionID: "51", Name: "Block", To: "6"},
+		},
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	graph := testWorkflowGraph()
+
+	path, err := graph.shortestPath("1", "done")
+	if err != nil {
+		t.Fatalf("shortestPath: %v", err)
+	}
+	var ids []string
+	for _, hop := range path {
+		ids = append(ids, hop.TransitionID)
+	}
+	if strings.Join(ids, ",") != "11,21,31" {
+		t.Errorf("path = %v, want 11,21,31", ids)
+	}
+	if got := describePath(graph, path); !strings.HasPrefix(got, `1. "Start" (ID: 11): To Do -> In Progress`) {
+		t.Errorf("describePath = %q", got)
+	}
+}
+
+func TestShortestPath_GlobalTransition(t *testing.T) {
+	path, err := testWorkflowGraph().shortestPath("4", "Blocked")
+	if err != nil {
+		t.Fatalf("shortestPath: %v", err)
+	}
+	if len(path) != 1 || path[0].TransitionID != "51" || path[0].From != "4" {
+		t.Errorf("path = %+v, want the global Block transition from In Review", path)
+	}
+}
+
+func TestShortestPath_AlreadyThereAndUnreachable(t *testing.T) {
+	graph := testWorkflowGraph()
+	if path, err := graph.shortestPath("5", "Done"); err != nil || len(path) != 0 {
+		t.Errorf("already in target: got (%v, %v)", path, err)
+	}
+	if _, err := graph.shortestPath("1", "Archived"); err == nil {
+		t.Error("expected an error for an unreachable status")
+	}
+}
+
+// workflowServerOptions changes how fakeWorkflowServer behaves.
+type workflowServerOptions struct {
+	// NoAdmin makes the workflow definition endpoints answer 403.
+	NoAdmin bool
+	// Reject is a transition ID whose execution fails with 409.
+	Reject string
+}
+
+// fakeWorkflowServer serves an issue moving through testWorkflowGraph's
+// workflow and records the transitions it executes.
+func fakeWorkflowServer(t *testing.T, options workflowServerOptions, executed *[]string) *services.RawClient {
+	t.Helper()
+	status := "1"
+	graph := testWorkflowGraph()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/rest/api/3/issue/X-1":
+			_, _ = w.Write([]byte(`{"fields":{"status":{"id":"` + status + `","name":"` + graph.statusName(status) + `"},"project":{"id":"100"},"issuetype":{"id":"7"}}}`))
+		case r.URL.Path == "/rest/api/3/workflowscheme/project" && options.NoAdmin:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errorMessages":["You are not authorized to perform this action."]}`))
+		case r.URL.Path == "/rest/api/3/workflowscheme/project":
+			_, _ = w.Write([]byte(`{"values":[{"workflowScheme":{"defaultWorkflow":"Default","issueTypeMappings":{"7":"Software"}}}]}`))
+		case r.URL.Path == "/rest/api/3/workflow/search":
+			if r.URL.Query().Get("workflowName") != "Software" {
+				t.Errorf("expected the issue type's workflow, got %q", r.URL.Query().Get("workflowName"))
+			}
+			_, _ = w.Write([]byte(`{"values":[{
+				"statuses":[{"id":"1","name":"To Do"},{"id":"3","name":"In Progress"},{"id":"4","name":"In Review"},{"id":"5","name":"Done"}],
+				"transitions":[
+					{"id":"1","name":"Create","from":[],"to":"1","type":"initial"},
+					{"id":"11","name":"Start","from":["1"],"to":"3","type":"directed"},
+					{"id":"21","name":"Review","from":["3"],"to":"4","type":"directed"},
+					{"id":"31","name":"Approve","from":["4"],"to":"5","type":"directed"}
+				]}]}`))
+		case r.URL.Path == "/rest/api/3/issue/X-1/transitions" && r.Method == http.MethodGet:
+			var live []map[string]any
+			for _, edge := range graph.Edges {
+				if edge.From == status {
+					live = append(live, map[string]any{"id": edge.TransitionID, "name": edge.Name, "to": map[string]string{"id": edge.To, "name": graph.statusName(edge.To)}})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"transitions": live})
+		case r.URL.Path == "/rest/api/3/issue/X-1/transitions" && r.Method == http.MethodPost:
+			var body struct {
+				Transition struct{ ID string } `json:"transition"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.Transition.ID == options.Reject {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"errorMessages":["A validator rejected the transition."]}`))
+				return
+			}
+			*executed = append(*executed, body.Transition.ID)
+			for _, edge := range graph.Edges {
+				if edge.TransitionID == body.Transition.ID {
+					status = edge.To
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+}
+
+func TestTransitionAlongPath_DryRun(t *testing.T) {
+	var executed []string
+	client := fakeWorkflowServer(t, workflowServerOptions{}, &executed)
+
+	result, _, err := transitionAlongPath(context.Background(), client, TransitionIssueInput{IssueKey: "X-1", ToStatus: "Done", FindPath: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("transitionAlongPath: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasPrefix(text, "Dry run") || !strings.Contains(text, `3. "Approve" (ID: 31): In Review -> Done`) {
+		t.Errorf("unexpected plan:\n%s", text)
+	}
+	if len(executed) != 0 {
+		t.Errorf("dry run executed transitions %v", executed)
+	}
+}
+
+func TestTransitionAlongPath_ExecutesEveryHop(t *testing.T) {
+	var executed []string
+	client := fakeWorkflowServer(t, workflowServerOptions{}, &executed)
+
+	_, reached, err := transitionAlongPath(context.Background(), client, TransitionIssueInput{IssueKey: "X-1", ToStatus: "Done", FindPath: true})
+	if err != nil {
+		t.Fatalf("transitionAlongPath: %v", err)
+	}
+	if reached != "Done" || strings.Join(executed, ",") != "11,21,31" {
+		t.Errorf("executed %v, want 11,21,31", executed)
+	}
+}
+
+func TestTransitionAlongPath_WithoutWorkflowPermission(t *testing.T) {
+	var executed []string
+	client := fakeWorkflowServer(t, workflowServerOptions{NoAdmin: true}, &executed)
+
+	if _, _, err := transitionAlongPath(context.Background(), client, TransitionIssueInput{IssueKey: "X-1", ToStatus: "Done", FindPath: true}); err == nil || !strings.Contains(err.Error(), "step by step") || !strings.Contains(err.Error(), "Start") {
+		t.Errorf("a multi-step path needs the workflow, got %v", err)
+	}
+	if _, _, err := transitionAlongPath(context.Background(), client, TransitionIssueInput{IssueKey: "X-1", ToStatus: "In Progress", FindPath: true}); err != nil {
+		t.Fatalf("a direct transition should still work: %v", err)
+	}
+	if strings.Join(executed, ",") != "11" {
+		t.Errorf("executed %v, want 11", executed)
+	}
+}
+
+func TestTransitionAlongPath_ReportsStatusReachedOnFailure(t *testing.T) {
+	var executed []string
+	client := fakeWorkflowServer(t, workflowServerOptions{Reject: "21"}, &executed)
+
+	_, reached, err := transitionAlongPath(context.Background(), client, TransitionIssueInput{IssueKey: "X-1", ToStatus: "Done", FindPath: true})
+	if err == nil || !strings.Contains(err.Error(), "stopped at step 2 of 3") || !strings.Contains(err.Error(), "left in In Progress") {
+		t.Errorf("err = %v", err)
+	}
+	if reached != "In Progress" {
+		t.Errorf("reached = %q, want In Progress so the first step is journaled", reached)
+	}
+}
@@ -3,7 +3,10 @@ package tools
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
@@ -18,28 +21,158 @@ type AddWorklogInput struct {
 	IssueKey  string `json:"issue_key" validate:"required"`
 	TimeSpent string `json:"time_spent" validate:"required"`
 	Comment   string `json:"comment,omitempty"`
+	Format    string `json:"format,omitempty"`
 	Started   string `json:"started,omitempty"`
+	// AdjustEstimate, NewEstimate and ReduceBy say what happens to the
+	// remaining estimate; see worklogEstimateQuery.
+	AdjustEstimate string `json:"adjust_estimate,omitempty"`
+	NewEstimate    string `json:"new_estimate,omitempty"`
+	ReduceBy       string `json:"reduce_by,omitempty"`
+}
+
+type GetWorklogsInput struct {
+	IssueKey      string `json:"issue_key" validate:"required"`
+	Author        string `json:"author,omitempty"`
+	StartedAfter  string `json:"started_after,omitempty"`
+	StartedBefore string `json:"started_before,omitempty"`
+	StartAt       int    `json:"start_at,omitempty"`
+	MaxResults    int    `json:"max_results,omitempty"`
+}
+
+type UpdateWorklogInput struct {
+	IssueKey       string `json:"issue_key" validate:"required"`
+	WorklogID      string `json:"worklog_id" validate:"required"`
+	TimeSpent      string `json:"time_spent,omitempty"`
+	Started        string `json:"started,omitempty"`
+	Comment        string `json:"comment,omitempty"`
+	Format         string `json:"format,omitempty"`
+	AdjustEstimate string `json:"adjust_estimate,omitempty"`
+	NewEstimate    string `json:"new_estimate,omitempty"`
+}
+
+type DeleteWorklogInput struct {
+	IssueKey       string `json:"issue_key" validate:"required"`
+	WorklogID      string `json:"worklog_id" validate:"required"`
+	AdjustEstimate string `json:"adjust_estimate,omitempty"`
+	NewEstimate    string `json:"new_estimate,omitempty"`
+	IncreaseBy     string `json:"increase_by,omitempty"`
+}
+
+// WorklogOutput is one worklog, with its comment rendered as markdown.
+type WorklogOutput struct {
+	ID               string `json:"id"`
+	Author           string `json:"author"`
+	AuthorAccountID  string `json:"author_account_id,omitempty"`
+	Started          string `json:"started"`
+	TimeSpent        string `json:"time_spent"`
+	TimeSpentSeconds int    `json:"time_spent_seconds"`
+	Comment          string `json:"comment,omitempty"`
+	Updated          string `json:"updated,omitempty"`
+}
+
+// GetWorklogsOutput is the structured result of jira_get_worklogs. Total
+// counts the worklogs Jira has in the date range; NextStartAt is set while
+// there are more pages.
+type GetWorklogsOutput struct {
+	IssueKey     string          `json:"issue_key"`
+	Total        int             `json:"total"`
+	StartAt      int             `json:"start_at"`
+	NextStartAt  int             `json:"next_start_at,omitempty"`
+	TotalSeconds int             `json:"total_seconds"`
+	Worklogs     []WorklogOutput `json:"worklogs"`
+}
+
+// WorklogChangeOutput is the structured result of jira_update_worklog and
+// jira_delete_worklog. Worklog is the updated worklog, nil after a delete.
+type WorklogChangeOutput struct {
+	IssueKey       string         `json:"issue_key"`
+	WorklogID      string         `json:"worklog_id"`
+	AdjustEstimate string         `json:"adjust_estimate"`
+	Worklog        *WorklogOutput `json:"worklog,omitempty"`
+	Message        string         `json:"message"`
+}
+
+// AddWorklogOutput is the structured result of jira_add_worklog.
+type AddWorklogOutput struct {
+	IssueKey         string `json:"issue_key"`
+	WorklogID        string `json:"worklog_id"`
+	TimeSpent        string `json:"time_spent"`
+	TimeSpentSeconds int    `json:"time_spent_seconds"`
+	Started          string `json:"started"`
+	Author           string `json:"author,omitempty"`
+	Message          string `json:"message"`
 }
 
 func RegisterJiraWorklogTool(s *server.MCPServer, filter *Filter) {
 	jiraAddWorklogTool := mcp.NewTool("jira_add_worklog",
 		mcp.WithDescription("Add a worklog to a Jira issue to track time spent on the issue"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
-		mcp.WithString("time_spent", mcp.Required(), mcp.Description("Time spent working on the issue (e.g., 3h, 30m, 1h 30m)")),
+		mcp.WithString("time_spent", mcp.Required(), mcp.Description("Time spent working on the issue, in Jira's notation (e.g., 3h, 30m, 1h 30m, 1.5h, 1d 2h); days and weeks follow the instance's working hours per day and days per week")),
 		mcp.WithString("comment", mcp.Description("Comment describing the work done")),
+		mcp.WithString("format", mcp.Description("Markup of the comment. "+bodyFormatParamDescription)),
 		mcp.WithString("started", mcp.Description("When the work began, in ISO 8601 format (e.g., 2023-05-01T10:00:00.000+0000). Defaults to current time.")),
+		mcp.WithString("adjust_estimate", mcp.Description(adjustEstimateParamDescription+`, "manual" (reduce by reduce_by)`)),
+		mcp.WithString("new_estimate", mcp.Description(`Remaining estimate to set with adjust_estimate "new" (e.g., 2d, 4h 30m)`)),
+		mcp.WithString("reduce_by", mcp.Description(`Amount to take off the remaining estimate with adjust_estimate "manual" (e.g., 1h)`)),
+		mcp.WithOutputSchema[AddWorklogOutput](),
+	)
+	filter.AddTool(s, jiraAddWorklogTool, ToolWrite, mcp.NewTypedToolHandler(jiraAddWorklogHandler))
+
+	jiraGetWorklogsTool := mcp.NewTool("jira_get_worklogs",
+		mcp.WithDescription("List the worklogs of a Jira issue, a page at a time, optionally only those of one author or started within a date range"),
+		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		mcp.WithString("author", mcp.Description("Only worklogs by this user: account ID, username, email or part of the display name")),
+		mcp.WithString("started_after", mcp.Description("Only worklogs started on or after this date (2024-05-01) or time (RFC 3339)")),
+		mcp.WithString("started_before", mcp.Description("Only worklogs started before this time (RFC 3339), or on or before this date (2024-05-31)")),
+		mcp.WithNumber("start_at", mcp.Description("Zero-based index of the first worklog of the page (default 0); use next_start_at from the previous page")),
+		mcp.WithNumber("max_results", mcp.Description(fmt.Sprintf("Worklogs per page (default %d, at most %d). The author filter applies within the page.", defaultWorklogPage, maxWorklogPage))),
+		mcp.WithOutputSchema[GetWorklogsOutput](),
 	)
-	filter.AddTool(s, jiraAddWorklogTool, mcp.NewTypedToolHandler(jiraAddWorklogHandler))
+	filter.AddTool(s, jiraGetWorklogsTool, ToolRead, mcp.NewTypedToolHandler(jiraGetWorklogsHandler))
+
+	jiraUpdateWorklogTool := mcp.NewTool("jira_update_worklog",
+		mcp.WithDescription("Correct a worklog on a Jira issue: its time spent, start time or comment. Fields left out are kept."),
+		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		mcp.WithString("worklog_id", mcp.Required(), mcp.Description("The ID of the worklog, from jira_get_worklogs")),
+		mcp.WithString("time_spent", mcp.Description("New time spent, in Jira's notation (e.g., 3h, 30m, 1h 30m, 1.5h, 1d 2h)")),
+		mcp.WithString("started", mcp.Description("New start time, in ISO 8601 format (e.g., 2023-05-01T10:00:00.000+0000)")),
+		mcp.WithString("comment", mcp.Description("New comment, replacing the old one")),
+		mcp.WithString("format", mcp.Description("Markup of the comment. "+bodyFormatParamDescription)),
+		mcp.WithString("adjust_estimate", mcp.Description(adjustEstimateParamDescription)),
+		mcp.WithString("new_estimate", mcp.Description(`Remaining estimate to set with adjust_estimate "new" (e.g., 2d, 4h 30m)`)),
+		mcp.WithOutputSchema[WorklogChangeOutput](),
+	)
+	filter.AddTool(s, jiraUpdateWorklogTool, ToolWrite, mcp.NewTypedToolHandler(jiraUpdateWorklogHandler))
+
+	jiraDeleteWorklogTool := mcp.NewTool("jira_delete_worklog",
+		mcp.WithDescription("Delete a worklog from a Jira issue. This cannot be undone."),
+		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		mcp.WithString("worklog_id", mcp.Required(), mcp.Description("The ID of the worklog, from jira_get_worklogs")),
+		mcp.WithString("adjust_estimate", mcp.Description(adjustEstimateParamDescription+`, "manual" (increase by increase_by)`)),
+		mcp.WithString("new_estimate", mcp.Description(`Remaining estimate to set with adjust_estimate "new" (e.g., 2d, 4h 30m)`)),
+		mcp.WithString("increase_by", mcp.Description(`Amount to add back to the remaining estimate with adjust_estimate "manual" (e.g., 1h)`)),
+		mcp.WithOutputSchema[WorklogChangeOutput](),
+	)
+	filter.AddTool(s, jiraDeleteWorklogTool, ToolDestructive, mcp.NewTypedToolHandler(jiraDeleteWorklogHandler))
 }
 
+// adjustEstimateParamDescription starts the description of the
+// adjust_estimate parameters; each tool lists the manual mode it supports.
+const adjustEstimateParamDescription = `What happens to the remaining estimate: "auto" (default, adjusted by the time logged), "leave" (unchanged), "new" (set to new_estimate)`
+
+// Page size of jira_get_worklogs.
+const (
+	defaultWorklogPage = 50
+	maxWorklogPage     = 1000
+)
+
 func jiraAddWorklogHandler(ctx context.Context, request mcp.CallToolRequest, input AddWorklogInput) (*mcp.CallToolResult, error) {
 	client := services.JiraClient()
+	calendar := services.JiraWorkingCalendar(ctx)
 
-	// Convert timeSpent to seconds (this is a simplification - in a real implementation 
-	// you would need to parse formats like "1h 30m" properly)
-	timeSpentSeconds, err := parseTimeSpent(input.TimeSpent)
+	timeSpentSeconds, err := parseTimeSpent(input.TimeSpent, calendar)
 	if err != nil {
-		return nil, fmt.Errorf("invalid time_spent format: %v", err)
+		return nil, err
 	}
 
 	// Get started time if provided, otherwise use current time
@@ -51,9 +184,15 @@ func jiraAddWorklogHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		started = time.Now().Format("2006-01-02T15:04:05.000-0700")
 	}
 
+	estimate, err := worklogEstimateQuery(calendar, input.AdjustEstimate, input.NewEstimate, input.ReduceBy, "reduceBy")
+	if err != nil {
+		return nil, err
+	}
 	options := &models.WorklogOptionsScheme{
 		Notify:         true,
-		AdjustEstimate: "auto",
+		AdjustEstimate: estimate.Get("adjustEstimate"),
+		NewEstimate:    estimate.Get("newEstimate"),
+		ReduceBy:       estimate.Get("reduceBy"),
 	}
 
 	payload := &models.WorklogADFPayloadScheme{
@@ -63,7 +202,11 @@ func jiraAddWorklogHandler(ctx context.Context, request mcp.CallToolRequest, inp
 
 	// Add comment if provided
 	if input.Comment != "" {
-		payload.Comment = util.MarkdownToADF(input.Comment)
+		comment, err := util.BodyToADF(input.Comment, input.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comment: %v", err)
+		}
+		payload.Comment = comment
 	}
 
 	// Call the Jira API to add the worklog
@@ -88,27 +231,339 @@ Author: %s`,
 		worklog.Started,
 		worklog.Author.DisplayName,
 	)
+	output := AddWorklogOutput{
+		IssueKey:         input.IssueKey,
+		WorklogID:        worklog.ID,
+		TimeSpent:        input.TimeSpent,
+		TimeSpentSeconds: worklog.TimeSpentSeconds,
+		Started:          worklog.Started,
+		Author:           worklog.Author.DisplayName,
+		Message:          "Worklog added successfully!",
+	}
+
+	return mcp.NewToolResultStructured(output, result), nil
+}
+
+// worklogEstimateQuery validates an adjust_estimate mode and returns it as
+// the query parameters Jira expects. byParam names the parameter carrying
+// the manual amount ("reduceBy" when adding, "increaseBy" when deleting);
+// an empty byParam means the call has no manual mode. Amounts are checked
+// against calendar and sent in minutes.
+func worklogEstimateQuery(calendar util.WorkingCalendar, mode, newEstimate, by, byParam string) (url.Values, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = "auto"
+	}
+	query := url.Values{"adjustEstimate": {mode}}
+	switch mode {
+	case "auto", "leave":
+	case "new":
+		if strings.TrimSpace(newEstimate) == "" {
+			return nil, fmt.Errorf(`adjust_estimate "new" needs new_estimate`)
+		}
+		estimate, err := jiraEstimate("new_estimate", newEstimate, calendar)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("newEstimate", estimate)
+	case "manual":
+		if byParam == "" {
+			return nil, fmt.Errorf(`adjust_estimate "manual" is not available here: use "auto", "leave" or "new"`)
+		}
+		if strings.TrimSpace(by) == "" {
+			return nil, fmt.Errorf(`adjust_estimate "manual" needs the amount to adjust by`)
+		}
+		amount, err := jiraEstimate("amount to adjust by", by, calendar)
+		if err != nil {
+			return nil, err
+		}
+		query.Set(byParam, amount)
+	default:
+		return nil, fmt.Errorf(`invalid adjust_estimate %q: use "auto", "leave", "new" or "manual"`, mode)
+	}
+	return query, nil
+}
+
+// jiraTimeLayout is how Jira writes worklog start times.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// parseWorklogDate reads a date (2024-05-01, in the local time zone) or a
+// time (RFC 3339 or Jira's own layout). With endOfDay, a date means the
+// start of the next day, so a range ending on it includes the whole day.
+func parseWorklogDate(value string, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if day, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			return day.AddDate(0, 0, 1), nil
+		}
+		return day, nil
+	}
+	for _, layout := range []string{time.RFC3339, jiraTimeLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected a date like 2024-05-01 or an RFC 3339 time, got %q", value)
+}
+
+// worklogOutput converts a Jira worklog for output.
+func worklogOutput(worklog *models.IssueWorklogADFScheme) WorklogOutput {
+	output := WorklogOutput{
+		ID:               worklog.ID,
+		Author:           "Unknown",
+		Started:          worklog.Started,
+		TimeSpent:        worklog.TimeSpent,
+		TimeSpentSeconds: worklog.TimeSpentSeconds,
+		Comment:          util.RenderADF(worklog.Comment),
+		Updated:          worklog.Updated,
+	}
+	if worklog.Author != nil {
+		output.Author = worklog.Author.DisplayName
+		output.AuthorAccountID = worklog.Author.AccountID
+	}
+	return output
+}
+
+// worklogByAuthor reports whether author (an account ID, username, email or
+// part of the display name) wrote worklog.
+func worklogByAuthor(worklog *models.IssueWorklogADFScheme, author string) bool {
+	if worklog.Author == nil {
+		return false
+	}
+	author = strings.ToLower(strings.TrimSpace(author))
+	user := worklog.Author
+	for _, id := range []string{user.AccountID, user.Name, user.Key, user.EmailAddress} {
+		if id != "" && strings.ToLower(id) == author {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(user.DisplayName), author)
+}
+
+func jiraGetWorklogsHandler(ctx context.Context, request mcp.CallToolRequest, input GetWorklogsInput) (*mcp.CallToolResult, error) {
+	output, err := GetWorklogs(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(output, FormatWorklogs(output)), nil
+}
+
+// GetWorklogs returns one page of the worklogs of input.IssueKey.
+func GetWorklogs(ctx context.Context, input GetWorklogsInput) (*GetWorklogsOutput, error) {
+	return getWorklogs(ctx, services.JiraRawClient(), input)
+}
+
+func getWorklogs(ctx context.Context, client *services.RawClient, input GetWorklogsInput) (*GetWorklogsOutput, error) {
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultWorklogPage
+	}
+	maxResults = min(maxResults, maxWorklogPage)
+	startAt := max(input.StartAt, 0)
+
+	query := url.Values{"startAt": {strconv.Itoa(startAt)}, "maxResults": {strconv.Itoa(maxResults)}}
+	var after, before time.Time
+	if input.StartedAfter != "" {
+		t, err := parseWorklogDate(input.StartedAfter, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid started_after: %v", err)
+		}
+		after = t
+		query.Set("startedAfter", strconv.FormatInt(t.UnixMilli(), 10))
+	}
+	if input.StartedBefore != "" {
+		t, err := parseWorklogDate(input.StartedBefore, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid started_before: %v", err)
+		}
+		before = t
+		query.Set("startedBefore", strconv.FormatInt(t.UnixMilli(), 10))
+	}
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		return nil, fmt.Errorf("started_after must be before started_before")
+	}
+
+	var page models.IssueWorklogADFPageScheme
+	endpoint := fmt.Sprintf("rest/api/3/issue/%s/worklog", url.PathEscape(input.IssueKey))
+	response, err := client.Do(ctx, http.MethodGet, endpoint, query, nil, &page)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get worklogs: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get worklogs: %v", err)
+	}
+
+	output := &GetWorklogsOutput{IssueKey: input.IssueKey, Total: page.Total, StartAt: startAt, Worklogs: []WorklogOutput{}}
+	if next := startAt + len(page.Worklogs); len(page.Worklogs) > 0 && next < page.Total {
+		output.NextStartAt = next
+	}
+	for _, worklog := range page.Worklogs {
+		if worklog == nil {
+			continue
+		}
+		if input.Author != "" && !worklogByAuthor(worklog, input.Author) {
+			continue
+		}
+		// Jira Server ignores the date parameters, so check them here too.
+		if started, err := time.Parse(jiraTimeLayout, worklog.Started); err == nil {
+			if (!after.IsZero() && started.Before(after)) || (!before.IsZero() && !started.Before(before)) {
+				continue
+			}
+		}
+		output.Worklogs = append(output.Worklogs, worklogOutput(worklog))
+		output.TotalSeconds += worklog.TimeSpentSeconds
+	}
+	return output, nil
+}
+
+// FormatWorklogs renders a page of worklogs as text.
+func FormatWorklogs(output *GetWorklogsOutput) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Worklogs for %s: %d shown, %d in total", output.IssueKey, len(output.Worklogs), output.Total)
+	if output.StartAt > 0 {
+		fmt.Fprintf(&sb, ", starting at %d", output.StartAt)
+	}
+	fmt.Fprintf(&sb, "\nTime shown: %s\n", util.FormatDuration(output.TotalSeconds, util.WorkingCalendar{}))
+	if output.NextStartAt > 0 {
+		fmt.Fprintf(&sb, "More worklogs: use start_at %d\n", output.NextStartAt)
+	}
+	if len(output.Worklogs) == 0 {
+		sb.WriteString("\nNo worklogs found.")
+		return sb.String()
+	}
+	for _, worklog := range output.Worklogs {
+		fmt.Fprintf(&sb, "\nID: %s\nAuthor: %s\nStarted: %s\nTime Spent: %s (%d seconds)\n",
+			worklog.ID, worklog.Author, worklog.Started, worklog.TimeSpent, worklog.TimeSpentSeconds)
+		if worklog.Comment != "" {
+			fmt.Fprintf(&sb, "Comment:\n%s\n", worklog.Comment)
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func jiraUpdateWorklogHandler(ctx context.Context, request mcp.CallToolRequest, input UpdateWorklogInput) (*mcp.CallToolResult, error) {
+	output, err := UpdateWorklog(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(output, FormatWorklogChange(output)), nil
+}
+
+// UpdateWorklog changes the time spent, start time or comment of a worklog.
+func UpdateWorklog(ctx context.Context, input UpdateWorklogInput) (*WorklogChangeOutput, error) {
+	return updateWorklog(ctx, services.JiraRawClient(), services.JiraWorkingCalendar(ctx), input)
+}
+
+func updateWorklog(ctx context.Context, client *services.RawClient, calendar util.WorkingCalendar, input UpdateWorklogInput) (*WorklogChangeOutput, error) {
+	if input.TimeSpent == "" && input.Started == "" && input.Comment == "" {
+		return nil, fmt.Errorf("nothing to update: give time_spent, started or comment")
+	}
+	query, err := worklogEstimateQuery(calendar, input.AdjustEstimate, input.NewEstimate, "", "")
+	if err != nil {
+		return nil, err
+	}
 
-	return mcp.NewToolResultText(result), nil
+	payload := &models.WorklogADFPayloadScheme{Started: input.Started}
+	if input.TimeSpent != "" {
+		seconds, err := parseTimeSpent(input.TimeSpent, calendar)
+		if err != nil {
+			return nil, err
+		}
+		payload.TimeSpentSeconds = seconds
+	}
+	if input.Comment != "" {
+		comment, err := util.BodyToADF(input.Comment, input.Format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comment: %v", err)
+		}
+		payload.Comment = comment
+	}
+
+	var worklog models.IssueWorklogADFScheme
+	endpoint := fmt.Sprintf("rest/api/3/issue/%s/worklog/%s", url.PathEscape(input.IssueKey), url.PathEscape(input.WorklogID))
+	response, err := client.Do(ctx, http.MethodPut, endpoint, query, payload, &worklog)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to update worklog: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to update worklog: %v", err)
+	}
+
+	updated := worklogOutput(&worklog)
+	return &WorklogChangeOutput{
+		IssueKey:       input.IssueKey,
+		WorklogID:      input.WorklogID,
+		AdjustEstimate: query.Get("adjustEstimate"),
+		Worklog:        &updated,
+		Message:        "Worklog updated successfully!",
+	}, nil
 }
 
-// parseTimeSpent converts time formats like "3h", "30m", "1h 30m" to seconds
-func parseTimeSpent(timeSpent string) (int, error) {
-	// This is a simplified version - a real implementation would be more robust
-	// For this example, we'll just handle hours (h) and minutes (m)
-	
-	// Simple case: if it's just a number, treat it as seconds
-	seconds, err := strconv.Atoi(timeSpent)
-	if err == nil {
-		return seconds, nil
+func jiraDeleteWorklogHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteWorklogInput) (*mcp.CallToolResult, error) {
+	output, err := DeleteWorklog(ctx, input)
+	if err != nil {
+		return nil, err
 	}
+	return mcp.NewToolResultStructured(output, FormatWorklogChange(output)), nil
+}
+
+// DeleteWorklog removes a worklog from an issue.
+func DeleteWorklog(ctx context.Context, input DeleteWorklogInput) (*WorklogChangeOutput, error) {
+	return deleteWorklog(ctx, services.JiraRawClient(), services.JiraWorkingCalendar(ctx), input)
+}
 
-	// Otherwise, try to parse as a duration
-	duration, err := time.ParseDuration(timeSpent)
-	if err == nil {
-		return int(duration.Seconds()), nil
+func deleteWorklog(ctx context.Context, client *services.RawClient, calendar util.WorkingCalendar, input DeleteWorklogInput) (*WorklogChangeOutput, error) {
+	query, err := worklogEstimateQuery(calendar, input.AdjustEstimate, input.NewEstimate, input.IncreaseBy, "increaseBy")
+	if err != nil {
+		return nil, err
 	}
 
-	// If all else fails, return an error
-	return 0, fmt.Errorf("could not parse time: %s", timeSpent)
+	endpoint := fmt.Sprintf("rest/api/3/issue/%s/worklog/%s", url.PathEscape(input.IssueKey), url.PathEscape(input.WorklogID))
+	response, err := client.Do(ctx, http.MethodDelete, endpoint, query, nil, nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to delete worklog: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to delete worklog: %v", err)
+	}
+	return &WorklogChangeOutput{
+		IssueKey:       input.IssueKey,
+		WorklogID:      input.WorklogID,
+		AdjustEstimate: query.Get("adjustEstimate"),
+		Message:        "Worklog deleted successfully!",
+	}, nil
+}
+
+// FormatWorklogChange renders the result of an update or delete as text.
+func FormatWorklogChange(output *WorklogChangeOutput) string {
+	result := fmt.Sprintf("%s\nIssue: %s\nWorklog ID: %s\nRemaining estimate: %s",
+		output.Message, output.IssueKey, output.WorklogID, output.AdjustEstimate)
+	if worklog := output.Worklog; worklog != nil {
+		result += fmt.Sprintf("\nTime Spent: %s (%d seconds)\nDate Started: %s\nAuthor: %s",
+			worklog.TimeSpent, worklog.TimeSpentSeconds, worklog.Started, worklog.Author)
+	}
+	return result
+}
+
+// parseTimeSpent reads the time spent of a worklog, in Jira's notation
+// ("1w 2d 3h 30m", "1.5h"), into seconds.
+func parseTimeSpent(timeSpent string, calendar util.WorkingCalendar) (int, error) {
+	seconds, err := util.ParseDuration(timeSpent, calendar)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time_spent: %v", err)
+	}
+	if seconds < 60 {
+		return 0, fmt.Errorf("invalid time_spent: %q is less than a minute", timeSpent)
+	}
+	return seconds, nil
+}
+
+// jiraEstimate reads an estimate in Jira's notation and writes it in
+// minutes, which Jira reads the same way whatever its working calendar.
+func jiraEstimate(name, value string, calendar util.WorkingCalendar) (string, error) {
+	seconds, err := util.ParseDuration(value, calendar)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return fmt.Sprintf("%dm", (seconds+30)/60), nil
 } 
\ No newline at end of file
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+func TestWorklogEstimateQuery(t *testing.T) {
+	tests := []struct {
+		mode, newEstimate, by, byParam string
+		want                           string
+	}{
+		{"", "", "", "reduceBy", "adjustEstimate=auto"},
+		{"Leave", "", "", "", "adjustEstimate=leave"},
+		{"new", "2d", "", "", "adjustEstimate=new&newEstimate=960m"},
+		{"manual", "", "1.5h", "increaseBy", "adjustEstimate=manual&increaseBy=90m"},
+	}
+	for _, tt := range tests {
+		query, err := worklogEstimateQuery(util.DefaultWorkingCalendar, tt.mode, tt.newEstimate, tt.by, tt.byParam)
+		if err != nil || query.Encode() != tt.want {
+			t.Errorf("worklogEstimateQuery(%q) = %q, %v, want %q", tt.mode, query.Encode(), err, tt.want)
+		}
+	}
+	for _, bad := range [][4]string{{"new", "", "", ""}, {"new", "soon", "", ""}, {"manual", "", "", "reduceBy"}, {"manual", "", "1h", ""}, {"reset", "", "", ""}} {
+		if _, err := worklogEstimateQuery(util.DefaultWorkingCalendar, bad[0], bad[1], bad[2], bad[3]); err == nil {
+			t.Errorf("worklogEstimateQuery(%q, %q, %q, %q) should fail", bad[0], bad[1], bad[2], bad[3])
+		}
+	}
+}
+
+// worklogServer answers the worklog endpoints of ABC-1 and records the last
+// request.
+func worklogServer(t *testing.T, response string) (*services.RawClient, *http.Request, *string) {
+	t.Helper()
+	last := &http.Request{}
+	body := new(string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = *r.Clone(context.Background())
+		raw, _ := io.ReadAll(r.Body)
+		*body = string(raw)
+		if !strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/ABC-1/worklog") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}, last, body
+}
+
+func TestGetWorklogs_FiltersAuthorAndDates(t *testing.T) {
+	page := `{"startAt":0,"maxResults":3,"total":5,"worklogs":[
+		{"id":"1","author":{"accountId":"a1","displayName":"Ann Lee"},"started":"2024-05-01T09:00:00.000+0000","timeSpent":"1h","timeSpentSeconds":3600},
+		{"id":"2","author":{"accountId":"b2","displayName":"Bob"},"started":"2024-05-01T10:00:00.000+0000","timeSpent":"2h","timeSpentSeconds":7200},
+		{"id":"3","author":{"accountId":"a1","displayName":"Ann Lee"},"started":"2024-06-01T09:00:00.000+0000","timeSpent":"30m","timeSpentSeconds":1800}
+	]}`
+	client, last, _ := worklogServer(t, page)
+
+	output, err := getWorklogs(context.Background(), client, GetWorklogsInput{
+		IssueKey:      "ABC-1",
+		Author:        "ann",
+		StartedAfter:  "2024-04-01T00:00:00Z",
+		StartedBefore: "2024-05-31T00:00:00Z",
+		MaxResults:    3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Worklogs) != 1 || output.Worklogs[0].ID != "1" || output.TotalSeconds != 3600 {
+		t.Errorf("worklogs = %+v", output.Worklogs)
+	}
+	if output.Total != 5 || output.NextStartAt != 3 {
+		t.Errorf("total = %d, next = %d", output.Total, output.NextStartAt)
+	}
+	query := last.URL.Query()
+	after := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	if query.Get("maxResults") != "3" || query.Get("startedAfter") != strconv.FormatInt(after, 10) || query.Get("startedBefore") == "" {
+		t.Errorf("query = %v", query)
+	}
+
+	if _, err := getWorklogs(context.Background(), client, GetWorklogsInput{IssueKey: "ABC-1", StartedAfter: "May 1st"}); err == nil {
+		t.Error("an unreadable date should be refused")
+	}
+}
+
+func TestParseWorklogDate_EndOfDay(t *testing.T) {
+	end, err := parseWorklogDate("2024-05-31", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local); !end.Equal(want) {
+		t.Errorf("end of 2024-05-31 = %s, want %s", end, want)
+	}
+}
+
+func TestUpdateAndDeleteWorklog(t *testing.T) {
+	client, last, body := worklogServer(t, `{"id":"7","author":{"displayName":"Ann"},"started":"2024-05-01T09:00:00.000+0000","timeSpent":"2h","timeSpentSeconds":7200}`)
+
+	calendar := util.WorkingCalendar{HoursPerDay: 6, DaysPerWeek: 4}
+	output, err := updateWorklog(context.Background(), client, calendar, UpdateWorklogInput{IssueKey: "ABC-1", WorklogID: "7", TimeSpent: "0.25d 30m", AdjustEstimate: "leave"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Method != http.MethodPut || last.URL.Path != "/rest/api/3/issue/ABC-1/worklog/7" || last.URL.Query().Get("adjustEstimate") != "leave" {
+		t.Errorf("request = %s %s", last.Method, last.URL)
+	}
+	// A quarter of a 6-hour day and 30 minutes.
+	if !strings.Contains(*body, `"timeSpentSeconds":7200`) || strings.Contains(*body, "comment") {
+		t.Errorf("body = %s", *body)
+	}
+	if output.Worklog == nil || output.Worklog.TimeSpent != "2h" {
+		t.Errorf("output = %+v", output)
+	}
+	if _, err := updateWorklog(context.Background(), client, calendar, UpdateWorklogInput{IssueKey: "ABC-1", WorklogID: "7"}); err == nil {
+		t.Error("an update without changes should be refused")
+	}
+	if _, err := updateWorklog(context.Background(), client, calendar, UpdateWorklogInput{IssueKey: "ABC-1", WorklogID: "7", TimeSpent: "1h", AdjustEstimate: "manual"}); err == nil {
+		t.Error("Jira has no manual adjustment for updates")
+	}
+	if _, err := updateWorklog(context.Background(), client, calendar, UpdateWorklogInput{IssueKey: "ABC-1", WorklogID: "7", TimeSpent: "0m"}); err == nil {
+		t.Error("a time spent under a minute should be refused")
+	}
+
+	if _, err := deleteWorklog(context.Background(), client, calendar, DeleteWorklogInput{IssueKey: "ABC-1", WorklogID: "7", AdjustEstimate: "manual", IncreaseBy: "1w"}); err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{"adjustEstimate": {"manual"}, "increaseBy": {"1440m"}}
+	if last.Method != http.MethodDelete || last.URL.Query().Encode() != want.Encode() {
+		t.Errorf("request = %s %s", last.Method, last.URL)
+	}
+}
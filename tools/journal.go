@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeKind says what a journal entry reverses.
+type ChangeKind string
+
+const (
+	// ChangeFields restores field values overwritten by jira_update_issue.
+	ChangeFields ChangeKind = "fields"
+	// ChangeTransition moves an issue back to the status it left.
+	ChangeTransition ChangeKind = "transition"
+	// ChangeLink deletes an issue link the server created.
+	ChangeLink ChangeKind = "link"
+	// ChangeComment deletes a comment the server added.
+	ChangeComment ChangeKind = "comment"
+	// ChangeArchive unarchives an issue archived by jira_delete_issue: moves
+	// it back to the status it left and removes the label it was given.
+	ChangeArchive ChangeKind = "archive"
+)
+
+// ChangeEntry is one change made through the tools, with what is needed to
+// reverse it.
+type ChangeEntry struct {
+	ID   int       `json:"id"`
+	Time time.Time `json:"time"`
+	// Host is the Jira instance the change was made on, as ATLASSIAN_HOST;
+	// a journal only shows and undoes the entries of its own host.
+	Host     string     `json:"host,omitempty"`
+	Tool     string     `json:"tool"`
+	IssueKey string     `json:"issueKey"`
+	Kind     ChangeKind `json:"kind"`
+	Summary  string     `json:"summary"`
+	// Before holds the raw JSON of each changed field, keyed by field ID, as
+	// Jira returned it just before the update.
+	Before     map[string]json.RawMessage `json:"before,omitempty"`
+	FromStatus string                     `json:"fromStatus,omitempty"`
+	ToStatus   string                     `json:"toStatus,omitempty"`
+	LinkID     string                     `json:"linkId,omitempty"`
+	CommentID  string                     `json:"commentId,omitempty"`
+	// Label is the label an archive added; empty when the issue had it.
+	Label    string     `json:"label,omitempty"`
+	UndoneAt *time.Time `json:"undoneAt,omitempty"`
+}
+
+// journalLimit is how many entries the journal keeps; older ones are dropped.
+const journalLimit = 200
+
+// journalLockWait is how long an operation waits for another process to
+// release the journal file, and journalLockStale how old a lock file must be
+// before it is taken to be left over from a crashed process.
+const (
+	journalLockWait  = 5 * time.Second
+	journalLockStale = 30 * time.Second
+)
+
+// ChangeJournal keeps the most recent changes so they can be undone. With a
+// path it is stored as a JSON file, re-read under a lock file before every
+// operation so several server processes, even for different Jira hosts, can
+// share it; without one it lives in memory.
+type ChangeJournal struct {
+	mu      sync.Mutex
+	path    string
+	host    string
+	entries []ChangeEntry
+	now     func() time.Time
+}
+
+// NewChangeJournal returns a journal of the changes made on host, stored at
+// path, or kept in memory when path is empty.
+func NewChangeJournal(path, host string) *ChangeJournal {
+	return &ChangeJournal{path: path, host: normalizeJournalHost(host), now: time.Now}
+}
+
+// normalizeJournalHost makes "https://x.atlassian.net/" and
+// "https://X.atlassian.net" the same host.
+func normalizeJournalHost(host string) string {
+	return strings.TrimRight(strings.ToLower(strings.TrimSpace(host)), "/")
+}
+
+// UndoJournal is the process-wide journal of changes on ATLASSIAN_HOST,
+// configured by JIRA_MCP_JOURNAL: a file path, or "off" to disable undo. By
+// default it is stored in the user cache directory. It is nil when disabled.
+var UndoJournal = sync.OnceValue(func() *ChangeJournal {
+	raw := strings.TrimSpace(os.Getenv("JIRA_MCP_JOURNAL"))
+	host := os.Getenv("ATLASSIAN_HOST")
+	switch strings.ToLower(raw) {
+	case "off", "none", "false":
+		return nil
+	case "":
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return NewChangeJournal("", host)
+		}
+		return NewChangeJournal(filepath.Join(dir, "jira-mcp", "changes.json"), host)
+	default:
+		return NewChangeJournal(raw, host)
+	}
+})
+
+// Location describes where the journal is kept, for the startup log.
+func (j *ChangeJournal) Location() string {
+	if j.path == "" {
+		return "memory (lost on restart)"
+	}
+	return j.path
+}
+
+// Record appends entry, assigning its ID and time, and returns the stored
+// copy. A nil journal records nothing.
+func (j *ChangeJournal) Record(entry ChangeEntry) (ChangeEntry, error) {
+	if j == nil {
+		return entry, nil
+	}
+	unlock, err := j.open()
+	if err != nil {
+		return entry, err
+	}
+	defer unlock()
+
+	entry.ID = 1
+	if n := len(j.entries); n > 0 {
+		entry.ID = j.entries[n-1].ID + 1
+	}
+	entry.Time = j.now().UTC()
+	entry.Host = j.host
+	j.entries = append(j.entries, entry)
+	if len(j.entries) > journalLimit {
+		j.entries = append([]ChangeEntry(nil), j.entries[len(j.entries)-journalLimit:]...)
+	}
+	return entry, j.save()
+}
+
+// Recent returns up to limit entries, newest first, optionally only those
+// for issueKey.
+func (j *ChangeJournal) Recent(issueKey string, limit int) ([]ChangeEntry, error) {
+	unlock, err := j.open()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var recent []ChangeEntry
+	for i := len(j.entries) - 1; i >= 0 && (limit <= 0 || len(recent) < limit); i-- {
+		if !j.owns(j.entries[i]) {
+			continue
+		}
+		if issueKey == "" || strings.EqualFold(j.entries[i].IssueKey, issueKey) {
+			recent = append(recent, j.entries[i])
+		}
+	}
+	return recent, nil
+}
+
+// Get returns the entry with the given ID.
+func (j *ChangeJournal) Get(id int) (ChangeEntry, bool, error) {
+	unlock, err := j.open()
+	if err != nil {
+		return ChangeEntry{}, false, err
+	}
+	defer unlock()
+	for _, entry := range j.entries {
+		if entry.ID == id && j.owns(entry) {
+			return entry, true, nil
+		}
+	}
+	return ChangeEntry{}, false, nil
+}
+
+// MarkUndone stamps the entry with the given ID as undone.
+func (j *ChangeJournal) MarkUndone(id int) error {
+	unlock, err := j.open()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	for i := range j.entries {
+		if j.entries[i].ID == id && j.owns(j.entries[i]) {
+			undoneAt := j.now().UTC()
+			j.entries[i].UndoneAt = &undoneAt
+			return j.save()
+		}
+	}
+	return fmt.Errorf("change #%d is not in the journal", id)
+}
+
+// owns reports whether entry was made on the journal's host. Entries
+// written before hosts were recorded belong to no host.
+func (j *ChangeJournal) owns(entry ChangeEntry) bool {
+	return entry.Host == j.host
+}
+
+// open locks the journal, across processes when it is stored in a file, and
+// loads its entries. The returned function releases the lock.
+func (j *ChangeJournal) open() (func(), error) {
+	j.mu.Lock()
+	release, err := j.lockFile()
+	if err != nil {
+		j.mu.Unlock()
+		return nil, err
+	}
+	if err := j.load(); err != nil {
+		release()
+		j.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		release()
+		j.mu.Unlock()
+	}, nil
+}
+
+// lockFile takes the journal's lock file, created exclusively next to it, so
+// two processes never interleave a read-modify-write. A lock older than
+// journalLockStale is removed as left over from a crash.
+func (j *ChangeJournal) lockFile() (func(), error) {
+	if j.path == "" {
+		return func() {}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to lock undo journal: %w", err)
+	}
+	lock := j.path + ".lock"
+	deadline := time.Now().Add(journalLockWait)
+	for {
+		file, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = file.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock undo journal: %w", err)
+		}
+		if info, statErr := os.Stat(lock); statErr == nil && time.Since(info.ModTime()) > journalLockStale {
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock undo journal: %s is held by another process", lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// load refreshes the entries from the journal file. A missing file is an
+// empty journal.
+func (j *ChangeJournal) load() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		j.entries = nil
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read undo journal: %w", err)
+	}
+	var entries []ChangeEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse undo journal %s: %w", j.path, err)
+	}
+	j.entries = entries
+	return nil
+}
+
+// save writes the entries to a temporary file of its own and renames it over
+// the journal, so a crash never leaves a half-written file behind.
+func (j *ChangeJournal) save() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(j.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode undo journal: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return fmt.Errorf("failed to write undo journal: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write undo journal: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), j.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write undo journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChangeJournal_PersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "changes.json")
+	journal := NewChangeJournal(path, "https://acme.atlassian.net")
+	journal.now = func() time.Time { return time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC) }
+
+	first, err := journal.Record(ChangeEntry{Tool: "jira_add_comment", IssueKey: "ABC-1", Kind: ChangeComment, CommentID: "100"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	second, err := journal.Record(ChangeEntry{Tool: "jira_add_comment", IssueKey: "DEF-2", Kind: ChangeComment, CommentID: "101"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 || !first.Time.Equal(journal.now()) {
+		t.Fatalf("entries = %+v, %+v", first, second)
+	}
+	if err := journal.MarkUndone(first.ID); err != nil {
+		t.Fatalf("MarkUndone: %v", err)
+	}
+
+	reopened := NewChangeJournal(path, "https://acme.atlassian.net")
+	recent, err := reopened.Recent("", 0)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(recent) != 2 || recent[0].ID != 2 || recent[1].UndoneAt == nil {
+		t.Errorf("reopened journal = %+v, want both entries newest first with #1 undone", recent)
+	}
+	if only, _ := reopened.Recent("abc-1", 0); len(only) != 1 || only[0].CommentID != "100" {
+		t.Errorf("filtering by issue = %+v", only)
+	}
+	if entry, ok, _ := reopened.Get(2); !ok || entry.IssueKey != "DEF-2" {
+		t.Errorf("Get(2) = %+v, %v", entry, ok)
+	}
+}
+
+func TestChangeJournal_KeepsNewestEntries(t *testing.T) {
+	journal := NewChangeJournal("", "")
+	for i := 0; i < journalLimit+5; i++ {
+		if _, err := journal.Record(ChangeEntry{IssueKey: "ABC-1", Kind: ChangeComment}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recent, _ := journal.Recent("", 0)
+	if len(recent) != journalLimit || recent[0].ID != journalLimit+5 || recent[len(recent)-1].ID != 6 {
+		t.Errorf("kept %d entries from #%d to #%d", len(recent), recent[len(recent)-1].ID, recent[0].ID)
+	}
+	if limited, _ := journal.Recent("", 3); len(limited) != 3 {
+		t.Errorf("Recent with limit 3 returned %d entries", len(limited))
+	}
+}
+
+func TestChangeJournal_NilRecordsNothing(t *testing.T) {
+	var journal *ChangeJournal
+	if _, err := journal.Record(ChangeEntry{IssueKey: "ABC-1"}); err != nil {
+		t.Errorf("a disabled journal should accept records silently, got %v", err)
+	}
+}
+
+func TestChangeJournal_SeparatesHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.json")
+	acme := NewChangeJournal(path, "https://acme.atlassian.net/")
+	other := NewChangeJournal(path, "https://jira.example.com")
+
+	mine, err := acme.Record(ChangeEntry{IssueKey: "ABC-1", Kind: ChangeComment, CommentID: "100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Record(ChangeEntry{IssueKey: "ABC-1", Kind: ChangeComment, CommentID: "200"}); err != nil {
+		t.Fatal(err)
+	}
+
+	recent, _ := NewChangeJournal(path, "https://ACME.atlassian.net").Recent("ABC-1", 0)
+	if len(recent) != 1 || recent[0].CommentID != "100" || recent[0].Host != "https://acme.atlassian.net" {
+		t.Errorf("acme entries = %+v", recent)
+	}
+	if _, ok, _ := other.Get(mine.ID); ok {
+		t.Error("another host should not see the entry")
+	}
+	if err := other.MarkUndone(mine.ID); err == nil {
+		t.Error("another host should not undo the entry")
+	}
+}
+
+func TestChangeJournal_ConcurrentProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.json")
+	var wg sync.WaitGroup
+	for range 4 {
+		// A journal per goroutine, as separate processes would have.
+		journal := NewChangeJournal(path, "https://acme.atlassian.net")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				if _, err := journal.Record(ChangeEntry{IssueKey: "ABC-1", Kind: ChangeComment}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	recent, err := NewChangeJournal(path, "https://acme.atlassian.net").Recent("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 40 || recent[0].ID != 40 {
+		t.Errorf("kept %d entries, newest #%d; want 40 with no lost writes", len(recent), recent[0].ID)
+	}
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// OutputMode selects what tool results carry, from JIRA_MCP_OUTPUT.
+type OutputMode string
+
+const (
+	// OutputText returns the human-readable text only, as the server always
+	// has. Tools declare no output schema.
+	OutputText OutputMode = "text"
+	// OutputJSON returns structuredContent, with its JSON serialisation as
+	// the text content for clients that do not read structuredContent.
+	OutputJSON OutputMode = "json"
+	// OutputBoth returns the human-readable text and structuredContent.
+	OutputBoth OutputMode = "both"
+)
+
+// Output returns the JIRA_MCP_OUTPUT in effect.
+func (f *Filter) Output() OutputMode {
+	if f == nil || f.output == "" {
+		return OutputText
+	}
+	return f.output
+}
+
+// InvalidOutput returns an unrecognised JIRA_MCP_OUTPUT value, or "" when
+// the value was valid. The filter treats an invalid value as text.
+func (f *Filter) InvalidOutput() string {
+	if f == nil {
+		return ""
+	}
+	return f.invalidOutput
+}
+
+// shapeOutput adapts a tool that declares an output schema and returns
+// structured content to mode. Every tool does both, so in text mode the
+// schema is removed and the structured content dropped, and in json mode
+// the text is replaced by the structured content. Error results are passed
+// through unchanged in every mode.
+func shapeOutput(mode OutputMode, tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	if mode == OutputBoth {
+		return tool, handler
+	}
+	if mode == OutputText {
+		tool.OutputSchema = mcp.ToolOutputSchema{}
+		tool.RawOutputSchema = nil
+	}
+
+	shaped := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError || result.StructuredContent == nil {
+			return result, err
+		}
+		if mode == OutputText {
+			result.StructuredContent = nil
+			return result, nil
+		}
+
+		data, err := json.MarshalIndent(result.StructuredContent, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode structured result: %v", err)
+		}
+		content := []mcp.Content{mcp.NewTextContent(string(data))}
+		for _, c := range result.Content {
+			// Images and embedded resources, e.g. downloaded attachments,
+			// have no place in the JSON and are kept.
+			if _, ok := c.(mcp.TextContent); !ok {
+				content = append(content, c)
+			}
+		}
+		result.Content = content
+		return result, nil
+	}
+	return tool, shaped
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type shapeTestOutput struct {
+	Key string `json:"key"`
+}
+
+func TestFilter_OutputFromEnv(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    OutputMode
+		invalid string
+	}{
+		{"", OutputText, ""},
+		{"text", OutputText, ""},
+		{" JSON ", OutputJSON, ""},
+		{"both", OutputBoth, ""},
+		{"yaml", OutputText, "yaml"},
+	}
+	for _, tt := range tests {
+		t.Setenv("JIRA_MCP_OUTPUT", tt.value)
+		f := NewFilterFromEnv()
+		if f.Output() != tt.want || f.InvalidOutput() != tt.invalid {
+			t.Errorf("JIRA_MCP_OUTPUT=%q: Output() = %q, InvalidOutput() = %q, want %q, %q", tt.value, f.Output(), f.InvalidOutput(), tt.want, tt.invalid)
+		}
+	}
+}
+
+func TestShapeOutput(t *testing.T) {
+	tool := mcp.NewTool("jira_test", mcp.WithOutputSchema[shapeTestOutput]())
+	image := mcp.NewImageContent("aGk=", "image/png")
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultStructured(shapeTestOutput{Key: "ABC-1"}, "Issue ABC-1")
+		result.Content = append(result.Content, image)
+		return result, nil
+	}
+
+	call := func(mode OutputMode) (mcp.Tool, *mcp.CallToolResult) {
+		t.Helper()
+		shapedTool, shaped := shapeOutput(mode, tool, handler)
+		result, err := shaped(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		return shapedTool, result
+	}
+
+	shapedTool, result := call(OutputText)
+	if shapedTool.OutputSchema.Type != "" || result.StructuredContent != nil {
+		t.Errorf("text mode must drop the schema and structured content")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Issue ABC-1" || len(result.Content) != 2 {
+		t.Errorf("text mode content = %v", result.Content)
+	}
+
+	shapedTool, result = call(OutputJSON)
+	if shapedTool.OutputSchema.Type != "object" || result.StructuredContent == nil {
+		t.Errorf("json mode must keep the schema and structured content")
+	}
+	var decoded shapeTestOutput
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &decoded); err != nil || decoded.Key != "ABC-1" {
+		t.Errorf("json mode text should be the structured content, got %v (%v)", result.Content[0], err)
+	}
+	if len(result.Content) != 2 || result.Content[1] != image {
+		t.Errorf("json mode must keep non-text content, got %v", result.Content)
+	}
+
+	shapedTool, result = call(OutputBoth)
+	if shapedTool.OutputSchema.Type != "object" || result.StructuredContent == nil || result.Content[0].(mcp.TextContent).Text != "Issue ABC-1" {
+		t.Errorf("both mode must return text and structured content, got %+v", result)
+	}
+}
+
+func TestShapeOutput_ErrorsPassThrough(t *testing.T) {
+	tool := mcp.NewTool("jira_test", mcp.WithOutputSchema[shapeTestOutput]())
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("missing fields"), nil
+	}
+	_, shaped := shapeOutput(OutputJSON, tool, handler)
+	result, err := shaped(context.Background(), mcp.CallToolRequest{})
+	if err != nil || !result.IsError || result.Content[0].(mcp.TextContent).Text != "missing fields" {
+		t.Errorf("error results must be returned unchanged, got %+v, %v", result, err)
+	}
+}
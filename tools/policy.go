@@ -0,0 +1,428 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectPolicy restricts which Jira projects the tools may read and write.
+// It is enforced by Filter.AddTool around every handler, before any request
+// reaches Jira, so a violation is reported as a plain tool error rather than
+// whatever Jira would answer.
+//
+// The projects a call touches are taken from its arguments: issue keys
+// (issue_key, parent_issue_key, inward_issue, outward_issue), project_key,
+// and board_id or sprint_id, which are looked up in Jira to find the
+// board's project. JQL arguments are not parsed; instead the query is
+// narrowed with a `project in (...)` clause when it is sent (see
+// restrictJQL). Calls that name no project at all, such as
+// jira_download_attachment, cannot be checked and are refused unless
+// allow_unscoped is set.
+type ProjectPolicy struct {
+	defaults      projectRules
+	tools         map[string]projectRules
+	allowUnscoped bool
+
+	// locate returns the project of a board_id or sprint_id argument; nil
+	// asks Jira (see locateAgileProject).
+	locate func(ctx context.Context, argument, id string) (string, error)
+}
+
+// projectRules are the projects readable and writable by a tool.
+type projectRules struct {
+	read, write projectSet
+}
+
+// projectSet is a set of upper-case project keys. A nil set allows every
+// project; an empty one allows none.
+type projectSet map[string]bool
+
+func newProjectSet(keys []string) projectSet {
+	set := projectSet{}
+	for _, key := range keys {
+		if key = strings.ToUpper(strings.TrimSpace(key)); key != "" {
+			set[key] = true
+		}
+	}
+	return set
+}
+
+func (s projectSet) sorted() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s projectSet) String() string {
+	if s == nil {
+		return "any"
+	}
+	if len(s) == 0 {
+		return "none"
+	}
+	return strings.Join(s.sorted(), ", ")
+}
+
+// policyFile is the YAML layout of JIRA_MCP_POLICY_FILE:
+//
+//	read: [ABC, DEF]        # projects every tool may read (omit for any)
+//	write: [ABC]            # projects every tool may change (defaults to read)
+//	allow_unscoped: false   # allow calls that name no project
+//	tools:
+//	  jira_delete_issue:
+//	    write: []           # per-tool override: this tool may delete nothing
+//
+// Pointers tell an omitted list (no restriction, or inherit) from an empty
+// one (nothing allowed).
+type policyFile struct {
+	Read          *[]string `yaml:"read"`
+	Write         *[]string `yaml:"write"`
+	AllowUnscoped bool      `yaml:"allow_unscoped"`
+	Tools         map[string]struct {
+		Read  *[]string `yaml:"read"`
+		Write *[]string `yaml:"write"`
+	} `yaml:"tools"`
+}
+
+// LoadProjectPolicyFromEnv reads the project policy from JIRA_MCP_POLICY_FILE
+// or, when no file is configured, from JIRA_MCP_PROJECTS (read and write) and
+// JIRA_MCP_WRITE_PROJECTS (narrows writes). It returns nil when no policy is
+// configured.
+func LoadProjectPolicyFromEnv() (*ProjectPolicy, error) {
+	if path := strings.TrimSpace(os.Getenv("JIRA_MCP_POLICY_FILE")); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file: %w", err)
+		}
+		return ParseProjectPolicy(data)
+	}
+
+	projects := strings.TrimSpace(os.Getenv("JIRA_MCP_PROJECTS"))
+	writeProjects := strings.TrimSpace(os.Getenv("JIRA_MCP_WRITE_PROJECTS"))
+	if projects == "" && writeProjects == "" {
+		return nil, nil
+	}
+	policy := &ProjectPolicy{tools: map[string]projectRules{}}
+	if projects != "" {
+		policy.defaults.read = newProjectSet(strings.Split(projects, ","))
+	}
+	policy.defaults.write = policy.defaults.read
+	if writeProjects != "" {
+		policy.defaults.write = newProjectSet(strings.Split(writeProjects, ","))
+	}
+	return policy, nil
+}
+
+// ParseProjectPolicy parses a policy file (see policyFile for the layout).
+func ParseProjectPolicy(data []byte) (*ProjectPolicy, error) {
+	var file policyFile
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	policy := &ProjectPolicy{allowUnscoped: file.AllowUnscoped, tools: map[string]projectRules{}}
+	if file.Read != nil {
+		policy.defaults.read = newProjectSet(*file.Read)
+	}
+	policy.defaults.write = policy.defaults.read
+	if file.Write != nil {
+		policy.defaults.write = newProjectSet(*file.Write)
+	}
+	for name, override := range file.Tools {
+		rules := policy.defaults
+		if override.Read != nil {
+			rules.read = newProjectSet(*override.Read)
+		}
+		if override.Write != nil {
+			rules.write = newProjectSet(*override.Write)
+		}
+		policy.tools[name] = rules
+	}
+	return policy, nil
+}
+
+// Describe summarises the policy for the startup log.
+func (p *ProjectPolicy) Describe() string {
+	summary := fmt.Sprintf("read: %s; write: %s", p.defaults.read, p.defaults.write)
+	if len(p.tools) > 0 {
+		summary += fmt.Sprintf("; %d tool override(s)", len(p.tools))
+	}
+	return summary
+}
+
+// allowed returns the projects toolName may touch given its category. Read
+// tools are held to the read set, everything else to the write set.
+func (p *ProjectPolicy) allowed(toolName string, category ToolCategory) projectSet {
+	rules, ok := p.tools[toolName]
+	if !ok {
+		rules = p.defaults
+	}
+	if category == ToolRead {
+		return rules.read
+	}
+	return rules.write
+}
+
+// issueKeyArgs and projectKeyArgs are the tool arguments that name a
+// project; agileArgs name a board or sprint whose project must be looked up.
+var (
+	issueKeyArgs   = []string{"issue_key", "parent_issue_key", "inward_issue", "outward_issue"}
+	projectKeyArgs = []string{"project_key"}
+	agileArgs      = []string{"board_id", "sprint_id"}
+)
+
+var issueKeyPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*)-\d+$`)
+
+// projectsInArguments lists the projects a call names. unresolved is set
+// when an issue is given by numeric ID, whose project cannot be told from
+// the argument alone.
+func projectsInArguments(args map[string]any) (projects []string, unresolved bool) {
+	for _, name := range issueKeyArgs {
+		value, _ := args[name].(string)
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		match := issueKeyPattern.FindStringSubmatch(value)
+		if match == nil {
+			unresolved = true
+			continue
+		}
+		projects = append(projects, strings.ToUpper(match[1]))
+	}
+	for _, name := range projectKeyArgs {
+		if value, _ := args[name].(string); strings.TrimSpace(value) != "" {
+			projects = append(projects, strings.ToUpper(strings.TrimSpace(value)))
+		}
+	}
+	return projects, unresolved
+}
+
+// guard wraps handler so every call is checked against the policy first.
+func (p *ProjectPolicy) guard(toolName string, category ToolCategory, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	access := "read"
+	if category != ToolRead {
+		access = "write"
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		allowed := p.allowed(toolName, category)
+		if allowed == nil {
+			return handler(ctx, request)
+		}
+
+		args := request.GetArguments()
+		projects, unresolved := projectsInArguments(args)
+		for _, name := range agileArgs {
+			value, _ := args[name].(string)
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			project, err := p.locateProject(ctx, name, value)
+			if err != nil {
+				return policyError("%s cannot check %s %s against the project policy: %v", toolName, name, value, err), nil
+			}
+			projects = append(projects, project)
+		}
+		for _, project := range projects {
+			if !allowed[project] {
+				return policyError("%s may not %s project %s (allowed: %s)", toolName, access, project, allowed), nil
+			}
+		}
+
+		_, hasJQL := args["jql"]
+		switch {
+		case hasJQL && len(allowed) == 0:
+			return policyError("%s may not %s any project", toolName, access), nil
+		case hasJQL:
+			ctx = withProjectScope(ctx, allowed)
+		case (unresolved || len(projects) == 0) && !p.allowUnscoped:
+			return policyError("%s can only be used with an issue key (e.g. ABC-123) or project_key while project restrictions are active (allowed: %s)", toolName, allowed), nil
+		}
+		return handler(ctx, request)
+	}
+}
+
+// locateProject returns the project of the board named by a board_id, or
+// of the board a sprint_id's sprint was created on.
+func (p *ProjectPolicy) locateProject(ctx context.Context, argument, id string) (string, error) {
+	if !isDigits(id) {
+		return "", fmt.Errorf("invalid %s: %q is not a number", argument, id)
+	}
+	locate := p.locate
+	if locate == nil {
+		locate = func(ctx context.Context, argument, id string) (string, error) {
+			return locateAgileProject(ctx, services.JiraRawClient(), argument, id)
+		}
+	}
+	project, err := locate(ctx, argument, id)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(project), nil
+}
+
+// locateAgileProject looks up the project of a board ("board_id") or of a
+// sprint's origin board ("sprint_id"). Boards that do not belong to a
+// project, such as those on a user's own filter, are an error.
+func locateAgileProject(ctx context.Context, client *services.RawClient, argument, id string) (string, error) {
+	boardID := id
+	if argument == "sprint_id" {
+		var sprint struct {
+			OriginBoardID int `json:"originBoardId"`
+		}
+		response, err := client.Do(ctx, http.MethodGet, "rest/agile/1.0/sprint/"+url.PathEscape(id), nil, nil, &sprint)
+		if err != nil {
+			if response != nil {
+				return "", fmt.Errorf("failed to get sprint: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return "", fmt.Errorf("failed to get sprint: %v", err)
+		}
+		boardID = strconv.Itoa(sprint.OriginBoardID)
+	}
+
+	var board struct {
+		Location struct {
+			ProjectKey string `json:"projectKey"`
+		} `json:"location"`
+	}
+	response, err := client.Do(ctx, http.MethodGet, "rest/agile/1.0/board/"+url.PathEscape(boardID), nil, nil, &board)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to get board: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get board: %v", err)
+	}
+	if board.Location.ProjectKey == "" {
+		return "", fmt.Errorf("board %s does not belong to a project", boardID)
+	}
+	return board.Location.ProjectKey, nil
+}
+
+// guardResource applies the read rules to a resource read, the way guard
+// does for a read tool: the projects the resource names must be readable,
+// resources naming none need allow_unscoped, and JQL sent for the read is
+// narrowed through the returned context.
+func (p *ProjectPolicy) guardResource(ctx context.Context, name string, projects []string) (context.Context, error) {
+	allowed := p.allowed(name, ToolRead)
+	if allowed == nil {
+		return ctx, nil
+	}
+	for _, project := range projects {
+		if !allowed[project] {
+			return ctx, fmt.Errorf("Blocked by project policy: %s may not read project %s (allowed: %s)", name, project, allowed)
+		}
+	}
+	if len(projects) == 0 && !p.allowUnscoped {
+		return ctx, fmt.Errorf("Blocked by project policy: %s names no project and cannot be checked while project restrictions are active (allowed: %s)", name, allowed)
+	}
+	return withProjectScope(ctx, allowed), nil
+}
+
+func policyError(format string, args ...any) *mcp.CallToolResult {
+	return mcp.NewToolResultError("Blocked by project policy: " + fmt.Sprintf(format, args...))
+}
+
+type projectScopeKey struct{}
+
+// withProjectScope records the projects JQL sent on behalf of this call must
+// stay within.
+func withProjectScope(ctx context.Context, projects projectSet) context.Context {
+	return context.WithValue(ctx, projectScopeKey{}, projects.sorted())
+}
+
+// scopeJQL narrows jql to the projects recorded in ctx, if any.
+func scopeJQL(ctx context.Context, jql string) (string, error) {
+	projects, ok := ctx.Value(projectScopeKey{}).([]string)
+	if !ok {
+		return jql, nil
+	}
+	return restrictJQL(jql, projects)
+}
+
+// restrictJQL ANDs a `project in (...)` clause onto jql, keeping any ORDER BY
+// at the end: `a = 1 ORDER BY b` becomes
+// `project in ("ABC") AND (a = 1) ORDER BY b`. jql with unbalanced
+// parentheses or quotes is refused, since it could close the wrapping
+// parenthesis and OR its way past the clause.
+func restrictJQL(jql string, projects []string) (string, error) {
+	quoted := make([]string, 0, len(projects))
+	for _, project := range projects {
+		quoted = append(quoted, fmt.Sprintf("%q", project))
+	}
+	clause := "project in (" + strings.Join(quoted, ", ") + ")"
+
+	where, orderBy, err := splitOrderBy(jql)
+	if err != nil {
+		return "", err
+	}
+	if where = strings.TrimSpace(where); where != "" {
+		clause += " AND (" + where + ")"
+	}
+	if orderBy != "" {
+		clause += " " + orderBy
+	}
+	return clause, nil
+}
+
+var orderByPattern = regexp.MustCompile(`(?i)^order\s+by\b`)
+
+// splitOrderBy separates a trailing ORDER BY from the rest of jql, ignoring
+// occurrences inside quoted strings. It fails when the parentheses or quotes
+// outside string literals do not balance.
+func splitOrderBy(jql string) (where, orderBy string, err error) {
+	var quote rune
+	depth, split := 0, -1
+	for i, r := range jql {
+		switch {
+		case quote != 0:
+			if r == '\\' {
+				continue
+			}
+			if r == quote && (i == 0 || jql[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth--; depth < 0 {
+				return "", "", fmt.Errorf("invalid JQL: unexpected ')' at position %d", i)
+			}
+		case split < 0 && (r == 'o' || r == 'O') && (i == 0 || !isJQLWordChar(rune(jql[i-1]))):
+			if orderByPattern.MatchString(jql[i:]) {
+				split = i
+			}
+		}
+	}
+	switch {
+	case quote != 0:
+		return "", "", fmt.Errorf("invalid JQL: unterminated %c quote", quote)
+	case depth > 0:
+		return "", "", fmt.Errorf("invalid JQL: %d unclosed '('", depth)
+	case split >= 0:
+		return jql[:split], strings.TrimSpace(jql[split:]), nil
+	}
+	return jql, "", nil
+}
+
+func isJQLWordChar(r rune) bool {
+	return r == '_' || r == '.' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
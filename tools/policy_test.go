@@ -0,0 +1,250 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestParseProjectPolicy(t *testing.T) {
+	policy, err := ParseProjectPolicy([]byte(`
+read: [abc, DEF]
+tools:
+  jira_delete_issue:
+    write: []
+  jira_search_issue:
+    read: [ABC]
+`))
+	if err != nil {
+		t.Fatalf("ParseProjectPolicy: %v", err)
+	}
+
+	if got := policy.allowed("jira_get_issue", ToolRead).String(); got != "ABC, DEF" {
+		t.Errorf("default read = %s", got)
+	}
+	if got := policy.allowed("jira_update_issue", ToolWrite).String(); got != "ABC, DEF" {
+		t.Errorf("write should default to the read list, got %s", got)
+	}
+	if got := policy.allowed("jira_delete_issue", ToolDestructive).String(); got != "none" {
+		t.Errorf("jira_delete_issue write = %s, want none", got)
+	}
+	if got := policy.allowed("jira_search_issue", ToolRead).String(); got != "ABC" {
+		t.Errorf("jira_search_issue read = %s, want ABC", got)
+	}
+
+	if _, err := ParseProjectPolicy([]byte("raed: [ABC]\n")); err == nil {
+		t.Errorf("a misspelled key must be rejected, not ignored")
+	}
+}
+
+func TestLoadProjectPolicyFromEnv(t *testing.T) {
+	t.Setenv("JIRA_MCP_POLICY_FILE", "")
+	t.Setenv("JIRA_MCP_PROJECTS", "")
+	t.Setenv("JIRA_MCP_WRITE_PROJECTS", "")
+	if policy, err := LoadProjectPolicyFromEnv(); policy != nil || err != nil {
+		t.Fatalf("no configuration should mean no policy, got %v, %v", policy, err)
+	}
+
+	t.Setenv("JIRA_MCP_PROJECTS", "ABC, DEF")
+	t.Setenv("JIRA_MCP_WRITE_PROJECTS", "ABC")
+	policy, err := LoadProjectPolicyFromEnv()
+	if err != nil {
+		t.Fatalf("LoadProjectPolicyFromEnv: %v", err)
+	}
+	if got := policy.Describe(); got != "read: ABC, DEF; write: ABC" {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("write: [XYZ]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JIRA_MCP_POLICY_FILE", path)
+	policy, err = LoadProjectPolicyFromEnv()
+	if err != nil {
+		t.Fatalf("LoadProjectPolicyFromEnv: %v", err)
+	}
+	if got := policy.Describe(); got != "read: any; write: XYZ" {
+		t.Errorf("the policy file should take precedence, got %q", got)
+	}
+}
+
+func TestRestrictJQL(t *testing.T) {
+	projects := []string{"ABC", "DEF"}
+	tests := []struct {
+		jql, want string
+	}{
+		{"status = Done", `project in ("ABC", "DEF") AND (status = Done)`},
+		{"status = Done ORDER BY created DESC", `project in ("ABC", "DEF") AND (status = Done) ORDER BY created DESC`},
+		{"order by rank", `project in ("ABC", "DEF") order by rank`},
+		{"", `project in ("ABC", "DEF")`},
+		{`summary ~ "sort order by date" AND reorder = 1`, `project in ("ABC", "DEF") AND (summary ~ "sort order by date" AND reorder = 1)`},
+		{"project = XYZ OR assignee = currentUser()", `project in ("ABC", "DEF") AND (project = XYZ OR assignee = currentUser())`},
+	}
+	for _, tt := range tests {
+		if got, err := restrictJQL(tt.jql, projects); err != nil || got != tt.want {
+			t.Errorf("restrictJQL(%q) = %q, %v, want %q", tt.jql, got, err, tt.want)
+		}
+	}
+}
+
+func TestRestrictJQL_Unbalanced(t *testing.T) {
+	for _, jql := range []string{
+		"a = 1) OR (project = SECRET",
+		"a = 1) ORDER BY b",
+		"(a = 1",
+		`summary ~ "open`,
+		`summary ~ 'a) OR (b`,
+	} {
+		if got, err := restrictJQL(jql, []string{"ABC"}); err == nil {
+			t.Errorf("restrictJQL(%q) = %q, want an error", jql, got)
+		}
+	}
+	if got, err := restrictJQL(`summary ~ "a) OR (b" AND (c = 1 OR d = 2)`, []string{"ABC"}); err != nil || got != `project in ("ABC") AND (summary ~ "a) OR (b" AND (c = 1 OR d = 2))` {
+		t.Errorf("parentheses in string literals should be ignored, got %q, %v", got, err)
+	}
+}
+
+func callGuarded(t *testing.T, policy *ProjectPolicy, toolName string, category ToolCategory, args map[string]any) (result *mcp.CallToolResult, called bool, ctx context.Context) {
+	t.Helper()
+	handler := policy.guard(toolName, category, func(c context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called, ctx = true, c
+		return mcp.NewToolResultText("ok"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = toolName
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	return result, called, ctx
+}
+
+func TestProjectPolicy_Guard(t *testing.T) {
+	policy, err := ParseProjectPolicy([]byte("read: [ABC, DEF]\nwrite: [ABC]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, called, _ := callGuarded(t, policy, "jira_get_issue", ToolRead, map[string]any{"issue_key": "def-12"}); !called {
+		t.Errorf("reading an allowed project should reach the handler")
+	}
+
+	result, called, _ := callGuarded(t, policy, "jira_update_issue", ToolWrite, map[string]any{"issue_key": "DEF-12"})
+	if called || !result.IsError {
+		t.Fatalf("writing DEF must be blocked")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Blocked by project policy: jira_update_issue may not write project DEF (allowed: ABC)" {
+		t.Errorf("unexpected message %q", text)
+	}
+
+	if _, called, _ := callGuarded(t, policy, "jira_link_issues", ToolWrite, map[string]any{"inward_issue": "ABC-1", "outward_issue": "XYZ-2"}); called {
+		t.Errorf("a link to a project outside the policy must be blocked")
+	}
+	if _, called, _ := callGuarded(t, policy, "jira_get_issue", ToolRead, map[string]any{"issue_key": "10042"}); called {
+		t.Errorf("a numeric issue ID cannot be checked and must be blocked")
+	}
+	if _, called, _ := callGuarded(t, policy, "jira_download_attachment", ToolRead, map[string]any{"attachment_id": "7"}); called {
+		t.Errorf("calls naming no project must be blocked unless allow_unscoped is set")
+	}
+
+	_, called, ctx := callGuarded(t, policy, "jira_search_issue", ToolRead, map[string]any{"jql": "status = Done"})
+	if !called {
+		t.Fatalf("JQL tools should run with a narrowed query")
+	}
+	if got, _ := scopeJQL(ctx, "status = Done"); got != `project in ("ABC", "DEF") AND (status = Done)` {
+		t.Errorf("scoped JQL = %q", got)
+	}
+	_, _, ctx = callGuarded(t, policy, "jira_bulk_update", ToolWrite, map[string]any{"jql": "sprint = 1"})
+	if got, _ := scopeJQL(ctx, "sprint = 1"); !strings.HasPrefix(got, `project in ("ABC") AND`) {
+		t.Errorf("write tools should be narrowed to the write list, got %q", got)
+	}
+}
+
+func TestProjectPolicy_AllowUnscoped(t *testing.T) {
+	policy, err := ParseProjectPolicy([]byte("read: [ABC]\nallow_unscoped: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, called, _ := callGuarded(t, policy, "jira_download_attachment", ToolRead, map[string]any{"attachment_id": "7"}); !called {
+		t.Errorf("allow_unscoped should let calls without a project through")
+	}
+	if _, called, _ := callGuarded(t, policy, "jira_get_issue", ToolRead, map[string]any{"issue_key": "XYZ-1"}); called {
+		t.Errorf("allow_unscoped must not relax calls that do name a project")
+	}
+}
+
+func TestProjectPolicy_GuardBoardsAndSprints(t *testing.T) {
+	policy, err := ParseProjectPolicy([]byte("read: [ABC]\nallow_unscoped: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy.locate = func(ctx context.Context, argument, id string) (string, error) {
+		switch argument + "=" + id {
+		case "board_id=1", "sprint_id=10":
+			return "abc", nil
+		case "board_id=2", "sprint_id=20":
+			return "XYZ", nil
+		}
+		return "", errors.New("board 3 does not belong to a project")
+	}
+
+	if _, called, _ := callGuarded(t, policy, "jira_get_sprint", ToolRead, map[string]any{"sprint_id": "10"}); !called {
+		t.Errorf("a sprint on an allowed project's board should be readable")
+	}
+	result, called, _ := callGuarded(t, policy, "jira_get_sprint", ToolRead, map[string]any{"sprint_id": "20"})
+	if called {
+		t.Fatalf("a sprint on another project's board must be blocked, even with allow_unscoped")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "Blocked by project policy: jira_get_sprint may not read project XYZ (allowed: ABC)" {
+		t.Errorf("unexpected message %q", text)
+	}
+	if _, called, _ := callGuarded(t, policy, "jira_list_sprints", ToolRead, map[string]any{"project_key": "ABC", "board_id": "2"}); called {
+		t.Errorf("board_id must be checked even when project_key is allowed")
+	}
+	if _, called, _ := callGuarded(t, policy, "jira_get_active_sprint", ToolRead, map[string]any{"board_id": "3"}); called {
+		t.Errorf("a board whose project cannot be found must be blocked")
+	}
+	if _, called, _ := callGuarded(t, policy, "jira_get_active_sprint", ToolRead, map[string]any{"board_id": "1 OR 2"}); called {
+		t.Errorf("a non-numeric board_id must be blocked")
+	}
+}
+
+func TestLocateAgileProject(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/agile/1.0/sprint/10":
+			_, _ = w.Write([]byte(`{"id":10,"originBoardId":1}`))
+		case "/rest/agile/1.0/board/1":
+			_, _ = w.Write([]byte(`{"id":1,"location":{"projectKey":"ABC"}}`))
+		case "/rest/agile/1.0/board/3":
+			_, _ = w.Write([]byte(`{"id":3,"location":{"type":"user"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client := &services.RawClient{Host: srv.URL, PAT: "pat", HTTP: srv.Client()}
+	ctx := context.Background()
+
+	if project, err := locateAgileProject(ctx, client, "sprint_id", "10"); err != nil || project != "ABC" {
+		t.Errorf("sprint 10 = %q, %v, want ABC", project, err)
+	}
+	if project, err := locateAgileProject(ctx, client, "board_id", "1"); err != nil || project != "ABC" {
+		t.Errorf("board 1 = %q, %v, want ABC", project, err)
+	}
+	if _, err := locateAgileProject(ctx, client, "board_id", "3"); err == nil {
+		t.Errorf("a board without a project should be an error")
+	}
+}
@@ -0,0 +1,562 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// defaultResourcePollInterval is how often subscribed issues are checked
+// for changes unless JIRA_MCP_RESOURCE_POLL says otherwise.
+const defaultResourcePollInterval = time.Minute
+
+// minResourcePollInterval keeps a low JIRA_MCP_RESOURCE_POLL from hammering
+// Jira.
+const minResourcePollInterval = 10 * time.Second
+
+// stdioSessionID is the session ID mcp-go gives the single stdio client.
+const stdioSessionID = "stdio"
+
+// issueResourcePrefix is the part of a jira://issue/{key} URI before the key.
+const issueResourcePrefix = "jira://issue/"
+
+// sessionIdleTimeout is how long after its last request an HTTP session
+// without a notification stream is still taken to be alive. Sessions idle
+// for longer are expired with their subscriptions.
+const sessionIdleTimeout = 30 * time.Minute
+
+// maxMCPRequestBody caps the size of an MCP request read by HTTPHandler,
+// on top of room for a base64 attachment of UploadLimit.
+const maxMCPRequestBody = 1 << 20
+
+// mcpRequestLimit is the largest MCP request HTTPHandler reads.
+func mcpRequestLimit() int64 {
+	limit := int64(maxMCPRequestBody)
+	if upload, err := UploadLimit(); err == nil {
+		limit += (upload + 2) / 3 * 4
+	}
+	return limit
+}
+
+// ResourceSubscriptions implements resources/subscribe for jira://
+// resources. mcp-go advertises the capability but leaves the methods to the
+// application, so they are answered here, in front of the transport (see
+// Stdio and HTTPHandler). Jira has no push channel an API token can use, so
+// Run polls each subscribed issue's updated timestamp and sends
+// notifications/resources/updated when it moves. When a WebhookReceiver
+// feeds it events, the other resources can be subscribed to as well.
+type ResourceSubscriptions struct {
+	filter   *Filter
+	interval time.Duration
+
+	// webhooks is set once a WebhookReceiver reports changes, which is the
+	// only way non-issue resources learn about them.
+	webhooks bool
+
+	mu sync.Mutex
+	// sessions holds the HTTP sessions issued through SessionIDManager; nil
+	// when sessions are not tracked.
+	sessions map[string]*sessionActivity
+	// subscribers maps a resource URI to the sessions subscribed to it.
+	subscribers map[string]map[string]bool
+	// updated holds the last updated timestamp seen for each URI.
+	updated map[string]string
+
+	// fetch returns an issue's updated timestamp, or "" when the issue no
+	// longer exists. notify tells one session that uri changed.
+	fetch  func(ctx context.Context, issueKey string) (string, error)
+	notify func(sessionID, uri string) error
+	now    func() time.Time
+}
+
+// sessionActivity is what is known about an HTTP session's liveness: when
+// it last made a request and how many notification streams it has open.
+type sessionActivity struct {
+	lastSeen time.Time
+	streams  int
+}
+
+// idle reports whether the session has had no request and no open stream
+// for longer than sessionIdleTimeout.
+func (a *sessionActivity) idle(now time.Time) bool {
+	return a.streams == 0 && now.Sub(a.lastSeen) > sessionIdleTimeout
+}
+
+// NewResourceSubscriptionsFromEnv creates the subscription handler for s,
+// polling every JIRA_MCP_RESOURCE_POLL (a Go duration, default 1m).
+// Subscriptions are checked against the filter's project policy.
+func NewResourceSubscriptionsFromEnv(s *server.MCPServer, filter *Filter) (*ResourceSubscriptions, error) {
+	interval := defaultResourcePollInterval
+	if raw := strings.TrimSpace(os.Getenv("JIRA_MCP_RESOURCE_POLL")); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JIRA_MCP_RESOURCE_POLL %q: %v", raw, err)
+		}
+		if parsed < minResourcePollInterval {
+			return nil, fmt.Errorf("JIRA_MCP_RESOURCE_POLL must be at least %s, got %s", minResourcePollInterval, parsed)
+		}
+		interval = parsed
+	}
+
+	subscriptions := newResourceSubscriptions(filter, interval)
+	subscriptions.fetch = func(ctx context.Context, issueKey string) (string, error) {
+		return fetchIssueUpdated(ctx, services.JiraRawClient(), issueKey)
+	}
+	subscriptions.notify = func(sessionID, uri string) error {
+		return s.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+	}
+	return subscriptions, nil
+}
+
+func newResourceSubscriptions(filter *Filter, interval time.Duration) *ResourceSubscriptions {
+	return &ResourceSubscriptions{
+		filter:      filter,
+		interval:    interval,
+		subscribers: map[string]map[string]bool{},
+		updated:     map[string]string{},
+		now:         time.Now,
+	}
+}
+
+// Interval returns how often subscribed issues are polled.
+func (r *ResourceSubscriptions) Interval() time.Duration {
+	return r.interval
+}
+
+// fetchIssueUpdated returns the issue's updated timestamp, or "" when Jira
+// reports it gone.
+func fetchIssueUpdated(ctx context.Context, client *services.RawClient, issueKey string) (string, error) {
+	var issue struct {
+		Fields struct {
+			Updated string `json:"updated"`
+		} `json:"fields"`
+	}
+	path := fmt.Sprintf("rest/api/3/issue/%s", url.PathEscape(issueKey))
+	response, err := client.Do(ctx, http.MethodGet, path, url.Values{"fields": {"updated"}}, nil, &issue)
+	if err != nil {
+		if response != nil && response.Code == http.StatusNotFound {
+			return "", nil
+		}
+		if response != nil {
+			return "", fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get issue: %v", err)
+	}
+	return issue.Fields.Updated, nil
+}
+
+// subscriptionRequest is the part of a JSON-RPC message handle looks at.
+type subscriptionRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		URI string `json:"uri"`
+	} `json:"params"`
+}
+
+type subscriptionResponse struct {
+	JSONRPC string             `json:"jsonrpc"`
+	ID      json.RawMessage    `json:"id"`
+	Result  *struct{}          `json:"result,omitempty"`
+	Error   *subscriptionError `json:"error,omitempty"`
+}
+
+type subscriptionError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// handle answers message when it is a resources/subscribe or
+// resources/unsubscribe request; anything else is left to mcp-go.
+func (r *ResourceSubscriptions) handle(ctx context.Context, sessionID string, message []byte) ([]byte, bool) {
+	var request subscriptionRequest
+	if err := json.Unmarshal(message, &request); err != nil {
+		return nil, false
+	}
+	if request.Method != "resources/subscribe" && request.Method != "resources/unsubscribe" {
+		return nil, false
+	}
+
+	response := subscriptionResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID}
+	if err := r.apply(ctx, sessionID, request.Method, request.Params.URI); err != nil {
+		response.Error = &subscriptionError{Code: mcp.INVALID_PARAMS, Message: err.Error()}
+	} else {
+		response.Result = &struct{}{}
+	}
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, false
+	}
+	return encoded, true
+}
+
+func (r *ResourceSubscriptions) apply(ctx context.Context, sessionID, method, uri string) error {
+	if sessionID == "" {
+		return fmt.Errorf("subscriptions need an MCP session")
+	}
+	template, projects, ok := resourceScope(uri)
+	switch {
+	case !ok && r.webhooks:
+		return fmt.Errorf("%q is not a jira:// resource", uri)
+	case !ok || (template != issueResourceTemplate && !r.webhooks):
+		return fmt.Errorf("only %s resources can be subscribed to, got %q", issueResourceTemplate, uri)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, live := r.sessions[sessionID]; r.sessions != nil && !live {
+		return fmt.Errorf("unknown MCP session %q: initialize a session before subscribing", sessionID)
+	}
+	if method == "resources/unsubscribe" {
+		if sessions := r.subscribers[uri]; sessions != nil {
+			delete(sessions, sessionID)
+			if len(sessions) == 0 {
+				delete(r.subscribers, uri)
+				delete(r.updated, uri)
+			}
+		}
+		return nil
+	}
+
+	if _, err := r.filter.guardResource(ctx, template, projects...); err != nil {
+		return err
+	}
+	if r.subscribers[uri] == nil {
+		r.subscribers[uri] = map[string]bool{}
+	}
+	r.subscribers[uri][sessionID] = true
+	return nil
+}
+
+// resourceScope parses a jira:// resource URI into its template and the
+// projects it names.
+func resourceScope(uri string) (template string, projects []string, ok bool) {
+	rest, ok := strings.CutPrefix(uri, "jira://")
+	if !ok {
+		return "", nil, false
+	}
+	parts := strings.Split(rest, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "issue":
+		match := issueKeyPattern.FindStringSubmatch(parts[1])
+		if match == nil {
+			return "", nil, false
+		}
+		return issueResourceTemplate, []string{strings.ToUpper(match[1])}, true
+	case len(parts) == 2 && parts[0] == "project" && parts[1] != "":
+		return projectResourceTemplate, []string{strings.ToUpper(parts[1])}, true
+	case len(parts) == 2 && parts[0] == "sprint" && isDigits(parts[1]):
+		return sprintResourceTemplate, nil, true
+	case len(parts) == 2 && parts[0] == "filter" && isDigits(parts[1]):
+		return filterResourceTemplate, nil, true
+	case len(parts) == 3 && parts[0] == "board" && isDigits(parts[1]) && parts[2] == "backlog":
+		return backlogResourceTemplate, nil, true
+	}
+	return "", nil, false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// changed notifies the subscribers of uri right away. updated, when known,
+// becomes the baseline for polling so the same change is not reported
+// twice.
+func (r *ResourceSubscriptions) changed(uri, updated string) {
+	r.mu.Lock()
+	sessions := r.subscribers[uri]
+	targets := make([]string, 0, len(sessions))
+	for sessionID := range sessions {
+		targets = append(targets, sessionID)
+	}
+	if len(sessions) > 0 && updated != "" {
+		r.updated[uri] = updated
+	}
+	r.mu.Unlock()
+
+	sort.Strings(targets)
+	for _, sessionID := range targets {
+		r.send(sessionID, uri)
+	}
+}
+
+// send notifies one session that uri changed. A session mcp-go no longer
+// knows is dropped with its subscriptions, unless it is an HTTP session that
+// is still making requests but has no notification stream open: that one
+// misses the update and keeps its subscriptions for the next.
+func (r *ResourceSubscriptions) send(sessionID, uri string) {
+	err := r.notify(sessionID, uri)
+	if !errors.Is(err, server.ErrSessionNotFound) {
+		return
+	}
+	r.mu.Lock()
+	activity, live := r.sessions[sessionID]
+	idle := !live || activity.idle(r.now())
+	r.mu.Unlock()
+	if idle {
+		r.dropSession(sessionID)
+	}
+}
+
+// expireSessions forgets the HTTP sessions that have been idle for longer
+// than sessionIdleTimeout, with their subscriptions, so clients that go
+// away without ending their session do not linger.
+func (r *ResourceSubscriptions) expireSessions() {
+	now := r.now()
+	var expired []string
+	r.mu.Lock()
+	for sessionID, activity := range r.sessions {
+		if activity.idle(now) {
+			delete(r.sessions, sessionID)
+			expired = append(expired, sessionID)
+		}
+	}
+	r.mu.Unlock()
+	for _, sessionID := range expired {
+		r.dropSession(sessionID)
+	}
+}
+
+// dropSession forgets every subscription of a closed session.
+func (r *ResourceSubscriptions) dropSession(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for uri, sessions := range r.subscribers {
+		delete(sessions, sessionID)
+		if len(sessions) == 0 {
+			delete(r.subscribers, uri)
+			delete(r.updated, uri)
+		}
+	}
+}
+
+// Run polls subscribed issues and expires idle sessions until ctx is
+// cancelled.
+func (r *ResourceSubscriptions) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.expireSessions()
+			r.poll(ctx)
+		}
+	}
+}
+
+// poll checks every subscribed issue once. The first check of an issue only
+// records its timestamp; later ones notify the subscribers when it changed.
+func (r *ResourceSubscriptions) poll(ctx context.Context) {
+	r.mu.Lock()
+	uris := make([]string, 0, len(r.subscribers))
+	for uri := range r.subscribers {
+		if strings.HasPrefix(uri, issueResourcePrefix) {
+			uris = append(uris, uri)
+		}
+	}
+	r.mu.Unlock()
+	sort.Strings(uris)
+
+	for _, uri := range uris {
+		updated, err := r.fetch(ctx, strings.TrimPrefix(uri, issueResourcePrefix))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "jira-mcp: resource poll of %s failed: %v\n", uri, err)
+			continue
+		}
+
+		r.mu.Lock()
+		sessions := r.subscribers[uri]
+		previous, seen := r.updated[uri]
+		if sessions == nil {
+			// Unsubscribed while we were fetching.
+			r.mu.Unlock()
+			continue
+		}
+		r.updated[uri] = updated
+		targets := make([]string, 0, len(sessions))
+		for sessionID := range sessions {
+			targets = append(targets, sessionID)
+		}
+		r.mu.Unlock()
+
+		if !seen || previous == updated {
+			continue
+		}
+		sort.Strings(targets)
+		for _, sessionID := range targets {
+			r.send(sessionID, uri)
+		}
+	}
+}
+
+// Stdio wraps the stdio transport's input and output. Subscription requests
+// read from in are answered on the returned writer, which the stdio server
+// must also use so responses never interleave.
+func (r *ResourceSubscriptions) Stdio(in io.Reader, out io.Writer) (io.Reader, io.Writer) {
+	writer := &lockedWriter{w: out}
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if response, ok := r.handle(context.Background(), stdioSessionID, line); ok {
+					_, _ = writer.Write(append(response, '\n'))
+				} else if _, werr := pipeWriter.Write(line); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				_ = pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pipeReader, writer
+}
+
+// lockedWriter serialises writes from the stdio server and Stdio.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// SessionIDManager returns the session ID manager the streamable HTTP server
+// must use with HTTPHandler. It remembers the sessions it issued, so only
+// those can subscribe, and drops a session's subscriptions when it ends or
+// expires (see expireSessions).
+func (r *ResourceSubscriptions) SessionIDManager() server.SessionIdManager {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions == nil {
+		r.sessions = map[string]*sessionActivity{}
+	}
+	return sessionIDManager{r}
+}
+
+// sessionIDManager implements server.SessionIdManager for
+// ResourceSubscriptions.SessionIDManager.
+type sessionIDManager struct {
+	r *ResourceSubscriptions
+}
+
+func (m sessionIDManager) Generate() string {
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	sessionID := "mcp-session-" + hex.EncodeToString(random)
+	m.r.mu.Lock()
+	defer m.r.mu.Unlock()
+	m.r.sessions[sessionID] = &sessionActivity{lastSeen: m.r.now()}
+	return sessionID
+}
+
+func (m sessionIDManager) Validate(sessionID string) (bool, error) {
+	m.r.mu.Lock()
+	defer m.r.mu.Unlock()
+	activity, live := m.r.sessions[sessionID]
+	if !live {
+		return false, fmt.Errorf("unknown session id: %s", sessionID)
+	}
+	activity.lastSeen = m.r.now()
+	return false, nil
+}
+
+func (m sessionIDManager) Terminate(sessionID string) (bool, error) {
+	m.r.mu.Lock()
+	delete(m.r.sessions, sessionID)
+	m.r.mu.Unlock()
+	m.r.dropSession(sessionID)
+	return false, nil
+}
+
+// HTTPHandler wraps the streamable HTTP handler. Subscription requests are
+// answered directly, keyed by the Mcp-Session-Id header; a DELETE ending a
+// session drops its subscriptions, and a session keeps from expiring while
+// it has a GET notification stream open.
+func (r *ResourceSubscriptions) HTTPHandler(next http.Handler) http.Handler {
+	limit := mcpRequestLimit()
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sessionID := req.Header.Get(server.HeaderKeySessionID)
+		switch req.Method {
+		case http.MethodPost:
+			body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, limit))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			if response, ok := r.handle(req.Context(), sessionID, body); ok {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(response)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		case http.MethodGet:
+			if sessionID != "" {
+				r.streamOpened(sessionID)
+				defer r.streamClosed(sessionID)
+			}
+		case http.MethodDelete:
+			if sessionID != "" {
+				r.dropSession(sessionID)
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// streamOpened and streamClosed count the notification streams a tracked
+// session has open; closing one counts as activity.
+func (r *ResourceSubscriptions) streamOpened(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if activity := r.sessions[sessionID]; activity != nil {
+		activity.streams++
+	}
+}
+
+func (r *ResourceSubscriptions) streamClosed(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if activity := r.sessions[sessionID]; activity != nil {
+		activity.streams--
+		activity.lastSeen = r.now()
+	}
+}
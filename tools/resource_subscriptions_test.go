@@ -0,0 +1,262 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestResourceSubscriptions_Handle(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	ctx := context.Background()
+
+	if _, ok := subscriptions.handle(ctx, "s1", []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)); ok {
+		t.Errorf("other methods must be left to the server")
+	}
+
+	response, ok := subscriptions.handle(ctx, "s1", []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`))
+	if !ok || string(response) != `{"jsonrpc":"2.0","id":2,"result":{}}` {
+		t.Errorf("subscribe response = %s", response)
+	}
+	response, _ = subscriptions.handle(ctx, "s1", []byte(`{"jsonrpc":"2.0","id":"x","method":"resources/subscribe","params":{"uri":"jira://sprint/7"}}`))
+	if !strings.Contains(string(response), `"code":-32602`) {
+		t.Errorf("only issues can be subscribed to, got %s", response)
+	}
+	if want := map[string]map[string]bool{"jira://issue/ABC-1": {"s1": true}}; !reflect.DeepEqual(subscriptions.subscribers, want) {
+		t.Errorf("subscribers = %v", subscriptions.subscribers)
+	}
+
+	subscriptions.handle(ctx, "s1", []byte(`{"jsonrpc":"2.0","id":3,"method":"resources/unsubscribe","params":{"uri":"jira://issue/ABC-1"}}`))
+	if len(subscriptions.subscribers) != 0 {
+		t.Errorf("unsubscribe left %v", subscriptions.subscribers)
+	}
+
+	policy, _ := ParseProjectPolicy([]byte("read: [DEF]\n"))
+	filter := &Filter{}
+	filter.UseProjectPolicy(policy)
+	subscriptions.filter = filter
+	response, _ = subscriptions.handle(ctx, "s1", []byte(`{"jsonrpc":"2.0","id":4,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`))
+	if !strings.Contains(string(response), "Blocked by project policy") {
+		t.Errorf("subscribing outside the policy must fail, got %s", response)
+	}
+}
+
+func TestResourceSubscriptions_Poll(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	ctx := context.Background()
+	updated := map[string]string{"ABC-1": "t1", "ABC-2": "t1"}
+	subscriptions.fetch = func(ctx context.Context, issueKey string) (string, error) {
+		return updated[issueKey], nil
+	}
+	var notified []string
+	subscriptions.notify = func(sessionID, uri string) error {
+		notified = append(notified, sessionID+" "+uri)
+		return nil
+	}
+	for _, message := range []string{
+		`{"id":1,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`,
+		`{"id":2,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-2"}}`,
+	} {
+		subscriptions.handle(ctx, "s1", []byte(message))
+	}
+	subscriptions.handle(ctx, "s2", []byte(`{"id":3,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`))
+
+	subscriptions.poll(ctx)
+	if len(notified) != 0 {
+		t.Fatalf("the first poll only records a baseline, got %v", notified)
+	}
+
+	updated["ABC-1"] = "t2"
+	delete(updated, "ABC-2")
+	subscriptions.poll(ctx)
+	want := []string{"s1 jira://issue/ABC-1", "s2 jira://issue/ABC-1", "s1 jira://issue/ABC-2"}
+	if !reflect.DeepEqual(notified, want) {
+		t.Errorf("notified = %v, want %v", notified, want)
+	}
+
+	notified = nil
+	subscriptions.dropSession("s1")
+	updated["ABC-1"] = "t3"
+	subscriptions.poll(ctx)
+	if want := []string{"s2 jira://issue/ABC-1"}; !reflect.DeepEqual(notified, want) {
+		t.Errorf("after dropping s1 notified = %v, want %v", notified, want)
+	}
+}
+
+func TestResourceSubscriptions_Stdio(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	input := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n")
+	var output strings.Builder
+
+	in, _ := subscriptions.Stdio(input, &output)
+	passed, err := io.ReadAll(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(passed) != `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n" {
+		t.Errorf("passed through %q", passed)
+	}
+	if output.String() != `{"jsonrpc":"2.0","id":1,"result":{}}`+"\n" {
+		t.Errorf("answered %q", output.String())
+	}
+	if !subscriptions.subscribers["jira://issue/ABC-1"][stdioSessionID] {
+		t.Errorf("the stdio session should be subscribed")
+	}
+}
+
+func TestResourceSubscriptions_HTTPHandler(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	var forwarded []string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		forwarded = append(forwarded, r.Method+" "+string(body))
+	})
+	handler := subscriptions.HTTPHandler(next)
+
+	send := func(method, body string) string {
+		req := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+		req.Header.Set(server.HeaderKeySessionID, "s1")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		line, _ := bufio.NewReader(recorder.Body).ReadString('\n')
+		return line
+	}
+
+	if got := send(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`); got != `{"jsonrpc":"2.0","id":1,"result":{}}` {
+		t.Errorf("subscribe answered %q", got)
+	}
+	send(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	send(http.MethodDelete, "")
+
+	if want := []string{`POST {"jsonrpc":"2.0","id":2,"method":"tools/list"}`, "DELETE "}; !reflect.DeepEqual(forwarded, want) {
+		t.Errorf("forwarded = %v, want %v", forwarded, want)
+	}
+	if len(subscriptions.subscribers) != 0 {
+		t.Errorf("ending the session should drop its subscriptions, got %v", subscriptions.subscribers)
+	}
+}
+
+func TestResourceSubscriptions_HTTPHandlerLimitsBody(t *testing.T) {
+	handler := newResourceSubscriptions(nil, time.Minute).HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("an oversized request should not be forwarded")
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(strings.Repeat("x", int(mcpRequestLimit())+1)))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestResourceSubscriptions_SessionIDManager(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	manager := subscriptions.SessionIDManager()
+	ctx := context.Background()
+	subscribe := []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`)
+
+	if response, _ := subscriptions.handle(ctx, "mcp-session-made-up", subscribe); !strings.Contains(string(response), "unknown MCP session") {
+		t.Errorf("an unknown session should be refused, got %s", response)
+	}
+	if _, err := manager.Validate("mcp-session-made-up"); err == nil {
+		t.Error("an ID the manager did not issue should not validate")
+	}
+
+	sessionID := manager.Generate()
+	if _, err := manager.Validate(sessionID); err != nil {
+		t.Fatalf("Validate(%s): %v", sessionID, err)
+	}
+	if response, _ := subscriptions.handle(ctx, sessionID, subscribe); string(response) != `{"jsonrpc":"2.0","id":1,"result":{}}` {
+		t.Errorf("subscribe answered %s", response)
+	}
+	if _, err := manager.Terminate(sessionID); err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions.subscribers) != 0 {
+		t.Errorf("ending the session should drop its subscriptions, got %v", subscriptions.subscribers)
+	}
+	if _, err := manager.Validate(sessionID); err == nil {
+		t.Error("a terminated session should not validate")
+	}
+}
+
+func TestResourceSubscriptions_DropsVanishedSessions(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	manager := subscriptions.SessionIDManager()
+	ctx := context.Background()
+	idle, active := manager.Generate(), manager.Generate()
+	subscriptions.sessions[idle].lastSeen = time.Now().Add(-2 * sessionIdleTimeout)
+	for _, sessionID := range []string{idle, active} {
+		subscriptions.handle(ctx, sessionID, []byte(`{"id":1,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`))
+	}
+	subscriptions.notify = func(sessionID, uri string) error {
+		// Neither has a notification stream open.
+		return server.ErrSessionNotFound
+	}
+
+	subscriptions.changed("jira://issue/ABC-1", "")
+	if sessions := subscriptions.subscribers["jira://issue/ABC-1"]; len(sessions) != 1 || !sessions[active] {
+		t.Errorf("only the session still making requests should stay subscribed, got %v", sessions)
+	}
+
+	subscriptions.notify = func(sessionID, uri string) error { return errors.New("channel blocked") }
+	subscriptions.changed("jira://issue/ABC-1", "")
+	if !subscriptions.subscribers["jira://issue/ABC-1"][active] {
+		t.Error("other notification errors should keep the subscription")
+	}
+}
+
+func TestResourceSubscriptions_ExpiresIdleSessions(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	subscriptions.now = func() time.Time { return now }
+	manager := subscriptions.SessionIDManager()
+	ctx := context.Background()
+	gone, streaming, active := manager.Generate(), manager.Generate(), manager.Generate()
+	for _, sessionID := range []string{gone, streaming, active} {
+		subscriptions.handle(ctx, sessionID, []byte(`{"id":1,"method":"resources/subscribe","params":{"uri":"jira://issue/ABC-1"}}`))
+	}
+
+	stream := make(chan struct{})
+	streamOpen := make(chan struct{})
+	handler := subscriptions.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(streamOpen)
+		<-stream
+	}))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		req.Header.Set(server.HeaderKeySessionID, streaming)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	<-streamOpen
+
+	now = now.Add(sessionIdleTimeout + time.Minute)
+	if _, err := manager.Validate(active); err != nil {
+		t.Fatal(err)
+	}
+	subscriptions.expireSessions()
+
+	if _, err := manager.Validate(gone); err == nil {
+		t.Error("a session idle past the timeout should expire")
+	}
+	if sessions := subscriptions.subscribers["jira://issue/ABC-1"]; len(sessions) != 2 || sessions[gone] {
+		t.Errorf("only the expired session's subscription should go, got %v", sessions)
+	}
+	if _, err := manager.Validate(streaming); err != nil {
+		t.Errorf("a session with an open stream should not expire: %v", err)
+	}
+
+	close(stream)
+	<-done
+}
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxWebhookBody caps the size of a webhook payload.
+const maxWebhookBody = 1 << 20
+
+// webhookLogger is the logger name on the notifications/message sent for
+// each event.
+const webhookLogger = "jira-webhook"
+
+// WebhookReceiver accepts Jira webhook deliveries and passes them on to MCP
+// clients: subscribers of the affected jira:// resources get
+// notifications/resources/updated, and every session gets a logging message
+// describing the event.
+//
+// Deliveries must prove they know JIRA_MCP_WEBHOOK_SECRET, either with an
+// X-Hub-Signature HMAC (Jira Cloud webhooks with a secret) or a secret query
+// parameter in the webhook URL (Jira Server / Data Center, which cannot sign).
+// Events for projects outside the project policy are dropped.
+type WebhookReceiver struct {
+	secret        []byte
+	filter        *Filter
+	subscriptions *ResourceSubscriptions
+
+	// broadcast sends a notification to every connected session.
+	broadcast func(method string, params map[string]any)
+}
+
+// NewWebhookReceiverFromEnv returns a receiver for s, or nil when
+// JIRA_MCP_WEBHOOK_SECRET is unset: without a secret anyone could forge
+// events, so the endpoint stays off.
+func NewWebhookReceiverFromEnv(s *server.MCPServer, filter *Filter, subscriptions *ResourceSubscriptions) *WebhookReceiver {
+	secret := strings.TrimSpace(os.Getenv("JIRA_MCP_WEBHOOK_SECRET"))
+	if secret == "" {
+		return nil
+	}
+	receiver := newWebhookReceiver(secret, filter, subscriptions)
+	receiver.broadcast = s.SendNotificationToAllClients
+	return receiver
+}
+
+func newWebhookReceiver(secret string, filter *Filter, subscriptions *ResourceSubscriptions) *WebhookReceiver {
+	return &WebhookReceiver{secret: []byte(secret), filter: filter, subscriptions: subscriptions}
+}
+
+// Mount serves the receiver at pattern on mux. Only then can clients
+// subscribe to the resources that webhooks alone report changes to, such as
+// sprints and boards; a receiver that is never mounted gets no events.
+func (w *WebhookReceiver) Mount(mux *http.ServeMux, pattern string) {
+	w.subscriptions.webhooks = true
+	mux.Handle(pattern, w)
+}
+
+// webhookEvent is the part of a Jira webhook payload the receiver uses.
+type webhookEvent struct {
+	Event string `json:"webhookEvent"`
+	User  *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Issue *struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			Updated string `json:"updated"`
+			Status  *struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	} `json:"issue"`
+	Changelog *struct {
+		Items []struct {
+			Field      string `json:"field"`
+			FromString string `json:"fromString"`
+			ToString   string `json:"toString"`
+		} `json:"items"`
+	} `json:"changelog"`
+	Comment *struct {
+		ID     string `json:"id"`
+		Author *struct {
+			DisplayName string `json:"displayName"`
+		} `json:"author"`
+	} `json:"comment"`
+	Sprint *struct {
+		ID            int    `json:"id"`
+		Name          string `json:"name"`
+		State         string `json:"state"`
+		OriginBoardID int    `json:"originBoardId"`
+	} `json:"sprint"`
+}
+
+func (w *WebhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(rw, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if !w.verify(req, body) {
+		http.Error(rw, "invalid webhook signature or secret", http.StatusUnauthorized)
+		return
+	}
+
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.Event == "" {
+		http.Error(rw, "not a Jira webhook payload", http.StatusBadRequest)
+		return
+	}
+	w.dispatch(req.Context(), event)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the X-Hub-Signature HMAC when present and the secret query
+// parameter otherwise.
+func (w *WebhookReceiver) verify(req *http.Request, body []byte) bool {
+	if signature := req.Header.Get("X-Hub-Signature"); signature != "" {
+		digest, ok := strings.CutPrefix(signature, "sha256=")
+		if !ok {
+			return false
+		}
+		got, err := hex.DecodeString(digest)
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(body)
+		return hmac.Equal(got, mac.Sum(nil))
+	}
+	secret := req.URL.Query().Get("secret")
+	return secret != "" && subtle.ConstantTimeCompare([]byte(secret), w.secret) == 1
+}
+
+// dispatch notifies clients of one event. Events the project policy does not
+// let clients read are dropped.
+func (w *WebhookReceiver) dispatch(ctx context.Context, event webhookEvent) {
+	var uris []string
+	data := map[string]any{"event": event.Event}
+	if event.User != nil && event.User.DisplayName != "" {
+		data["user"] = event.User.DisplayName
+	}
+
+	switch {
+	case event.Issue != nil && event.Issue.Key != "":
+		issueURI := issueResourcePrefix + event.Issue.Key
+		template, projects, ok := resourceScope(issueURI)
+		if !ok {
+			return
+		}
+		if _, err := w.filter.guardResource(ctx, template, projects...); err != nil {
+			return
+		}
+		uris = append(uris, issueURI)
+		data["issue"] = event.Issue.Key
+		data["summary"] = event.Issue.Fields.Summary
+		if event.Issue.Fields.Status != nil {
+			data["status"] = event.Issue.Fields.Status.Name
+		}
+		if event.Changelog != nil && len(event.Changelog.Items) > 0 {
+			changes := make([]string, 0, len(event.Changelog.Items))
+			for _, item := range event.Changelog.Items {
+				changes = append(changes, fmt.Sprintf("%s: %s → %s", item.Field, item.FromString, item.ToString))
+			}
+			data["changes"] = changes
+		}
+		if event.Comment != nil {
+			data["comment_id"] = event.Comment.ID
+			if event.Comment.Author != nil {
+				data["user"] = event.Comment.Author.DisplayName
+			}
+		}
+		w.subscriptions.changed(issueURI, event.Issue.Fields.Updated)
+
+	case event.Sprint != nil:
+		if _, err := w.filter.guardResource(ctx, sprintResourceTemplate); err != nil {
+			return
+		}
+		uris = append(uris, fmt.Sprintf("jira://sprint/%d", event.Sprint.ID))
+		if event.Sprint.OriginBoardID != 0 {
+			// Starting or closing a sprint moves issues in or out of the
+			// backlog.
+			uris = append(uris, fmt.Sprintf("jira://board/%d/backlog", event.Sprint.OriginBoardID))
+		}
+		data["sprint"] = event.Sprint.Name
+		data["sprint_id"] = event.Sprint.ID
+		data["state"] = event.Sprint.State
+		for _, uri := range uris {
+			w.subscriptions.changed(uri, "")
+		}
+
+	default:
+		// Events about neither an issue nor a sprint (projects, versions,
+		// users) carry nothing the policy can check.
+		if _, err := w.filter.guardResource(ctx, webhookLogger); err != nil {
+			return
+		}
+	}
+
+	if len(uris) > 0 {
+		data["uris"] = uris
+	}
+	if w.broadcast != nil {
+		w.broadcast("notifications/message", map[string]any{
+			"level":  mcp.LoggingLevelInfo,
+			"logger": webhookLogger,
+			"data":   data,
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type webhookHarness struct {
+	receiver *WebhookReceiver
+	notified []string
+	logged   []map[string]any
+}
+
+func newWebhookHarness(t *testing.T, filter *Filter) *webhookHarness {
+	t.Helper()
+	h := &webhookHarness{}
+	subscriptions := newResourceSubscriptions(filter, time.Minute)
+	subscriptions.notify = func(sessionID, uri string) error {
+		h.notified = append(h.notified, sessionID+" "+uri)
+		return nil
+	}
+	h.receiver = newWebhookReceiver("s3cret", filter, subscriptions)
+	h.receiver.Mount(http.NewServeMux(), "/webhook")
+	h.receiver.broadcast = func(method string, params map[string]any) {
+		if method != "notifications/message" {
+			t.Errorf("broadcast method = %s", method)
+		}
+		h.logged = append(h.logged, params["data"].(map[string]any))
+	}
+	return h
+}
+
+func (h *webhookHarness) subscribe(t *testing.T, sessionID, uri string) {
+	t.Helper()
+	response, _ := h.receiver.subscriptions.handle(context.Background(), sessionID, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":%q}}`, uri)))
+	if !strings.Contains(string(response), `"result"`) {
+		t.Fatalf("subscribe %s: %s", uri, response)
+	}
+}
+
+// replay posts a recorded payload from testdata/webhooks, signed with the
+// secret the way Jira Cloud signs deliveries.
+func (h *webhookHarness) replay(t *testing.T, name string) int {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "webhooks", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(payload)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(string(payload)))
+	req.Header.Set("X-Hub-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	recorder := httptest.NewRecorder()
+	h.receiver.ServeHTTP(recorder, req)
+	return recorder.Code
+}
+
+func TestWebhookReceiver_Replay(t *testing.T) {
+	h := newWebhookHarness(t, nil)
+	h.subscribe(t, "s1", "jira://issue/ABC-1")
+	h.subscribe(t, "s2", "jira://sprint/7")
+
+	for _, name := range []string{"issue_created", "issue_updated", "comment_created", "sprint_started"} {
+		if code := h.replay(t, name); code != http.StatusNoContent {
+			t.Fatalf("%s: status %d", name, code)
+		}
+	}
+
+	want := []string{"s1 jira://issue/ABC-1", "s1 jira://issue/ABC-1", "s2 jira://sprint/7"}
+	if !reflect.DeepEqual(h.notified, want) {
+		t.Errorf("notified = %v, want %v", h.notified, want)
+	}
+	if got := h.receiver.subscriptions.updated["jira://issue/ABC-1"]; got != "2026-10-17T10:00:00.000+0000" {
+		t.Errorf("the webhook's timestamp should become the polling baseline, got %q", got)
+	}
+
+	if len(h.logged) != 4 {
+		t.Fatalf("logged %d events, want 4", len(h.logged))
+	}
+	checks := []map[string]any{
+		{"event": "jira:issue_created", "issue": "ABC-2", "user": "Mia Krystof"},
+		{"event": "jira:issue_updated", "status": "In Progress", "changes": []string{"status: To Do → In Progress"}},
+		{"event": "comment_created", "issue": "ABC-1", "user": "Emma Richards", "comment_id": "10100"},
+		{"event": "sprint_started", "sprint": "Sprint 7", "uris": []string{"jira://sprint/7", "jira://board/12/backlog"}},
+	}
+	for i, check := range checks {
+		for key, value := range check {
+			if !reflect.DeepEqual(h.logged[i][key], value) {
+				t.Errorf("event %d %s = %v, want %v", i, key, h.logged[i][key], value)
+			}
+		}
+	}
+}
+
+func TestWebhookReceiver_Verify(t *testing.T) {
+	h := newWebhookHarness(t, nil)
+	body := `{"webhookEvent":"jira:issue_updated","issue":{"key":"ABC-1"}}`
+
+	tests := []struct {
+		name      string
+		target    string
+		signature string
+		want      int
+	}{
+		{"no secret", "/webhook", "", http.StatusUnauthorized},
+		{"wrong secret", "/webhook?secret=guess", "", http.StatusUnauthorized},
+		{"bad signature", "/webhook", "sha256=00", http.StatusUnauthorized},
+		{"signature beats query", "/webhook?secret=s3cret", "sha256=00", http.StatusUnauthorized},
+		{"query secret", "/webhook?secret=s3cret", "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(body))
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature", tt.signature)
+			}
+			recorder := httptest.NewRecorder()
+			h.receiver.ServeHTTP(recorder, req)
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+	if len(h.logged) != 1 {
+		t.Errorf("only the authenticated delivery should reach clients, got %d", len(h.logged))
+	}
+}
+
+func TestWebhookReceiver_ProjectPolicy(t *testing.T) {
+	policy, err := ParseProjectPolicy([]byte("read: [DEF]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := &Filter{}
+	filter.UseProjectPolicy(policy)
+	h := newWebhookHarness(t, filter)
+
+	for _, name := range []string{"issue_updated", "sprint_started"} {
+		if code := h.replay(t, name); code != http.StatusNoContent {
+			t.Fatalf("%s: status %d", name, code)
+		}
+	}
+	if len(h.logged) != 0 || len(h.notified) != 0 {
+		t.Errorf("events outside the policy must be dropped, got %v %v", h.logged, h.notified)
+	}
+}
+
+func TestWebhookReceiver_SprintSubscriptionsNeedMounting(t *testing.T) {
+	subscriptions := newResourceSubscriptions(nil, time.Minute)
+	receiver := newWebhookReceiver("s3cret", nil, subscriptions)
+	subscribe := []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"jira://sprint/7"}}`)
+
+	if response, _ := subscriptions.handle(context.Background(), "s1", subscribe); !strings.Contains(string(response), `"error"`) {
+		t.Errorf("a receiver that is not mounted gets no sprint events, got %s", response)
+	}
+	receiver.Mount(http.NewServeMux(), "/webhook")
+	if response, _ := subscriptions.handle(context.Background(), "s1", subscribe); !strings.Contains(string(response), `"result"`) {
+		t.Errorf("subscribe after mounting: %s", response)
+	}
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestADFGolden converts each testdata/adf/*.md to ADF, compares it with
+// the .json golden file beside it, and checks that rendering the ADF gives
+// back the markdown. Run with -update to rewrite the golden files.
+func TestADFGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "adf", "*.md"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no golden inputs: %v", err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			markdown := strings.TrimSpace(string(source))
+
+			doc := MarkdownToADF(markdown)
+			got, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := strings.TrimSuffix(file, ".md") + ".json"
+			if *updateGolden {
+				if err := os.WriteFile(golden, append(got, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != strings.TrimSpace(string(want)) {
+				t.Errorf("MarkdownToADF differs from %s:\n%s", golden, got)
+			}
+
+			if back := RenderADF(doc); back != markdown {
+				t.Errorf("round trip changed the markdown:\n--- got\n%s\n--- want\n%s", back, markdown)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// AttachmentKind says how the content of an attachment can be shown inline.
+type AttachmentKind string
+
+const (
+	// AttachmentImage is an image an MCP client can display.
+	AttachmentImage AttachmentKind = "image"
+	// AttachmentText is plain text: logs, JSON, CSV, source code and so on.
+	AttachmentText AttachmentKind = "text"
+	// AttachmentDocument is a PDF or Office document whose text can be
+	// extracted with ExtractAttachmentText.
+	AttachmentDocument AttachmentKind = "document"
+	// AttachmentBinary is anything else.
+	AttachmentBinary AttachmentKind = "binary"
+)
+
+// inlineImageTypes are the image types MCP clients are expected to render.
+var inlineImageTypes = map[string]bool{"image/png": true, "image/jpeg": true, "image/gif": true, "image/webp": true}
+
+var textExtensions = map[string]bool{
+	".txt": true, ".log": true, ".md": true, ".csv": true, ".tsv": true, ".json": true, ".xml": true,
+	".yaml": true, ".yml": true, ".html": true, ".htm": true, ".svg": true, ".sql": true, ".sh": true,
+	".go": true, ".py": true, ".js": true, ".ts": true, ".java": true, ".diff": true, ".patch": true,
+}
+
+var textMimeTypes = map[string]bool{
+	"application/json": true, "application/xml": true, "application/x-yaml": true, "application/yaml": true,
+	"application/javascript": true, "application/x-sh": true, "application/sql": true, "image/svg+xml": true,
+}
+
+// officeDocuments maps the Office Open XML extensions to their extractors.
+var officeDocuments = map[string]func(*zip.Reader) (string, error){
+	".docx": docxText,
+	".pptx": pptxText,
+	".xlsx": xlsxText,
+}
+
+// ClassifyAttachment decides how an attachment can be shown, from its MIME
+// type, its extension and, for unlabelled files, a look at the content.
+func ClassifyAttachment(filename, mimeType string, content []byte) AttachmentKind {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	ext := strings.ToLower(path.Ext(filename))
+
+	switch {
+	case inlineImageTypes[mimeType]:
+		return AttachmentImage
+	case mimeType == "application/pdf" || ext == ".pdf":
+		return AttachmentDocument
+	case officeDocuments[ext] != nil:
+		return AttachmentDocument
+	case strings.HasPrefix(mimeType, "text/") || textMimeTypes[mimeType] || textExtensions[ext]:
+		return AttachmentText
+	case mimeType == "" || mimeType == "application/octet-stream":
+		if looksLikeText(content) {
+			return AttachmentText
+		}
+	}
+	return AttachmentBinary
+}
+
+// looksLikeText reports whether the start of content is UTF-8 without NUL
+// bytes.
+func looksLikeText(content []byte) bool {
+	sample := content
+	if len(sample) > 8192 {
+		sample = sample[:8192]
+		// Do not judge a rune cut in half at the end of the sample.
+		for i := 0; i < utf8.UTFMax && len(sample) > 0 && !utf8.Valid(sample); i++ {
+			sample = sample[:len(sample)-1]
+		}
+	}
+	return len(content) > 0 && utf8.Valid(sample) && !bytes.ContainsRune(sample, 0)
+}
+
+// ExtractAttachmentText returns the text of a text or document attachment
+// (see ClassifyAttachment): the content itself, the text of each PDF page,
+// the paragraphs of a .docx, the slides of a .pptx or the sheets of a .xlsx
+// as tab-separated rows.
+func ExtractAttachmentText(filename, mimeType string, content []byte) (string, error) {
+	switch ClassifyAttachment(filename, mimeType, content) {
+	case AttachmentText:
+		return strings.ToValidUTF8(string(content), "�"), nil
+	case AttachmentDocument:
+	default:
+		return "", fmt.Errorf("%s has no text to extract", filename)
+	}
+
+	ext := strings.ToLower(path.Ext(filename))
+	if extract := officeDocuments[ext]; extract != nil {
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return "", fmt.Errorf("failed to open %s: %v", filename, err)
+		}
+		text, err := extract(archive)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %v", filename, err)
+		}
+		return text, nil
+	}
+	text, err := pdfText(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", filename, err)
+	}
+	return text, nil
+}
+
+// pdfText extracts the text of every page. The PDF reader panics on some
+// malformed files, which is turned into an error.
+func pdfText(content []byte) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unreadable PDF: %v", r)
+		}
+	}()
+	reader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		pageText, err := page.GetPlainText(nil)
+		if err != nil {
+			return "", fmt.Errorf("page %d: %v", i, err)
+		}
+		if pageText = strings.TrimSpace(pageText); pageText != "" {
+			if sb.Len() > 0 {
+				sb.WriteString("\n\n")
+			}
+			sb.WriteString(pageText)
+		}
+	}
+	return sb.String(), nil
+}
+
+// zipPartLimit is the most of one Office document part that is
+// decompressed, so a small archive cannot expand to exhaust memory.
+var zipPartLimit int64 = 64 << 20
+
+// readZipFile returns the content of name in archive, or nil when it is
+// missing. Parts over zipPartLimit, by their header or once read, are an
+// error.
+func readZipFile(archive *zip.Reader, name string) ([]byte, error) {
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+		if file.UncompressedSize64 > uint64(zipPartLimit) {
+			return nil, fmt.Errorf("%s is over %dMB uncompressed", name, zipPartLimit>>20)
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(io.LimitReader(reader, zipPartLimit+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > zipPartLimit {
+			return nil, fmt.Errorf("%s is over %dMB uncompressed", name, zipPartLimit>>20)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// xmlParagraphs collects the text of the <t> elements of an Office XML part,
+// one line per <p> element.
+func xmlParagraphs(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var sb, line strings.Builder
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				line.WriteString("\t")
+			case "br":
+				line.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if text := strings.TrimRight(line.String(), " \t"); text != "" {
+					sb.WriteString(text + "\n")
+				}
+				line.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				line.Write(t)
+			}
+		}
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func docxText(archive *zip.Reader) (string, error) {
+	data, err := readZipFile(archive, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		return "", fmt.Errorf("no word/document.xml")
+	}
+	return xmlParagraphs(data)
+}
+
+// numberedParts returns the archive files matching pattern, whose first
+// group is a number, in numeric order.
+func numberedParts(archive *zip.Reader, pattern *regexp.Regexp) []string {
+	type part struct {
+		name   string
+		number int
+	}
+	var parts []part
+	for _, file := range archive.File {
+		if m := pattern.FindStringSubmatch(file.Name); m != nil {
+			number, _ := strconv.Atoi(m[1])
+			parts = append(parts, part{file.Name, number})
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].number < parts[j].number })
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		names[i] = p.name
+	}
+	return names
+}
+
+var (
+	slidePartRe = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+	sheetPartRe = regexp.MustCompile(`^xl/worksheets/sheet(\d+)\.xml$`)
+)
+
+func pptxText(archive *zip.Reader) (string, error) {
+	var sb strings.Builder
+	for i, name := range numberedParts(archive, slidePartRe) {
+		data, err := readZipFile(archive, name)
+		if err != nil {
+			return "", err
+		}
+		text, err := xmlParagraphs(data)
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", name, err)
+		}
+		fmt.Fprintf(&sb, "--- Slide %d ---\n%s\n\n", i+1, text)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func xlsxText(archive *zip.Reader) (string, error) {
+	var shared []string
+	if data, err := readZipFile(archive, "xl/sharedStrings.xml"); err != nil {
+		return "", err
+	} else if data != nil {
+		var table struct {
+			Items []struct {
+				Text string `xml:"t"`
+				Runs []struct {
+					Text string `xml:"t"`
+				} `xml:"r"`
+			} `xml:"si"`
+		}
+		if err := xml.Unmarshal(data, &table); err != nil {
+			return "", fmt.Errorf("sharedStrings.xml: %v", err)
+		}
+		for _, item := range table.Items {
+			text := item.Text
+			for _, run := range item.Runs {
+				text += run.Text
+			}
+			shared = append(shared, text)
+		}
+	}
+
+	var sb strings.Builder
+	for i, name := range numberedParts(archive, sheetPartRe) {
+		data, err := readZipFile(archive, name)
+		if err != nil {
+			return "", err
+		}
+		var sheet struct {
+			Rows []struct {
+				Cells []struct {
+					Type   string `xml:"t,attr"`
+					Value  string `xml:"v"`
+					Inline string `xml:"is>t"`
+				} `xml:"c"`
+			} `xml:"sheetData>row"`
+		}
+		if err := xml.Unmarshal(data, &sheet); err != nil {
+			return "", fmt.Errorf("%s: %v", name, err)
+		}
+		fmt.Fprintf(&sb, "--- Sheet %d ---\n", i+1)
+		for _, row := range sheet.Rows {
+			values := make([]string, len(row.Cells))
+			for j, cell := range row.Cells {
+				switch cell.Type {
+				case "s":
+					if index, err := strconv.Atoi(cell.Value); err == nil && index >= 0 && index < len(shared) {
+						values[j] = shared[index]
+					}
+				case "inlineStr":
+					values[j] = cell.Inline
+				default:
+					values[j] = cell.Value
+				}
+			}
+			sb.WriteString(strings.Join(values, "\t") + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,129 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// zipOf builds an in-memory archive from name → content pairs.
+func zipOf(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestClassifyAttachment(t *testing.T) {
+	tests := []struct {
+		filename, mimeType string
+		content            string
+		want               AttachmentKind
+	}{
+		{"shot.png", "image/png", "", AttachmentImage},
+		{"shot.tiff", "image/tiff", "", AttachmentBinary},
+		{"server.log", "application/octet-stream", "", AttachmentText},
+		{"data.json", "application/json", "", AttachmentText},
+		{"notes", "text/plain; charset=utf-8", "", AttachmentText},
+		{"spec.pdf", "application/pdf", "", AttachmentDocument},
+		{"report.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "", AttachmentDocument},
+		{"dump", "application/octet-stream", "plain words", AttachmentText},
+		{"dump", "application/octet-stream", "bin\x00ary", AttachmentBinary},
+	}
+	for _, tt := range tests {
+		if got := ClassifyAttachment(tt.filename, tt.mimeType, []byte(tt.content)); got != tt.want {
+			t.Errorf("ClassifyAttachment(%q, %q) = %s, want %s", tt.filename, tt.mimeType, got, tt.want)
+		}
+	}
+}
+
+func TestExtractAttachmentText_Office(t *testing.T) {
+	docx := zipOf(t, map[string]string{
+		"word/document.xml": `<w:document xmlns:w="w"><w:body>` +
+			`<w:p><w:r><w:t>Steps to</w:t></w:r><w:r><w:t xml:space="preserve"> reproduce</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t>Crash</w:t><w:tab/><w:t>always</w:t></w:r></w:p>` +
+			`</w:body></w:document>`,
+	})
+	pptx := zipOf(t, map[string]string{
+		"ppt/slides/slide10.xml": `<p:sld xmlns:a="a" xmlns:p="p"><a:p><a:r><a:t>Last</a:t></a:r></a:p></p:sld>`,
+		"ppt/slides/slide2.xml":  `<p:sld xmlns:a="a" xmlns:p="p"><a:p><a:r><a:t>First</a:t></a:r></a:p></p:sld>`,
+	})
+	xlsx := zipOf(t, map[string]string{
+		"xl/sharedStrings.xml":     `<sst><si><t>Name</t></si><si><r><t>Ti</t></r><r><t>me</t></r></si><si><t>Build</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row><row><c t="s"><v>2</v></c><c><v>42</v></c><c t="inlineStr"><is><t>ok</t></is></c></row></sheetData></worksheet>`,
+	})
+
+	tests := []struct {
+		filename string
+		content  []byte
+		want     string
+	}{
+		{"bug.docx", docx, "Steps to reproduce\nCrash\talways"},
+		{"deck.pptx", pptx, "--- Slide 1 ---\nFirst\n\n--- Slide 2 ---\nLast"},
+		{"times.xlsx", xlsx, "--- Sheet 1 ---\nName\tTime\nBuild\t42\tok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := ExtractAttachmentText(tt.filename, "application/octet-stream", tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractAttachmentText_OfficePartLimit(t *testing.T) {
+	defer func(limit int64) { zipPartLimit = limit }(zipPartLimit)
+	zipPartLimit = 1 << 10
+	document := `<w:document xmlns:w="w"><w:body><w:p><w:r><w:t>` + strings.Repeat("a", 2<<10) + `</w:t></w:r></w:p></w:body></w:document>`
+
+	_, err := ExtractAttachmentText("big.docx", "", zipOf(t, map[string]string{"word/document.xml": document}))
+	if err == nil || !strings.Contains(err.Error(), "uncompressed") {
+		t.Errorf("a part over the limit should fail, got %v", err)
+	}
+}
+
+func TestExtractAttachmentText_PDF(t *testing.T) {
+	content, err := os.ReadFile("testdata/attachments/hello.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExtractAttachmentText("hello.pdf", "application/pdf", content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "Hello from page one") {
+		t.Errorf("got %q", got)
+	}
+
+	if _, err := ExtractAttachmentText("broken.pdf", "application/pdf", []byte("%PDF-1.4 garbage")); err == nil {
+		t.Error("expected an error for a malformed PDF")
+	}
+}
+
+func TestExtractAttachmentText_Binary(t *testing.T) {
+	if _, err := ExtractAttachmentText("shot.png", "image/png", []byte{0x89, 'P', 'N', 'G'}); err == nil {
+		t.Error("expected an error for an image")
+	}
+	got, err := ExtractAttachmentText("app.log", "text/plain", []byte("line\xffone"))
+	if err != nil || got != "line�one" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// BodyFormat is the markup a description or comment is written in.
+type BodyFormat string
+
+const (
+	// BodyFormatMarkdown is read by MarkdownToADF.
+	BodyFormatMarkdown BodyFormat = "markdown"
+	// BodyFormatWiki is Jira wiki markup, read by WikiToADF.
+	BodyFormatWiki BodyFormat = "wiki"
+	// BodyFormatADFJSON is an ADF document as JSON, sent unchanged.
+	BodyFormatADFJSON BodyFormat = "adf_json"
+)
+
+// BodyFormatNames lists the formats, for parameter descriptions.
+const BodyFormatNames = "markdown, wiki, adf_json"
+
+// ParseBodyFormat resolves a format name. An empty value is markdown.
+func ParseBodyFormat(value string) (BodyFormat, error) {
+	switch format := BodyFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return BodyFormatMarkdown, nil
+	case BodyFormatMarkdown, BodyFormatWiki, BodyFormatADFJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: use one of %s", value, BodyFormatNames)
+	}
+}
+
+// BodyToADF converts text written in format (see ParseBodyFormat) into an
+// ADF document. adf_json text must be a document whose type is "doc".
+func BodyToADF(text, format string) (*models.CommentNodeScheme, error) {
+	bodyFormat, err := ParseBodyFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	switch bodyFormat {
+	case BodyFormatWiki:
+		return WikiToADF(text), nil
+	case BodyFormatADFJSON:
+		var doc models.CommentNodeScheme
+		if err := json.Unmarshal([]byte(text), &doc); err != nil {
+			return nil, fmt.Errorf("adf_json is not valid JSON: %v", err)
+		}
+		if doc.Type != "doc" {
+			return nil, fmt.Errorf(`adf_json must be a document with "type": "doc", got %q`, doc.Type)
+		}
+		if doc.Version == 0 {
+			doc.Version = 1
+		}
+		return &doc, nil
+	default:
+		return MarkdownToADF(text), nil
+	}
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestBodyToADF(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		format string
+		want   string
+	}{
+		{"default is markdown", "**bold**", "", "**bold**"},
+		{"markdown", "- [x] done", "markdown", "- [x] done"},
+		{"wiki", "h2. Plan\n\n*bold* for [~jdoe]", "Wiki", "## Plan\n\n**bold** for @[jdoe](jdoe)"},
+		{"adf_json", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"raw"}]}]}`, "adf_json", "raw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := BodyToADF(tt.text, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if doc.Version != 1 {
+				t.Errorf("version = %d", doc.Version)
+			}
+			if got := RenderADF(doc); got != tt.want {
+				t.Errorf("RenderADF = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyToADF_Errors(t *testing.T) {
+	for _, tt := range []struct{ text, format string }{
+		{"text", "html"},
+		{"{not json", "adf_json"},
+		{`{"type":"paragraph"}`, "adf_json"},
+	} {
+		if _, err := BodyToADF(tt.text, tt.format); err == nil {
+			t.Errorf("BodyToADF(%q, %q) should fail", tt.text, tt.format)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WorkingCalendar is how a Jira instance counts days and weeks of work, and
+// the unit it assumes for a number written without one. See
+// services.JiraWorkingCalendar for the instance's own settings.
+type WorkingCalendar struct {
+	HoursPerDay float64 `json:"hours_per_day"`
+	DaysPerWeek float64 `json:"days_per_week"`
+	// DefaultUnit is "minute", "hour", "day" or "week"; empty is minute.
+	DefaultUnit string `json:"default_unit,omitempty"`
+}
+
+// DefaultWorkingCalendar is Jira's out-of-the-box calendar: 8-hour days,
+// 5-day weeks, minutes by default.
+var DefaultWorkingCalendar = WorkingCalendar{HoursPerDay: 8, DaysPerWeek: 5, DefaultUnit: "minute"}
+
+// unitSeconds returns the length of a duration unit in seconds and its
+// one-letter name: w, d, h or m, or their long forms.
+func (c WorkingCalendar) unitSeconds(unit string) (float64, string, bool) {
+	switch unit {
+	case "w", "week", "weeks":
+		return c.DaysPerWeek * c.HoursPerDay * 3600, "w", c.DaysPerWeek > 0 && c.HoursPerDay > 0
+	case "d", "day", "days":
+		return c.HoursPerDay * 3600, "d", c.HoursPerDay > 0
+	case "h", "hour", "hours":
+		return 3600, "h", true
+	case "m", "minute", "minutes":
+		return 60, "m", true
+	}
+	return 0, "", false
+}
+
+// ParseDuration reads a duration in Jira's notation, such as "1w 2d 3h 30m",
+// "1.5h", "2h30m" or "90", into seconds. Weeks and days are counted with
+// calendar, and a number on its own is in calendar.DefaultUnit. Spaces
+// between parts are optional, decimals may use a point or a comma, and each
+// unit may appear once.
+func ParseDuration(value string, calendar WorkingCalendar) (int, error) {
+	rest := strings.ToLower(strings.TrimSpace(value))
+	if rest == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total float64
+	seen := map[string]bool{}
+	for first := true; rest != ""; first = false {
+		end := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if end == 0 {
+			return 0, fmt.Errorf("invalid duration %q: expected a number at %q", value, rest)
+		}
+		if end < 0 {
+			end = len(rest)
+		}
+		number, err := strconv.ParseFloat(strings.Replace(rest[:end], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: bad number %q", value, rest[:end])
+		}
+		rest = strings.TrimLeft(rest[end:], " ")
+
+		unitEnd := strings.IndexFunc(rest, func(r rune) bool { return r < 'a' || r > 'z' })
+		if unitEnd < 0 {
+			unitEnd = len(rest)
+		}
+		unit := rest[:unitEnd]
+		rest = strings.TrimLeft(rest[unitEnd:], " ")
+		if unit == "" {
+			if !first || rest != "" {
+				return 0, fmt.Errorf("invalid duration %q: each part needs a unit (w, d, h or m)", value)
+			}
+			unit = calendar.DefaultUnit
+			if unit == "" {
+				unit = "minute"
+			}
+		}
+		seconds, name, ok := calendar.unitSeconds(unit)
+		if !ok {
+			if name != "" {
+				return 0, fmt.Errorf("invalid duration %q: %q needs hours per day and days per week", value, unit)
+			}
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q (use w, d, h or m)", value, unit)
+		}
+		if seen[name] {
+			return 0, fmt.Errorf("invalid duration %q: %q appears twice", value, name)
+		}
+		seen[name] = true
+		total += number * seconds
+	}
+	return int(math.Round(total)), nil
+}
+
+// FormatDuration writes seconds in Jira's notation, largest unit first:
+// 153000 is "1w 2h 30m" with the default calendar. Weeks and days are only
+// used when calendar defines them, so the zero WorkingCalendar gives hours
+// and minutes. Leftover seconds are dropped; zero is "0m".
+func FormatDuration(seconds int, calendar WorkingCalendar) string {
+	minutes := seconds / 60
+	var parts []string
+	for _, unit := range []string{"w", "d", "h", "m"} {
+		unitSeconds, _, ok := calendar.unitSeconds(unit)
+		unitMinutes := int(unitSeconds) / 60
+		if !ok || unitMinutes <= 0 {
+			continue
+		}
+		if count := minutes / unitMinutes; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", count, unit))
+			minutes -= count * unitMinutes
+		}
+	}
+	if len(parts) == 0 {
+		return "0m"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,68 @@
+package util
+
+import "testing"
+
+func TestParseDuration(t *testing.T) {
+	calendar := WorkingCalendar{HoursPerDay: 8, DaysPerWeek: 5, DefaultUnit: "minute"}
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"1w 2d 3h 30m", (40+16+3)*3600 + 1800},
+		{"2h30m", 9000},
+		{"1.5h", 5400},
+		{"0,5d", 4 * 3600},
+		{" 3 H ", 3 * 3600},
+		{"1 day 2 hours", 10 * 3600},
+		{"45", 45 * 60},
+		{"1w", 40 * 3600},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value, calendar)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %d, %v, want %d", tt.value, got, err, tt.want)
+		}
+	}
+
+	short := WorkingCalendar{HoursPerDay: 6, DaysPerWeek: 4, DefaultUnit: "hour"}
+	if got, _ := ParseDuration("1w 1d", short); got != 30*3600 {
+		t.Errorf("1w 1d with 6-hour days and 4-day weeks = %d s, want 30h", got)
+	}
+	if got, _ := ParseDuration("2", short); got != 2*3600 {
+		t.Errorf("a bare number should be in the default unit, got %d s", got)
+	}
+
+	for _, bad := range []string{"", "soon", "1h 30", "2h 1h", "3y", "1..5h", "h"} {
+		if _, err := ParseDuration(bad, calendar); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", bad)
+		}
+	}
+	if _, err := ParseDuration("1d", WorkingCalendar{}); err == nil {
+		t.Error("days need hours per day")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		seconds  int
+		calendar WorkingCalendar
+		want     string
+	}{
+		{(40+16+3)*3600 + 1800, DefaultWorkingCalendar, "1w 2d 3h 30m"},
+		{153000, DefaultWorkingCalendar, "1w 2h 30m"},
+		{30 * 3600, WorkingCalendar{HoursPerDay: 6, DaysPerWeek: 4}, "1w 1d"},
+		{30 * 3600, WorkingCalendar{}, "30h"},
+		{59, DefaultWorkingCalendar, "0m"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.seconds, tt.calendar); got != tt.want {
+			t.Errorf("FormatDuration(%d, %+v) = %q, want %q", tt.seconds, tt.calendar, got, tt.want)
+		}
+	}
+	for _, value := range []string{"1w 2d 3h 30m", "4h 15m", "3d"} {
+		seconds, _ := ParseDuration(value, DefaultWorkingCalendar)
+		if got := FormatDuration(seconds, DefaultWorkingCalendar); got != value {
+			t.Errorf("round trip of %q = %q", value, got)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/tidwall/gjson"
+)
+
+// CustomFieldValue is a rendered custom field ready for display.
+type CustomFieldValue struct {
+	ID    string
+	Name  string
+	Value string
+}
+
+// Custom field type keys with a payload shape that their schema type does
+// not describe on its own.
+const (
+	customTypeTextarea = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+	customTypeSprint   = "com.pyxis.greenhopper.jira:gh-sprint"
+)
+
+// CoerceFieldValue converts a loosely typed value supplied by an agent into
+// the JSON shape Jira expects for field, based on the field's schema:
+//
+//   - number: 5 or "5" -> 5
+//   - option: "High" -> {"value": "High"}
+//   - user: "5b10ac8d82e05b22cc7d4ef5" -> {"accountId": ...}
+//   - version, component, priority: "1.0" -> {"name": "1.0"}
+//   - array: a single value or a list, each item coerced by the item type
+//   - date: "2024-05-01" (validated); datetime: RFC 3339, reformatted for Jira
+//   - textarea custom fields: markdown -> ADF
+//   - sprint: the numeric sprint ID
+//
+// Objects are passed through verbatim, so callers can always send the exact
+// payload Jira documents (e.g. {"name": "jdoe"} for a user on Server/DC).
+func CoerceFieldValue(field *models.IssueFieldScheme, value any) (any, error) {
+	if field == nil || field.Schema == nil || value == nil {
+		return value, nil
+	}
+	if _, isObject := value.(map[string]any); isObject {
+		return value, nil
+	}
+
+	schema := field.Schema
+	if schema.Custom == customTypeSprint {
+		return coerceNumber(value)
+	}
+	if schema.Custom == customTypeTextarea {
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected text, got %T", value)
+		}
+		return MarkdownToADF(text), nil
+	}
+
+	if schema.Type == "array" {
+		items, ok := value.([]any)
+		if !ok {
+			items = []any{value}
+		}
+		out := make([]any, 0, len(items))
+		for _, item := range items {
+			if _, isObject := item.(map[string]any); isObject {
+				out = append(out, item)
+				continue
+			}
+			coerced, err := coerceScalar(schema.Items, item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, coerced)
+		}
+		return out, nil
+	}
+
+	return coerceScalar(schema.Type, value)
+}
+
+func coerceScalar(schemaType string, value any) (any, error) {
+	switch schemaType {
+	case "number":
+		return coerceNumber(value)
+	case "string", "team":
+		return coerceString(value)
+	case "option":
+		return wrapString("value", value)
+	case "user":
+		return wrapString("accountId", value)
+	case "version", "component", "priority", "resolution", "issuetype", "group":
+		return wrapString("name", value)
+	case "project":
+		return wrapString("key", value)
+	case "date":
+		s, err := coerceString(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, fmt.Errorf("expected a date like 2024-05-01, got %q", s)
+		}
+		return s, nil
+	case "datetime":
+		s, err := coerceString(value)
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("expected an RFC 3339 date-time like 2024-05-01T09:00:00Z, got %q", s)
+		}
+		return t.Format("2006-01-02T15:04:05.000-0700"), nil
+	default:
+		return value, nil
+	}
+}
+
+func coerceNumber(value any) (any, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", v)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("expected a number, got %T", value)
+	}
+}
+
+func coerceString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64, int, bool, json.Number:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("expected text, got %T", value)
+	}
+}
+
+func wrapString(key string, value any) (any, error) {
+	s, err := coerceString(value)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{key: s}, nil
+}
+
+// ExtractCustomFields reads the customfield_* entries of a raw issue JSON
+// document and renders each non-empty one, labelled with its display name
+// from names (field ID -> name). Fields missing from names keep their ID.
+// The result is sorted by name.
+func ExtractCustomFields(rawIssue []byte, names map[string]string) []CustomFieldValue {
+	var values []CustomFieldValue
+	gjson.GetBytes(rawIssue, "fields").ForEach(func(key, value gjson.Result) bool {
+		id := key.String()
+		if !strings.HasPrefix(id, "customfield_") {
+			return true
+		}
+		rendered := renderFieldValue(value)
+		if rendered == "" {
+			return true
+		}
+		name := names[id]
+		if name == "" {
+			name = id
+		}
+		values = append(values, CustomFieldValue{ID: id, Name: name, Value: rendered})
+		return true
+	})
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Name != values[j].Name {
+			return values[i].Name < values[j].Name
+		}
+		return values[i].ID < values[j].ID
+	})
+	return values
+}
+
+// legacySprintName pulls the name out of the toString() form Jira Server
+// uses for sprint values: "com.atlassian.greenhopper...Sprint@1a2b[id=1,name=Sprint 1,...]".
+var legacySprintName = regexp.MustCompile(`\[.*\bname=([^,\]]*)`)
+
+func renderFieldValue(value gjson.Result) string {
+	switch {
+	case value.Type == gjson.Null:
+		return ""
+	case value.IsArray():
+		var parts []string
+		for _, item := range value.Array() {
+			if rendered := renderFieldValue(item); rendered != "" {
+				parts = append(parts, rendered)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case value.IsObject():
+		if value.Get("type").String() == "doc" {
+			var doc models.CommentNodeScheme
+			if err := json.Unmarshal([]byte(value.Raw), &doc); err == nil {
+				return RenderADF(&doc)
+			}
+		}
+		for _, key := range []string{"displayName", "name", "value", "title", "key"} {
+			if label := value.Get(key).String(); label != "" {
+				if child := value.Get("child.value").String(); child != "" {
+					return label + " -> " + child
+				}
+				return label
+			}
+		}
+		return value.Raw
+	case value.Type == gjson.String:
+		if match := legacySprintName.FindStringSubmatch(value.String()); match != nil {
+			return match[1]
+		}
+		return value.String()
+	default:
+		return value.String()
+	}
+}
+
+// FormatFieldErrors renders the per-field "errors" object of a Jira 400
+// response as one line per field, labelled with display names where known.
+// It returns "" when the body carries no field errors.
+func FormatFieldErrors(body []byte, names map[string]string) string {
+	var payload struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || len(payload.Errors) == 0 {
+		return ""
+	}
+
+	ids := make([]string, 0, len(payload.Errors))
+	for id := range payload.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var sb strings.Builder
+	sb.WriteString("field validation failed:")
+	for _, msg := range payload.ErrorMessages {
+		sb.WriteString("\n" + msg)
+	}
+	for _, id := range ids {
+		if name := names[id]; name != "" && name != id {
+			sb.WriteString(fmt.Sprintf("\n- %s (%s): %s", name, id, payload.Errors[id]))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n- %s: %s", id, payload.Errors[id]))
+		}
+	}
+	return sb.String()
+}
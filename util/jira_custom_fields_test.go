@@ -0,0 +1,145 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func field(id, name, schemaType, items, custom string) *models.IssueFieldScheme {
+	return &models.IssueFieldScheme{
+		ID:     id,
+		Name:   name,
+		Schema: &models.IssueFieldSchemaScheme{Type: schemaType, Items: items, Custom: custom},
+	}
+}
+
+func TestCoerceFieldValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *models.IssueFieldScheme
+		in    any
+		want  any
+	}{
+		{"number from float", field("customfield_1", "Story Points", "number", "", ""), 5.0, 5.0},
+		{"number from string", field("customfield_1", "Story Points", "number", "", ""), " 3.5 ", 3.5},
+		{"option", field("customfield_2", "Severity", "option", "", ""), "High", map[string]any{"value": "High"}},
+		{"user", field("customfield_3", "Reviewer", "user", "", ""), "abc123", map[string]any{"accountId": "abc123"}},
+		{"array of strings from scalar", field("labels", "Labels", "array", "string", ""), "ops", []any{"ops"}},
+		{"array of options", field("customfield_4", "Areas", "array", "option", ""), []any{"UI", "API"}, []any{map[string]any{"value": "UI"}, map[string]any{"value": "API"}}},
+		{"array of versions", field("fixVersions", "Fix versions", "array", "version", ""), []any{"1.0"}, []any{map[string]any{"name": "1.0"}}},
+		{"date", field("duedate", "Due date", "date", "", ""), "2024-05-01", "2024-05-01"},
+		{"datetime", field("customfield_5", "Go live", "datetime", "", ""), "2024-05-01T09:30:00Z", "2024-05-01T09:30:00.000+0000"},
+		{"sprint", field("customfield_6", "Sprint", "array", "json", customTypeSprint), "42", 42.0},
+		{"object passes through", field("customfield_3", "Reviewer", "user", "", ""), map[string]any{"name": "jdoe"}, map[string]any{"name": "jdoe"}},
+		{"unknown type passes through", field("customfield_7", "Odd", "any", "", ""), "x", "x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoerceFieldValue(tt.field, tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerceFieldValue_Textarea(t *testing.T) {
+	got, err := CoerceFieldValue(field("customfield_8", "Notes", "string", "", customTypeTextarea), "**bold**")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, ok := got.(*models.CommentNodeScheme)
+	if !ok || doc.Type != "doc" {
+		t.Fatalf("expected an ADF document, got %#v", got)
+	}
+	if RenderADF(doc) != "**bold**" {
+		t.Errorf("rendered = %q", RenderADF(doc))
+	}
+}
+
+func TestCoerceFieldValue_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *models.IssueFieldScheme
+		in    any
+	}{
+		{"number", field("customfield_1", "Story Points", "number", "", ""), "five"},
+		{"date", field("duedate", "Due date", "date", "", ""), "May 1st"},
+		{"datetime", field("customfield_5", "Go live", "datetime", "", ""), "2024-05-01"},
+		{"option from list", field("customfield_2", "Severity", "option", "", ""), []any{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CoerceFieldValue(tt.field, tt.in); err == nil {
+				t.Errorf("expected an error for %#v", tt.in)
+			}
+		})
+	}
+}
+
+func TestExtractCustomFields(t *testing.T) {
+	raw := []byte(`{
+		"key": "X-1",
+		"fields": {
+			"summary": "ignored",
+			"customfield_10016": 5,
+			"customfield_10001": {"id": "t1", "name": "Platform"},
+			"customfield_10020": [{"id": 3, "name": "Sprint 3", "state": "active"}],
+			"customfield_10030": {"value": "Hardware", "child": {"value": "Keyboard"}},
+			"customfield_10040": null,
+			"customfield_10050": [],
+			"customfield_10060": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "note"}]}]},
+			"customfield_10070": ["com.atlassian.greenhopper.service.sprint.Sprint@1f[id=7,rapidViewId=1,state=CLOSED,name=Legacy Sprint,startDate=2024]"]
+		}
+	}`)
+	names := map[string]string{
+		"customfield_10016": "Story Points",
+		"customfield_10001": "Team",
+		"customfield_10020": "Sprint",
+		"customfield_10030": "Component Tree",
+		"customfield_10060": "Notes",
+	}
+
+	got := ExtractCustomFields(raw, names)
+	want := []CustomFieldValue{
+		{ID: "customfield_10030", Name: "Component Tree", Value: "Hardware -> Keyboard"},
+		{ID: "customfield_10060", Name: "Notes", Value: "note"},
+		{ID: "customfield_10020", Name: "Sprint", Value: "Sprint 3"},
+		{ID: "customfield_10016", Name: "Story Points", Value: "5"},
+		{ID: "customfield_10001", Name: "Team", Value: "Platform"},
+		{ID: "customfield_10070", Name: "customfield_10070", Value: "Legacy Sprint"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestFormatJiraIssue_CustomFields(t *testing.T) {
+	issue := &models.IssueScheme{Key: "X-1", Fields: &models.IssueFieldsScheme{Summary: "s"}}
+	out := FormatJiraIssue(issue, CustomFieldValue{ID: "customfield_10016", Name: "Story Points", Value: "5"})
+	if !strings.Contains(out, "Custom Fields:\n- Story Points: 5\n") {
+		t.Errorf("custom fields missing from output:\n%s", out)
+	}
+	if strings.Contains(FormatJiraIssue(issue), "Custom Fields") {
+		t.Error("no custom fields section expected when none are passed")
+	}
+}
+
+func TestFormatFieldErrors(t *testing.T) {
+	body := []byte(`{"errorMessages":[],"errors":{"customfield_10016":"Number value expected","duedate":"Invalid date"}}`)
+	got := FormatFieldErrors(body, map[string]string{"customfield_10016": "Story Points", "duedate": "Due date"})
+	want := "field validation failed:\n- Story Points (customfield_10016): Number value expected\n- Due date (duedate): Invalid date"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	if got := FormatFieldErrors([]byte(`{"errorMessages":["Issue does not exist"],"errors":{}}`), nil); got != "" {
+		t.Errorf("expected empty string without field errors, got %q", got)
+	}
+}
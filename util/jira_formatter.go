@@ -7,19 +7,21 @@ import (
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 )
 
-// RenderADF converts an Atlassian Document Format (ADF) structure to markdown text
+// RenderADF converts an Atlassian Document Format (ADF) structure to markdown text.
+// It writes the forms MarkdownToADF reads, so tables, task lists, panels,
+// expands and mentions survive a trip through markdown and back.
 func RenderADF(node *models.CommentNodeScheme) string {
 	if node == nil {
 		return ""
 	}
 
 	var sb strings.Builder
-	renderADFNode(node, &sb, 0, "")
+	renderADFNode(node, &sb)
 	return strings.TrimSpace(sb.String())
 }
 
-// renderADFNode recursively renders an ADF node to a string builder
-func renderADFNode(node *models.CommentNodeScheme, sb *strings.Builder, depth int, listPrefix string) {
+// renderADFNode recursively renders an ADF block node to a string builder
+func renderADFNode(node *models.CommentNodeScheme, sb *strings.Builder) {
 	if node == nil {
 		return
 	}
@@ -28,132 +30,219 @@ func renderADFNode(node *models.CommentNodeScheme, sb *strings.Builder, depth in
 	case "doc":
 		// Document root - just render children
 		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
+			renderADFNode(child, sb)
 		}
 
 	case "paragraph":
-		// Paragraph - render children and add newline
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
-		}
+		renderADFInline(node, sb)
 		sb.WriteString("\n\n")
 
-	case "text":
-		// Text node - apply marks (bold, italic, etc.) and write text
-		text := node.Text
-		if len(node.Marks) > 0 {
-			for _, mark := range node.Marks {
-				switch mark.Type {
-				case "strong":
-					text = "**" + text + "**"
-				case "em":
-					text = "*" + text + "*"
-				case "code":
-					text = "`" + text + "`"
-				case "strike":
-					text = "~~" + text + "~~"
-				case "underline":
-					text = "__" + text + "__"
-				}
-			}
-		}
-		sb.WriteString(text)
-
-	case "hardBreak":
-		sb.WriteString("\n")
-
 	case "heading":
-		// Heading with level
 		level := 1
-		if attrs := node.Attrs; attrs != nil {
-			if lvl, ok := attrs["level"].(float64); ok {
-				level = int(lvl)
-			}
+		if lvl, ok := node.Attrs["level"].(float64); ok {
+			level = int(lvl)
 		}
 		sb.WriteString(strings.Repeat("#", level) + " ")
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
-		}
+		renderADFInline(node, sb)
 		sb.WriteString("\n\n")
 
-	case "bulletList":
-		// Bullet list - render children with bullet points
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, "- ")
-		}
-
-	case "orderedList":
-		// Ordered list - render children with numbers
-		for i, child := range node.Content {
-			prefix := fmt.Sprintf("%d. ", i+1)
-			renderADFNode(child, sb, depth, prefix)
-		}
-
-	case "listItem":
-		// List item - add prefix and render children
-		if listPrefix != "" {
-			sb.WriteString(strings.Repeat("  ", depth))
-			sb.WriteString(listPrefix)
-		}
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth+1, "")
-		}
+	case "bulletList", "orderedList", "taskList":
+		renderADFList(node, sb, "")
+		sb.WriteString("\n")
 
 	case "codeBlock":
-		// Code block
-		language := ""
-		if attrs := node.Attrs; attrs != nil {
-			if lang, ok := attrs["language"].(string); ok {
-				language = lang
-			}
-		}
+		language, _ := node.Attrs["language"].(string)
 		sb.WriteString("```" + language + "\n")
+		var code strings.Builder
 		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
+			code.WriteString(child.Text)
+		}
+		sb.WriteString(code.String())
+		if !strings.HasSuffix(code.String(), "\n") {
+			sb.WriteString("\n")
 		}
 		sb.WriteString("```\n\n")
 
 	case "blockquote":
 		// Blockquote - prefix each line with >
-		var innerSb strings.Builder
-		for _, child := range node.Content {
-			renderADFNode(child, &innerSb, depth, listPrefix)
-		}
-		lines := strings.Split(strings.TrimSpace(innerSb.String()), "\n")
-		for _, line := range lines {
-			sb.WriteString("> " + line + "\n")
+		for _, line := range strings.Split(renderADFBlocks(node), "\n") {
+			sb.WriteString(strings.TrimRight("> "+line, " ") + "\n")
 		}
 		sb.WriteString("\n")
 
 	case "rule":
-		// Horizontal rule
 		sb.WriteString("---\n\n")
 
 	case "table":
-		// Table - simplified rendering (ADF tables are complex)
-		sb.WriteString("\n[Table Content]\n")
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
+		renderADFTable(node, sb)
+
+	case "panel":
+		panelType, _ := node.Attrs["panelType"].(string)
+		if !panelTypes[panelType] {
+			panelType = "info"
 		}
-		sb.WriteString("\n")
+		sb.WriteString(":::" + panelType + "\n" + renderADFBlocks(node) + "\n:::\n\n")
 
-	case "tableRow":
-		sb.WriteString("| ")
+	case "expand", "nestedExpand":
+		title, _ := node.Attrs["title"].(string)
+		sb.WriteString("<details>\n<summary>" + title + "</summary>\n\n" + renderADFBlocks(node) + "\n\n</details>\n\n")
+
+	case "mediaSingle", "mediaGroup":
+		renderADFInline(node, sb)
+		sb.WriteString("\n\n")
+
+	default:
+		// Inline nodes at block level, or unknown nodes - render the children
+		if len(node.Content) == 0 {
+			renderADFInlineNode(node, sb)
+			return
+		}
 		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
-			sb.WriteString(" | ")
+			renderADFNode(child, sb)
 		}
+	}
+}
+
+// renderADFBlocks renders the block children of node, trimmed.
+func renderADFBlocks(node *models.CommentNodeScheme) string {
+	var sb strings.Builder
+	for _, child := range node.Content {
+		renderADFNode(child, &sb)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// renderADFList renders a list one item per line, nested lists indented
+// under their item.
+func renderADFList(list *models.CommentNodeScheme, sb *strings.Builder, indent string) {
+	number := 1
+	if order, ok := list.Attrs["order"].(float64); ok {
+		number = int(order)
+	}
+	for _, item := range list.Content {
+		switch {
+		case item.Type == "taskList":
+			// Nested task lists are siblings of the item they belong to.
+			renderADFList(item, sb, indent+"  ")
+		case item.Type == "taskItem":
+			marker := "- [ ] "
+			if state, _ := item.Attrs["state"].(string); state == "DONE" {
+				marker = "- [x] "
+			}
+			sb.WriteString(indent + marker)
+			renderADFInline(item, sb)
+			sb.WriteString("\n")
+		case list.Type == "orderedList":
+			marker := fmt.Sprintf("%d. ", number)
+			number++
+			renderADFListItem(item, sb, indent, marker)
+		default:
+			renderADFListItem(item, sb, indent, "- ")
+		}
+	}
+}
+
+func renderADFListItem(item *models.CommentNodeScheme, sb *strings.Builder, indent, marker string) {
+	sb.WriteString(indent + marker)
+	childIndent := indent + strings.Repeat(" ", len(marker))
+	if len(item.Content) == 0 {
 		sb.WriteString("\n")
+	}
+	for i, child := range item.Content {
+		switch child.Type {
+		case "paragraph":
+			if i > 0 {
+				sb.WriteString(childIndent)
+			}
+			renderADFInline(child, sb)
+			sb.WriteString("\n")
+		case "bulletList", "orderedList", "taskList":
+			if i == 0 {
+				sb.WriteString("\n")
+			}
+			renderADFList(child, sb, childIndent)
+		default:
+			if i == 0 {
+				sb.WriteString("\n")
+			}
+			var block strings.Builder
+			renderADFNode(child, &block)
+			for _, line := range strings.Split(strings.TrimSpace(block.String()), "\n") {
+				sb.WriteString(strings.TrimRight(childIndent+line, " ") + "\n")
+			}
+		}
+	}
+}
 
-	case "tableHeader", "tableCell":
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
+// renderADFTable renders a GFM table. Markdown tables need a header row, so
+// when the first row is not made of header cells it is used as one anyway.
+func renderADFTable(table *models.CommentNodeScheme, sb *strings.Builder) {
+	var rows [][]string
+	width := 0
+	for _, row := range table.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			text := strings.Join(strings.Fields(renderADFBlocks(cell)), " ")
+			cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+		}
+		rows = append(rows, cells)
+		width = max(width, len(cells))
+	}
+	if width == 0 {
+		return
+	}
+	for i, cells := range rows {
+		for len(cells) < width {
+			cells = append(cells, "")
 		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	sb.WriteString("\n")
+}
 
-	case "mediaSingle", "mediaGroup":
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
+// renderADFInline renders the inline children of node.
+func renderADFInline(node *models.CommentNodeScheme, sb *strings.Builder) {
+	for _, child := range node.Content {
+		renderADFInlineNode(child, sb)
+	}
+}
+
+func renderADFInlineNode(node *models.CommentNodeScheme, sb *strings.Builder) {
+	switch node.Type {
+	case "text":
+		// Text node - apply marks (bold, italic, etc.) and write text
+		text := node.Text
+		href := ""
+		for _, mark := range node.Marks {
+			switch mark.Type {
+			case "strong":
+				text = "**" + text + "**"
+			case "em":
+				text = "*" + text + "*"
+			case "code":
+				text = "`" + text + "`"
+			case "strike":
+				text = "~~" + text + "~~"
+			case "underline":
+				text = "__" + text + "__"
+			case "link":
+				href, _ = mark.Attrs["href"].(string)
+			}
 		}
+		switch {
+		case href == "":
+		case href == node.Text && len(node.Marks) == 1:
+			text = "<" + href + ">"
+		default:
+			text = "[" + text + "](" + href + ")"
+		}
+		sb.WriteString(text)
+
+	case "hardBreak":
+		sb.WriteString("\n")
 
 	case "media":
 		attrs := node.Attrs
@@ -161,6 +250,12 @@ func renderADFNode(node *models.CommentNodeScheme, sb *strings.Builder, depth in
 			sb.WriteString("[Media/Image]")
 			break
 		}
+		if url, _ := attrs["url"].(string); url != "" {
+			// External media, as the image MarkdownToADF reads
+			alt, _ := attrs["alt"].(string)
+			sb.WriteString("![" + alt + "](" + url + ")")
+			break
+		}
 		mediaID, _ := attrs["id"].(string)
 		mediaType, _ := attrs["type"].(string)
 		alt, _ := attrs["alt"].(string)
@@ -185,310 +280,41 @@ func renderADFNode(node *models.CommentNodeScheme, sb *strings.Builder, depth in
 		sb.WriteString("]")
 
 	case "mention":
-		// User mention
-		if attrs := node.Attrs; attrs != nil {
-			if text, ok := attrs["text"].(string); ok {
-				sb.WriteString("@" + text)
-			}
+		// User mention, as @[Name](accountId) so it can be written back
+		id, _ := node.Attrs["id"].(string)
+		name, _ := node.Attrs["text"].(string)
+		name = strings.TrimPrefix(name, "@")
+		switch {
+		case id != "" && name != "":
+			sb.WriteString("@[" + name + "](" + id + ")")
+		case name != "":
+			sb.WriteString("@" + name)
+		case id != "":
+			sb.WriteString("@" + id)
 		}
 
 	case "emoji":
-		// Emoji
-		if attrs := node.Attrs; attrs != nil {
-			if shortName, ok := attrs["shortName"].(string); ok {
-				sb.WriteString(shortName)
-			}
+		if shortName, ok := node.Attrs["shortName"].(string); ok {
+			sb.WriteString(shortName)
 		}
 
 	case "inlineCard":
-		// Inline card/link
-		if attrs := node.Attrs; attrs != nil {
-			if url, ok := attrs["url"].(string); ok {
-				sb.WriteString(url)
-			}
+		if url, ok := node.Attrs["url"].(string); ok {
+			sb.WriteString(url)
 		}
 
 	default:
-		// Unknown node type - try to render children
-		for _, child := range node.Content {
-			renderADFNode(child, sb, depth, listPrefix)
-		}
+		renderADFInline(node, sb)
 	}
 }
 
 // FormatJiraIssue converts a Jira issue struct to a formatted string representation
-// It handles all available fields from IssueFieldsSchemeV2 and related schemas
-func FormatJiraIssue(issue *models.IssueScheme) string {
-	var sb strings.Builder
-
-	// Basic issue information
-	sb.WriteString(fmt.Sprintf("Key: %s\n", issue.Key))
-	
-	if issue.ID != "" {
-		sb.WriteString(fmt.Sprintf("ID: %s\n", issue.ID))
-	}
-	
-	if issue.Self != "" {
-		sb.WriteString(fmt.Sprintf("URL: %s\n", issue.Self))
-	}
-
-	// Fields information
-	if issue.Fields != nil {
-		fields := issue.Fields
-
-		// Summary and Description
-		if fields.Summary != "" {
-			sb.WriteString(fmt.Sprintf("Summary: %s\n", fields.Summary))
-		}
-
-		if fields.Description != nil {
-			renderedDescription := RenderADF(fields.Description)
-			if renderedDescription != "" {
-				sb.WriteString(fmt.Sprintf("Description:\n%s\n", renderedDescription))
-			}
-		}
-
-		// Issue Type
-		if fields.IssueType != nil {
-			sb.WriteString(fmt.Sprintf("Type: %s\n", fields.IssueType.Name))
-			if fields.IssueType.Description != "" {
-				sb.WriteString(fmt.Sprintf("Type Description: %s\n", fields.IssueType.Description))
-			}
-		}
-
-		// Status
-		if fields.Status != nil {
-			sb.WriteString(fmt.Sprintf("Status: %s\n", fields.Status.Name))
-			if fields.Status.Description != "" {
-				sb.WriteString(fmt.Sprintf("Status Description: %s\n", fields.Status.Description))
-			}
-		}
-
-		// Priority
-		if fields.Priority != nil {
-			sb.WriteString(fmt.Sprintf("Priority: %s\n", fields.Priority.Name))
-		} else {
-			sb.WriteString("Priority: None\n")
-		}
-
-		// Resolution
-		if fields.Resolution != nil {
-			sb.WriteString(fmt.Sprintf("Resolution: %s\n", fields.Resolution.Name))
-			if fields.Resolution.Description != "" {
-				sb.WriteString(fmt.Sprintf("Resolution Description: %s\n", fields.Resolution.Description))
-			}
-		}
-
-		// Resolution Date
-		if fields.Resolutiondate != "" {
-			sb.WriteString(fmt.Sprintf("Resolution Date: %s\n", fields.Resolutiondate))
-		}
-
-		// People
-		if fields.Reporter != nil {
-			sb.WriteString(fmt.Sprintf("Reporter: %s", fields.Reporter.DisplayName))
-			if fields.Reporter.EmailAddress != "" {
-				sb.WriteString(fmt.Sprintf(" (%s)", fields.Reporter.EmailAddress))
-			}
-			sb.WriteString("\n")
-		} else {
-			sb.WriteString("Reporter: Unassigned\n")
-		}
-
-		if fields.Assignee != nil {
-			sb.WriteString(fmt.Sprintf("Assignee: %s", fields.Assignee.DisplayName))
-			if fields.Assignee.EmailAddress != "" {
-				sb.WriteString(fmt.Sprintf(" (%s)", fields.Assignee.EmailAddress))
-			}
-			sb.WriteString("\n")
-		} else {
-			sb.WriteString("Assignee: Unassigned\n")
-		}
-
-		if fields.Creator != nil {
-			sb.WriteString(fmt.Sprintf("Creator: %s", fields.Creator.DisplayName))
-			if fields.Creator.EmailAddress != "" {
-				sb.WriteString(fmt.Sprintf(" (%s)", fields.Creator.EmailAddress))
-			}
-			sb.WriteString("\n")
-		}
-
-		// Dates
-		if fields.Created != "" {
-			sb.WriteString(fmt.Sprintf("Created: %s\n", fields.Created))
-		}
-
-		if fields.Updated != "" {
-			sb.WriteString(fmt.Sprintf("Updated: %s\n", fields.Updated))
-		}
-
-		if fields.LastViewed != "" {
-			sb.WriteString(fmt.Sprintf("Last Viewed: %s\n", fields.LastViewed))
-		}
-
-		if fields.StatusCategoryChangeDate != "" {
-			sb.WriteString(fmt.Sprintf("Status Category Change Date: %s\n", fields.StatusCategoryChangeDate))
-		}
-
-		// Project information
-		if fields.Project != nil {
-			sb.WriteString(fmt.Sprintf("Project: %s", fields.Project.Name))
-			if fields.Project.Key != "" {
-				sb.WriteString(fmt.Sprintf(" (%s)", fields.Project.Key))
-			}
-			sb.WriteString("\n")
-		}
-
-		// Parent issue
-		if fields.Parent != nil {
-			sb.WriteString(fmt.Sprintf("Parent: %s", fields.Parent.Key))
-			if fields.Parent.Fields != nil && fields.Parent.Fields.Summary != "" {
-				sb.WriteString(fmt.Sprintf(" - %s", fields.Parent.Fields.Summary))
-			}
-			sb.WriteString("\n")
-		}
-
-		// Work information
-		if fields.Workratio > 0 {
-			sb.WriteString(fmt.Sprintf("Work Ratio: %d\n", fields.Workratio))
-		}
-
-		// Labels
-		if len(fields.Labels) > 0 {
-			sb.WriteString(fmt.Sprintf("Labels: %s\n", strings.Join(fields.Labels, ", ")))
-		}
-
-		// Components
-		if len(fields.Components) > 0 {
-			sb.WriteString("Components:\n")
-			for _, component := range fields.Components {
-				sb.WriteString(fmt.Sprintf("- %s", component.Name))
-				if component.Description != "" {
-					sb.WriteString(fmt.Sprintf(" (%s)", component.Description))
-				}
-				sb.WriteString("\n")
-			}
-		}
-
-		// Fix Versions
-		if len(fields.FixVersions) > 0 {
-			sb.WriteString("Fix Versions:\n")
-			for _, version := range fields.FixVersions {
-				sb.WriteString(fmt.Sprintf("- %s", version.Name))
-				if version.Description != "" {
-					sb.WriteString(fmt.Sprintf(" (%s)", version.Description))
-				}
-				sb.WriteString("\n")
-			}
-		}
-
-		// Affected Versions
-		if len(fields.Versions) > 0 {
-			sb.WriteString("Affected Versions:\n")
-			for _, version := range fields.Versions {
-				sb.WriteString(fmt.Sprintf("- %s", version.Name))
-				if version.Description != "" {
-					sb.WriteString(fmt.Sprintf(" (%s)", version.Description))
-				}
-				sb.WriteString("\n")
-			}
-		}
-
-		// Security Level
-		if fields.Security != nil {
-			sb.WriteString(fmt.Sprintf("Security Level: %s\n", fields.Security.Name))
-		}
-
-		// Subtasks
-		if len(fields.Subtasks) > 0 {
-			sb.WriteString("Subtasks:\n")
-			for _, subtask := range fields.Subtasks {
-				sb.WriteString(fmt.Sprintf("- %s", subtask.Key))
-				if subtask.Fields != nil && subtask.Fields.Summary != "" {
-					sb.WriteString(fmt.Sprintf(": %s", subtask.Fields.Summary))
-				}
-				if subtask.Fields != nil && subtask.Fields.Status != nil {
-					sb.WriteString(fmt.Sprintf(" [%s]", subtask.Fields.Status.Name))
-				}
-				sb.WriteString("\n")
-			}
-		}
-
-		// Issue Links
-		if len(fields.IssueLinks) > 0 {
-			sb.WriteString("Issue Links:\n")
-			for _, link := range fields.IssueLinks {
-				if link.OutwardIssue != nil {
-					sb.WriteString(fmt.Sprintf("- %s %s", link.Type.Outward, link.OutwardIssue.Key))
-					if link.OutwardIssue.Fields != nil && link.OutwardIssue.Fields.Summary != "" {
-						sb.WriteString(fmt.Sprintf(": %s", link.OutwardIssue.Fields.Summary))
-					}
-					sb.WriteString("\n")
-				}
-				if link.InwardIssue != nil {
-					sb.WriteString(fmt.Sprintf("- %s %s", link.Type.Inward, link.InwardIssue.Key))
-					if link.InwardIssue.Fields != nil && link.InwardIssue.Fields.Summary != "" {
-						sb.WriteString(fmt.Sprintf(": %s", link.InwardIssue.Fields.Summary))
-					}
-					sb.WriteString("\n")
-				}
-			}
-		}
-
-		// Watchers
-		if fields.Watcher != nil {
-			sb.WriteString(fmt.Sprintf("Watchers: %d\n", fields.Watcher.WatchCount))
-		}
-
-		// Votes
-		if fields.Votes != nil {
-			sb.WriteString(fmt.Sprintf("Votes: %d\n", fields.Votes.Votes))
-		}
-
-		// Attachments
-		if len(fields.Attachment) > 0 {
-			sb.WriteString("Attachments:\n")
-			for _, att := range fields.Attachment {
-				sb.WriteString(fmt.Sprintf("- %s (ID: %s, Type: %s, Size: %d bytes)\n",
-					att.Title, att.ID, att.MediaType, att.FileSize))
-			}
-		}
-
-		// Comments (summary only to avoid too much text)
-		if fields.Comment != nil && fields.Comment.Total > 0 {
-			sb.WriteString(fmt.Sprintf("Comments: %d total\n", fields.Comment.Total))
-		}
-
-		// Worklogs (summary only)
-		if fields.Worklog != nil && fields.Worklog.Total > 0 {
-			sb.WriteString(fmt.Sprintf("Worklogs: %d entries\n", fields.Worklog.Total))
-		}
-	}
-
-	// Available Transitions
-	if len(issue.Transitions) > 0 {
-		sb.WriteString("\nAvailable Transitions:\n")
-		for _, transition := range issue.Transitions {
-			sb.WriteString(fmt.Sprintf("- %s (ID: %s)\n", transition.Name, transition.ID))
-		}
-	}
-
-	// Story point estimate from changelog (if available)
-	if issue.Changelog != nil && issue.Changelog.Histories != nil {
-		storyPoint := ""
-		for _, history := range issue.Changelog.Histories {
-			for _, item := range history.Items {
-				if item.Field == "Story point estimate" && item.ToString != "" {
-					storyPoint = item.ToString
-				}
-			}
-		}
-		if storyPoint != "" {
-			sb.WriteString(fmt.Sprintf("Story Point Estimate: %s\n", storyPoint))
-		}
-	}
-
-	return sb.String()
+// It handles all available fields from IssueFieldsSchemeV2 and related schemas.
+// The typed scheme drops custom fields, so callers pass them separately
+// (see ExtractCustomFields) to have them listed by display name.
+// It renders everything; see RenderIssue for shorter renderings.
+func FormatJiraIssue(issue *models.IssueScheme, customFields ...CustomFieldValue) string {
+	return RenderIssue(issue, ProfileFull, customFields...)
 }
 
 // FormatJiraIssueCompact returns a compact single-line representation of a Jira issue
@@ -525,4 +351,25 @@ func FormatJiraIssueCompact(issue *models.IssueScheme) string {
 	}
 	
 	return strings.Join(parts, " | ")
-} 
\ No newline at end of file
+}
+
+// FormatSprint renders a sprint's details, one field per line.
+func FormatSprint(sprint *models.SprintScheme) string {
+	return fmt.Sprintf(`ID: %d
+Name: %s
+State: %s
+Start Date: %s
+End Date: %s
+Complete Date: %s
+Origin Board ID: %d
+Goal: %s`,
+		sprint.ID,
+		sprint.Name,
+		sprint.State,
+		sprint.StartDate,
+		sprint.EndDate,
+		sprint.CompleteDate,
+		sprint.OriginBoardID,
+		sprint.Goal,
+	)
+}
@@ -0,0 +1,702 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// IssueSection is a part of an issue that a RenderProfile can include.
+type IssueSection string
+
+const (
+	SectionSummary      IssueSection = "summary"
+	SectionDescription  IssueSection = "description"
+	SectionType         IssueSection = "type"
+	SectionStatus       IssueSection = "status"
+	SectionPriority     IssueSection = "priority"
+	SectionResolution   IssueSection = "resolution"
+	SectionAssignee     IssueSection = "assignee"
+	SectionReporter     IssueSection = "reporter" // reporter and creator
+	SectionDates        IssueSection = "dates"
+	SectionProject      IssueSection = "project"
+	SectionParent       IssueSection = "parent"
+	SectionLabels       IssueSection = "labels"
+	SectionComponents   IssueSection = "components"
+	SectionVersions     IssueSection = "versions" // fix and affected versions
+	SectionSecurity     IssueSection = "security"
+	SectionSubtasks     IssueSection = "subtasks"
+	SectionLinks        IssueSection = "links"
+	SectionWatchers     IssueSection = "watchers" // watchers and votes
+	SectionAttachments  IssueSection = "attachments"
+	SectionComments     IssueSection = "comments"
+	SectionWorklogs     IssueSection = "worklogs"
+	SectionCustomFields IssueSection = "custom_fields"
+	SectionTransitions  IssueSection = "transitions"
+	SectionChangelog    IssueSection = "changelog"
+)
+
+// sectionFields are the Jira fields each section reads. Transitions and
+// changelog are expands rather than fields, and custom fields need them all.
+var sectionFields = map[IssueSection][]string{
+	SectionSummary:     {"summary"},
+	SectionDescription: {"description"},
+	SectionType:        {"issuetype"},
+	SectionStatus:      {"status"},
+	SectionPriority:    {"priority"},
+	SectionResolution:  {"resolution", "resolutiondate"},
+	SectionAssignee:    {"assignee"},
+	SectionReporter:    {"reporter", "creator"},
+	SectionDates:       {"created", "updated", "lastViewed", "statuscategorychangedate"},
+	SectionProject:     {"project"},
+	SectionParent:      {"parent"},
+	SectionLabels:      {"labels"},
+	SectionComponents:  {"components"},
+	SectionVersions:    {"fixVersions", "versions"},
+	SectionSecurity:    {"security"},
+	SectionSubtasks:    {"subtasks"},
+	SectionLinks:       {"issuelinks"},
+	SectionWatchers:    {"watches", "votes"},
+	SectionAttachments: {"attachment"},
+	SectionComments:    {"comment"},
+	SectionWorklogs:    {"worklog"},
+}
+
+// allSections lists every section in rendering order.
+var allSections = []IssueSection{
+	SectionSummary, SectionDescription, SectionType, SectionStatus, SectionPriority,
+	SectionResolution, SectionReporter, SectionAssignee, SectionDates, SectionProject,
+	SectionParent, SectionLabels, SectionComponents, SectionVersions, SectionSecurity,
+	SectionSubtasks, SectionLinks, SectionWatchers, SectionAttachments, SectionComments,
+	SectionWorklogs, SectionCustomFields, SectionTransitions, SectionChangelog,
+}
+
+// RenderProfile selects which sections of an issue are rendered and how much
+// of its long text is kept. Zero limits mean unlimited.
+type RenderProfile struct {
+	Name     string
+	Sections map[IssueSection]bool
+	// Verbose adds descriptions of the type, status and resolution, email
+	// addresses and the rarely needed dates.
+	Verbose bool
+	// DescriptionChars caps the rendered description.
+	DescriptionChars int
+	// Comments is how many of the most recent comments are shown; 0 shows
+	// only the count and -1 every comment Jira returned.
+	Comments     int
+	CommentChars int
+	// ListItems caps subtasks, links, attachments, components and versions.
+	ListItems int
+}
+
+func sections(names ...IssueSection) map[IssueSection]bool {
+	set := make(map[IssueSection]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+var (
+	// ProfileMinimal is enough to triage an issue: what it is, where it
+	// stands and who has it.
+	ProfileMinimal = RenderProfile{
+		Name:     "minimal",
+		Sections: sections(SectionSummary, SectionType, SectionStatus, SectionPriority, SectionAssignee, SectionParent),
+	}
+
+	// ProfileStandard is the default: everything an agent usually needs to
+	// work on an issue, with long text and lists cut down and no changelog.
+	ProfileStandard = RenderProfile{
+		Name: "standard",
+		Sections: sections(SectionSummary, SectionDescription, SectionType, SectionStatus, SectionPriority,
+			SectionResolution, SectionReporter, SectionAssignee, SectionDates, SectionProject, SectionParent,
+			SectionLabels, SectionComponents, SectionVersions, SectionSubtasks, SectionLinks, SectionAttachments,
+			SectionComments, SectionWorklogs, SectionCustomFields, SectionTransitions),
+		DescriptionChars: 1500,
+		Comments:         3,
+		CommentChars:     500,
+		ListItems:        20,
+	}
+
+	// ProfileFull renders everything Jira returned, untruncated.
+	ProfileFull = RenderProfile{
+		Name:     "full",
+		Sections: sections(allSections...),
+		Verbose:  true,
+		Comments: -1,
+	}
+)
+
+// RenderProfileNames lists the named profiles, for parameter descriptions.
+const RenderProfileNames = "minimal, standard, full"
+
+// ParseRenderProfile resolves a profile name, or a comma-separated list of
+// sections for a custom profile that uses the standard limits. An empty
+// value is the standard profile.
+func ParseRenderProfile(value string) (RenderProfile, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "", ProfileStandard.Name:
+		return ProfileStandard, nil
+	case ProfileMinimal.Name:
+		return ProfileMinimal, nil
+	case ProfileFull.Name:
+		return ProfileFull, nil
+	}
+
+	known := sections(allSections...)
+	profile := ProfileStandard
+	profile.Name = "custom"
+	profile.Sections = map[IssueSection]bool{}
+	for _, name := range strings.Split(value, ",") {
+		section := IssueSection(strings.TrimSpace(name))
+		if section == "" {
+			continue
+		}
+		if !known[section] {
+			return RenderProfile{}, fmt.Errorf("unknown profile or section %q: use %s, or a comma-separated list of sections (%s)", name, RenderProfileNames, sectionList())
+		}
+		profile.Sections[section] = true
+	}
+	if len(profile.Sections) == 0 {
+		return RenderProfile{}, fmt.Errorf("profile %q names no sections", value)
+	}
+	return profile, nil
+}
+
+func sectionList() string {
+	names := make([]string, len(allSections))
+	for i, section := range allSections {
+		names[i] = string(section)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Has reports whether the profile renders section.
+func (p RenderProfile) Has(section IssueSection) bool {
+	return p.Sections[section]
+}
+
+// JiraFields returns the fields to request from Jira for the profile.
+// Custom fields cannot be named up front, so a profile that shows them
+// requests every field.
+func (p RenderProfile) JiraFields() []string {
+	if p.Has(SectionCustomFields) {
+		return []string{"*all"}
+	}
+	var fields []string
+	for _, section := range allSections {
+		if p.Has(section) {
+			fields = append(fields, sectionFields[section]...)
+		}
+	}
+	if p.Verbose {
+		fields = append(fields, "workratio")
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Expand returns the expansions to request from Jira for the profile.
+func (p RenderProfile) Expand() []string {
+	var expand []string
+	if p.Has(SectionTransitions) {
+		expand = append(expand, "transitions")
+	}
+	if p.Has(SectionChangelog) {
+		expand = append(expand, "changelog")
+	}
+	return expand
+}
+
+// IssueOutput is NewIssueOutput restricted to the profile's sections, with
+// the description cut to the profile's limit.
+func (p RenderProfile) IssueOutput(issue *models.IssueScheme, customFields ...CustomFieldValue) IssueOutput {
+	output := NewIssueOutput(issue, customFields...)
+	if !p.Has(SectionSummary) {
+		output.Summary = ""
+	}
+	if !p.Has(SectionDescription) {
+		output.Description = ""
+	}
+	output.Description = TruncateText(output.Description, p.DescriptionChars)
+	if !p.Has(SectionType) {
+		output.Type = ""
+	}
+	if !p.Has(SectionStatus) {
+		output.Status = ""
+	}
+	if !p.Has(SectionPriority) {
+		output.Priority = ""
+	}
+	if !p.Has(SectionResolution) {
+		output.Resolution, output.ResolutionDate = "", ""
+	}
+	if !p.Has(SectionAssignee) {
+		output.Assignee = nil
+	}
+	if !p.Has(SectionReporter) {
+		output.Reporter, output.Creator = nil, nil
+	}
+	if !p.Has(SectionDates) {
+		output.Created, output.Updated = "", ""
+	}
+	if !p.Has(SectionProject) {
+		output.Project = ""
+	}
+	if !p.Has(SectionParent) {
+		output.Parent = nil
+	}
+	if !p.Has(SectionLabels) {
+		output.Labels = nil
+	}
+	if !p.Has(SectionComponents) {
+		output.Components = nil
+	}
+	if !p.Has(SectionVersions) {
+		output.FixVersions, output.AffectedVersions = nil, nil
+	}
+	if !p.Has(SectionSubtasks) {
+		output.Subtasks = nil
+	}
+	if !p.Has(SectionLinks) {
+		output.Links = nil
+	}
+	if !p.Has(SectionAttachments) {
+		output.Attachments = nil
+	}
+	if !p.Has(SectionComments) {
+		output.CommentCount = 0
+	}
+	if !p.Has(SectionWorklogs) {
+		output.WorklogCount = 0
+	}
+	if !p.Has(SectionCustomFields) {
+		output.CustomFields = nil
+	}
+	if !p.Has(SectionTransitions) {
+		output.Transitions = nil
+	}
+	return output
+}
+
+// TruncateText cuts text to at most limit characters, preferring a line or
+// word boundary, and appends a marker saying how much was left out. A limit
+// of 0 or less leaves the text alone.
+func TruncateText(text string, limit int) string {
+	total := utf8.RuneCountInString(text)
+	if limit <= 0 || total <= limit {
+		return text
+	}
+	runes := []rune(text)
+	marker := func(cut int) string { return fmt.Sprintf("… [%d more chars]", total-cut) }
+
+	cut := limit - utf8.RuneCountInString(marker(limit))
+	if cut <= 0 {
+		return string(runes[:limit])
+	}
+	// Back off to a boundary when one is close, so words stay whole.
+	for i := cut; i > cut*4/5; i-- {
+		if runes[i] == '\n' || runes[i] == ' ' {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRight(string(runes[:cut]), " \n") + marker(cut)
+}
+
+// minDescriptionChars is the least of a description a budget keeps before
+// the rendering is cut outright.
+const minDescriptionChars = 200
+
+// budgetSteps shrink a profile, most expendable text first, when an issue
+// renders over its budget. Each gets the number of characters over.
+var budgetSteps = []func(p RenderProfile, issue *models.IssueScheme, over int) RenderProfile{
+	func(p RenderProfile, _ *models.IssueScheme, _ int) RenderProfile {
+		if p.Comments < 0 || p.Comments > 3 {
+			p.Comments = 3
+		}
+		if p.CommentChars == 0 || p.CommentChars > 300 {
+			p.CommentChars = 300
+		}
+		return p
+	},
+	func(p RenderProfile, issue *models.IssueScheme, over int) RenderProfile {
+		if issue.Fields == nil || issue.Fields.Description == nil {
+			return p
+		}
+		length := utf8.RuneCountInString(RenderADF(issue.Fields.Description))
+		if p.DescriptionChars > 0 && p.DescriptionChars < length {
+			length = p.DescriptionChars
+		}
+		p.DescriptionChars = max(minDescriptionChars, length-over)
+		return p
+	},
+	func(p RenderProfile, _ *models.IssueScheme, _ int) RenderProfile {
+		p.Comments = 0
+		return p
+	},
+	func(p RenderProfile, _ *models.IssueScheme, _ int) RenderProfile {
+		if p.ListItems == 0 || p.ListItems > 5 {
+			p.ListItems = 5
+		}
+		return p
+	},
+}
+
+// RenderIssueBudget renders an issue with profile in at most maxChars
+// characters. Over budget, it keeps fewer and shorter comments, shortens
+// the description, drops comment bodies and shortens lists before cutting
+// the text itself. A maxChars of 0 or less is unlimited.
+func RenderIssueBudget(issue *models.IssueScheme, profile RenderProfile, maxChars int, customFields ...CustomFieldValue) string {
+	text := RenderIssue(issue, profile, customFields...)
+	if maxChars <= 0 {
+		return text
+	}
+	for _, shrink := range budgetSteps {
+		over := utf8.RuneCountInString(text) - maxChars
+		if over <= 0 {
+			return text
+		}
+		profile = shrink(profile, issue, over)
+		text = RenderIssue(issue, profile, customFields...)
+	}
+	return TruncateText(text, maxChars)
+}
+
+// minIssueChars is the least budget worth rendering another issue of a
+// list into.
+const minIssueChars = 200
+
+// RenderIssueList renders issues separated by "===" lines within maxChars
+// characters in total, sharing what is left of the budget equally among the
+// remaining issues but giving each at least minIssueChars. Issues that no
+// longer fit are counted in a closing line.
+func RenderIssueList(issues []*models.IssueScheme, profile RenderProfile, maxChars int, customFields func(*models.IssueScheme) []CustomFieldValue) string {
+	const separator = "\n===\n"
+	var sb strings.Builder
+	remaining := maxChars
+	for index, issue := range issues {
+		if index > 0 {
+			sb.WriteString(separator)
+			remaining -= utf8.RuneCountInString(separator)
+		}
+		if maxChars <= 0 {
+			sb.WriteString(RenderIssue(issue, profile, customFields(issue)...))
+			continue
+		}
+		if remaining < minIssueChars {
+			sb.WriteString(fmt.Sprintf("… %d more issues not shown (raise max_chars or use a smaller profile)\n", len(issues)-index))
+			return sb.String()
+		}
+		share := min(remaining, max(remaining/(len(issues)-index), minIssueChars))
+		text := RenderIssueBudget(issue, profile, share, customFields(issue)...)
+		sb.WriteString(text)
+		remaining -= utf8.RuneCountInString(text)
+	}
+	return sb.String()
+}
+
+// RenderIssue renders the sections of an issue selected by profile, within
+// the profile's limits. FormatJiraIssue is RenderIssue with ProfileFull.
+func RenderIssue(issue *models.IssueScheme, profile RenderProfile, customFields ...CustomFieldValue) string {
+	r := issueRenderer{profile: profile}
+	r.render(issue, customFields)
+	return r.sb.String()
+}
+
+type issueRenderer struct {
+	profile RenderProfile
+	sb      strings.Builder
+}
+
+func (r *issueRenderer) has(section IssueSection) bool {
+	return r.profile.Has(section)
+}
+
+func (r *issueRenderer) printf(format string, args ...any) {
+	r.sb.WriteString(fmt.Sprintf(format, args...))
+}
+
+// list writes a header and one "- " line per item, capped at the profile's
+// ListItems.
+func (r *issueRenderer) list(header string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	r.printf("%s:\n", header)
+	for i, item := range items {
+		if r.profile.ListItems > 0 && i == r.profile.ListItems {
+			r.printf("- … %d more\n", len(items)-i)
+			break
+		}
+		r.printf("- %s\n", item)
+	}
+}
+
+func (r *issueRenderer) user(label string, user *models.UserScheme, unset string) {
+	switch {
+	case user == nil && unset != "":
+		r.printf("%s: %s\n", label, unset)
+	case user != nil && user.EmailAddress != "" && r.profile.Verbose:
+		r.printf("%s: %s (%s)\n", label, user.DisplayName, user.EmailAddress)
+	case user != nil:
+		r.printf("%s: %s\n", label, user.DisplayName)
+	}
+}
+
+func (r *issueRenderer) render(issue *models.IssueScheme, customFields []CustomFieldValue) {
+	r.printf("Key: %s\n", issue.Key)
+	if issue.ID != "" {
+		r.printf("ID: %s\n", issue.ID)
+	}
+	if issue.Self != "" {
+		r.printf("URL: %s\n", issue.Self)
+	}
+
+	if fields := issue.Fields; fields != nil {
+		r.renderFields(fields)
+	}
+
+	if r.has(SectionCustomFields) && len(customFields) > 0 {
+		r.printf("Custom Fields:\n")
+		for _, field := range customFields {
+			if strings.Contains(field.Value, "\n") {
+				r.printf("- %s:\n%s\n", field.Name, field.Value)
+			} else {
+				r.printf("- %s: %s\n", field.Name, field.Value)
+			}
+		}
+	}
+
+	if r.has(SectionTransitions) && len(issue.Transitions) > 0 {
+		r.printf("\nAvailable Transitions:\n")
+		for _, transition := range issue.Transitions {
+			r.printf("- %s (ID: %s)\n", transition.Name, transition.ID)
+		}
+	}
+
+	// Story point estimate from changelog (if available)
+	if r.has(SectionChangelog) && issue.Changelog != nil {
+		storyPoint := ""
+		for _, history := range issue.Changelog.Histories {
+			for _, item := range history.Items {
+				if item.Field == "Story point estimate" && item.ToString != "" {
+					storyPoint = item.ToString
+				}
+			}
+		}
+		if storyPoint != "" {
+			r.printf("Story Point Estimate: %s\n", storyPoint)
+		}
+	}
+}
+
+func (r *issueRenderer) renderFields(fields *models.IssueFieldsScheme) {
+	verbose := r.profile.Verbose
+
+	if r.has(SectionSummary) && fields.Summary != "" {
+		r.printf("Summary: %s\n", fields.Summary)
+	}
+	if r.has(SectionDescription) && fields.Description != nil {
+		if description := RenderADF(fields.Description); description != "" {
+			r.printf("Description:\n%s\n", TruncateText(description, r.profile.DescriptionChars))
+		}
+	}
+
+	if r.has(SectionType) && fields.IssueType != nil {
+		r.printf("Type: %s\n", fields.IssueType.Name)
+		if verbose && fields.IssueType.Description != "" {
+			r.printf("Type Description: %s\n", fields.IssueType.Description)
+		}
+	}
+	if r.has(SectionStatus) && fields.Status != nil {
+		r.printf("Status: %s\n", fields.Status.Name)
+		if verbose && fields.Status.Description != "" {
+			r.printf("Status Description: %s\n", fields.Status.Description)
+		}
+	}
+	if r.has(SectionPriority) {
+		if fields.Priority != nil {
+			r.printf("Priority: %s\n", fields.Priority.Name)
+		} else {
+			r.printf("Priority: None\n")
+		}
+	}
+	if r.has(SectionResolution) {
+		if fields.Resolution != nil {
+			r.printf("Resolution: %s\n", fields.Resolution.Name)
+			if verbose && fields.Resolution.Description != "" {
+				r.printf("Resolution Description: %s\n", fields.Resolution.Description)
+			}
+		}
+		if fields.Resolutiondate != "" {
+			r.printf("Resolution Date: %s\n", fields.Resolutiondate)
+		}
+	}
+
+	if r.has(SectionReporter) {
+		r.user("Reporter", fields.Reporter, "Unassigned")
+	}
+	if r.has(SectionAssignee) {
+		r.user("Assignee", fields.Assignee, "Unassigned")
+	}
+	if r.has(SectionReporter) {
+		r.user("Creator", fields.Creator, "")
+	}
+
+	if r.has(SectionDates) {
+		if fields.Created != "" {
+			r.printf("Created: %s\n", fields.Created)
+		}
+		if fields.Updated != "" {
+			r.printf("Updated: %s\n", fields.Updated)
+		}
+		if verbose && fields.LastViewed != "" {
+			r.printf("Last Viewed: %s\n", fields.LastViewed)
+		}
+		if verbose && fields.StatusCategoryChangeDate != "" {
+			r.printf("Status Category Change Date: %s\n", fields.StatusCategoryChangeDate)
+		}
+	}
+
+	if r.has(SectionProject) && fields.Project != nil {
+		if fields.Project.Key != "" {
+			r.printf("Project: %s (%s)\n", fields.Project.Name, fields.Project.Key)
+		} else {
+			r.printf("Project: %s\n", fields.Project.Name)
+		}
+	}
+	if r.has(SectionParent) && fields.Parent != nil {
+		if fields.Parent.Fields != nil && fields.Parent.Fields.Summary != "" {
+			r.printf("Parent: %s - %s\n", fields.Parent.Key, fields.Parent.Fields.Summary)
+		} else {
+			r.printf("Parent: %s\n", fields.Parent.Key)
+		}
+	}
+	if verbose && fields.Workratio > 0 {
+		r.printf("Work Ratio: %d\n", fields.Workratio)
+	}
+
+	if r.has(SectionLabels) && len(fields.Labels) > 0 {
+		r.printf("Labels: %s\n", strings.Join(fields.Labels, ", "))
+	}
+	if r.has(SectionComponents) {
+		var items []string
+		for _, component := range fields.Components {
+			items = append(items, withDescription(component.Name, component.Description, verbose))
+		}
+		r.list("Components", items)
+	}
+	if r.has(SectionVersions) {
+		var fixVersions, affectedVersions []string
+		for _, version := range fields.FixVersions {
+			fixVersions = append(fixVersions, withDescription(version.Name, version.Description, verbose))
+		}
+		for _, version := range fields.Versions {
+			affectedVersions = append(affectedVersions, withDescription(version.Name, version.Description, verbose))
+		}
+		r.list("Fix Versions", fixVersions)
+		r.list("Affected Versions", affectedVersions)
+	}
+	if r.has(SectionSecurity) && fields.Security != nil {
+		r.printf("Security Level: %s\n", fields.Security.Name)
+	}
+
+	if r.has(SectionSubtasks) {
+		var items []string
+		for _, subtask := range fields.Subtasks {
+			item := subtask.Key
+			if subtask.Fields != nil && subtask.Fields.Summary != "" {
+				item += ": " + subtask.Fields.Summary
+			}
+			if subtask.Fields != nil && subtask.Fields.Status != nil {
+				item += fmt.Sprintf(" [%s]", subtask.Fields.Status.Name)
+			}
+			items = append(items, item)
+		}
+		r.list("Subtasks", items)
+	}
+	if r.has(SectionLinks) {
+		var items []string
+		for _, link := range fields.IssueLinks {
+			if link.Type == nil {
+				continue
+			}
+			if link.OutwardIssue != nil {
+				items = append(items, linkItem(link.Type.Outward, link.OutwardIssue))
+			}
+			if link.InwardIssue != nil {
+				items = append(items, linkItem(link.Type.Inward, link.InwardIssue))
+			}
+		}
+		r.list("Issue Links", items)
+	}
+
+	if r.has(SectionWatchers) {
+		if fields.Watcher != nil {
+			r.printf("Watchers: %d\n", fields.Watcher.WatchCount)
+		}
+		if fields.Votes != nil {
+			r.printf("Votes: %d\n", fields.Votes.Votes)
+		}
+	}
+
+	if r.has(SectionAttachments) {
+		var items []string
+		for _, att := range fields.Attachment {
+			items = append(items, fmt.Sprintf("%s (ID: %s, Type: %s, Size: %d bytes)", att.Title, att.ID, att.MediaType, att.FileSize))
+		}
+		r.list("Attachments", items)
+	}
+
+	if r.has(SectionComments) && fields.Comment != nil && fields.Comment.Total > 0 {
+		r.renderComments(fields.Comment)
+	}
+	if r.has(SectionWorklogs) && fields.Worklog != nil && fields.Worklog.Total > 0 {
+		r.printf("Worklogs: %d entries\n", fields.Worklog.Total)
+	}
+}
+
+// renderComments writes the comment count and the most recent comments the
+// profile allows. Jira returns comments oldest first.
+func (r *issueRenderer) renderComments(page *models.IssueCommentPageScheme) {
+	r.printf("Comments: %d total\n", page.Total)
+	comments := page.Comments
+	if r.profile.Comments == 0 || len(comments) == 0 {
+		return
+	}
+	if r.profile.Comments > 0 && len(comments) > r.profile.Comments {
+		comments = comments[len(comments)-r.profile.Comments:]
+	}
+	if hidden := page.Total - len(comments); hidden > 0 {
+		r.printf("… %d earlier comments not shown\n", hidden)
+	}
+	for _, comment := range comments {
+		author := "Unknown"
+		if comment.Author != nil {
+			author = comment.Author.DisplayName
+		}
+		r.printf("--- %s, %s (ID: %s)\n", author, comment.Created, comment.ID)
+		r.printf("%s\n", TruncateText(strings.TrimSpace(RenderADF(comment.Body)), r.profile.CommentChars))
+	}
+}
+
+func withDescription(name, description string, verbose bool) string {
+	if verbose && description != "" {
+		return fmt.Sprintf("%s (%s)", name, description)
+	}
+	return name
+}
+
+func linkItem(relation string, linked *models.LinkedIssueScheme) string {
+	item := fmt.Sprintf("%s %s", relation, linked.Key)
+	if linked.Fields != nil && linked.Fields.Summary != "" {
+		item += ": " + linked.Fields.Summary
+	}
+	return item
+}
@@ -0,0 +1,178 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// renderTestIssue has a long description and five comments.
+func renderTestIssue(t *testing.T) *models.IssueScheme {
+	t.Helper()
+	var comments []string
+	for i := 1; i <= 5; i++ {
+		comments = append(comments, fmt.Sprintf(`{"id": "%d", "author": {"displayName": "Mia Krystof"}, "created": "2026-10-0%dT09:00:00.000+0000",
+			"body": {"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "comment %d %s"}]}]}}`, i, i, i, strings.Repeat("x", 400)))
+	}
+	raw := fmt.Sprintf(`{
+		"key": "ABC-1",
+		"fields": {
+			"summary": "Login fails",
+			"description": {"type": "doc", "content": [{"type": "paragraph", "content": [{"type": "text", "text": %q}]}]},
+			"issuetype": {"name": "Bug", "description": "A problem"},
+			"status": {"name": "In Progress"},
+			"assignee": {"displayName": "Mia Krystof", "emailAddress": "mia@example.com"},
+			"labels": ["auth"],
+			"comment": {"total": 5, "comments": [%s]}
+		},
+		"transitions": [{"id": "31", "name": "Done"}],
+		"changelog": {"histories": [{"items": [{"field": "Story point estimate", "toString": "3"}]}]}
+	}`, strings.Repeat("word ", 1000), strings.Join(comments, ","))
+
+	var issue models.IssueScheme
+	if err := json.Unmarshal([]byte(raw), &issue); err != nil {
+		t.Fatal(err)
+	}
+	return &issue
+}
+
+func TestParseRenderProfile(t *testing.T) {
+	for value, want := range map[string]string{"": "standard", "Minimal": "minimal", " full ": "full", "summary, comments": "custom"} {
+		profile, err := ParseRenderProfile(value)
+		if err != nil || profile.Name != want {
+			t.Errorf("ParseRenderProfile(%q) = %q, %v, want %q", value, profile.Name, err, want)
+		}
+	}
+
+	custom, _ := ParseRenderProfile("summary,comments")
+	if !custom.Has(SectionComments) || custom.Has(SectionDescription) || custom.Comments != ProfileStandard.Comments {
+		t.Errorf("custom profile = %+v", custom)
+	}
+	if got := custom.JiraFields(); !reflect.DeepEqual(got, []string{"comment", "summary"}) {
+		t.Errorf("custom JiraFields = %v", got)
+	}
+
+	for _, value := range []string{"tiny", "summary,bogus", ","} {
+		if _, err := ParseRenderProfile(value); err == nil {
+			t.Errorf("ParseRenderProfile(%q) should fail", value)
+		}
+	}
+}
+
+func TestRenderProfile_Expand(t *testing.T) {
+	if got := ProfileStandard.Expand(); !reflect.DeepEqual(got, []string{"transitions"}) {
+		t.Errorf("standard Expand = %v", got)
+	}
+	if got := ProfileFull.Expand(); !reflect.DeepEqual(got, []string{"transitions", "changelog"}) {
+		t.Errorf("full Expand = %v", got)
+	}
+	if got := ProfileMinimal.JiraFields(); !reflect.DeepEqual(got, []string{"assignee", "issuetype", "parent", "priority", "status", "summary"}) {
+		t.Errorf("minimal JiraFields = %v", got)
+	}
+}
+
+func TestRenderIssue_Profiles(t *testing.T) {
+	issue := renderTestIssue(t)
+
+	minimal := RenderIssue(issue, ProfileMinimal)
+	want := "Key: ABC-1\nSummary: Login fails\nType: Bug\nStatus: In Progress\nPriority: None\nAssignee: Mia Krystof\n"
+	if minimal != want {
+		t.Errorf("minimal =\n%s\nwant\n%s", minimal, want)
+	}
+
+	standard := RenderIssue(issue, ProfileStandard)
+	for _, part := range []string{"more chars]", "Comments: 5 total\n… 2 earlier comments not shown\n", "comment 5 ", "Available Transitions:"} {
+		if !strings.Contains(standard, part) {
+			t.Errorf("standard rendering lacks %q:\n%s", part, standard)
+		}
+	}
+	for _, part := range []string{"comment 2 ", "Type Description", "mia@example.com", "Story Point Estimate"} {
+		if strings.Contains(standard, part) {
+			t.Errorf("standard rendering should not contain %q", part)
+		}
+	}
+
+	full := FormatJiraIssue(issue)
+	for _, part := range []string{"comment 1 ", "Type Description: A problem", "Assignee: Mia Krystof (mia@example.com)", "Story Point Estimate: 3"} {
+		if !strings.Contains(full, part) {
+			t.Errorf("full rendering lacks %q", part)
+		}
+	}
+	if strings.Contains(full, "more chars]") {
+		t.Error("full rendering must not truncate")
+	}
+}
+
+func TestRenderIssueBudget(t *testing.T) {
+	issue := renderTestIssue(t)
+	for _, budget := range []int{3000, 1200, 300} {
+		got := RenderIssueBudget(issue, ProfileFull, budget)
+		if n := utf8.RuneCountInString(got); n > budget {
+			t.Errorf("budget %d: rendered %d chars", budget, n)
+		}
+		if !strings.HasPrefix(got, "Key: ABC-1\nSummary: Login fails\n") {
+			t.Errorf("budget %d: the start of the issue must survive:\n%s", budget, got)
+		}
+	}
+
+	// Comments go before the description does.
+	got := RenderIssueBudget(issue, ProfileFull, 3000)
+	if !strings.Contains(got, "Status: In Progress") || strings.Contains(got, "comment 1 ") {
+		t.Errorf("budget 3000 should drop old comments and keep the fields:\n%s", got)
+	}
+	if RenderIssueBudget(issue, ProfileFull, 0) != FormatJiraIssue(issue) {
+		t.Error("no budget must render in full")
+	}
+}
+
+func TestRenderIssueList(t *testing.T) {
+	issue := renderTestIssue(t)
+	issues := []*models.IssueScheme{issue, issue, issue}
+	none := func(*models.IssueScheme) []CustomFieldValue { return nil }
+
+	got := RenderIssueList(issues, ProfileMinimal, 0, none)
+	if strings.Count(got, "\n===\n") != 2 {
+		t.Errorf("unbudgeted list should separate three issues:\n%s", got)
+	}
+
+	got = RenderIssueList(issues, ProfileStandard, 500, none)
+	if n := utf8.RuneCountInString(got); n > 600 {
+		t.Errorf("list rendered %d chars for a budget of 500", n)
+	}
+	if !strings.HasSuffix(got, "… 1 more issues not shown (raise max_chars or use a smaller profile)\n") {
+		t.Errorf("list should count the issues left out:\n%s", got)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := TruncateText("short", 10); got != "short" {
+		t.Errorf("got %q", got)
+	}
+	if got := TruncateText("short", 0); got != "short" {
+		t.Errorf("got %q", got)
+	}
+	got := TruncateText(strings.Repeat("abc ", 25), 40)
+	if got != "abc abc abc abc abc abc… [77 more chars]" {
+		t.Errorf("got %q", got)
+	}
+	if n := utf8.RuneCountInString(got); n > 40 {
+		t.Errorf("truncated to %d chars", n)
+	}
+}
+
+func TestRenderProfile_IssueOutput(t *testing.T) {
+	issue := renderTestIssue(t)
+	got := ProfileMinimal.IssueOutput(issue)
+	if got.Description != "" || got.Labels != nil || got.Transitions != nil || got.CommentCount != 0 || got.Status != "In Progress" {
+		t.Errorf("minimal output = %+v", got)
+	}
+	standard := ProfileStandard.IssueOutput(issue)
+	if n := utf8.RuneCountInString(standard.Description); n > ProfileStandard.DescriptionChars {
+		t.Errorf("standard description is %d chars", n)
+	}
+}
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// SearchMaxPageSize is the largest page /rest/api/3/search/jql returns when
+// issue fields are requested. Larger requests are silently clamped by Jira.
+const SearchMaxPageSize = 100
+
+// SearchDefaultMaxResults is used when the caller does not ask for a size.
+const SearchDefaultMaxResults = 30
+
+// SearchFetchAllCap bounds fetch_all mode so a broad JQL query (e.g.
+// "order by created") cannot pull an entire Jira instance into memory.
+const SearchFetchAllCap = 1000
+
+// SearchJQLResult mirrors the /rest/api/3/search/jql response. Unlike the
+// legacy /search endpoint it carries no total or startAt: callers continue
+// with NextPageToken until IsLast is true.
+type SearchJQLResult struct {
+	Issues        []*models.IssueScheme `json:"issues"`
+	NextPageToken string                `json:"nextPageToken,omitempty"`
+	IsLast        bool                  `json:"isLast"`
+
+	// RawIssues holds each issue's JSON as received, in the same order as
+	// Issues. models.IssueScheme drops custom fields; these keep them.
+	RawIssues []json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the typed issues and keeps their raw JSON too.
+func (r *SearchJQLResult) UnmarshalJSON(data []byte) error {
+	type plain SearchJQLResult
+	var typed plain
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	var raw struct {
+		Issues []json.RawMessage `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = SearchJQLResult(typed)
+	r.RawIssues = raw.Issues
+	return nil
+}
+
+// SearchPageFetcher retrieves a single page of search results.
+type SearchPageFetcher func(ctx context.Context, pageToken string, maxResults int) (*SearchJQLResult, error)
+
+// FetchSearchPages follows the nextPageToken chain starting at pageToken until
+// maxResults issues are collected or Jira reports the last page. When fetchAll
+// is true maxResults is ignored and SearchFetchAllCap applies instead.
+//
+// The returned nextPageToken is empty once the result set is exhausted;
+// otherwise it resumes exactly after the last returned issue.
+func FetchSearchPages(
+	ctx context.Context,
+	fetch SearchPageFetcher,
+	pageToken string,
+	maxResults int,
+	fetchAll bool,
+) (issues []*models.IssueScheme, nextPageToken string, err error) {
+	limit := maxResults
+	if limit <= 0 {
+		limit = SearchDefaultMaxResults
+	}
+	if fetchAll {
+		limit = SearchFetchAllCap
+	}
+
+	cursor := pageToken
+	for len(issues) < limit {
+		size := limit - len(issues)
+		if size > SearchMaxPageSize {
+			size = SearchMaxPageSize
+		}
+
+		page, callErr := fetch(ctx, cursor, size)
+		if callErr != nil {
+			return nil, "", callErr
+		}
+		issues = append(issues, page.Issues...)
+
+		if page.IsLast || page.NextPageToken == "" || len(page.Issues) == 0 {
+			return issues, "", nil
+		}
+		cursor = page.NextPageToken
+	}
+	return issues, cursor, nil
+}
+
+// FormatSearchHeader builds an AI-friendly header for paginated search
+// results, mirroring FormatCommentsHeader. total < 0 means the count is
+// unknown (the approximate-count endpoint is best effort).
+func FormatSearchHeader(total, returned int, nextPageToken string, fetchAll bool) string {
+	header := "Search results — "
+	if total >= 0 {
+		header += fmt.Sprintf("total: ~%d issues, ", total)
+	}
+	header += fmt.Sprintf("returned: %d", returned)
+	if nextPageToken == "" {
+		return header + ", more: false"
+	}
+	if fetchAll {
+		header += fmt.Sprintf(", more: true (fetch_all stopped at the %d-issue safety cap", SearchFetchAllCap)
+		return header + fmt.Sprintf("; call again with page_token=%q to continue)", nextPageToken)
+	}
+	return header + fmt.Sprintf(", more: true (call again with page_token=%q to continue)", nextPageToken)
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// fakeSearchPages returns a SearchPageFetcher over `total` issues keyed
+// PROJ-1..PROJ-total. The page token is the stringified offset, which is
+// enough to exercise the token chain without a real Jira.
+func fakeSearchPages(t *testing.T, total int) (SearchPageFetcher, *[]int) {
+	t.Helper()
+	var sizes []int
+	fetch := func(_ context.Context, pageToken string, maxResults int) (*SearchJQLResult, error) {
+		sizes = append(sizes, maxResults)
+		start := 0
+		if pageToken != "" {
+			var err error
+			start, err = strconv.Atoi(pageToken)
+			if err != nil {
+				t.Fatalf("unexpected page token %q", pageToken)
+			}
+		}
+		end := start + maxResults
+		if end > total {
+			end = total
+		}
+		page := &SearchJQLResult{IsLast: end >= total}
+		for i := start; i < end; i++ {
+			page.Issues = append(page.Issues, &models.IssueScheme{Key: fmt.Sprintf("PROJ-%d", i+1)})
+		}
+		if !page.IsLast {
+			page.NextPageToken = strconv.Itoa(end)
+		}
+		return page, nil
+	}
+	return fetch, &sizes
+}
+
+func TestFetchSearchPages_DefaultSizeReturnsToken(t *testing.T) {
+	fetch, sizes := fakeSearchPages(t, 75)
+
+	issues, next, err := FetchSearchPages(context.Background(), fetch, "", 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != SearchDefaultMaxResults {
+		t.Errorf("got %d issues, want %d", len(issues), SearchDefaultMaxResults)
+	}
+	if next != "30" {
+		t.Errorf("nextPageToken = %q, want %q", next, "30")
+	}
+	if len(*sizes) != 1 {
+		t.Errorf("expected a single request, got %d", len(*sizes))
+	}
+}
+
+func TestFetchSearchPages_ResumesFromToken(t *testing.T) {
+	fetch, _ := fakeSearchPages(t, 75)
+
+	issues, next, err := FetchSearchPages(context.Background(), fetch, "60", 30, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 15 || issues[0].Key != "PROJ-61" {
+		t.Errorf("expected PROJ-61..PROJ-75, got %d issues starting at %s", len(issues), issues[0].Key)
+	}
+	if next != "" {
+		t.Errorf("last page should clear nextPageToken, got %q", next)
+	}
+}
+
+func TestFetchSearchPages_LargeMaxResultsSpansPages(t *testing.T) {
+	fetch, sizes := fakeSearchPages(t, 500)
+
+	issues, next, err := FetchSearchPages(context.Background(), fetch, "", 250, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 250 {
+		t.Errorf("got %d issues, want 250", len(issues))
+	}
+	if next != "250" {
+		t.Errorf("nextPageToken = %q, want 250", next)
+	}
+	// Every request must respect the per-page limit, and the final request
+	// must ask for exactly the remainder so the token resumes cleanly.
+	want := []int{100, 100, 50}
+	if fmt.Sprint(*sizes) != fmt.Sprint(want) {
+		t.Errorf("page sizes = %v, want %v", *sizes, want)
+	}
+}
+
+func TestFetchSearchPages_FetchAllStopsAtCap(t *testing.T) {
+	fetch, _ := fakeSearchPages(t, SearchFetchAllCap+200)
+
+	issues, next, err := FetchSearchPages(context.Background(), fetch, "", 5, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != SearchFetchAllCap {
+		t.Errorf("got %d issues, want cap %d", len(issues), SearchFetchAllCap)
+	}
+	if next == "" {
+		t.Error("capped fetch_all must still report a continuation token")
+	}
+}
+
+func TestFetchSearchPages_PropagatesError(t *testing.T) {
+	boom := errors.New("boom")
+	fetch := func(context.Context, string, int) (*SearchJQLResult, error) { return nil, boom }
+
+	if _, _, err := FetchSearchPages(context.Background(), fetch, "", 10, false); !errors.Is(err, boom) {
+		t.Errorf("expected fetch error to propagate, got %v", err)
+	}
+}
+
+func TestFormatSearchHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		returned int
+		token    string
+		fetchAll bool
+		want     []string
+	}{
+		{"complete", 12, 12, "", false, []string{"total: ~12", "returned: 12", "more: false"}},
+		{"unknown total", -1, 3, "", false, []string{"returned: 3"}},
+		{"more pages", 80, 30, "tok", false, []string{"more: true", `page_token="tok"`}},
+		{"capped fetch_all", 5000, SearchFetchAllCap, "tok", true, []string{"safety cap", `page_token="tok"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatSearchHeader(tt.total, tt.returned, tt.token, tt.fetchAll)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("header %q missing %q", got, w)
+				}
+			}
+			if tt.total < 0 && strings.Contains(got, "total") {
+				t.Errorf("unknown total should be omitted, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package util
+
+import (
+	"time"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// The types below are the structured (JSON) counterparts of the text
+// formatters in this package. Tools return them as MCP structuredContent so
+// programmatic clients need not parse the text.
+
+// UserOutput identifies a Jira user.
+type UserOutput struct {
+	AccountID   string `json:"account_id,omitempty"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email,omitempty"`
+}
+
+// IssueRefOutput is another issue referenced from an issue: parent,
+// subtask or link target.
+type IssueRefOutput struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// IssueLinkOutput is a link as seen from the issue holding it, e.g.
+// relation "blocks" to ABC-2.
+type IssueLinkOutput struct {
+	ID       string         `json:"id,omitempty"`
+	Relation string         `json:"relation"`
+	Issue    IssueRefOutput `json:"issue"`
+}
+
+// AttachmentOutput describes one attachment.
+type AttachmentOutput struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mime_type,omitempty"`
+	Size     int    `json:"size"`
+}
+
+// TransitionOutput is a workflow transition available on an issue.
+type TransitionOutput struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   string `json:"to,omitempty"`
+}
+
+// CustomFieldOutput is a custom field value, rendered as text.
+type CustomFieldOutput struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// IssueOutput is the structured form of FormatJiraIssue.
+type IssueOutput struct {
+	Key              string              `json:"key"`
+	ID               string              `json:"id,omitempty"`
+	URL              string              `json:"url,omitempty"`
+	Summary          string              `json:"summary,omitempty"`
+	Description      string              `json:"description,omitempty"`
+	Type             string              `json:"type,omitempty"`
+	Status           string              `json:"status,omitempty"`
+	Priority         string              `json:"priority,omitempty"`
+	Resolution       string              `json:"resolution,omitempty"`
+	ResolutionDate   string              `json:"resolution_date,omitempty"`
+	Reporter         *UserOutput         `json:"reporter,omitempty"`
+	Assignee         *UserOutput         `json:"assignee,omitempty"`
+	Creator          *UserOutput         `json:"creator,omitempty"`
+	Created          string              `json:"created,omitempty"`
+	Updated          string              `json:"updated,omitempty"`
+	Project          string              `json:"project,omitempty"`
+	Parent           *IssueRefOutput     `json:"parent,omitempty"`
+	Labels           []string            `json:"labels,omitempty"`
+	Components       []string            `json:"components,omitempty"`
+	FixVersions      []string            `json:"fix_versions,omitempty"`
+	AffectedVersions []string            `json:"affected_versions,omitempty"`
+	Subtasks         []IssueRefOutput    `json:"subtasks,omitempty"`
+	Links            []IssueLinkOutput   `json:"links,omitempty"`
+	Attachments      []AttachmentOutput  `json:"attachments,omitempty"`
+	CommentCount     int                 `json:"comment_count,omitempty"`
+	WorklogCount     int                 `json:"worklog_count,omitempty"`
+	CustomFields     []CustomFieldOutput `json:"custom_fields,omitempty"`
+	Transitions      []TransitionOutput  `json:"transitions,omitempty"`
+}
+
+// IssueSummaryOutput is the structured form of FormatJiraIssueCompact.
+type IssueSummaryOutput struct {
+	Key      string `json:"key"`
+	Summary  string `json:"summary,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Assignee string `json:"assignee,omitempty"`
+	Priority string `json:"priority,omitempty"`
+}
+
+// SprintOutput is the structured form of FormatSprint.
+type SprintOutput struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	StartDate     string `json:"start_date,omitempty"`
+	EndDate       string `json:"end_date,omitempty"`
+	CompleteDate  string `json:"complete_date,omitempty"`
+	OriginBoardID int    `json:"origin_board_id,omitempty"`
+	// BoardID is the board the sprint was found on, when listed per board.
+	BoardID int    `json:"board_id,omitempty"`
+	Goal    string `json:"goal,omitempty"`
+}
+
+// NewUserOutput returns nil for a nil user.
+func NewUserOutput(user *models.UserScheme) *UserOutput {
+	if user == nil {
+		return nil
+	}
+	return &UserOutput{AccountID: user.AccountID, DisplayName: user.DisplayName, Email: user.EmailAddress}
+}
+
+// NewIssueOutput converts an issue the way FormatJiraIssue renders it.
+func NewIssueOutput(issue *models.IssueScheme, customFields ...CustomFieldValue) IssueOutput {
+	output := IssueOutput{Key: issue.Key, ID: issue.ID, URL: issue.Self}
+
+	if fields := issue.Fields; fields != nil {
+		output.Summary = fields.Summary
+		if fields.Description != nil {
+			output.Description = RenderADF(fields.Description)
+		}
+		if fields.IssueType != nil {
+			output.Type = fields.IssueType.Name
+		}
+		if fields.Status != nil {
+			output.Status = fields.Status.Name
+		}
+		if fields.Priority != nil {
+			output.Priority = fields.Priority.Name
+		}
+		if fields.Resolution != nil {
+			output.Resolution = fields.Resolution.Name
+		}
+		output.ResolutionDate = fields.Resolutiondate
+		output.Reporter = NewUserOutput(fields.Reporter)
+		output.Assignee = NewUserOutput(fields.Assignee)
+		output.Creator = NewUserOutput(fields.Creator)
+		output.Created = fields.Created
+		output.Updated = fields.Updated
+		if fields.Project != nil {
+			output.Project = fields.Project.Key
+		}
+		if fields.Parent != nil {
+			parent := &IssueRefOutput{Key: fields.Parent.Key}
+			if fields.Parent.Fields != nil {
+				parent.Summary = fields.Parent.Fields.Summary
+				if fields.Parent.Fields.Status != nil {
+					parent.Status = fields.Parent.Fields.Status.Name
+				}
+			}
+			output.Parent = parent
+		}
+		output.Labels = fields.Labels
+		for _, component := range fields.Components {
+			output.Components = append(output.Components, component.Name)
+		}
+		for _, version := range fields.FixVersions {
+			output.FixVersions = append(output.FixVersions, version.Name)
+		}
+		for _, version := range fields.Versions {
+			output.AffectedVersions = append(output.AffectedVersions, version.Name)
+		}
+		for _, subtask := range fields.Subtasks {
+			output.Subtasks = append(output.Subtasks, NewIssueRefOutput(subtask.Key, subtask.Fields))
+		}
+		for _, link := range fields.IssueLinks {
+			if link.Type == nil {
+				continue
+			}
+			if link.OutwardIssue != nil {
+				output.Links = append(output.Links, IssueLinkOutput{ID: link.ID, Relation: link.Type.Outward, Issue: linkedIssueRef(link.OutwardIssue.Key, link.OutwardIssue.Fields)})
+			}
+			if link.InwardIssue != nil {
+				output.Links = append(output.Links, IssueLinkOutput{ID: link.ID, Relation: link.Type.Inward, Issue: linkedIssueRef(link.InwardIssue.Key, link.InwardIssue.Fields)})
+			}
+		}
+		for _, attachment := range fields.Attachment {
+			output.Attachments = append(output.Attachments, AttachmentOutput{ID: attachment.ID, Filename: attachment.Title, MimeType: attachment.MediaType, Size: attachment.FileSize})
+		}
+		if fields.Comment != nil {
+			output.CommentCount = fields.Comment.Total
+		}
+		if fields.Worklog != nil {
+			output.WorklogCount = fields.Worklog.Total
+		}
+	}
+
+	for _, field := range customFields {
+		output.CustomFields = append(output.CustomFields, CustomFieldOutput{ID: field.ID, Name: field.Name, Value: field.Value})
+	}
+	for _, transition := range issue.Transitions {
+		converted := TransitionOutput{ID: transition.ID, Name: transition.Name}
+		if transition.To != nil {
+			converted.To = transition.To.Name
+		}
+		output.Transitions = append(output.Transitions, converted)
+	}
+	return output
+}
+
+// NewIssueRefOutput summarises a related issue from whatever fields Jira
+// included.
+func NewIssueRefOutput(key string, fields *models.IssueFieldsScheme) IssueRefOutput {
+	ref := IssueRefOutput{Key: key}
+	if fields != nil {
+		ref.Summary = fields.Summary
+		if fields.Status != nil {
+			ref.Status = fields.Status.Name
+		}
+	}
+	return ref
+}
+
+// linkedIssueRef is NewIssueRefOutput for the trimmed fields Jira embeds in links.
+func linkedIssueRef(key string, fields *models.IssueLinkFieldsScheme) IssueRefOutput {
+	ref := IssueRefOutput{Key: key}
+	if fields != nil {
+		ref.Summary = fields.Summary
+		if fields.Status != nil {
+			ref.Status = fields.Status.Name
+		}
+	}
+	return ref
+}
+
+// NewIssueSummaryOutput converts an issue the way FormatJiraIssueCompact
+// renders it.
+func NewIssueSummaryOutput(issue *models.IssueScheme) IssueSummaryOutput {
+	output := IssueSummaryOutput{Key: issue.Key}
+	if fields := issue.Fields; fields != nil {
+		output.Summary = fields.Summary
+		if fields.Status != nil {
+			output.Status = fields.Status.Name
+		}
+		if fields.Assignee != nil {
+			output.Assignee = fields.Assignee.DisplayName
+		}
+		if fields.Priority != nil {
+			output.Priority = fields.Priority.Name
+		}
+	}
+	return output
+}
+
+// NewSprintOutput converts a sprint the way FormatSprint renders it.
+func NewSprintOutput(sprint *models.SprintScheme) SprintOutput {
+	return SprintOutput{
+		ID:            sprint.ID,
+		Name:          sprint.Name,
+		State:         sprint.State,
+		StartDate:     formatSprintDate(sprint.StartDate),
+		EndDate:       formatSprintDate(sprint.EndDate),
+		CompleteDate:  formatSprintDate(sprint.CompleteDate),
+		OriginBoardID: sprint.OriginBoardID,
+		Goal:          sprint.Goal,
+	}
+}
+
+// formatSprintDate renders an unset sprint date as "".
+func formatSprintDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(time.RFC3339)
+}
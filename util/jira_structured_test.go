@@ -0,0 +1,70 @@
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func TestNewIssueOutput(t *testing.T) {
+	raw := `{
+		"id": "10001",
+		"key": "ABC-1",
+		"self": "https://example.atlassian.net/rest/api/3/issue/10001",
+		"fields": {
+			"summary": "Login fails",
+			"issuetype": {"name": "Bug"},
+			"status": {"name": "In Progress"},
+			"priority": {"name": "High"},
+			"assignee": {"accountId": "u1", "displayName": "Mia Krystof"},
+			"project": {"key": "ABC"},
+			"parent": {"key": "ABC-0", "fields": {"summary": "Auth epic", "status": {"name": "To Do"}}},
+			"labels": ["auth"],
+			"fixVersions": [{"name": "1.2"}],
+			"subtasks": [{"key": "ABC-2", "fields": {"summary": "Write test", "status": {"name": "Done"}}}],
+			"issuelinks": [{"id": "500", "type": {"inward": "is blocked by", "outward": "blocks"}, "outwardIssue": {"key": "ABC-3", "fields": {"summary": "Release", "status": {"name": "To Do"}}}}],
+			"comment": {"total": 4}
+		},
+		"transitions": [{"id": "31", "name": "Done", "to": {"name": "Done"}}]
+	}`
+	var issue models.IssueScheme
+	if err := json.Unmarshal([]byte(raw), &issue); err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewIssueOutput(&issue, CustomFieldValue{ID: "customfield_10016", Name: "Story Points", Value: "5"})
+	want := IssueOutput{
+		Key:          "ABC-1",
+		ID:           "10001",
+		URL:          "https://example.atlassian.net/rest/api/3/issue/10001",
+		Summary:      "Login fails",
+		Type:         "Bug",
+		Status:       "In Progress",
+		Priority:     "High",
+		Assignee:     &UserOutput{AccountID: "u1", DisplayName: "Mia Krystof"},
+		Project:      "ABC",
+		Parent:       &IssueRefOutput{Key: "ABC-0", Summary: "Auth epic", Status: "To Do"},
+		Labels:       []string{"auth"},
+		FixVersions:  []string{"1.2"},
+		Subtasks:     []IssueRefOutput{{Key: "ABC-2", Summary: "Write test", Status: "Done"}},
+		Links:        []IssueLinkOutput{{ID: "500", Relation: "blocks", Issue: IssueRefOutput{Key: "ABC-3", Summary: "Release", Status: "To Do"}}},
+		CommentCount: 4,
+		CustomFields: []CustomFieldOutput{{ID: "customfield_10016", Name: "Story Points", Value: "5"}},
+		Transitions:  []TransitionOutput{{ID: "31", Name: "Done", To: "Done"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewIssueOutput =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestNewSprintOutput(t *testing.T) {
+	start := time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)
+	got := NewSprintOutput(&models.SprintScheme{ID: 7, Name: "Sprint 7", State: "active", StartDate: start, OriginBoardID: 12})
+	want := SprintOutput{ID: 7, Name: "Sprint 7", State: "active", StartDate: "2026-10-05T09:00:00Z", OriginBoardID: 12}
+	if got != want {
+		t.Errorf("NewSprintOutput = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,335 @@
+package util
+
+import (
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// SearchFormat selects how a list of issues is rendered.
+type SearchFormat string
+
+const (
+	// SearchFormatFull renders each issue with RenderIssue, separated by
+	// "===" lines.
+	SearchFormatFull SearchFormat = "full"
+	// SearchFormatCompact renders one "Column: value | ..." line per issue.
+	SearchFormatCompact SearchFormat = "compact"
+	// SearchFormatMarkdownTable renders a markdown table, one row per issue.
+	SearchFormatMarkdownTable SearchFormat = "markdown_table"
+	// SearchFormatCSV renders CSV with a header row.
+	SearchFormatCSV SearchFormat = "csv"
+)
+
+// SearchFormatNames lists the formats, for parameter descriptions.
+const SearchFormatNames = "full, compact, markdown_table, csv"
+
+// ParseSearchFormat resolves a format name. An empty value is full.
+func ParseSearchFormat(value string) (SearchFormat, error) {
+	switch format := SearchFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return SearchFormatFull, nil
+	case SearchFormatFull, SearchFormatCompact, SearchFormatMarkdownTable, SearchFormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: use one of %s", value, SearchFormatNames)
+	}
+}
+
+// DefaultIssueColumns are the columns of the tabular formats when none are
+// chosen: the ones FormatJiraIssueCompact shows.
+const DefaultIssueColumns = "key,summary,status,assignee,priority"
+
+// IssueColumn is one column of a tabular issue list: a built-in column or a
+// custom field.
+type IssueColumn struct {
+	Name   string
+	Header string
+	// Fields are the Jira fields the column reads.
+	Fields []string
+
+	value         func(*models.IssueScheme) string
+	customFieldID string
+}
+
+type builtinColumn struct {
+	header string
+	fields []string
+	value  func(fields *models.IssueFieldsScheme) string
+}
+
+var builtinColumns = map[string]builtinColumn{
+	"summary": {"Summary", []string{"summary"}, func(f *models.IssueFieldsScheme) string { return f.Summary }},
+	"status": {"Status", []string{"status"}, func(f *models.IssueFieldsScheme) string {
+		if f.Status == nil {
+			return ""
+		}
+		return f.Status.Name
+	}},
+	"assignee": {"Assignee", []string{"assignee"}, func(f *models.IssueFieldsScheme) string {
+		if f.Assignee == nil {
+			return "Unassigned"
+		}
+		return f.Assignee.DisplayName
+	}},
+	"reporter": {"Reporter", []string{"reporter"}, func(f *models.IssueFieldsScheme) string {
+		if f.Reporter == nil {
+			return ""
+		}
+		return f.Reporter.DisplayName
+	}},
+	"priority": {"Priority", []string{"priority"}, func(f *models.IssueFieldsScheme) string {
+		if f.Priority == nil {
+			return ""
+		}
+		return f.Priority.Name
+	}},
+	"type": {"Type", []string{"issuetype"}, func(f *models.IssueFieldsScheme) string {
+		if f.IssueType == nil {
+			return ""
+		}
+		return f.IssueType.Name
+	}},
+	"resolution": {"Resolution", []string{"resolution"}, func(f *models.IssueFieldsScheme) string {
+		if f.Resolution == nil {
+			return ""
+		}
+		return f.Resolution.Name
+	}},
+	"created":  {"Created", []string{"created"}, func(f *models.IssueFieldsScheme) string { return f.Created }},
+	"updated":  {"Updated", []string{"updated"}, func(f *models.IssueFieldsScheme) string { return f.Updated }},
+	"resolved": {"Resolved", []string{"resolutiondate"}, func(f *models.IssueFieldsScheme) string { return f.Resolutiondate }},
+	"labels":   {"Labels", []string{"labels"}, func(f *models.IssueFieldsScheme) string { return strings.Join(f.Labels, ", ") }},
+	"components": {"Components", []string{"components"}, func(f *models.IssueFieldsScheme) string {
+		var names []string
+		for _, component := range f.Components {
+			names = append(names, component.Name)
+		}
+		return strings.Join(names, ", ")
+	}},
+	"fix_versions": {"Fix Versions", []string{"fixVersions"}, func(f *models.IssueFieldsScheme) string {
+		var names []string
+		for _, version := range f.FixVersions {
+			names = append(names, version.Name)
+		}
+		return strings.Join(names, ", ")
+	}},
+	"parent": {"Parent", []string{"parent"}, func(f *models.IssueFieldsScheme) string {
+		if f.Parent == nil {
+			return ""
+		}
+		return f.Parent.Key
+	}},
+	"project": {"Project", []string{"project"}, func(f *models.IssueFieldsScheme) string {
+		if f.Project == nil {
+			return ""
+		}
+		return f.Project.Key
+	}},
+}
+
+// builtinColumnNames is the order built-in columns are listed in errors.
+var builtinColumnNames = []string{"key", "summary", "status", "assignee", "reporter", "priority", "type", "resolution",
+	"created", "updated", "resolved", "labels", "components", "fix_versions", "parent", "project"}
+
+var customFieldIDPattern = regexp.MustCompile(`^customfield_\d+$`)
+
+// ParseIssueColumns resolves a comma-separated column list: built-in
+// columns, or custom fields by name or ID using names (field ID -> name).
+// An empty value is DefaultIssueColumns.
+func ParseIssueColumns(value string, names map[string]string) ([]IssueColumn, error) {
+	if strings.TrimSpace(value) == "" {
+		value = DefaultIssueColumns
+	}
+	var columns []IssueColumn
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		column, err := resolveIssueColumn(name, names)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns in %q", value)
+	}
+	return columns, nil
+}
+
+func resolveIssueColumn(name string, names map[string]string) (IssueColumn, error) {
+	key := strings.ToLower(name)
+	if key == "key" {
+		return IssueColumn{Name: key, Header: "Key", value: func(issue *models.IssueScheme) string { return issue.Key }}, nil
+	}
+	if builtin, ok := builtinColumns[key]; ok {
+		value := func(issue *models.IssueScheme) string {
+			if issue.Fields == nil {
+				return ""
+			}
+			return builtin.value(issue.Fields)
+		}
+		return IssueColumn{Name: key, Header: builtin.header, Fields: builtin.fields, value: value}, nil
+	}
+
+	if customFieldIDPattern.MatchString(key) {
+		header := names[key]
+		if header == "" {
+			header = key
+		}
+		return IssueColumn{Name: key, Header: header, Fields: []string{key}, customFieldID: key}, nil
+	}
+	var matches []string
+	for id, fieldName := range names {
+		if strings.HasPrefix(id, "customfield_") && strings.EqualFold(fieldName, name) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return IssueColumn{Name: name, Header: names[matches[0]], Fields: matches, customFieldID: matches[0]}, nil
+	case 0:
+		return IssueColumn{}, fmt.Errorf("unknown column %q: use %s, or a custom field name or ID", name, strings.Join(builtinColumnNames, ", "))
+	default:
+		return IssueColumn{}, fmt.Errorf("column %q matches several custom fields, use one of: %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// IssueColumnFields returns the Jira fields to request for columns.
+func IssueColumnFields(columns []IssueColumn) []string {
+	var fields []string
+	seen := map[string]bool{}
+	for _, column := range columns {
+		for _, field := range column.Fields {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// Value returns the column's value for issue. Custom fields are read from
+// customFields, as returned by ExtractCustomFields.
+func (c IssueColumn) Value(issue *models.IssueScheme, customFields []CustomFieldValue) string {
+	if c.value != nil {
+		return c.value(issue)
+	}
+	for _, field := range customFields {
+		if field.ID == c.customFieldID {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// FormatIssueTable renders issues in a compact, markdown_table or csv
+// format. Compact and markdown_table stop adding rows at maxChars (0 is
+// unlimited) and count the issues left out; csv is never cut, so that it
+// stays parseable.
+func FormatIssueTable(format SearchFormat, issues []*models.IssueScheme, columns []IssueColumn, customFields func(*models.IssueScheme) []CustomFieldValue, maxChars int) (string, error) {
+	rows := make([][]string, len(issues))
+	for i, issue := range issues {
+		values := customFields(issue)
+		rows[i] = make([]string, len(columns))
+		for j, column := range columns {
+			rows[i][j] = column.Value(issue, values)
+		}
+	}
+
+	switch format {
+	case SearchFormatCSV:
+		var sb strings.Builder
+		writer := csv.NewWriter(&sb)
+		headers := make([]string, len(columns))
+		for i, column := range columns {
+			headers[i] = csvCell(column.Header)
+		}
+		writer.Write(headers)
+		for _, row := range rows {
+			for j, value := range row {
+				row[j] = csvCell(value)
+			}
+		}
+		writer.WriteAll(rows)
+		if err := writer.Error(); err != nil {
+			return "", fmt.Errorf("failed to write csv: %v", err)
+		}
+		return sb.String(), nil
+
+	case SearchFormatCompact:
+		lines := make([]string, len(rows))
+		for i, row := range rows {
+			var parts []string
+			for j, value := range row {
+				if value != "" {
+					parts = append(parts, fmt.Sprintf("%s: %s", columns[j].Header, flattenCell(value)))
+				}
+			}
+			lines[i] = strings.Join(parts, " | ")
+		}
+		return fitLines("", lines, maxChars), nil
+
+	case SearchFormatMarkdownTable:
+		var header strings.Builder
+		header.WriteString("|")
+		for _, column := range columns {
+			header.WriteString(" " + markdownCell(column.Header) + " |")
+		}
+		header.WriteString("\n|")
+		header.WriteString(strings.Repeat(" --- |", len(columns)))
+		header.WriteString("\n")
+		lines := make([]string, len(rows))
+		for i, row := range rows {
+			cells := make([]string, len(row))
+			for j, value := range row {
+				cells[j] = markdownCell(value)
+			}
+			lines[i] = "| " + strings.Join(cells, " | ") + " |"
+		}
+		return fitLines(header.String(), lines, maxChars), nil
+	}
+	return "", fmt.Errorf("format %q is not tabular", format)
+}
+
+// fitLines joins header and lines, one per line, leaving out the lines that
+// would take the text past maxChars.
+func fitLines(header string, lines []string, maxChars int) string {
+	var sb strings.Builder
+	sb.WriteString(header)
+	used := utf8.RuneCountInString(header)
+	for i, line := range lines {
+		length := utf8.RuneCountInString(line) + 1
+		if maxChars > 0 && used+length > maxChars && i > 0 {
+			sb.WriteString(fmt.Sprintf("… %d more issues not shown (raise max_chars)\n", len(lines)-i))
+			break
+		}
+		sb.WriteString(line + "\n")
+		used += length
+	}
+	return sb.String()
+}
+
+// csvCell quotes a value that a spreadsheet would run as a formula, such as
+// a summary of "=HYPERLINK(...)", by prefixing a single quote.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func flattenCell(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func markdownCell(value string) string {
+	return strings.ReplaceAll(flattenCell(value), "|", `\|`)
+}
@@ -0,0 +1,128 @@
+package util
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func tableTestIssues(t *testing.T) []*models.IssueScheme {
+	t.Helper()
+	raw := `[
+		{"key": "ABC-1", "fields": {"summary": "Login | signup fails", "status": {"name": "In Progress"}, "priority": {"name": "High"}, "assignee": {"displayName": "Mia Krystof"}}},
+		{"key": "ABC-2", "fields": {"summary": "Update \"docs\"\nfor 2.0", "status": {"name": "To Do"}}}
+	]`
+	var issues []*models.IssueScheme
+	if err := json.Unmarshal([]byte(raw), &issues); err != nil {
+		t.Fatal(err)
+	}
+	return issues
+}
+
+var tableTestFieldNames = map[string]string{"customfield_10016": "Story Points", "customfield_10020": "Sprint", "customfield_10021": "Sprint"}
+
+func tableTestCustomFields(issue *models.IssueScheme) []CustomFieldValue {
+	if issue.Key == "ABC-1" {
+		return []CustomFieldValue{{ID: "customfield_10016", Name: "Story Points", Value: "5"}}
+	}
+	return nil
+}
+
+func TestParseIssueColumns(t *testing.T) {
+	columns, err := ParseIssueColumns("", nil)
+	if err != nil || len(columns) != 5 || columns[0].Header != "Key" || columns[4].Header != "Priority" {
+		t.Fatalf("default columns = %+v, %v", columns, err)
+	}
+
+	columns, err = ParseIssueColumns("Key, fix_versions, story points, customfield_10099", tableTestFieldNames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var headers []string
+	for _, column := range columns {
+		headers = append(headers, column.Header)
+	}
+	if strings.Join(headers, ",") != "Key,Fix Versions,Story Points,customfield_10099" {
+		t.Errorf("headers = %v", headers)
+	}
+	if fields := IssueColumnFields(columns); strings.Join(fields, ",") != "fixVersions,customfield_10016,customfield_10099" {
+		t.Errorf("fields = %v", fields)
+	}
+
+	for _, value := range []string{"summary,estimate", "sprint"} {
+		if _, err := ParseIssueColumns(value, tableTestFieldNames); err == nil {
+			t.Errorf("ParseIssueColumns(%q) should fail", value)
+		}
+	}
+}
+
+func TestParseSearchFormat(t *testing.T) {
+	if format, err := ParseSearchFormat(""); err != nil || format != SearchFormatFull {
+		t.Errorf("default format = %q, %v", format, err)
+	}
+	if format, err := ParseSearchFormat(" CSV "); err != nil || format != SearchFormatCSV {
+		t.Errorf("format = %q, %v", format, err)
+	}
+	if _, err := ParseSearchFormat("xml"); err == nil {
+		t.Error("xml should be rejected")
+	}
+}
+
+func TestFormatIssueTable(t *testing.T) {
+	issues := tableTestIssues(t)
+	columns, err := ParseIssueColumns("key,summary,status,assignee,Story Points", tableTestFieldNames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format SearchFormat
+		want   string
+	}{
+		{SearchFormatCompact, "Key: ABC-1 | Summary: Login | signup fails | Status: In Progress | Assignee: Mia Krystof | Story Points: 5\n" +
+			"Key: ABC-2 | Summary: Update \"docs\" for 2.0 | Status: To Do | Assignee: Unassigned\n"},
+		{SearchFormatMarkdownTable, "| Key | Summary | Status | Assignee | Story Points |\n| --- | --- | --- | --- | --- |\n" +
+			"| ABC-1 | Login \\| signup fails | In Progress | Mia Krystof | 5 |\n" +
+			"| ABC-2 | Update \"docs\" for 2.0 | To Do | Unassigned |  |\n"},
+		{SearchFormatCSV, "Key,Summary,Status,Assignee,Story Points\n" +
+			"ABC-1,Login | signup fails,In Progress,Mia Krystof,5\n" +
+			"ABC-2,\"Update \"\"docs\"\"\nfor 2.0\",To Do,Unassigned,\n"},
+	}
+	for _, tt := range tests {
+		got, err := FormatIssueTable(tt.format, issues, columns, tableTestCustomFields, 0)
+		if err != nil || got != tt.want {
+			t.Errorf("%s =\n%s\nwant\n%s (%v)", tt.format, got, tt.want, err)
+		}
+	}
+}
+
+func TestFormatIssueTable_CSVFormulas(t *testing.T) {
+	issues := tableTestIssues(t)
+	issues[0].Fields.Summary = `=HYPERLINK("http://evil.example","x")`
+	issues[1].Fields.Summary = "-1 day"
+	columns, _ := ParseIssueColumns("key,summary", nil)
+
+	got, _ := FormatIssueTable(SearchFormatCSV, issues, columns, tableTestCustomFields, 0)
+	want := "Key,Summary\nABC-1,\"'=HYPERLINK(\"\"http://evil.example\"\",\"\"x\"\")\"\nABC-2,'-1 day\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatIssueTable_MaxChars(t *testing.T) {
+	issues := tableTestIssues(t)
+	columns, _ := ParseIssueColumns("key,summary", nil)
+
+	got, _ := FormatIssueTable(SearchFormatCompact, issues, columns, tableTestCustomFields, 50)
+	want := "Key: ABC-1 | Summary: Login | signup fails\n… 1 more issues not shown (raise max_chars)\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	csv, _ := FormatIssueTable(SearchFormatCSV, issues, columns, tableTestCustomFields, 10)
+	if strings.Count(csv, "ABC-") != 2 {
+		t.Errorf("csv must never be cut, got %q", csv)
+	}
+}
@@ -0,0 +1,173 @@
+package util
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	gutil "github.com/yuin/goldmark/util"
+)
+
+// adfSyntax extends goldmark with the markdown conventions for ADF nodes
+// that have no CommonMark equivalent. RenderADF writes the same forms:
+//
+//	:::info            panel (info, note, tip, warning, error, success)
+//	...
+//	:::
+//
+//	<details>          expand
+//	<summary>Title</summary>
+//	...
+//	</details>
+//
+//	@[Name](accountId) mention
+type adfSyntax struct{}
+
+func (adfSyntax) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			gutil.Prioritized(panelParser{}, 750),
+			// Ahead of the HTML block parser, which would take <details>.
+			gutil.Prioritized(detailsParser{}, 850),
+		),
+		parser.WithInlineParsers(gutil.Prioritized(mentionParser{}, 150)),
+	)
+}
+
+// panelTypes are the ADF panel types.
+var panelTypes = map[string]bool{"info": true, "note": true, "tip": true, "warning": true, "error": true, "success": true}
+
+var kindPanel = ast.NewNodeKind("Panel")
+
+// panelNode is a ":::type" block.
+type panelNode struct {
+	ast.BaseBlock
+	PanelType string
+}
+
+func (n *panelNode) Kind() ast.NodeKind { return kindPanel }
+
+func (n *panelNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"PanelType": n.PanelType}, nil)
+}
+
+var panelOpenRe = regexp.MustCompile(`^:::\s*(\w+)\s*$`)
+
+type panelParser struct{}
+
+func (panelParser) Trigger() []byte { return []byte{':'} }
+
+func (panelParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	m := panelOpenRe.FindSubmatch(bytes.TrimSpace(line))
+	if m == nil || !panelTypes[strings.ToLower(string(m[1]))] {
+		return nil, parser.NoChildren
+	}
+	reader.AdvanceToEOL()
+	return &panelNode{PanelType: strings.ToLower(string(m[1]))}, parser.HasChildren
+}
+
+func (panelParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	if string(bytes.TrimSpace(line)) == ":::" {
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+func (panelParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (panelParser) CanInterruptParagraph() bool { return true }
+
+func (panelParser) CanAcceptIndentedLine() bool { return false }
+
+var kindDetails = ast.NewNodeKind("Details")
+
+// detailsNode is a <details> block; Title comes from its <summary>.
+type detailsNode struct {
+	ast.BaseBlock
+	Title string
+}
+
+func (n *detailsNode) Kind() ast.NodeKind { return kindDetails }
+
+func (n *detailsNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Title": n.Title}, nil)
+}
+
+var (
+	detailsOpenRe = regexp.MustCompile(`(?i)^<details>\s*(?:<summary>(.*?)</summary>)?$`)
+	summaryRe     = regexp.MustCompile(`(?i)^<summary>(.*?)</summary>$`)
+)
+
+type detailsParser struct{}
+
+func (detailsParser) Trigger() []byte { return []byte{'<'} }
+
+func (detailsParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	m := detailsOpenRe.FindSubmatch(bytes.TrimSpace(line))
+	if m == nil {
+		return nil, parser.NoChildren
+	}
+	reader.AdvanceToEOL()
+	return &detailsNode{Title: strings.TrimSpace(string(m[1]))}, parser.HasChildren
+}
+
+func (detailsParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	trimmed := bytes.TrimSpace(line)
+	if strings.EqualFold(string(trimmed), "</details>") {
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+	details := node.(*detailsNode)
+	if m := summaryRe.FindSubmatch(trimmed); m != nil && details.Title == "" && !details.HasChildren() {
+		details.Title = strings.TrimSpace(string(m[1]))
+		reader.AdvanceToEOL()
+		return parser.Continue | parser.NoChildren
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+func (detailsParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (detailsParser) CanInterruptParagraph() bool { return true }
+
+func (detailsParser) CanAcceptIndentedLine() bool { return false }
+
+var kindMention = ast.NewNodeKind("Mention")
+
+// mentionNode is an "@[Name](accountId)" mention.
+type mentionNode struct {
+	ast.BaseInline
+	Name      string
+	AccountID string
+}
+
+func (n *mentionNode) Kind() ast.NodeKind { return kindMention }
+
+func (n *mentionNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Name": n.Name, "AccountID": n.AccountID}, nil)
+}
+
+var mentionRe = regexp.MustCompile(`^@\[([^\]\n]+)\]\(([^)\s]+)\)`)
+
+type mentionParser struct{}
+
+func (mentionParser) Trigger() []byte { return []byte{'@'} }
+
+func (mentionParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	m := mentionRe.FindSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	block.Advance(len(m[0]))
+	return &mentionNode{Name: string(m[1]), AccountID: string(m[2])}
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/yuin/goldmark"
@@ -10,10 +11,13 @@ import (
 	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
+	gutil "github.com/yuin/goldmark/util"
 )
 
 // MarkdownToADF converts a markdown string to an Atlassian Document Format (ADF) CommentNodeScheme.
-// Plain text without markdown syntax is wrapped in a paragraph node.
+// Plain text without markdown syntax is wrapped in a paragraph node. Besides
+// CommonMark it reads GFM tables, strikethrough and task lists, and the
+// panel, expand and mention forms described at adfSyntax.
 func MarkdownToADF(input string) *models.CommentNodeScheme {
 	doc := &models.CommentNodeScheme{
 		Version: 1,
@@ -27,7 +31,7 @@ func MarkdownToADF(input string) *models.CommentNodeScheme {
 	source := []byte(input)
 
 	md := goldmark.New(
-		goldmark.WithExtensions(extension.Strikethrough),
+		goldmark.WithExtensions(extension.Strikethrough, extension.Table, extension.TaskList, adfSyntax{}),
 		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
 	)
 
@@ -35,10 +39,23 @@ func MarkdownToADF(input string) *models.CommentNodeScheme {
 	tree := md.Parser().Parse(reader)
 
 	walkChildren(tree, doc, source)
+	assignLocalIDs(doc, new(int))
 
 	return doc
 }
 
+// assignLocalIDs numbers the task lists and items of doc, which ADF
+// requires to carry a localId unique within the document.
+func assignLocalIDs(node *models.CommentNodeScheme, next *int) {
+	if node.Type == "taskList" || node.Type == "taskItem" {
+		*next++
+		node.Attrs["localId"] = fmt.Sprintf("task-%d", *next)
+	}
+	for _, child := range node.Content {
+		assignLocalIDs(child, next)
+	}
+}
+
 func walkChildren(parent ast.Node, adfParent *models.CommentNodeScheme, source []byte) {
 	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
 		nodes := convertNode(child, source)
@@ -51,6 +68,11 @@ func walkChildren(parent ast.Node, adfParent *models.CommentNodeScheme, source [
 func convertNode(n ast.Node, source []byte) []*models.CommentNodeScheme {
 	switch node := n.(type) {
 	case *ast.Paragraph:
+		// A paragraph holding only an image is an image block; ADF has no
+		// inline images.
+		if image, ok := node.FirstChild().(*ast.Image); ok && node.ChildCount() == 1 {
+			return []*models.CommentNodeScheme{ExternalMediaNode(string(image.Destination), string(image.Text(source)))}
+		}
 		p := &models.CommentNodeScheme{Type: "paragraph"}
 		walkInline(node, p, source, nil)
 		return []*models.CommentNodeScheme{p}
@@ -111,6 +133,9 @@ func convertNode(n ast.Node, source []byte) []*models.CommentNodeScheme {
 		return []*models.CommentNodeScheme{bq}
 
 	case *ast.List:
+		if isTaskList(node) {
+			return []*models.CommentNodeScheme{convertTaskList(node, source)}
+		}
 		listType := "bulletList"
 		if node.IsOrdered() {
 			listType = "orderedList"
@@ -135,6 +160,25 @@ func convertNode(n ast.Node, source []byte) []*models.CommentNodeScheme {
 		walkInline(node, p, source, nil)
 		return []*models.CommentNodeScheme{p}
 
+	case *east.Table:
+		return []*models.CommentNodeScheme{convertTable(node, source)}
+
+	case *panelNode:
+		panel := &models.CommentNodeScheme{
+			Type:  "panel",
+			Attrs: map[string]interface{}{"panelType": node.PanelType},
+		}
+		walkChildren(node, panel, source)
+		return []*models.CommentNodeScheme{panel}
+
+	case *detailsNode:
+		expand := &models.CommentNodeScheme{
+			Type:  "expand",
+			Attrs: map[string]interface{}{"title": node.Title},
+		}
+		walkChildren(node, expand, source)
+		return []*models.CommentNodeScheme{expand}
+
 	default:
 		// For unknown block nodes, try to process children
 		var result []*models.CommentNodeScheme
@@ -145,6 +189,61 @@ func convertNode(n ast.Node, source []byte) []*models.CommentNodeScheme {
 	}
 }
 
+// convertTable converts a GFM table. The header row becomes tableHeader
+// cells; every cell holds one paragraph.
+func convertTable(node *east.Table, source []byte) *models.CommentNodeScheme {
+	table := &models.CommentNodeScheme{Type: "table"}
+	for row := node.FirstChild(); row != nil; row = row.NextSibling() {
+		cellType := "tableCell"
+		if _, ok := row.(*east.TableHeader); ok {
+			cellType = "tableHeader"
+		}
+		tableRow := &models.CommentNodeScheme{Type: "tableRow"}
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			p := &models.CommentNodeScheme{Type: "paragraph"}
+			walkInline(cell, p, source, nil)
+			tableRow.AppendNode(&models.CommentNodeScheme{Type: cellType, Content: []*models.CommentNodeScheme{p}})
+		}
+		table.AppendNode(tableRow)
+	}
+	return table
+}
+
+// isTaskList reports whether a list's first item starts with a checkbox.
+func isTaskList(list *ast.List) bool {
+	item := list.FirstChild()
+	if item == nil || item.FirstChild() == nil {
+		return false
+	}
+	_, ok := item.FirstChild().FirstChild().(*east.TaskCheckBox)
+	return ok
+}
+
+// convertTaskList converts a list of "- [ ]" items. ADF task items hold
+// inline content only, and nested lists become task lists beside the item
+// they were nested in, as Jira's editor makes them.
+func convertTaskList(list *ast.List, source []byte) *models.CommentNodeScheme {
+	tasks := &models.CommentNodeScheme{Type: "taskList", Attrs: map[string]interface{}{}}
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		task := &models.CommentNodeScheme{Type: "taskItem", Attrs: map[string]interface{}{"state": "TODO"}}
+		tasks.AppendNode(task)
+		for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+			if nested, ok := child.(*ast.List); ok {
+				tasks.AppendNode(convertTaskList(nested, source))
+				continue
+			}
+			if box, ok := child.FirstChild().(*east.TaskCheckBox); ok && box.IsChecked {
+				task.Attrs["state"] = "DONE"
+			}
+			if len(task.Content) > 0 {
+				task.AppendNode(&models.CommentNodeScheme{Type: "hardBreak"})
+			}
+			walkInline(child, task, source, nil)
+		}
+	}
+	return tasks
+}
+
 func walkInline(parent ast.Node, adfParent *models.CommentNodeScheme, source []byte, marks []*models.MarkScheme) {
 	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
 		convertInline(child, adfParent, source, marks)
@@ -154,7 +253,9 @@ func walkInline(parent ast.Node, adfParent *models.CommentNodeScheme, source []b
 func convertInline(n ast.Node, adfParent *models.CommentNodeScheme, source []byte, marks []*models.MarkScheme) {
 	switch node := n.(type) {
 	case *ast.Text:
-		t := string(node.Segment.Value(source))
+		// Backslash escapes ("\*", and "\|" in table cells) stand for the
+		// character itself.
+		t := string(gutil.UnescapePunctuations(node.Segment.Value(source)))
 		if t != "" {
 			textNode := &models.CommentNodeScheme{
 				Type: "text",
@@ -237,6 +338,15 @@ func convertInline(n ast.Node, adfParent *models.CommentNodeScheme, source []byt
 		newMarks := append(copyMarks(marks), &models.MarkScheme{Type: "strike"})
 		walkInline(node, adfParent, source, newMarks)
 
+	case *mentionNode:
+		adfParent.AppendNode(&models.CommentNodeScheme{
+			Type:  "mention",
+			Attrs: map[string]interface{}{"id": node.AccountID, "text": "@" + node.Name},
+		})
+
+	case *east.TaskCheckBox:
+		// The state is read by convertTaskList.
+
 	default:
 		// For unknown inline nodes, try to walk children
 		walkInline(n, adfParent, source, marks)
@@ -251,3 +361,17 @@ func copyMarks(marks []*models.MarkScheme) []*models.MarkScheme {
 	copy(result, marks)
 	return result
 }
+
+// ExternalMediaNode returns a mediaSingle block showing the image at url,
+// with alt as its alternative text.
+func ExternalMediaNode(url, alt string) *models.CommentNodeScheme {
+	media := &models.CommentNodeScheme{Type: "media", Attrs: map[string]interface{}{"type": "external", "url": url}}
+	if alt != "" {
+		media.Attrs["alt"] = alt
+	}
+	return &models.CommentNodeScheme{
+		Type:    "mediaSingle",
+		Attrs:   map[string]interface{}{"layout": "center"},
+		Content: []*models.CommentNodeScheme{media},
+	}
+}
@@ -0,0 +1,253 @@
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TimesheetFormat selects how a timesheet is rendered.
+type TimesheetFormat string
+
+const (
+	// TimesheetFormatMarkdownTable renders a user × day table of hours
+	// followed by the user × day × issue breakdown.
+	TimesheetFormatMarkdownTable TimesheetFormat = "markdown_table"
+	// TimesheetFormatCSV renders one row per user, day and issue.
+	TimesheetFormatCSV TimesheetFormat = "csv"
+	// TimesheetFormatJSON renders the Timesheet itself.
+	TimesheetFormatJSON TimesheetFormat = "json"
+)
+
+// TimesheetFormatNames lists the formats, for parameter descriptions.
+const TimesheetFormatNames = "markdown_table, csv, json"
+
+// ParseTimesheetFormat resolves a format name. An empty value is
+// markdown_table.
+func ParseTimesheetFormat(value string) (TimesheetFormat, error) {
+	switch format := TimesheetFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return TimesheetFormatMarkdownTable, nil
+	case TimesheetFormatMarkdownTable, TimesheetFormatCSV, TimesheetFormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: use one of %s", value, TimesheetFormatNames)
+	}
+}
+
+// TimesheetWorklog is one worklog as a timesheet needs it. Day is the date
+// the work started, as the author logged it (2024-05-01).
+type TimesheetWorklog struct {
+	Author          string
+	AuthorAccountID string
+	Day             string
+	IssueKey        string
+	Summary         string
+	Seconds         int
+}
+
+// TimesheetEntry is the time one user logged on one issue on one day.
+type TimesheetEntry struct {
+	Author          string  `json:"author"`
+	AuthorAccountID string  `json:"author_account_id,omitempty"`
+	Day             string  `json:"day"`
+	IssueKey        string  `json:"issue_key"`
+	Summary         string  `json:"summary,omitempty"`
+	Seconds         int     `json:"seconds"`
+	Hours           float64 `json:"hours"`
+}
+
+// TimesheetTotal is the time logged by one user, on one day or on one
+// issue.
+type TimesheetTotal struct {
+	Name    string  `json:"name"`
+	Seconds int     `json:"seconds"`
+	Hours   float64 `json:"hours"`
+}
+
+// Timesheet is worklogs summed by user, day and issue, with totals along
+// each of them.
+type Timesheet struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Worklogs     int              `json:"worklogs"`
+	TotalSeconds int              `json:"total_seconds"`
+	TotalHours   float64          `json:"total_hours"`
+	Entries      []TimesheetEntry `json:"entries"`
+	ByUser       []TimesheetTotal `json:"by_user"`
+	ByDay        []TimesheetTotal `json:"by_day"`
+	ByIssue      []TimesheetTotal `json:"by_issue"`
+}
+
+// BuildTimesheet sums worklogs by user, day and issue. Entries are sorted by
+// user, day and issue key; user and issue totals by time logged, most
+// first; day totals by date.
+func BuildTimesheet(from, to string, worklogs []TimesheetWorklog) *Timesheet {
+	sheet := &Timesheet{From: from, To: to, Worklogs: len(worklogs), Entries: []TimesheetEntry{}}
+
+	type entryKey struct{ author, day, issue string }
+	entries := map[entryKey]*TimesheetEntry{}
+	byUser, byDay, byIssue := map[string]int{}, map[string]int{}, map[string]int{}
+	for _, worklog := range worklogs {
+		key := entryKey{worklog.Author, worklog.Day, worklog.IssueKey}
+		entry := entries[key]
+		if entry == nil {
+			entry = &TimesheetEntry{Author: worklog.Author, AuthorAccountID: worklog.AuthorAccountID, Day: worklog.Day, IssueKey: worklog.IssueKey, Summary: worklog.Summary}
+			entries[key] = entry
+		}
+		entry.Seconds += worklog.Seconds
+		byUser[worklog.Author] += worklog.Seconds
+		byDay[worklog.Day] += worklog.Seconds
+		byIssue[worklog.IssueKey] += worklog.Seconds
+		sheet.TotalSeconds += worklog.Seconds
+	}
+	sheet.TotalHours = secondsToHours(sheet.TotalSeconds)
+
+	for _, entry := range entries {
+		entry.Hours = secondsToHours(entry.Seconds)
+		sheet.Entries = append(sheet.Entries, *entry)
+	}
+	sort.Slice(sheet.Entries, func(i, j int) bool {
+		a, b := sheet.Entries[i], sheet.Entries[j]
+		if a.Author != b.Author {
+			return a.Author < b.Author
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return compareIssueKeys(a.IssueKey, b.IssueKey)
+	})
+
+	sheet.ByUser = timesheetTotals(byUser, nil)
+	sheet.ByDay = timesheetTotals(byDay, func(a, b string) bool { return a < b })
+	sheet.ByIssue = timesheetTotals(byIssue, nil)
+	return sheet
+}
+
+// timesheetTotals turns a name → seconds map into totals, ordered by less,
+// or by seconds (most first) and then name when less is nil.
+func timesheetTotals(seconds map[string]int, less func(a, b string) bool) []TimesheetTotal {
+	totals := make([]TimesheetTotal, 0, len(seconds))
+	for name, s := range seconds {
+		totals = append(totals, TimesheetTotal{Name: name, Seconds: s, Hours: secondsToHours(s)})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if less != nil {
+			return less(totals[i].Name, totals[j].Name)
+		}
+		if totals[i].Seconds != totals[j].Seconds {
+			return totals[i].Seconds > totals[j].Seconds
+		}
+		return totals[i].Name < totals[j].Name
+	})
+	return totals
+}
+
+// compareIssueKeys orders PROJ-9 before PROJ-10.
+func compareIssueKeys(a, b string) bool {
+	aProject, aNumber, _ := strings.Cut(a, "-")
+	bProject, bNumber, _ := strings.Cut(b, "-")
+	if aProject != bProject {
+		return aProject < bProject
+	}
+	an, aErr := strconv.Atoi(aNumber)
+	bn, bErr := strconv.Atoi(bNumber)
+	if aErr != nil || bErr != nil {
+		return a < b
+	}
+	return an < bn
+}
+
+// secondsToHours converts seconds to hours, rounded to two decimals.
+func secondsToHours(seconds int) float64 {
+	hours, _ := strconv.ParseFloat(strconv.FormatFloat(float64(seconds)/3600, 'f', 2, 64), 64)
+	return hours
+}
+
+func formatHours(seconds int) string {
+	return strconv.FormatFloat(secondsToHours(seconds), 'f', -1, 64)
+}
+
+// FormatTimesheet renders sheet in format.
+func FormatTimesheet(sheet *Timesheet, format TimesheetFormat) (string, error) {
+	switch format {
+	case TimesheetFormatJSON:
+		data, err := json.MarshalIndent(sheet, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to write json: %v", err)
+		}
+		return string(data), nil
+
+	case TimesheetFormatCSV:
+		var sb strings.Builder
+		writer := csv.NewWriter(&sb)
+		writer.Write([]string{"User", "Account ID", "Date", "Issue", "Summary", "Seconds", "Hours"})
+		for _, entry := range sheet.Entries {
+			writer.Write([]string{entry.Author, entry.AuthorAccountID, entry.Day, entry.IssueKey, entry.Summary, strconv.Itoa(entry.Seconds), formatHours(entry.Seconds)})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return "", fmt.Errorf("failed to write csv: %v", err)
+		}
+		return sb.String(), nil
+
+	case TimesheetFormatMarkdownTable:
+		return markdownTimesheet(sheet), nil
+	}
+	return "", fmt.Errorf("unknown format %q", format)
+}
+
+// markdownTimesheet renders hours per user and day, then the breakdown by
+// issue.
+func markdownTimesheet(sheet *Timesheet) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Timesheet %s to %s: %s hours from %d worklogs\n", sheet.From, sheet.To, formatHours(sheet.TotalSeconds), sheet.Worklogs)
+	if len(sheet.Entries) == 0 {
+		sb.WriteString("\nNo time logged.\n")
+		return sb.String()
+	}
+
+	perUserDay := map[string]map[string]int{}
+	for _, entry := range sheet.Entries {
+		if perUserDay[entry.Author] == nil {
+			perUserDay[entry.Author] = map[string]int{}
+		}
+		perUserDay[entry.Author][entry.Day] += entry.Seconds
+	}
+
+	sb.WriteString("\n| User |")
+	for _, day := range sheet.ByDay {
+		sb.WriteString(" " + day.Name + " |")
+	}
+	sb.WriteString(" Total |\n|" + strings.Repeat(" --- |", len(sheet.ByDay)+2) + "\n")
+	for _, user := range sheet.ByUser {
+		sb.WriteString("| " + markdownCell(user.Name) + " |")
+		for _, day := range sheet.ByDay {
+			cell := ""
+			if seconds := perUserDay[user.Name][day.Name]; seconds > 0 {
+				cell = formatHours(seconds)
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString(" " + formatHours(user.Seconds) + " |\n")
+	}
+	sb.WriteString("| **Total** |")
+	for _, day := range sheet.ByDay {
+		sb.WriteString(" " + formatHours(day.Seconds) + " |")
+	}
+	sb.WriteString(" **" + formatHours(sheet.TotalSeconds) + "** |\n")
+
+	sb.WriteString("\n| User | Date | Issue | Summary | Hours |\n| --- | --- | --- | --- | --- |\n")
+	for _, entry := range sheet.Entries {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", markdownCell(entry.Author), entry.Day, entry.IssueKey, markdownCell(entry.Summary), formatHours(entry.Seconds))
+	}
+
+	sb.WriteString("\n| Issue | Hours |\n| --- | --- |\n")
+	for _, issue := range sheet.ByIssue {
+		fmt.Fprintf(&sb, "| %s | %s |\n", issue.Name, formatHours(issue.Seconds))
+	}
+	return sb.String()
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func sampleTimesheet() *Timesheet {
+	return BuildTimesheet("2024-05-06", "2024-05-07", []TimesheetWorklog{
+		{Author: "Bob", Day: "2024-05-06", IssueKey: "PROJ-10", Summary: "Login | SSO", Seconds: 3600},
+		{Author: "Ann", AuthorAccountID: "a1", Day: "2024-05-07", IssueKey: "PROJ-9", Summary: "Search", Seconds: 5400},
+		{Author: "Ann", AuthorAccountID: "a1", Day: "2024-05-06", IssueKey: "PROJ-10", Summary: "Login | SSO", Seconds: 1800},
+		{Author: "Ann", AuthorAccountID: "a1", Day: "2024-05-06", IssueKey: "PROJ-10", Summary: "Login | SSO", Seconds: 1800},
+	})
+}
+
+func TestBuildTimesheet(t *testing.T) {
+	sheet := sampleTimesheet()
+	if sheet.Worklogs != 4 || sheet.TotalSeconds != 12600 || sheet.TotalHours != 3.5 {
+		t.Errorf("totals = %d worklogs, %d s, %v h", sheet.Worklogs, sheet.TotalSeconds, sheet.TotalHours)
+	}
+	var entries []string
+	for _, entry := range sheet.Entries {
+		entries = append(entries, entry.Author+" "+entry.Day+" "+entry.IssueKey+" "+formatHours(entry.Seconds))
+	}
+	want := "Ann 2024-05-06 PROJ-10 1,Ann 2024-05-07 PROJ-9 1.5,Bob 2024-05-06 PROJ-10 1"
+	if got := strings.Join(entries, ","); got != want {
+		t.Errorf("entries = %s, want %s", got, want)
+	}
+	if sheet.ByUser[0].Name != "Ann" || sheet.ByUser[0].Hours != 2.5 {
+		t.Errorf("by user = %+v", sheet.ByUser)
+	}
+	if sheet.ByDay[0].Name != "2024-05-06" || sheet.ByDay[0].Hours != 2 {
+		t.Errorf("by day = %+v", sheet.ByDay)
+	}
+	if sheet.ByIssue[0].Name != "PROJ-10" || sheet.ByIssue[0].Seconds != 7200 {
+		t.Errorf("by issue = %+v", sheet.ByIssue)
+	}
+}
+
+func TestFormatTimesheet(t *testing.T) {
+	sheet := sampleTimesheet()
+
+	table, err := FormatTimesheet(sheet, TimesheetFormatMarkdownTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| User | 2024-05-06 | 2024-05-07 | Total |",
+		"| Ann | 1 | 1.5 | 2.5 |",
+		"| Bob | 1 |  | 1 |",
+		"| **Total** | 2 | 1.5 | **3.5** |",
+		`| Ann | 2024-05-06 | PROJ-10 | Login \| SSO | 1 |`,
+	} {
+		if !strings.Contains(table, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, table)
+		}
+	}
+
+	csv, err := FormatTimesheet(sheet, TimesheetFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv), "\n")
+	if len(lines) != 4 || lines[0] != "User,Account ID,Date,Issue,Summary,Seconds,Hours" || lines[1] != "Ann,a1,2024-05-06,PROJ-10,Login | SSO,3600,1" {
+		t.Errorf("csv = %q", csv)
+	}
+
+	raw, err := FormatTimesheet(sheet, TimesheetFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Timesheet
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil || decoded.TotalSeconds != 12600 {
+		t.Errorf("json = %s (%v)", raw, err)
+	}
+
+	empty, _ := FormatTimesheet(BuildTimesheet("2024-05-06", "2024-05-06", nil), TimesheetFormatMarkdownTable)
+	if !strings.Contains(empty, "No time logged.") {
+		t.Errorf("empty timesheet = %q", empty)
+	}
+	if _, err := ParseTimesheetFormat("xlsx"); err == nil {
+		t.Error("an unknown format should be refused")
+	}
+}
@@ -0,0 +1,442 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// ADFToWiki converts an Atlassian Document Format tree into Jira wiki markup,
+// the rich-text format used by Jira Server / Data Center (REST API v2).
+// Panels become {info}-style macros and task items "* [x]" bullets.
+func ADFToWiki(node *models.CommentNodeScheme) string {
+	if node == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	renderWikiNode(node, &sb, "")
+	return strings.TrimSpace(sb.String())
+}
+
+// renderWikiNode recursively renders an ADF node as wiki markup. listPrefix
+// accumulates the "*"/"#" markers of enclosing lists so nested items come out
+// as "**" or "#*" the way Jira expects.
+func renderWikiNode(node *models.CommentNodeScheme, sb *strings.Builder, listPrefix string) {
+	if node == nil {
+		return
+	}
+
+	switch node.Type {
+	case "doc":
+		for _, child := range node.Content {
+			renderWikiNode(child, sb, listPrefix)
+		}
+
+	case "paragraph":
+		renderWikiInline(node, sb)
+		sb.WriteString("\n\n")
+
+	case "heading":
+		level := 1
+		if lvl, ok := node.Attrs["level"].(float64); ok {
+			level = int(lvl)
+		}
+		fmt.Fprintf(sb, "h%d. ", level)
+		renderWikiInline(node, sb)
+		sb.WriteString("\n\n")
+
+	case "bulletList", "orderedList":
+		marker := "*"
+		if node.Type == "orderedList" {
+			marker = "#"
+		}
+		for _, item := range node.Content {
+			renderWikiNode(item, sb, listPrefix+marker)
+		}
+		if listPrefix == "" {
+			sb.WriteString("\n")
+		}
+
+	case "listItem":
+		sb.WriteString(listPrefix + " ")
+		for i, child := range node.Content {
+			switch child.Type {
+			case "bulletList", "orderedList":
+				renderWikiNode(child, sb, listPrefix)
+			default:
+				if i > 0 {
+					sb.WriteString(listPrefix + " ")
+				}
+				renderWikiInline(child, sb)
+				sb.WriteString("\n")
+			}
+		}
+
+	case "codeBlock":
+		if lang, ok := node.Attrs["language"].(string); ok && lang != "" {
+			sb.WriteString("{code:" + lang + "}\n")
+		} else {
+			sb.WriteString("{code}\n")
+		}
+		var code strings.Builder
+		for _, child := range node.Content {
+			code.WriteString(child.Text)
+		}
+		sb.WriteString(strings.TrimSuffix(code.String(), "\n"))
+		sb.WriteString("\n{code}\n\n")
+
+	case "blockquote":
+		var inner strings.Builder
+		for _, child := range node.Content {
+			renderWikiNode(child, &inner, listPrefix)
+		}
+		sb.WriteString("{quote}\n" + strings.TrimSpace(inner.String()) + "\n{quote}\n\n")
+
+	case "rule":
+		sb.WriteString("----\n\n")
+
+	case "table":
+		for _, row := range node.Content {
+			renderWikiNode(row, sb, listPrefix)
+		}
+		sb.WriteString("\n")
+
+	case "tableRow":
+		sep := "|"
+		for _, cell := range node.Content {
+			if cell.Type == "tableHeader" {
+				sep = "||"
+			}
+			sb.WriteString(sep)
+			var inner strings.Builder
+			for _, child := range cell.Content {
+				renderWikiInline(child, &inner)
+				inner.WriteString(" ")
+			}
+			sb.WriteString(strings.TrimSpace(inner.String()))
+		}
+		sb.WriteString(sep + "\n")
+
+	case "taskList":
+		for _, item := range node.Content {
+			if item.Type == "taskList" {
+				renderWikiNode(item, sb, listPrefix+"*")
+				continue
+			}
+			box := "[ ]"
+			if state, _ := item.Attrs["state"].(string); state == "DONE" {
+				box = "[x]"
+			}
+			sb.WriteString(listPrefix + "* " + box + " ")
+			renderWikiInline(item, sb)
+			sb.WriteString("\n")
+		}
+		if listPrefix == "" {
+			sb.WriteString("\n")
+		}
+
+	case "panel":
+		panelType, _ := node.Attrs["panelType"].(string)
+		macro := wikiPanelMacros[panelType]
+		if macro == "" {
+			macro = "info"
+		}
+		var inner strings.Builder
+		for _, child := range node.Content {
+			renderWikiNode(child, &inner, "")
+		}
+		sb.WriteString("{" + macro + "}\n" + strings.TrimSpace(inner.String()) + "\n{" + macro + "}\n\n")
+
+	case "expand", "nestedExpand":
+		// Jira wiki markup has no expand macro; keep the title as a bold
+		// line above the content.
+		if title, _ := node.Attrs["title"].(string); title != "" {
+			sb.WriteString("*" + title + "*\n\n")
+		}
+		for _, child := range node.Content {
+			renderWikiNode(child, sb, "")
+		}
+
+	case "mediaSingle", "mediaGroup", "media":
+		// Media references point at Cloud media-services IDs that do not
+		// exist on Server/DC, so only external images are emitted.
+		if url, _ := node.Attrs["url"].(string); url != "" {
+			sb.WriteString("!" + url + "!\n\n")
+		}
+		for _, child := range node.Content {
+			renderWikiNode(child, sb, listPrefix)
+		}
+
+	default:
+		// Inline nodes at block level (or unknown blocks) — render children.
+		if len(node.Content) > 0 {
+			for _, child := range node.Content {
+				renderWikiNode(child, sb, listPrefix)
+			}
+			return
+		}
+		renderWikiInlineNode(node, sb)
+	}
+}
+
+func renderWikiInline(node *models.CommentNodeScheme, sb *strings.Builder) {
+	for _, child := range node.Content {
+		renderWikiInlineNode(child, sb)
+	}
+}
+
+func renderWikiInlineNode(node *models.CommentNodeScheme, sb *strings.Builder) {
+	switch node.Type {
+	case "text":
+		text := node.Text
+		var href string
+		for _, mark := range node.Marks {
+			switch mark.Type {
+			case "strong":
+				text = "*" + text + "*"
+			case "em":
+				text = "_" + text + "_"
+			case "code":
+				text = "{{" + text + "}}"
+			case "strike":
+				text = "-" + text + "-"
+			case "underline":
+				text = "+" + text + "+"
+			case "link":
+				href, _ = mark.Attrs["href"].(string)
+			}
+		}
+		if href != "" {
+			text = "[" + text + "|" + href + "]"
+		}
+		sb.WriteString(text)
+
+	case "hardBreak":
+		sb.WriteString("\n")
+
+	case "mention":
+		if id, ok := node.Attrs["id"].(string); ok && id != "" {
+			sb.WriteString("[~" + id + "]")
+		} else if text, ok := node.Attrs["text"].(string); ok {
+			sb.WriteString(text)
+		}
+
+	case "emoji":
+		if shortName, ok := node.Attrs["shortName"].(string); ok {
+			sb.WriteString(shortName)
+		}
+
+	case "inlineCard":
+		if url, ok := node.Attrs["url"].(string); ok {
+			sb.WriteString("[" + url + "]")
+		}
+
+	default:
+		renderWikiInline(node, sb)
+	}
+}
+
+// wikiPanelMacros maps ADF panel types to the wiki macros that draw them;
+// error and success have no macro of their own.
+var wikiPanelMacros = map[string]string{
+	"info":    "info",
+	"note":    "note",
+	"tip":     "tip",
+	"success": "tip",
+	"warning": "warning",
+	"error":   "warning",
+}
+
+var (
+	wikiHeadingRe   = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	wikiListRe      = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+	wikiCodeStartRe = regexp.MustCompile(`^\{code(?::([^}|]+))?(?:\|[^}]*)?\}\s*$`)
+	wikiMonospaceRe = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiLinkRe      = regexp.MustCompile(`\[([^\[\]|]+)\|([^\[\]]+)\]`)
+	wikiMentionRe   = regexp.MustCompile(`\[~(?:accountid:)?([^\]]+)\]`)
+	wikiBareLinkRe  = regexp.MustCompile(`\[((?:https?|mailto):[^\]]+)\]`)
+	wikiBoldRe      = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*\S)?)\*($|[^\w*])`)
+	wikiItalicRe    = regexp.MustCompile(`(^|[^\w_])_(\S(?:[^_]*\S)?)_($|[^\w_])`)
+	wikiStrikeRe    = regexp.MustCompile(`(^|\s)-(\S(?:[^-]*\S)?)-($|[\s.,;:!?])`)
+	wikiColorRe     = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+	wikiPanelRe     = regexp.MustCompile(`^\{(info|note|tip|warning|panel)(?::([^}]*))?\}$`)
+	wikiTitleRe     = regexp.MustCompile(`(?:^|\|)title=([^|]*)`)
+)
+
+// WikiToMarkdown converts Jira wiki markup into markdown so Server/DC bodies
+// can flow through the same MarkdownToADF → RenderADF pipeline as Cloud.
+// The {info}, {note}, {tip}, {warning} and {panel} macros become ":::"
+// panels, mentions become "@[id](id)" and colours are dropped; other
+// constructs without a markdown equivalent (anchors, images) are left as
+// literal text.
+func WikiToMarkdown(input string) string {
+	if input == "" {
+		return ""
+	}
+
+	lines := strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	inCode := false
+	inQuote := false
+	inTable := false
+	panel := ""
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if inCode {
+			if trimmed == "{code}" || trimmed == "{noformat}" {
+				out = append(out, "```")
+				inCode = false
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+
+		if m := wikiCodeStartRe.FindStringSubmatch(trimmed); m != nil {
+			out = append(out, "```"+m[1])
+			inCode = true
+			continue
+		}
+		if trimmed == "{noformat}" {
+			out = append(out, "```")
+			inCode = true
+			continue
+		}
+		if trimmed == "{quote}" {
+			inQuote = !inQuote
+			continue
+		}
+		if m := wikiPanelRe.FindStringSubmatch(trimmed); m != nil {
+			if panel == m[1] && m[2] == "" {
+				out = append(out, ":::")
+				panel = ""
+				continue
+			}
+			if panel == "" {
+				panel = m[1]
+				panelType := m[1]
+				if panelType == "panel" {
+					panelType = "info"
+				}
+				out = append(out, ":::"+panelType)
+				if title := wikiTitleRe.FindStringSubmatch(m[2]); title != nil && strings.TrimSpace(title[1]) != "" {
+					out = append(out, "**"+strings.TrimSpace(title[1])+"**", "")
+				}
+				continue
+			}
+		}
+
+		// Markdown tables need a header row; a wiki table that starts
+		// without one gets its first row promoted.
+		isRow := strings.HasPrefix(trimmed, "|")
+		if isRow && !inTable && !strings.HasPrefix(trimmed, "||") {
+			trimmed = "||" + strings.ReplaceAll(strings.Trim(trimmed, "|"), "|", "||") + "||"
+		}
+		inTable = isRow
+
+		converted := wikiLineToMarkdown(trimmed)
+		if inQuote {
+			converted = "> " + converted
+		}
+		out = append(out, converted)
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// wikiLineToMarkdown converts a single non-code line of wiki markup.
+func wikiLineToMarkdown(line string) string {
+	switch {
+	case line == "":
+		return ""
+
+	case line == "----":
+		return "---"
+
+	case strings.HasPrefix(line, "bq. "):
+		return "> " + wikiInlineToMarkdown(strings.TrimPrefix(line, "bq. "))
+
+	case strings.HasPrefix(line, "||"):
+		cells := strings.Split(strings.Trim(line, "|"), "||")
+		row := wikiTableRow(cells)
+		return row + "\n" + strings.TrimSpace(strings.Repeat("| --- ", len(cells))+"|")
+
+	case strings.HasPrefix(line, "|"):
+		return wikiTableRow(strings.Split(strings.Trim(line, "|"), "|"))
+	}
+
+	if m := wikiHeadingRe.FindStringSubmatch(line); m != nil {
+		return strings.Repeat("#", int(m[1][0]-'0')) + " " + wikiInlineToMarkdown(m[2])
+	}
+
+	if m := wikiListRe.FindStringSubmatch(line); m != nil {
+		markers := m[1]
+		bullet := "- "
+		if strings.HasSuffix(markers, "#") {
+			bullet = "1. "
+		}
+		depth := len(markers)
+		if markers == "-" {
+			depth = 1
+		}
+		return strings.Repeat("  ", depth-1) + bullet + wikiInlineToMarkdown(m[2])
+	}
+
+	return wikiInlineToMarkdown(line)
+}
+
+func wikiTableRow(cells []string) string {
+	var sb strings.Builder
+	sb.WriteString("|")
+	for _, cell := range cells {
+		sb.WriteString(" " + wikiInlineToMarkdown(strings.TrimSpace(cell)) + " |")
+	}
+	return sb.String()
+}
+
+// wikiInlineToMarkdown converts inline wiki formatting. Monospace spans and
+// mentions are converted first and shielded so their contents are not
+// re-interpreted.
+func wikiInlineToMarkdown(text string) string {
+	var protected []string
+	protect := func(converted string) string {
+		protected = append(protected, converted)
+		return fmt.Sprintf("\x00%d\x00", len(protected)-1)
+	}
+	text = wikiMonospaceRe.ReplaceAllStringFunc(text, func(m string) string {
+		return protect("`" + wikiMonospaceRe.FindStringSubmatch(m)[1] + "`")
+	})
+	text = wikiMentionRe.ReplaceAllStringFunc(text, func(m string) string {
+		id := wikiMentionRe.FindStringSubmatch(m)[1]
+		return protect("@[" + id + "](" + id + ")")
+	})
+
+	text = wikiColorRe.ReplaceAllString(text, "")
+	text = wikiLinkRe.ReplaceAllString(text, "[$1]($2)")
+	text = wikiBareLinkRe.ReplaceAllString(text, "<$1>")
+	text = wikiBoldRe.ReplaceAllString(text, "$1**$2**$3")
+	text = wikiItalicRe.ReplaceAllString(text, "$1*$2*$3")
+	text = wikiStrikeRe.ReplaceAllString(text, "$1~~$2~~$3")
+	text = strings.ReplaceAll(text, `\\`, "\n")
+
+	for i, code := range protected {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), code, 1)
+	}
+	return text
+}
+
+// WikiToADF converts Jira wiki markup into an ADF document by way of
+// markdown.
+func WikiToADF(input string) *models.CommentNodeScheme {
+	return MarkdownToADF(WikiToMarkdown(input))
+}
+
+// MarkdownToWiki converts markdown into Jira wiki markup by way of ADF.
+func MarkdownToWiki(input string) string {
+	return ADFToWiki(MarkdownToADF(input))
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestADFToWiki_Blocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"plain", "Hello world", "Hello world"},
+		{"heading", "## Overview", "h2. Overview"},
+		{"bold and italic", "**bold** and *italic*", "*bold* and _italic_"},
+		{"inline code", "run `make`", "run {{make}}"},
+		{"strike", "~~gone~~", "-gone-"},
+		{"link", "[docs](https://example.com)", "[docs|https://example.com]"},
+		{"bullet list", "- one\n- two", "* one\n* two"},
+		{"nested list", "- one\n  - inner", "* one\n** inner"},
+		{"ordered list", "1. first\n2. second", "# first\n# second"},
+		{"code block", "```go\nfmt.Println()\n```", "{code:go}\nfmt.Println()\n{code}"},
+		{"quote", "> quoted", "{quote}\nquoted\n{quote}"},
+		{"rule", "---", "----"},
+		{"table", "| a | b |\n| --- | --- |\n| 1 | 2 |", "||a||b||\n|1|2|"},
+		{"tasks", "- [ ] open\n- [x] done", "* [ ] open\n* [x] done"},
+		{"panel", ":::error\nBroken\n:::", "{warning}\nBroken\n{warning}"},
+		{"expand", "<details>\n<summary>More</summary>\n\nhidden\n\n</details>", "*More*\n\nhidden"},
+		{"mention", "hi @[Mia](5b10a)", "hi [~5b10a]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ADFToWiki(MarkdownToADF(tt.markdown))
+			if got != tt.want {
+				t.Errorf("ADFToWiki(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestADFToWiki_Nil(t *testing.T) {
+	if got := ADFToWiki(nil); got != "" {
+		t.Errorf("nil node should render empty, got %q", got)
+	}
+}
+
+func TestWikiToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		wiki string
+		want string
+	}{
+		{"heading", "h3. Steps", "### Steps"},
+		{"bold", "this is *important*", "this is **important**"},
+		{"italic", "an _aside_ here", "an *aside* here"},
+		{"monospace shields content", "call {{do_it_now}}", "call `do_it_now`"},
+		{"strike", "was -wrong- fixed", "was ~~wrong~~ fixed"},
+		{"hyphenated words untouched", "a well-known re-run", "a well-known re-run"},
+		{"link", "see [docs|https://example.com]", "see [docs](https://example.com)"},
+		{"bare link", "[https://example.com]", "<https://example.com>"},
+		{"mention", "ping [~jdoe]", "ping @[jdoe](jdoe)"},
+		{"cloud mention", "ping [~accountid:5b10a]", "ping @[5b10a](5b10a)"},
+		{"mention shielded", "[~j_doe_x] and _this_", "@[j_doe_x](j_doe_x) and *this*"},
+		{"color dropped", "{color:red}alert{color} now", "alert now"},
+		{"bullets", "* one\n** two", "- one\n  - two"},
+		{"numbered", "# one\n# two", "1. one\n1. two"},
+		{"code block", "{code:java}\nint x = 1;\n{code}", "```java\nint x = 1;\n```"},
+		{"noformat", "{noformat}\n*raw*\n{noformat}", "```\n*raw*\n```"},
+		{"quote block", "{quote}\nsaid it\n{quote}", "> said it"},
+		{"bq", "bq. short quote", "> short quote"},
+		{"rule", "----", "---"},
+		{"table", "||a||b||\n|1|2|", "| a | b |\n| --- | --- |\n| 1 | 2 |"},
+		{"headless table", "|1|2|\n|3|4|", "| 1 | 2 |\n| --- | --- |\n| 3 | 4 |"},
+		{"panel", "{warning}\nCareful\n{warning}", ":::warning\nCareful\n:::"},
+		{"titled panel", "{panel:title=Notes|borderStyle=dashed}\ntext\n{panel}", ":::info\n**Notes**\n\ntext\n:::"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WikiToMarkdown(tt.wiki); got != tt.want {
+				t.Errorf("WikiToMarkdown(%q) = %q, want %q", tt.wiki, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWikiToMarkdown_RendersThroughADF(t *testing.T) {
+	// Server/DC descriptions take the path wiki → markdown → ADF → RenderADF.
+	// Make sure the formatting survives the whole trip.
+	wiki := "h1. Title\n\nSome *bold* text with {{code}}.\n\n* item one\n* item two"
+	got := RenderADF(MarkdownToADF(WikiToMarkdown(wiki)))
+	want := "# Title\n\nSome **bold** text with `code`.\n\n- item one\n- item two"
+	if got != want {
+		t.Errorf("round trip = %q, want %q", got, want)
+	}
+}
+
+func TestWikiRoundTrip(t *testing.T) {
+	// Wiki that MarkdownToWiki can write should survive wiki → ADF → wiki.
+	wiki := "h2. Plan\n\n{info}\nShip *Friday*\n{info}\n\n||Step||Owner||\n|Build|[~jdoe]|\n\n* [x] tests\n* [ ] docs"
+	if got := ADFToWiki(WikiToADF(wiki)); got != wiki {
+		t.Errorf("round trip =\n%s\nwant\n%s", got, wiki)
+	}
+	if got := MarkdownToWiki("**bold**"); got != "*bold*" {
+		t.Errorf("MarkdownToWiki = %q", got)
+	}
+}